@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const usage = `adminctl talks to the server's admin API.
+
+Usage:
+  adminctl [flags] rooms                  list rooms
+  adminctl [flags] room <code>            dump a room's state
+  adminctl [flags] events <code>          print a room's event log and check it rebuilds the room
+  adminctl [flags] end <code> [reason]    force-end a room without a result
+  adminctl [flags] weights <file.json>    replace the default heuristic weights ("-" reads stdin)
+  adminctl [flags] reload                 reload the server's config file
+  adminctl [flags] logs <code> [-f]       print (and follow) a room's recent events
+
+Flags:
+`
+
+// client calls the admin API with the operator token
+type client struct {
+	base  string
+	token string
+	http  *http.Client
+}
+
+// Operator CLI for the admin API. The server URL and token come from flags or
+// the ADMINCTL_URL and ADMIN_TOKEN environment variables.
+func main() {
+	addr := flag.String("url", envOr("ADMINCTL_URL", "http://localhost:9000"), "server base URL")
+	token := flag.String("token", os.Getenv("ADMIN_TOKEN"), "admin token")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *token == "" {
+		fail("an admin token is required (-token or ADMIN_TOKEN)")
+	}
+
+	c := &client{
+		base:  strings.TrimRight(*addr, "/"),
+		token: *token,
+		http:  &http.Client{Timeout: 10 * time.Second},
+	}
+
+	var err error
+	switch args[0] {
+	case "rooms":
+		err = c.printJSON(http.MethodGet, "/api/admin/rooms", nil)
+	case "room":
+		if len(args) != 2 {
+			fail("usage: adminctl room <code>")
+		}
+		err = c.printJSON(http.MethodGet, "/api/admin/rooms/"+url.PathEscape(args[1]), nil)
+	case "events":
+		if len(args) != 2 {
+			fail("usage: adminctl events <code>")
+		}
+		err = c.printJSON(http.MethodGet, "/api/admin/rooms/"+url.PathEscape(args[1])+"/events", nil)
+	case "end":
+		if len(args) < 2 {
+			fail("usage: adminctl end <code> [reason]")
+		}
+		path := "/api/admin/rooms/" + url.PathEscape(args[1]) + "/end"
+		if len(args) > 2 {
+			path += "?reason=" + url.QueryEscape(strings.Join(args[2:], " "))
+		}
+		err = c.printJSON(http.MethodPost, path, nil)
+	case "weights":
+		if len(args) != 2 {
+			fail("usage: adminctl weights <file.json>")
+		}
+		var body []byte
+		body, err = readInput(args[1])
+		if err == nil {
+			err = c.printJSON(http.MethodPut, "/api/admin/weights/default", body)
+		}
+	case "reload":
+		err = c.printJSON(http.MethodPost, "/api/admin/config/reload", nil)
+	case "logs":
+		if len(args) < 2 {
+			fail("usage: adminctl logs <code> [-f]")
+		}
+		err = c.logs(args[1], args[2:])
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fail(err.Error())
+	}
+}
+
+// do sends a request and returns the response body, turning API errors into Go errors
+func (c *client) do(method, path string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(method, c.base+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Admin-Token", c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("%s (%s, HTTP %d)", apiErr.Error.Message, apiErr.Error.Code, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return data, nil
+}
+
+// printJSON performs a request and pretty-prints the response
+func (c *client) printJSON(method, path string, body []byte) error {
+	data, err := c.do(method, path, body)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		os.Stdout.Write(data)
+		return nil
+	}
+	out.WriteByte('\n')
+	_, err = out.WriteTo(os.Stdout)
+	return err
+}
+
+// logs prints a room's recent events, polling for new ones with -f
+func (c *client) logs(code string, args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	follow := fs.Bool("f", false, "follow the room")
+	interval := fs.Duration("interval", time.Second, "poll interval when following")
+	fs.Parse(args)
+
+	var since uint64
+	for {
+		path := fmt.Sprintf("/api/debug/rooms/%s/events?since=%d", url.PathEscape(code), since)
+		data, err := c.do(http.MethodGet, path, nil)
+		if err != nil {
+			return err
+		}
+
+		var resp struct {
+			Data struct {
+				Events []struct {
+					At       time.Time `json:"at"`
+					Kind     string    `json:"kind"`
+					PlayerID string    `json:"player_id"`
+					Message  string    `json:"message"`
+				} `json:"events"`
+				Next      uint64 `json:"next"`
+				Truncated bool   `json:"truncated"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return err
+		}
+		if resp.Data.Truncated && since > 0 {
+			fmt.Println("... (older events dropped)")
+		}
+		for _, e := range resp.Data.Events {
+			fmt.Printf("%s %-12s %-10s %s\n", e.At.Format(time.RFC3339), e.Kind, e.PlayerID, e.Message)
+		}
+
+		if !*follow {
+			return nil
+		}
+		since = resp.Data.Next
+		time.Sleep(*interval)
+	}
+}
+
+func readInput(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(name)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func fail(msg string) {
+	fmt.Fprintln(os.Stderr, "adminctl:", msg)
+	os.Exit(1)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/engine"
+	"javanese-chess/internal/game"
+	"log"
+	"math/rand"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// position is a board with the hand of the player to move
+type position struct {
+	board  game.Board
+	hand   []int
+	player string
+}
+
+// Times bot move selection, legal moves and their parallel scoring, over a
+// fixed set of boards for each worker count, so speedups of the evaluation
+// pool can be measured and compared between commits
+func main() {
+	games := flag.Int("games", 20, "seeded self-play games the boards are taken from")
+	seed := flag.Int64("seed", 1, "seed of the deals; the same seed gives the same boards")
+	size := flag.Int("size", 9, "board size")
+	rounds := flag.Int("rounds", 3, "times every board is timed per worker count; the fastest round counts")
+	workerList := flag.String("workers", fmt.Sprintf("0,1,2,4,%d", runtime.NumCPU()), "comma-separated pool sizes to time")
+	flag.Parse()
+
+	cfg := config.Load()
+	log.SetOutput(io.Discard)
+
+	workers, err := parseWorkers(*workerList)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	eng, _ := engine.Get(engine.Default)
+	positions := selfPlay(eng, cfg, *games, *seed, *size)
+	fmt.Printf("%d boards from %d games (seed %d, %dx%d), GOMAXPROCS %d\n",
+		len(positions), *games, *seed, *size, *size, runtime.GOMAXPROCS(0))
+
+	var base time.Duration
+	var picks []game.Move
+	for _, w := range workers {
+		pool := game.NewEvalPool(w)
+		best := time.Duration(0)
+		var chosen []game.Move
+		for round := 0; round < *rounds; round++ {
+			start := time.Now()
+			chosen = chosen[:0]
+			for _, p := range positions {
+				chosen = append(chosen, selectMove(eng, pool, cfg, p))
+			}
+			if elapsed := time.Since(start); best == 0 || elapsed < best {
+				best = elapsed
+			}
+		}
+
+		// Every pool size must pick the same moves as scoring one by one
+		if picks == nil {
+			picks = append([]game.Move(nil), chosen...)
+		} else {
+			for i := range picks {
+				if picks[i] != chosen[i] {
+					fmt.Printf("FAIL workers %d: board %d picks %+v instead of %+v\n", w, i, chosen[i], picks[i])
+					os.Exit(1)
+				}
+			}
+		}
+
+		if base == 0 {
+			base = best
+		}
+		perMove := best / time.Duration(max(len(positions), 1))
+		fmt.Printf("workers %-3d %10v total %10v per move  %.2fx\n", w, best.Round(time.Microsecond), perMove, float64(base)/float64(best))
+	}
+}
+
+// selectMove scores the legal moves on the pool the way a bot does and
+// returns the best
+func selectMove(eng engine.Engine, pool *game.EvalPool, cfg *config.Config, p position) game.Move {
+	cands := eng.LegalMoves(&p.board, p.hand, p.player)
+	scored, _ := pool.Score(cands, time.Time{}, func(mv game.Move) int {
+		return game.EvaluateMove(&p.board, mv.X, mv.Y, mv.Card, p.player, cfg)
+	})
+	chosen, _ := game.SampleMove(scored, 0, nil)
+	return chosen.Move
+}
+
+// selfPlay plays seeded games between two default bots and keeps the board
+// before every move. Hands are drawn from the seed, so the boards only
+// depend on the seed and the heuristic.
+func selfPlay(eng engine.Engine, cfg *config.Config, games int, seed int64, size int) []position {
+	rng := rand.New(rand.NewSource(seed))
+	players := []string{"a", "b"}
+	pool := game.NewEvalPool(0)
+
+	var out []position
+	for g := 0; g < games; g++ {
+		b := eng.NewGame(size)
+		for ply := 0; ply < size*size; ply++ {
+			p := position{board: b.Clone(), player: players[ply%2]}
+			for len(p.hand) < 3 {
+				p.hand = append(p.hand, 1+rng.Intn(9))
+			}
+			if len(eng.LegalMoves(&p.board, p.hand, p.player)) == 0 {
+				break
+			}
+			out = append(out, p)
+
+			mv := selectMove(eng, pool, cfg, p)
+			mv.PlayerID = p.player
+			eng.Apply(&b, mv)
+			if eng.Winner(&b, mv.X, mv.Y, p.player) != nil {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// parseWorkers reads the pool sizes to time, each once in the given order
+func parseWorkers(list string) ([]int, error) {
+	var out []int
+	for _, s := range strings.Split(list, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("bad worker count %q", s)
+		}
+		if !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/regression"
+	"log"
+	"os"
+)
+
+// Replays the curated critical positions through the bot and exits non-zero
+// when it no longer plays an approved move or its evaluation degraded
+func main() {
+	tolerance := flag.Float64("tolerance", 0.05, "allowed drop of the approved move's score, as a fraction")
+	weightsPath := flag.String("weights", "", "JSON file of heuristic weights layered over the defaults")
+	verbose := flag.Bool("v", false, "print the bot's choice in every position")
+	flag.Parse()
+
+	cfg := config.Load()
+	log.SetOutput(io.Discard)
+
+	weights := cfg.DefaultWeights.Clone()
+	if *weightsPath != "" {
+		data, err := os.ReadFile(*weightsPath)
+		if err == nil {
+			err = json.Unmarshal(data, &weights)
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+		if !weights.ValidateWeights() {
+			fmt.Println("weights must be non-negative")
+			os.Exit(2)
+		}
+	}
+
+	results, failures := regression.Check(&weights, *tolerance)
+	if *verbose {
+		for _, r := range results {
+			fmt.Printf("%-42s card %d at (%d,%d), score %d\n", r.Position, r.Chosen.Card, r.Chosen.X, r.Chosen.Y, r.Score)
+		}
+	}
+	for _, f := range failures {
+		fmt.Println("FAIL", f)
+	}
+
+	fmt.Printf("%d positions, %d failures\n", len(regression.Positions), len(failures))
+	if len(failures) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"javanese-chess/internal/wstest"
+	"log"
+	"os"
+)
+
+// Runs the end-to-end flows against an in-process server and exits
+// non-zero when any flow fails
+func main() {
+	verbose := flag.Bool("v", false, "show the server log")
+	flag.Parse()
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	failures := wstest.Run(wstest.Flows)
+	for _, f := range failures {
+		fmt.Println("FAIL", f)
+	}
+
+	fmt.Printf("%d flows, %d failures\n", len(wstest.Flows), len(failures))
+	if len(failures) > 0 {
+		os.Exit(1)
+	}
+}
@@ -1,20 +1,19 @@
 package main
 
 import (
+	"context"
 	"io"
-	httpapi "javanese-chess/internal/api/http"
-	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/app"
 	"javanese-chess/internal/config"
 	"javanese-chess/internal/room"
-	"javanese-chess/internal/store"
+	"javanese-chess/internal/tracing"
 	"log"
-	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	// swagger packages
 	_ "javanese-chess/docs"
-
-	"github.com/gin-gonic/gin"
 )
 
 // @title Javanese Chess Bot API
@@ -24,8 +23,25 @@ import (
 // @contact.email backend@yourcompany.com
 // @BasePath /
 func main() {
+	// Offline tools run instead of the server
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "replay":
+			os.Exit(runReplay(os.Args[2:]))
+		case "tournament":
+			os.Exit(runTournament(os.Args[2:]))
+		case "verify":
+			os.Exit(runVerify(os.Args[2:]))
+		}
+	}
+
+	cfg := config.Load()
+	if err := config.LoadErr(); err != nil {
+		log.Fatalf("config: %v", err)
+	}
+
 	// Setup logging to both file and console
-	logFile, err := os.OpenFile("javanese-chess.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	logFile, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
 	if err != nil {
 		log.Printf("Warning: Could not open log file: %v. Logging to console only.", err)
 	} else {
@@ -36,30 +52,51 @@ func main() {
 		log.Println("=== Javanese Chess Server Started ===")
 	}
 
-	cfg := config.Load()
-	mem := store.NewMemoryStore()
-	hub := ws.NewHub(room.NewManager(mem, *cfg, nil))
-	rm := room.NewManager(mem, *cfg, hub)
-
-	// Create the Manager first, with a nil Hub
-	rm = room.NewManager(mem, *cfg, nil)
-
-	// Create the Hub, passing the Manager
-	hub = ws.NewHub(rm)
+	log.Printf("Using %s profile", cfg.Profile.Name)
+	if path := config.ConfigFile(); path != "" {
+		log.Printf("Using config file %s", path)
+	}
 
-	// Set the Hub in the Manager
-	rm.SetHub(hub)
+	// Export traces when a collector is configured
+	tracer := tracing.New(tracing.Options{
+		Endpoint:    cfg.TracingEndpoint,
+		Headers:     cfg.TracingHeaders,
+		Service:     cfg.TracingService,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if tracer != nil {
+		log.Printf("Exporting traces to %s", cfg.TracingEndpoint)
+	}
+	tracing.SetGlobal(tracer)
+	defer tracer.Shutdown()
 
-	r := httpapi.SetupRouter(rm, mem, hub)
+	a, err := app.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// Optional: Add root redirect to swagger
-	r.GET("/", func(c *gin.Context) {
-		c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
-	})
+	// SIGHUP reloads the room defaults from the config file
+	reloadOnHangup(a.Manager)
 
-	// Use HTTP address from config (which reads from env or uses default)
-	log.Printf("listening on %s", cfg.HTTPAddr)
-	if err := r.Run(cfg.HTTPAddr); err != nil {
+	// SIGINT and SIGTERM shut the server down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := a.Run(ctx); err != nil {
 		log.Fatal(err)
 	}
+	log.Println("=== Javanese Chess Server Stopped ===")
+}
+
+// reloadOnHangup reloads the configuration every time the process receives
+// SIGHUP. A broken file is reported and the previous settings stay.
+func reloadOnHangup(rm *room.Manager) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if _, err := rm.ReloadConfig(); err != nil {
+				log.Printf("Config reload failed, keeping the previous settings: %v", err)
+			}
+		}
+	}()
 }
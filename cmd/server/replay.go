@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/record"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const replayUsage = `Usage: server replay [-no-color] <file.json>
+
+Steps through an exported game record. Commands:
+  n, next, <enter>   next move
+  p, prev            previous move
+  j <n>, jump <n>    go to move n (0 is the empty board)
+  first, last        go to the start or end of the game
+  q, quit            exit
+`
+
+// ansiColors maps player colors to terminal color codes
+var ansiColors = map[string]string{
+	"red":    "31",
+	"green":  "32",
+	"blue":   "34",
+	"purple": "35",
+}
+
+// viewer steps through a game record and renders it to a terminal
+type viewer struct {
+	rec     *record.GameRecord
+	step    int // Number of moves applied
+	color   bool
+	players map[string]record.PlayerRecord
+	seats   map[string]byte // Player ID to the letter shown on the board
+	out     io.Writer
+}
+
+// runReplay is the replay subcommand: an offline viewer for games exported
+// from /api/rooms/{code}/export, for debugging reported games
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	noColor := fs.Bool("no-color", false, "render without ANSI colors")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, replayUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	data, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	rec, err := record.Import(data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	v := &viewer{rec: rec, color: !*noColor, players: map[string]record.PlayerRecord{}, seats: map[string]byte{}, out: os.Stdout}
+	for i, p := range rec.Players {
+		v.players[p.ID] = p
+		v.seats[p.ID] = byte('A' + i)
+	}
+	v.run(os.Stdin)
+	return 0
+}
+
+// run renders the current move and reads commands until quit or end of input
+func (v *viewer) run(in io.Reader) {
+	v.render()
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(v.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(v.out)
+			return
+		}
+
+		fields := strings.Fields(scanner.Text())
+		cmd := "next"
+		if len(fields) > 0 {
+			cmd = strings.ToLower(fields[0])
+		}
+
+		switch cmd {
+		case "n", "next":
+			v.jump(v.step + 1)
+		case "p", "prev":
+			v.jump(v.step - 1)
+		case "j", "jump":
+			if len(fields) != 2 {
+				fmt.Fprintln(v.out, "usage: jump <n>")
+				continue
+			}
+			n, err := strconv.Atoi(fields[1])
+			if err != nil {
+				fmt.Fprintf(v.out, "invalid move number %q\n", fields[1])
+				continue
+			}
+			v.jump(n)
+		case "first":
+			v.jump(0)
+		case "last":
+			v.jump(len(v.rec.Moves))
+		case "q", "quit", "exit":
+			return
+		default:
+			fmt.Fprint(v.out, replayUsage)
+			continue
+		}
+		v.render()
+	}
+}
+
+// jump moves to move n, clamped to the game
+func (v *viewer) jump(n int) {
+	if n < 0 {
+		n = 0
+	}
+	if n > len(v.rec.Moves) {
+		n = len(v.rec.Moves)
+	}
+	v.step = n
+}
+
+// render prints the move just played, the board after it and, at the end of
+// the game, the result
+func (v *viewer) render() {
+	board := record.Replay(v.rec, v.step)
+	last := game.Coord{X: -1, Y: -1}
+
+	fmt.Fprintf(v.out, "\n%s  move %d/%d\n", v.rec.RoomCode, v.step, len(v.rec.Moves))
+	if v.step > 0 {
+		mv := v.rec.Moves[v.step-1]
+		if mv.Type.Normalize() == game.MovePlace {
+			last = game.Coord{X: mv.X, Y: mv.Y}
+		}
+		fmt.Fprintln(v.out, v.describe(mv))
+	}
+
+	// Column letters, then one line per row with its 1-based number
+	fmt.Fprint(v.out, "   ")
+	for x := 0; x < board.Size; x++ {
+		fmt.Fprintf(v.out, " %c  ", 'A'+x)
+	}
+	fmt.Fprintln(v.out)
+	for y := 0; y < board.Size; y++ {
+		fmt.Fprintf(v.out, "%2d ", y+1)
+		for x := 0; x < board.Size; x++ {
+			fmt.Fprint(v.out, v.cell(board.At(game.Coord{X: x, Y: y}), last.X == x && last.Y == y))
+		}
+		fmt.Fprintln(v.out)
+	}
+	fmt.Fprintln(v.out, v.legend())
+
+	if v.step == len(v.rec.Moves) {
+		fmt.Fprintln(v.out, v.result())
+	}
+}
+
+// cell renders one cell as four characters: the owner's letter and the card,
+// bracketed when it is the last move played, with "!" on locked cells
+func (v *viewer) cell(c game.Cell, last bool) string {
+	if c.Value == 0 {
+		return " .  "
+	}
+
+	seat, ok := v.seats[c.OwnerID]
+	if !ok {
+		seat = '?'
+	}
+	text := fmt.Sprintf("%c%d", seat, c.Value)
+	switch {
+	case last:
+		text = "[" + text + "]"
+	case c.Locked:
+		text = " " + text + "!"
+	default:
+		text = " " + text + " "
+	}
+	return v.paint(c.OwnerID, text)
+}
+
+// paint colors text with the owner's player color
+func (v *viewer) paint(ownerID, text string) string {
+	code, ok := ansiColors[v.players[ownerID].Color]
+	if !v.color || !ok {
+		return text
+	}
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+// describe explains a move in words
+func (v *viewer) describe(mv game.MoveRecord) string {
+	who := v.name(mv.PlayerID)
+	switch mv.Type.Normalize() {
+	case game.MoveSkip:
+		return who + " skips"
+	case game.MoveResign:
+		return who + " resigns"
+	case game.MoveSwap:
+		return fmt.Sprintf("%s swaps a %d", who, mv.Card)
+	}
+
+	at := game.Coord{X: mv.X, Y: mv.Y}.Algebraic()
+	text := fmt.Sprintf("%s plays %d at %s", who, mv.Card, at)
+	if prev := mv.PrevCell; prev.Value != 0 && prev.OwnerID != mv.PlayerID {
+		text += fmt.Sprintf(", capturing %s's %d", v.name(prev.OwnerID), prev.Value)
+	}
+	return text
+}
+
+// legend lists the players by letter, in their colors
+func (v *viewer) legend() string {
+	parts := make([]string, 0, len(v.rec.Players))
+	for _, p := range v.rec.Players {
+		label := fmt.Sprintf("%c %s", v.seats[p.ID], p.Name)
+		if p.IsBot {
+			label += " (bot)"
+		}
+		parts = append(parts, v.paint(p.ID, label))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// result describes how the game ended
+func (v *viewer) result() string {
+	res := v.rec.Result
+	switch {
+	case res.WinnerID != nil:
+		return "Result: " + v.name(*res.WinnerID) + " wins"
+	case res.Draw:
+		return "Result: draw"
+	}
+	return "Result: " + res.Status
+}
+
+// name is a player's display name, falling back to the ID
+func (v *viewer) name(id string) string {
+	if p, ok := v.players[id]; ok && p.Name != "" {
+		return p.Name
+	}
+	return id
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/room"
+	"javanese-chess/internal/store"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const tournamentUsage = `Usage: server tournament [flags] <profile> <profile> [profile...]
+
+Plays every pair of weight profiles against each other on a range of seeds,
+once from each seat, and reports the standings. A profile is a JSON file of
+heuristic weights (missing fields keep their default) or "default" for the
+built-in weights. The profile name is the file name without extension.
+
+Flags:
+`
+
+// profile is a named set of weights taking part in the tournament
+type profile struct {
+	Name    string
+	Weights config.HeuristicWeights
+}
+
+// tournamentGame is one game to play: profiles A and B on a seed, A in seat 1
+type tournamentGame struct {
+	Seed int64
+	A, B int
+}
+
+// GameResult is the outcome of one tournament game
+type GameResult struct {
+	Seed   int64  `json:"seed"`
+	Seat1  string `json:"seat_1"`
+	Seat2  string `json:"seat_2"`
+	Winner string `json:"winner,omitempty"` // Profile name; empty for draws and failed games
+	Draw   bool   `json:"draw"`
+	Moves  int    `json:"moves"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Standing is a profile's overall tournament record. Points count a win as
+// one and a draw as a half.
+type Standing struct {
+	Profile string  `json:"profile"`
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	Draws   int     `json:"draws"`
+	Points  float64 `json:"points"`
+	WinRate float64 `json:"win_rate"`
+}
+
+// Pairing is the head-to-head record of two profiles
+type Pairing struct {
+	A     string `json:"a"`
+	B     string `json:"b"`
+	AWins int    `json:"a_wins"`
+	BWins int    `json:"b_wins"`
+	Draws int    `json:"draws"`
+}
+
+// TournamentReport is the JSON results summary
+type TournamentReport struct {
+	Seeds     int          `json:"seeds"`
+	SeedStart int64        `json:"seed_start"`
+	Standings []Standing   `json:"standings"`
+	Pairings  []Pairing    `json:"pairings"`
+	Games     []GameResult `json:"games"`
+}
+
+// runTournament is the tournament subcommand: a headless round robin between
+// weight profiles for research evaluation, without the HTTP server
+func runTournament(args []string) int {
+	fs := flag.NewFlagSet("tournament", flag.ContinueOnError)
+	seeds := fs.Int("seeds", 20, "number of seeds each pair plays")
+	seedStart := fs.Int64("seed-start", 1, "first seed")
+	workers := fs.Int("workers", runtime.NumCPU(), "games played in parallel")
+	maxMoves := fs.Int("max-moves", 500, "turns after which a game is abandoned")
+	out := fs.String("out", "", "write results to a .csv (standings) or .json (full report) file")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, tournamentUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 2 || *seeds < 1 || *workers < 1 {
+		fs.Usage()
+		return 2
+	}
+
+	// Bots log every evaluated move
+	log.SetOutput(io.Discard)
+	cfg := config.Load()
+
+	profiles, err := loadProfiles(fs.Args(), cfg.DefaultWeights)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var games []tournamentGame
+	for a := range profiles {
+		for b := a + 1; b < len(profiles); b++ {
+			for s := int64(0); s < int64(*seeds); s++ {
+				seed := *seedStart + s
+				games = append(games, tournamentGame{Seed: seed, A: a, B: b}, tournamentGame{Seed: seed, A: b, B: a})
+			}
+		}
+	}
+
+	results := playTournament(cfg, profiles, games, *workers, *maxMoves)
+	report := TournamentReport{
+		Seeds:     *seeds,
+		SeedStart: *seedStart,
+		Standings: standings(profiles, results),
+		Pairings:  pairings(profiles, results),
+		Games:     results,
+	}
+
+	printStandings(os.Stdout, report)
+	if *out != "" {
+		if err := writeReport(*out, report); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// loadProfiles reads the weight profiles, each starting from the defaults
+func loadProfiles(paths []string, defaults config.HeuristicWeights) ([]profile, error) {
+	seen := map[string]bool{}
+	out := make([]profile, 0, len(paths))
+	for _, path := range paths {
+		p := profile{Name: path, Weights: defaults.Clone()}
+		if path != "default" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, &p.Weights); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if !p.Weights.ValidateWeights() {
+				return nil, fmt.Errorf("%s: weights must be non-negative", path)
+			}
+			p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("two profiles are named %q", p.Name)
+		}
+		seen[p.Name] = true
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// playTournament plays the games on a pool of workers and returns the
+// results in game order
+func playTournament(cfg *config.Config, profiles []profile, games []tournamentGame, workers, maxMoves int) []GameResult {
+	results := make([]GameResult, len(games))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = playTournamentGame(cfg, profiles, games[i], maxMoves)
+			}
+		}()
+	}
+	for i := range games {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// playTournamentGame plays one game in a throwaway room manager, so games
+// never share state
+func playTournamentGame(cfg *config.Config, profiles []profile, g tournamentGame, maxMoves int) GameResult {
+	a, b := profiles[g.A], profiles[g.B]
+	res := GameResult{Seed: g.Seed, Seat1: a.Name, Seat2: b.Name}
+
+	rm := room.NewManager(store.NewMemoryStore(), *cfg, nil)
+	rm.SetBotDelay(0)
+	r, err := rm.CreateBotGame("TOURNAMENT", g.Seed, []*config.HeuristicWeights{&a.Weights, &b.Weights})
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	if err := rm.PlayBotGame(r, maxMoves); err != nil {
+		res.Error = err.Error()
+	}
+	res.Moves = len(r.History)
+	res.Draw = r.Draw
+	if r.WinnerID != nil {
+		for _, p := range r.Players {
+			if p.ID != *r.WinnerID {
+				continue
+			}
+			res.Winner = a.Name
+			if p.Name == "Seat 2" {
+				res.Winner = b.Name
+			}
+		}
+	}
+	return res
+}
+
+// standings totals each profile's results, best first. Failed games do not count.
+func standings(profiles []profile, results []GameResult) []Standing {
+	byName := make(map[string]*Standing, len(profiles))
+	out := make([]Standing, len(profiles))
+	for i, p := range profiles {
+		out[i].Profile = p.Name
+		byName[p.Name] = &out[i]
+	}
+
+	for _, res := range results {
+		if res.Error != "" {
+			continue
+		}
+		for _, name := range []string{res.Seat1, res.Seat2} {
+			s := byName[name]
+			s.Games++
+			switch {
+			case res.Winner == name:
+				s.Wins++
+				s.Points++
+			case res.Winner == "":
+				s.Draws++
+				s.Points += 0.5
+			default:
+				s.Losses++
+			}
+		}
+	}
+
+	for i := range out {
+		if out[i].Games > 0 {
+			out[i].WinRate = float64(out[i].Wins) / float64(out[i].Games)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Points > out[j].Points })
+	return out
+}
+
+// pairings totals the head-to-head results of every pair of profiles
+func pairings(profiles []profile, results []GameResult) []Pairing {
+	index := map[[2]string]*Pairing{}
+	var out []Pairing
+	for a := range profiles {
+		for b := a + 1; b < len(profiles); b++ {
+			out = append(out, Pairing{A: profiles[a].Name, B: profiles[b].Name})
+		}
+	}
+	for i := range out {
+		index[[2]string{out[i].A, out[i].B}] = &out[i]
+		index[[2]string{out[i].B, out[i].A}] = &out[i]
+	}
+
+	for _, res := range results {
+		if res.Error != "" {
+			continue
+		}
+		p := index[[2]string{res.Seat1, res.Seat2}]
+		switch res.Winner {
+		case "":
+			p.Draws++
+		case p.A:
+			p.AWins++
+		default:
+			p.BWins++
+		}
+	}
+	return out
+}
+
+// printStandings writes a plain text summary
+func printStandings(w io.Writer, report TournamentReport) {
+	failed := 0
+	for _, res := range report.Games {
+		if res.Error != "" {
+			failed++
+		}
+	}
+
+	fmt.Fprintf(w, "%d games over %d seeds", len(report.Games), report.Seeds)
+	if failed > 0 {
+		fmt.Fprintf(w, " (%d failed)", failed)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%-20s %6s %6s %6s %6s %7s %6s\n", "profile", "games", "wins", "losses", "draws", "points", "win%")
+	for _, s := range report.Standings {
+		fmt.Fprintf(w, "%-20s %6d %6d %6d %6d %7.1f %5.1f%%\n", s.Profile, s.Games, s.Wins, s.Losses, s.Draws, s.Points, s.WinRate*100)
+	}
+	for _, p := range report.Pairings {
+		fmt.Fprintf(w, "%s vs %s: %d-%d, %d drawn\n", p.A, p.B, p.AWins, p.BWins, p.Draws)
+	}
+}
+
+// writeReport saves the standings as CSV or the full report as JSON,
+// depending on the file extension
+func writeReport(path string, report TournamentReport) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case ".csv":
+		w := csv.NewWriter(f)
+		w.Write([]string{"profile", "games", "wins", "losses", "draws", "points", "win_rate"})
+		for _, s := range report.Standings {
+			w.Write([]string{
+				s.Profile,
+				strconv.Itoa(s.Games),
+				strconv.Itoa(s.Wins),
+				strconv.Itoa(s.Losses),
+				strconv.Itoa(s.Draws),
+				strconv.FormatFloat(s.Points, 'f', 1, 64),
+				strconv.FormatFloat(s.WinRate, 'f', 4, 64),
+			})
+		}
+		w.Flush()
+		return w.Error()
+	}
+	return fmt.Errorf("unknown results format %q (want .csv or .json)", filepath.Ext(path))
+}
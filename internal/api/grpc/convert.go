@@ -0,0 +1,67 @@
+package grpcapi
+
+import (
+	"javanese-chess/internal/api/grpc/gamepb"
+	"javanese-chess/internal/engine"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+)
+
+// roomState builds the room view, revealing players as the room's broadcast
+// policy allows
+func roomState(rx *shared.Room) *gamepb.RoomState {
+	st := &gamepb.RoomState{
+		RoomCode:  rx.Code,
+		Status:    rx.Status,
+		Engine:    rx.Engine,
+		TurnOrder: rx.TurnOrder,
+		Board:     board(rx.Board),
+		Draw:      rx.Draw,
+	}
+	if st.Engine == "" {
+		st.Engine = engine.Default
+	}
+	if rx.WinnerID != nil {
+		st.WinnerId = *rx.WinnerID
+	}
+	if rx.Status == "playing" && rx.WinnerID == nil && !rx.Draw && len(rx.Players) > 0 {
+		st.NextTurn = rx.Players[rx.TurnIdx].ID
+	}
+
+	for _, p := range rx.PlayerView() {
+		st.Players = append(st.Players, &gamepb.Player{
+			Id:        p.ID,
+			Name:      p.Name,
+			IsBot:     p.IsBot,
+			Color:     p.Color,
+			Resigned:  p.Resigned,
+			Hand:      int32s(p.Hand),
+			HandCount: int32(p.HandCount),
+			Ready:     p.Ready,
+		})
+	}
+	return st
+}
+
+// board lists the cells row by row
+func board(b game.Board) *gamepb.Board {
+	out := &gamepb.Board{
+		Size:  int32(b.Size),
+		Cells: make([]*gamepb.Cell, 0, b.Size*b.Size),
+		Hash:  uint64(b.Hash),
+	}
+	for _, row := range b.Cells {
+		for _, c := range row {
+			out.Cells = append(out.Cells, &gamepb.Cell{OwnerId: c.OwnerID, Value: int32(c.Value)})
+		}
+	}
+	return out
+}
+
+func int32s(values []int) []int32 {
+	out := make([]int32, len(values))
+	for i, v := range values {
+		out[i] = int32(v)
+	}
+	return out
+}
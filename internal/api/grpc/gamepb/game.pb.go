@@ -0,0 +1,1079 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: game.proto
+
+// Game service for backends that drive or watch games without the browser
+// WebSocket protocol, such as a matchmaking service or a training pipeline.
+
+package gamepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateRoomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomCode      string                 `protobuf:"bytes,1,opt,name=room_code,json=roomCode,proto3" json:"room_code,omitempty"`
+	PlayerName    string                 `protobuf:"bytes,2,opt,name=player_name,json=playerName,proto3" json:"player_name,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"` // Optional: players must give it to join
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateRoomRequest) Reset() {
+	*x = CreateRoomRequest{}
+	mi := &file_game_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRoomRequest) ProtoMessage() {}
+
+func (x *CreateRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRoomRequest.ProtoReflect.Descriptor instead.
+func (*CreateRoomRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateRoomRequest) GetRoomCode() string {
+	if x != nil {
+		return x.RoomCode
+	}
+	return ""
+}
+
+func (x *CreateRoomRequest) GetPlayerName() string {
+	if x != nil {
+		return x.PlayerName
+	}
+	return ""
+}
+
+func (x *CreateRoomRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type JoinRoomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomCode      string                 `protobuf:"bytes,1,opt,name=room_code,json=roomCode,proto3" json:"room_code,omitempty"`
+	PlayerName    string                 `protobuf:"bytes,2,opt,name=player_name,json=playerName,proto3" json:"player_name,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinRoomRequest) Reset() {
+	*x = JoinRoomRequest{}
+	mi := &file_game_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinRoomRequest) ProtoMessage() {}
+
+func (x *JoinRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinRoomRequest.ProtoReflect.Descriptor instead.
+func (*JoinRoomRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *JoinRoomRequest) GetRoomCode() string {
+	if x != nil {
+		return x.RoomCode
+	}
+	return ""
+}
+
+func (x *JoinRoomRequest) GetPlayerName() string {
+	if x != nil {
+		return x.PlayerName
+	}
+	return ""
+}
+
+func (x *JoinRoomRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// JoinResult is the room and the seat the caller now holds
+type JoinResult struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Room     *RoomState             `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	PlayerId string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	// Identifies the WebSocket connection as this seat when it has no account
+	SeatSecret    string `protobuf:"bytes,3,opt,name=seat_secret,json=seatSecret,proto3" json:"seat_secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinResult) Reset() {
+	*x = JoinResult{}
+	mi := &file_game_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinResult) ProtoMessage() {}
+
+func (x *JoinResult) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinResult.ProtoReflect.Descriptor instead.
+func (*JoinResult) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *JoinResult) GetRoom() *RoomState {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+func (x *JoinResult) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *JoinResult) GetSeatSecret() string {
+	if x != nil {
+		return x.SeatSecret
+	}
+	return ""
+}
+
+type SetReadyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomCode      string                 `protobuf:"bytes,1,opt,name=room_code,json=roomCode,proto3" json:"room_code,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Ready         bool                   `protobuf:"varint,3,opt,name=ready,proto3" json:"ready,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetReadyRequest) Reset() {
+	*x = SetReadyRequest{}
+	mi := &file_game_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetReadyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetReadyRequest) ProtoMessage() {}
+
+func (x *SetReadyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetReadyRequest.ProtoReflect.Descriptor instead.
+func (*SetReadyRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SetReadyRequest) GetRoomCode() string {
+	if x != nil {
+		return x.RoomCode
+	}
+	return ""
+}
+
+func (x *SetReadyRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *SetReadyRequest) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+type StartGameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomCode      string                 `protobuf:"bytes,1,opt,name=room_code,json=roomCode,proto3" json:"room_code,omitempty"`
+	Bots          int32                  `protobuf:"varint,2,opt,name=bots,proto3" json:"bots,omitempty"` // Bots to seat before starting
+	Seed          int64                  `protobuf:"varint,3,opt,name=seed,proto3" json:"seed,omitempty"` // Optional: fixed seed for reproducible dealing
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartGameRequest) Reset() {
+	*x = StartGameRequest{}
+	mi := &file_game_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartGameRequest) ProtoMessage() {}
+
+func (x *StartGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartGameRequest.ProtoReflect.Descriptor instead.
+func (*StartGameRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StartGameRequest) GetRoomCode() string {
+	if x != nil {
+		return x.RoomCode
+	}
+	return ""
+}
+
+func (x *StartGameRequest) GetBots() int32 {
+	if x != nil {
+		return x.Bots
+	}
+	return 0
+}
+
+func (x *StartGameRequest) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+type SubmitMoveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomCode      string                 `protobuf:"bytes,1,opt,name=room_code,json=roomCode,proto3" json:"room_code,omitempty"`
+	PlayerId      string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	X             int32                  `protobuf:"varint,3,opt,name=x,proto3" json:"x,omitempty"`      // 0-based column
+	Y             int32                  `protobuf:"varint,4,opt,name=y,proto3" json:"y,omitempty"`      // 0-based row
+	Cell          string                 `protobuf:"bytes,5,opt,name=cell,proto3" json:"cell,omitempty"` // Algebraic cell such as "E5"; takes precedence over x/y
+	Card          int32                  `protobuf:"varint,6,opt,name=card,proto3" json:"card,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitMoveRequest) Reset() {
+	*x = SubmitMoveRequest{}
+	mi := &file_game_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitMoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitMoveRequest) ProtoMessage() {}
+
+func (x *SubmitMoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitMoveRequest.ProtoReflect.Descriptor instead.
+func (*SubmitMoveRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SubmitMoveRequest) GetRoomCode() string {
+	if x != nil {
+		return x.RoomCode
+	}
+	return ""
+}
+
+func (x *SubmitMoveRequest) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *SubmitMoveRequest) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *SubmitMoveRequest) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *SubmitMoveRequest) GetCell() string {
+	if x != nil {
+		return x.Cell
+	}
+	return ""
+}
+
+func (x *SubmitMoveRequest) GetCard() int32 {
+	if x != nil {
+		return x.Card
+	}
+	return 0
+}
+
+type WatchRoomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomCode      string                 `protobuf:"bytes,1,opt,name=room_code,json=roomCode,proto3" json:"room_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRoomRequest) Reset() {
+	*x = WatchRoomRequest{}
+	mi := &file_game_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRoomRequest) ProtoMessage() {}
+
+func (x *WatchRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRoomRequest.ProtoReflect.Descriptor instead.
+func (*WatchRoomRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WatchRoomRequest) GetRoomCode() string {
+	if x != nil {
+		return x.RoomCode
+	}
+	return ""
+}
+
+type RoomState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomCode      string                 `protobuf:"bytes,1,opt,name=room_code,json=roomCode,proto3" json:"room_code,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // lobby, playing, ended, aborted or closed
+	Engine        string                 `protobuf:"bytes,3,opt,name=engine,proto3" json:"engine,omitempty"`
+	TurnOrder     []string               `protobuf:"bytes,4,rep,name=turn_order,json=turnOrder,proto3" json:"turn_order,omitempty"`
+	Players       []*Player              `protobuf:"bytes,5,rep,name=players,proto3" json:"players,omitempty"` // As the room's broadcast policy allows
+	Board         *Board                 `protobuf:"bytes,6,opt,name=board,proto3" json:"board,omitempty"`
+	NextTurn      string                 `protobuf:"bytes,7,opt,name=next_turn,json=nextTurn,proto3" json:"next_turn,omitempty"` // Only while the game is being played
+	WinnerId      string                 `protobuf:"bytes,8,opt,name=winner_id,json=winnerId,proto3" json:"winner_id,omitempty"`
+	Draw          bool                   `protobuf:"varint,9,opt,name=draw,proto3" json:"draw,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomState) Reset() {
+	*x = RoomState{}
+	mi := &file_game_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomState) ProtoMessage() {}
+
+func (x *RoomState) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomState.ProtoReflect.Descriptor instead.
+func (*RoomState) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RoomState) GetRoomCode() string {
+	if x != nil {
+		return x.RoomCode
+	}
+	return ""
+}
+
+func (x *RoomState) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RoomState) GetEngine() string {
+	if x != nil {
+		return x.Engine
+	}
+	return ""
+}
+
+func (x *RoomState) GetTurnOrder() []string {
+	if x != nil {
+		return x.TurnOrder
+	}
+	return nil
+}
+
+func (x *RoomState) GetPlayers() []*Player {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *RoomState) GetBoard() *Board {
+	if x != nil {
+		return x.Board
+	}
+	return nil
+}
+
+func (x *RoomState) GetNextTurn() string {
+	if x != nil {
+		return x.NextTurn
+	}
+	return ""
+}
+
+func (x *RoomState) GetWinnerId() string {
+	if x != nil {
+		return x.WinnerId
+	}
+	return ""
+}
+
+func (x *RoomState) GetDraw() bool {
+	if x != nil {
+		return x.Draw
+	}
+	return false
+}
+
+type Player struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	IsBot         bool                   `protobuf:"varint,3,opt,name=is_bot,json=isBot,proto3" json:"is_bot,omitempty"`
+	Color         string                 `protobuf:"bytes,4,opt,name=color,proto3" json:"color,omitempty"`
+	Resigned      bool                   `protobuf:"varint,5,opt,name=resigned,proto3" json:"resigned,omitempty"`
+	Hand          []int32                `protobuf:"varint,6,rep,packed,name=hand,proto3" json:"hand,omitempty"` // Empty when the room hides hands
+	HandCount     int32                  `protobuf:"varint,7,opt,name=hand_count,json=handCount,proto3" json:"hand_count,omitempty"`
+	Ready         bool                   `protobuf:"varint,8,opt,name=ready,proto3" json:"ready,omitempty"` // Lobby only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Player) Reset() {
+	*x = Player{}
+	mi := &file_game_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Player) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Player) ProtoMessage() {}
+
+func (x *Player) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Player.ProtoReflect.Descriptor instead.
+func (*Player) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Player) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Player) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Player) GetIsBot() bool {
+	if x != nil {
+		return x.IsBot
+	}
+	return false
+}
+
+func (x *Player) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Player) GetResigned() bool {
+	if x != nil {
+		return x.Resigned
+	}
+	return false
+}
+
+func (x *Player) GetHand() []int32 {
+	if x != nil {
+		return x.Hand
+	}
+	return nil
+}
+
+func (x *Player) GetHandCount() int32 {
+	if x != nil {
+		return x.HandCount
+	}
+	return 0
+}
+
+func (x *Player) GetReady() bool {
+	if x != nil {
+		return x.Ready
+	}
+	return false
+}
+
+type Board struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Size          int32                  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Cells         []*Cell                `protobuf:"bytes,2,rep,name=cells,proto3" json:"cells,omitempty"` // Row by row, size*size cells
+	Hash          uint64                 `protobuf:"varint,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Board) Reset() {
+	*x = Board{}
+	mi := &file_game_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Board) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Board) ProtoMessage() {}
+
+func (x *Board) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Board.ProtoReflect.Descriptor instead.
+func (*Board) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Board) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Board) GetCells() []*Cell {
+	if x != nil {
+		return x.Cells
+	}
+	return nil
+}
+
+func (x *Board) GetHash() uint64 {
+	if x != nil {
+		return x.Hash
+	}
+	return 0
+}
+
+type Cell struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerId       string                 `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"` // Empty for a free cell
+	Value         int32                  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cell) Reset() {
+	*x = Cell{}
+	mi := &file_game_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cell) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cell) ProtoMessage() {}
+
+func (x *Cell) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cell.ProtoReflect.Descriptor instead.
+func (*Cell) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Cell) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Cell) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+// GameEvent is the room's state when watching starts, then one event the
+// room broadcast to its WebSocket clients
+type GameEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*GameEvent_State
+	//	*GameEvent_Broadcast
+	Event         isGameEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameEvent) Reset() {
+	*x = GameEvent{}
+	mi := &file_game_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameEvent) ProtoMessage() {}
+
+func (x *GameEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameEvent.ProtoReflect.Descriptor instead.
+func (*GameEvent) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GameEvent) GetEvent() isGameEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *GameEvent) GetState() *RoomState {
+	if x != nil {
+		if x, ok := x.Event.(*GameEvent_State); ok {
+			return x.State
+		}
+	}
+	return nil
+}
+
+func (x *GameEvent) GetBroadcast() *Broadcast {
+	if x != nil {
+		if x, ok := x.Event.(*GameEvent_Broadcast); ok {
+			return x.Broadcast
+		}
+	}
+	return nil
+}
+
+type isGameEvent_Event interface {
+	isGameEvent_Event()
+}
+
+type GameEvent_State struct {
+	State *RoomState `protobuf:"bytes,1,opt,name=state,proto3,oneof"`
+}
+
+type GameEvent_Broadcast struct {
+	Broadcast *Broadcast `protobuf:"bytes,2,opt,name=broadcast,proto3,oneof"`
+}
+
+func (*GameEvent_State) isGameEvent_Event() {}
+
+func (*GameEvent_Broadcast) isGameEvent_Event() {}
+
+// Broadcast is a WebSocket broadcast: its action and its data as JSON, in the
+// same shape WebSocket clients receive with full boards
+type Broadcast struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Data          string                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Broadcast) Reset() {
+	*x = Broadcast{}
+	mi := &file_game_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Broadcast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Broadcast) ProtoMessage() {}
+
+func (x *Broadcast) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Broadcast.ProtoReflect.Descriptor instead.
+func (*Broadcast) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Broadcast) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *Broadcast) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+var File_game_proto protoreflect.FileDescriptor
+
+const file_game_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"game.proto\x12\x10javanesechess.v1\"m\n" +
+	"\x11CreateRoomRequest\x12\x1b\n" +
+	"\troom_code\x18\x01 \x01(\tR\broomCode\x12\x1f\n" +
+	"\vplayer_name\x18\x02 \x01(\tR\n" +
+	"playerName\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\"k\n" +
+	"\x0fJoinRoomRequest\x12\x1b\n" +
+	"\troom_code\x18\x01 \x01(\tR\broomCode\x12\x1f\n" +
+	"\vplayer_name\x18\x02 \x01(\tR\n" +
+	"playerName\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\"{\n" +
+	"\n" +
+	"JoinResult\x12/\n" +
+	"\x04room\x18\x01 \x01(\v2\x1b.javanesechess.v1.RoomStateR\x04room\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x1f\n" +
+	"\vseat_secret\x18\x03 \x01(\tR\n" +
+	"seatSecret\"a\n" +
+	"\x0fSetReadyRequest\x12\x1b\n" +
+	"\troom_code\x18\x01 \x01(\tR\broomCode\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\x14\n" +
+	"\x05ready\x18\x03 \x01(\bR\x05ready\"W\n" +
+	"\x10StartGameRequest\x12\x1b\n" +
+	"\troom_code\x18\x01 \x01(\tR\broomCode\x12\x12\n" +
+	"\x04bots\x18\x02 \x01(\x05R\x04bots\x12\x12\n" +
+	"\x04seed\x18\x03 \x01(\x03R\x04seed\"\x91\x01\n" +
+	"\x11SubmitMoveRequest\x12\x1b\n" +
+	"\troom_code\x18\x01 \x01(\tR\broomCode\x12\x1b\n" +
+	"\tplayer_id\x18\x02 \x01(\tR\bplayerId\x12\f\n" +
+	"\x01x\x18\x03 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x04 \x01(\x05R\x01y\x12\x12\n" +
+	"\x04cell\x18\x05 \x01(\tR\x04cell\x12\x12\n" +
+	"\x04card\x18\x06 \x01(\x05R\x04card\"/\n" +
+	"\x10WatchRoomRequest\x12\x1b\n" +
+	"\troom_code\x18\x01 \x01(\tR\broomCode\"\xa8\x02\n" +
+	"\tRoomState\x12\x1b\n" +
+	"\troom_code\x18\x01 \x01(\tR\broomCode\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06engine\x18\x03 \x01(\tR\x06engine\x12\x1d\n" +
+	"\n" +
+	"turn_order\x18\x04 \x03(\tR\tturnOrder\x122\n" +
+	"\aplayers\x18\x05 \x03(\v2\x18.javanesechess.v1.PlayerR\aplayers\x12-\n" +
+	"\x05board\x18\x06 \x01(\v2\x17.javanesechess.v1.BoardR\x05board\x12\x1b\n" +
+	"\tnext_turn\x18\a \x01(\tR\bnextTurn\x12\x1b\n" +
+	"\twinner_id\x18\b \x01(\tR\bwinnerId\x12\x12\n" +
+	"\x04draw\x18\t \x01(\bR\x04draw\"\xbe\x01\n" +
+	"\x06Player\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x15\n" +
+	"\x06is_bot\x18\x03 \x01(\bR\x05isBot\x12\x14\n" +
+	"\x05color\x18\x04 \x01(\tR\x05color\x12\x1a\n" +
+	"\bresigned\x18\x05 \x01(\bR\bresigned\x12\x12\n" +
+	"\x04hand\x18\x06 \x03(\x05R\x04hand\x12\x1d\n" +
+	"\n" +
+	"hand_count\x18\a \x01(\x05R\thandCount\x12\x14\n" +
+	"\x05ready\x18\b \x01(\bR\x05ready\"]\n" +
+	"\x05Board\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x05R\x04size\x12,\n" +
+	"\x05cells\x18\x02 \x03(\v2\x16.javanesechess.v1.CellR\x05cells\x12\x12\n" +
+	"\x04hash\x18\x03 \x01(\x04R\x04hash\"7\n" +
+	"\x04Cell\x12\x19\n" +
+	"\bowner_id\x18\x01 \x01(\tR\aownerId\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value\"\x86\x01\n" +
+	"\tGameEvent\x123\n" +
+	"\x05state\x18\x01 \x01(\v2\x1b.javanesechess.v1.RoomStateH\x00R\x05state\x12;\n" +
+	"\tbroadcast\x18\x02 \x01(\v2\x1b.javanesechess.v1.BroadcastH\x00R\tbroadcastB\a\n" +
+	"\x05event\"7\n" +
+	"\tBroadcast\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\tR\x04data2\xde\x03\n" +
+	"\x04Game\x12O\n" +
+	"\n" +
+	"CreateRoom\x12#.javanesechess.v1.CreateRoomRequest\x1a\x1c.javanesechess.v1.JoinResult\x12K\n" +
+	"\bJoinRoom\x12!.javanesechess.v1.JoinRoomRequest\x1a\x1c.javanesechess.v1.JoinResult\x12J\n" +
+	"\bSetReady\x12!.javanesechess.v1.SetReadyRequest\x1a\x1b.javanesechess.v1.RoomState\x12L\n" +
+	"\tStartGame\x12\".javanesechess.v1.StartGameRequest\x1a\x1b.javanesechess.v1.RoomState\x12N\n" +
+	"\n" +
+	"SubmitMove\x12#.javanesechess.v1.SubmitMoveRequest\x1a\x1b.javanesechess.v1.RoomState\x12N\n" +
+	"\tWatchRoom\x12\".javanesechess.v1.WatchRoomRequest\x1a\x1b.javanesechess.v1.GameEvent0\x01B)Z'javanese-chess/internal/api/grpc/gamepbb\x06proto3"
+
+var (
+	file_game_proto_rawDescOnce sync.Once
+	file_game_proto_rawDescData []byte
+)
+
+func file_game_proto_rawDescGZIP() []byte {
+	file_game_proto_rawDescOnce.Do(func() {
+		file_game_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_game_proto_rawDesc), len(file_game_proto_rawDesc)))
+	})
+	return file_game_proto_rawDescData
+}
+
+var file_game_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_game_proto_goTypes = []any{
+	(*CreateRoomRequest)(nil), // 0: javanesechess.v1.CreateRoomRequest
+	(*JoinRoomRequest)(nil),   // 1: javanesechess.v1.JoinRoomRequest
+	(*JoinResult)(nil),        // 2: javanesechess.v1.JoinResult
+	(*SetReadyRequest)(nil),   // 3: javanesechess.v1.SetReadyRequest
+	(*StartGameRequest)(nil),  // 4: javanesechess.v1.StartGameRequest
+	(*SubmitMoveRequest)(nil), // 5: javanesechess.v1.SubmitMoveRequest
+	(*WatchRoomRequest)(nil),  // 6: javanesechess.v1.WatchRoomRequest
+	(*RoomState)(nil),         // 7: javanesechess.v1.RoomState
+	(*Player)(nil),            // 8: javanesechess.v1.Player
+	(*Board)(nil),             // 9: javanesechess.v1.Board
+	(*Cell)(nil),              // 10: javanesechess.v1.Cell
+	(*GameEvent)(nil),         // 11: javanesechess.v1.GameEvent
+	(*Broadcast)(nil),         // 12: javanesechess.v1.Broadcast
+}
+var file_game_proto_depIdxs = []int32{
+	7,  // 0: javanesechess.v1.JoinResult.room:type_name -> javanesechess.v1.RoomState
+	8,  // 1: javanesechess.v1.RoomState.players:type_name -> javanesechess.v1.Player
+	9,  // 2: javanesechess.v1.RoomState.board:type_name -> javanesechess.v1.Board
+	10, // 3: javanesechess.v1.Board.cells:type_name -> javanesechess.v1.Cell
+	7,  // 4: javanesechess.v1.GameEvent.state:type_name -> javanesechess.v1.RoomState
+	12, // 5: javanesechess.v1.GameEvent.broadcast:type_name -> javanesechess.v1.Broadcast
+	0,  // 6: javanesechess.v1.Game.CreateRoom:input_type -> javanesechess.v1.CreateRoomRequest
+	1,  // 7: javanesechess.v1.Game.JoinRoom:input_type -> javanesechess.v1.JoinRoomRequest
+	3,  // 8: javanesechess.v1.Game.SetReady:input_type -> javanesechess.v1.SetReadyRequest
+	4,  // 9: javanesechess.v1.Game.StartGame:input_type -> javanesechess.v1.StartGameRequest
+	5,  // 10: javanesechess.v1.Game.SubmitMove:input_type -> javanesechess.v1.SubmitMoveRequest
+	6,  // 11: javanesechess.v1.Game.WatchRoom:input_type -> javanesechess.v1.WatchRoomRequest
+	2,  // 12: javanesechess.v1.Game.CreateRoom:output_type -> javanesechess.v1.JoinResult
+	2,  // 13: javanesechess.v1.Game.JoinRoom:output_type -> javanesechess.v1.JoinResult
+	7,  // 14: javanesechess.v1.Game.SetReady:output_type -> javanesechess.v1.RoomState
+	7,  // 15: javanesechess.v1.Game.StartGame:output_type -> javanesechess.v1.RoomState
+	7,  // 16: javanesechess.v1.Game.SubmitMove:output_type -> javanesechess.v1.RoomState
+	11, // 17: javanesechess.v1.Game.WatchRoom:output_type -> javanesechess.v1.GameEvent
+	12, // [12:18] is the sub-list for method output_type
+	6,  // [6:12] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_game_proto_init() }
+func file_game_proto_init() {
+	if File_game_proto != nil {
+		return
+	}
+	file_game_proto_msgTypes[11].OneofWrappers = []any{
+		(*GameEvent_State)(nil),
+		(*GameEvent_Broadcast)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_game_proto_rawDesc), len(file_game_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_game_proto_goTypes,
+		DependencyIndexes: file_game_proto_depIdxs,
+		MessageInfos:      file_game_proto_msgTypes,
+	}.Build()
+	File_game_proto = out.File
+	file_game_proto_goTypes = nil
+	file_game_proto_depIdxs = nil
+}
@@ -0,0 +1,334 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: game.proto
+
+// Game service for backends that drive or watch games without the browser
+// WebSocket protocol, such as a matchmaking service or a training pipeline.
+
+package gamepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Game_CreateRoom_FullMethodName = "/javanesechess.v1.Game/CreateRoom"
+	Game_JoinRoom_FullMethodName   = "/javanesechess.v1.Game/JoinRoom"
+	Game_SetReady_FullMethodName   = "/javanesechess.v1.Game/SetReady"
+	Game_StartGame_FullMethodName  = "/javanesechess.v1.Game/StartGame"
+	Game_SubmitMove_FullMethodName = "/javanesechess.v1.Game/SubmitMove"
+	Game_WatchRoom_FullMethodName  = "/javanesechess.v1.Game/WatchRoom"
+)
+
+// GameClient is the client API for Game service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GameClient interface {
+	// CreateRoom opens a lobby with the caller as its room master
+	CreateRoom(ctx context.Context, in *CreateRoomRequest, opts ...grpc.CallOption) (*JoinResult, error)
+	// JoinRoom seats a player in a lobby
+	JoinRoom(ctx context.Context, in *JoinRoomRequest, opts ...grpc.CallOption) (*JoinResult, error)
+	// SetReady marks a lobby player as ready for the game to start, or not
+	SetReady(ctx context.Context, in *SetReadyRequest, opts ...grpc.CallOption) (*RoomState, error)
+	// StartGame fills the lobby with bots if asked, deals and starts the game.
+	// Every human but the room master must be ready.
+	StartGame(ctx context.Context, in *StartGameRequest, opts ...grpc.CallOption) (*RoomState, error)
+	// SubmitMove places a card for a player. Bots reply on their own.
+	SubmitMove(ctx context.Context, in *SubmitMoveRequest, opts ...grpc.CallOption) (*RoomState, error)
+	// WatchRoom streams the room's state, then every event broadcast to it
+	// until the client cancels
+	WatchRoom(ctx context.Context, in *WatchRoomRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GameEvent], error)
+}
+
+type gameClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGameClient(cc grpc.ClientConnInterface) GameClient {
+	return &gameClient{cc}
+}
+
+func (c *gameClient) CreateRoom(ctx context.Context, in *CreateRoomRequest, opts ...grpc.CallOption) (*JoinResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JoinResult)
+	err := c.cc.Invoke(ctx, Game_CreateRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameClient) JoinRoom(ctx context.Context, in *JoinRoomRequest, opts ...grpc.CallOption) (*JoinResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JoinResult)
+	err := c.cc.Invoke(ctx, Game_JoinRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameClient) SetReady(ctx context.Context, in *SetReadyRequest, opts ...grpc.CallOption) (*RoomState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomState)
+	err := c.cc.Invoke(ctx, Game_SetReady_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameClient) StartGame(ctx context.Context, in *StartGameRequest, opts ...grpc.CallOption) (*RoomState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomState)
+	err := c.cc.Invoke(ctx, Game_StartGame_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameClient) SubmitMove(ctx context.Context, in *SubmitMoveRequest, opts ...grpc.CallOption) (*RoomState, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RoomState)
+	err := c.cc.Invoke(ctx, Game_SubmitMove_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameClient) WatchRoom(ctx context.Context, in *WatchRoomRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GameEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Game_ServiceDesc.Streams[0], Game_WatchRoom_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRoomRequest, GameEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Game_WatchRoomClient = grpc.ServerStreamingClient[GameEvent]
+
+// GameServer is the server API for Game service.
+// All implementations must embed UnimplementedGameServer
+// for forward compatibility.
+type GameServer interface {
+	// CreateRoom opens a lobby with the caller as its room master
+	CreateRoom(context.Context, *CreateRoomRequest) (*JoinResult, error)
+	// JoinRoom seats a player in a lobby
+	JoinRoom(context.Context, *JoinRoomRequest) (*JoinResult, error)
+	// SetReady marks a lobby player as ready for the game to start, or not
+	SetReady(context.Context, *SetReadyRequest) (*RoomState, error)
+	// StartGame fills the lobby with bots if asked, deals and starts the game.
+	// Every human but the room master must be ready.
+	StartGame(context.Context, *StartGameRequest) (*RoomState, error)
+	// SubmitMove places a card for a player. Bots reply on their own.
+	SubmitMove(context.Context, *SubmitMoveRequest) (*RoomState, error)
+	// WatchRoom streams the room's state, then every event broadcast to it
+	// until the client cancels
+	WatchRoom(*WatchRoomRequest, grpc.ServerStreamingServer[GameEvent]) error
+	mustEmbedUnimplementedGameServer()
+}
+
+// UnimplementedGameServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGameServer struct{}
+
+func (UnimplementedGameServer) CreateRoom(context.Context, *CreateRoomRequest) (*JoinResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRoom not implemented")
+}
+func (UnimplementedGameServer) JoinRoom(context.Context, *JoinRoomRequest) (*JoinResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinRoom not implemented")
+}
+func (UnimplementedGameServer) SetReady(context.Context, *SetReadyRequest) (*RoomState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetReady not implemented")
+}
+func (UnimplementedGameServer) StartGame(context.Context, *StartGameRequest) (*RoomState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartGame not implemented")
+}
+func (UnimplementedGameServer) SubmitMove(context.Context, *SubmitMoveRequest) (*RoomState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitMove not implemented")
+}
+func (UnimplementedGameServer) WatchRoom(*WatchRoomRequest, grpc.ServerStreamingServer[GameEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRoom not implemented")
+}
+func (UnimplementedGameServer) mustEmbedUnimplementedGameServer() {}
+func (UnimplementedGameServer) testEmbeddedByValue()              {}
+
+// UnsafeGameServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GameServer will
+// result in compilation errors.
+type UnsafeGameServer interface {
+	mustEmbedUnimplementedGameServer()
+}
+
+func RegisterGameServer(s grpc.ServiceRegistrar, srv GameServer) {
+	// If the following call pancis, it indicates UnimplementedGameServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Game_ServiceDesc, srv)
+}
+
+func _Game_CreateRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServer).CreateRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Game_CreateRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServer).CreateRoom(ctx, req.(*CreateRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Game_JoinRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServer).JoinRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Game_JoinRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServer).JoinRoom(ctx, req.(*JoinRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Game_SetReady_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetReadyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServer).SetReady(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Game_SetReady_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServer).SetReady(ctx, req.(*SetReadyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Game_StartGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServer).StartGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Game_StartGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServer).StartGame(ctx, req.(*StartGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Game_SubmitMove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitMoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameServer).SubmitMove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Game_SubmitMove_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameServer).SubmitMove(ctx, req.(*SubmitMoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Game_WatchRoom_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRoomRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(GameServer).WatchRoom(m, &grpc.GenericServerStream[WatchRoomRequest, GameEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Game_WatchRoomServer = grpc.ServerStreamingServer[GameEvent]
+
+// Game_ServiceDesc is the grpc.ServiceDesc for Game service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Game_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "javanesechess.v1.Game",
+	HandlerType: (*GameServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateRoom",
+			Handler:    _Game_CreateRoom_Handler,
+		},
+		{
+			MethodName: "JoinRoom",
+			Handler:    _Game_JoinRoom_Handler,
+		},
+		{
+			MethodName: "SetReady",
+			Handler:    _Game_SetReady_Handler,
+		},
+		{
+			MethodName: "StartGame",
+			Handler:    _Game_StartGame_Handler,
+		},
+		{
+			MethodName: "SubmitMove",
+			Handler:    _Game_SubmitMove_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchRoom",
+			Handler:       _Game_WatchRoom_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "game.proto",
+}
@@ -0,0 +1,225 @@
+// Package grpcapi serves the core game operations over gRPC for other
+// backends. The service is defined in gamepb/game.proto; regenerate the Go
+// code after changing it with:
+//
+//	protoc -I gamepb --go_out=gamepb --go_opt=paths=source_relative \
+//		--go-grpc_out=gamepb --go-grpc_opt=paths=source_relative game.proto
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"javanese-chess/internal/api/grpc/gamepb"
+	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/room"
+	"javanese-chess/internal/shared"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the Game service on top of the room manager. Changes go
+// through the same manager calls and hub broadcasts as the REST and WebSocket
+// APIs, so clients of all three see each other's games.
+type Server struct {
+	gamepb.UnimplementedGameServer
+
+	rm  *room.Manager
+	hub *ws.Hub
+}
+
+// New returns a gRPC server offering the Game service. Callers authenticate
+// like REST clients, with an "authorization: Bearer <token>" metadata entry;
+// calls without one are anonymous. Calls acting for a seat without an account
+// show its secret in a "seat-secret" entry.
+func New(rm *room.Manager, hub *ws.Hub, authSvc *auth.Service) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryAuth(authSvc)),
+		grpc.ChainStreamInterceptor(streamAuth(authSvc)),
+	)
+	gamepb.RegisterGameServer(srv, &Server{rm: rm, hub: hub})
+	return srv
+}
+
+// CreateRoom opens a lobby with the caller as its room master
+func (s *Server) CreateRoom(ctx context.Context, req *gamepb.CreateRoomRequest) (*gamepb.JoinResult, error) {
+	if req.RoomCode == "" || req.PlayerName == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_code and player_name are required")
+	}
+	rx, err := s.rm.CreateLobbyRoom(req.RoomCode, req.PlayerName)
+	if errors.Is(err, room.ErrRoomExists) {
+		return nil, status.Error(codes.AlreadyExists, "room already exists")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to create room")
+	}
+	if req.Password != "" {
+		if err := s.rm.SetRoomPassword(rx, req.Password); err != nil {
+			return nil, status.Error(codes.Internal, "failed to set room password")
+		}
+	}
+	master := rx.Players[0].ID
+	s.rm.BindUser(rx, master, userID(ctx))
+	s.rm.ClaimRoom(rx, userID(ctx))
+
+	s.hub.Broadcast(rx.Code, "room_created", gin.H{
+		"room_code":          rx.Code,
+		"status":             "lobby",
+		"password_protected": rx.HasPassword(),
+	})
+	return &gamepb.JoinResult{Room: roomState(rx), PlayerId: master, SeatSecret: s.rm.SeatSecret(rx, master)}, nil
+}
+
+// JoinRoom seats a player in a lobby
+func (s *Server) JoinRoom(ctx context.Context, req *gamepb.JoinRoomRequest) (*gamepb.JoinResult, error) {
+	if req.RoomCode == "" || req.PlayerName == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_code and player_name are required")
+	}
+	rx, err := s.room(req.RoomCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rm.CheckRoomPassword(rx, req.Password); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	rx, seat, err := s.rm.JoinRoom(req.RoomCode, req.PlayerName)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	s.rm.BindUser(rx, seat.ID, userID(ctx))
+	return &gamepb.JoinResult{Room: roomState(rx), PlayerId: seat.ID, SeatSecret: s.rm.SeatSecret(rx, seat.ID)}, nil
+}
+
+// SetReady marks a lobby player as ready for the game to start, or not
+func (s *Server) SetReady(ctx context.Context, req *gamepb.SetReadyRequest) (*gamepb.RoomState, error) {
+	rx, err := s.room(req.RoomCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rm.AuthorizeSeat(rx, req.PlayerId, userID(ctx), seatSecret(ctx)); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err := s.rm.SetReady(rx, req.PlayerId, req.Ready); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	s.hub.BroadcastLobby(rx.Code)
+	return roomState(rx), nil
+}
+
+// StartGame seats the requested bots, deals and starts the game
+func (s *Server) StartGame(ctx context.Context, req *gamepb.StartGameRequest) (*gamepb.RoomState, error) {
+	rx, err := s.room(req.RoomCode)
+	if err != nil {
+		return nil, err
+	}
+	if rx.Status != "lobby" {
+		return nil, status.Error(codes.FailedPrecondition, "game has already started")
+	}
+	if err := s.rm.CheckReady(rx); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err := s.rm.CheckFeatures(rx); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if req.Bots < 0 {
+		return nil, status.Error(codes.InvalidArgument, "bots must not be negative")
+	}
+	if len(rx.Players)+int(req.Bots) < config.MinPlayers {
+		return nil, status.Error(codes.FailedPrecondition, "a game needs at least 2 players")
+	}
+
+	if req.Bots > 0 {
+		if err := s.rm.AddBots(rx, int(req.Bots)); err != nil {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+	if req.Seed != 0 {
+		s.rm.Reseed(rx, req.Seed)
+	}
+	s.rm.StartGame(rx)
+	s.resumeBots(rx)
+	return roomState(rx), nil
+}
+
+// SubmitMove places a card for a player. Bots to move next reply on their
+// own, and their moves reach watchers like any other broadcast.
+func (s *Server) SubmitMove(ctx context.Context, req *gamepb.SubmitMoveRequest) (*gamepb.RoomState, error) {
+	if req.PlayerId == "" {
+		return nil, status.Error(codes.InvalidArgument, "player_id is required")
+	}
+	rx, err := s.room(req.RoomCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rm.AuthorizeSeat(rx, req.PlayerId, userID(ctx), seatSecret(ctx)); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	coord, err := rx.Board.Locate(req.Cell, int(req.X), int(req.Y))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.rm.ApplyMove(ctx, rx, req.PlayerId, coord.X, coord.Y, int(req.Card)); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	s.resumeBots(rx)
+	return roomState(rx), nil
+}
+
+// WatchRoom sends the room's state, then every broadcast to the room until
+// the client goes away. A watcher that cannot keep up is cut off with
+// ResourceExhausted and should watch again.
+func (s *Server) WatchRoom(req *gamepb.WatchRoomRequest, stream gamepb.Game_WatchRoomServer) error {
+	rx, err := s.room(req.RoomCode)
+	if err != nil {
+		return err
+	}
+
+	// Subscribe before taking the state so no event falls in between
+	events, cancel := s.hub.Subscribe(rx.Code)
+	defer cancel()
+	if err := stream.Send(&gamepb.GameEvent{Event: &gamepb.GameEvent_State{State: roomState(rx)}}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "watcher fell behind the room's events")
+			}
+			if err := stream.Send(&gamepb.GameEvent{Event: &gamepb.GameEvent_Broadcast{
+				Broadcast: &gamepb.Broadcast{Action: ev.Action, Data: string(ev.Data)},
+			}}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) room(code string) (*shared.Room, error) {
+	if code == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_code is required")
+	}
+	rx, ok := s.rm.Get(code)
+	if !ok {
+		return nil, status.Error(codes.NotFound, "room not found")
+	}
+	return rx, nil
+}
+
+// resumeBots starts the bots' turns when a bot is to move
+func (s *Server) resumeBots(rx *shared.Room) {
+	if rx.Status == "playing" && rx.WinnerID == nil && !rx.Draw && rx.Players[rx.TurnIdx].IsBot {
+		s.hub.ResumeBots(rx.Code)
+	}
+}
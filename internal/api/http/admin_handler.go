@@ -0,0 +1,254 @@
+package http
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler serves the operator API used by cmd/adminctl
+type AdminHandler struct {
+	rm *room.Manager
+}
+
+func NewAdminHandler(rm *room.Manager) *AdminHandler {
+	return &AdminHandler{rm: rm}
+}
+
+// requireAdminToken rejects requests without the operator token in X-Admin-Token
+func requireAdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		got := c.GetHeader("X-Admin-Token")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			abortError(c, http.StatusUnauthorized, "invalid admin token")
+			return
+		}
+		c.Next()
+	}
+}
+
+// ListRoomsHandler returns a summary of every room
+// @Summary List rooms
+// @Description Operator view of every room with its status and players
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Operator token"
+// @Param status query string false "Only rooms with this status"
+// @Param q query string false "Room code or a player name contains this text, ignoring case"
+// @Param page query int false "Page number (1-based)"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} Response{data=AdminRoomPage}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/admin/rooms [get]
+func (h *AdminHandler) ListRoomsHandler(c *gin.Context) {
+	page, err := pageQuery(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := c.Query("status")
+	text := strings.ToLower(c.Query("q"))
+
+	rooms := h.rm.ListRooms()
+	out := make([]AdminRoomSummary, 0, len(rooms))
+	for _, rx := range rooms {
+		if status != "" && rx.Status != status {
+			continue
+		}
+		found := text == "" || strings.Contains(strings.ToLower(rx.Code), text)
+		names := make([]string, 0, len(rx.Players))
+		for _, p := range rx.Players {
+			names = append(names, p.Name)
+			found = found || strings.Contains(strings.ToLower(p.Name), text)
+		}
+		if !found {
+			continue
+		}
+		out = append(out, AdminRoomSummary{
+			Code:      rx.Code,
+			Status:    rx.Status,
+			Players:   names,
+			Moves:     len(rx.History),
+			WinnerID:  rx.WinnerID,
+			CreatedAt: rx.CreatedAt,
+		})
+	}
+
+	start, end := page.window(len(out))
+	respondOK(c, AdminRoomPage{Rooms: out[start:end], Page: page})
+}
+
+// GetRoomHandler dumps a room's full state, including hands
+// @Summary Dump a room
+// @Description Operator view of a room's complete state
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Operator token"
+// @Param code path string true "Room Code"
+// @Success 200 {object} Response{data=AdminRoomDump}
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/admin/rooms/{code} [get]
+func (h *AdminHandler) GetRoomHandler(c *gin.Context) {
+	rx, ok := h.rm.Get(c.Param("code"))
+	if !ok {
+		respondError(c, http.StatusNotFound, "room not found")
+		return
+	}
+
+	respondOK(c, AdminRoomDump{Room: rx, History: rx.History})
+}
+
+// RoomEventsHandler returns a room's event log and checks that folding it
+// gives the room's current state
+// @Summary Room event log
+// @Description Operator view of every event appended to a room, and whether the log rebuilds the live room
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Operator token"
+// @Param code path string true "Room Code"
+// @Success 200 {object} Response{data=RoomEventLog}
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/admin/rooms/{code}/events [get]
+func (h *AdminHandler) RoomEventsHandler(c *gin.Context) {
+	rx, ok := h.rm.Get(c.Param("code"))
+	if !ok {
+		respondError(c, http.StatusNotFound, "room not found")
+		return
+	}
+
+	out := RoomEventLog{Code: rx.Code, Events: h.rm.Events(rx.Code)}
+	folded, err := h.rm.Rebuild(rx.Code)
+	switch {
+	case err != nil:
+		out.Error = err.Error()
+	case folded.Board.Hash != rx.Board.Hash || len(folded.History) != len(rx.History) || folded.Status != rx.Status:
+		out.Error = "folded state differs from the live room"
+	default:
+		out.Consistent = true
+	}
+	respondOK(c, out)
+}
+
+// RoomDebugEventsHandler returns a room's recent events kept in memory.
+// Clients follow a room by passing back the returned next as since.
+// @Summary Recent room events
+// @Description Recent structured events of one room (joins, moves, clocks, bot fallbacks, rejected moves), oldest first
+// @Tags Debug
+// @Produce json
+// @Param X-Admin-Token header string true "Operator token"
+// @Param code path string true "Room Code"
+// @Param since query int false "Only events numbered after this one"
+// @Success 200 {object} Response{data=RoomDebugLog}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/debug/rooms/{code}/events [get]
+func (h *AdminHandler) RoomDebugEventsHandler(c *gin.Context) {
+	rx, ok := h.rm.Get(c.Param("code"))
+	if !ok {
+		respondError(c, http.StatusNotFound, "room not found")
+		return
+	}
+
+	var since uint64
+	if q := c.Query("since"); q != "" {
+		n, err := strconv.ParseUint(q, 10, 64)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "since must be a non-negative integer")
+			return
+		}
+		since = n
+	}
+
+	events, truncated := h.rm.DebugEvents(rx.Code, since)
+	next := since
+	if len(events) > 0 {
+		next = events[len(events)-1].Seq
+	}
+	respondOK(c, RoomDebugLog{Code: rx.Code, Events: events, Next: next, Truncated: truncated})
+}
+
+// EndRoomHandler force-ends a room without a result
+// @Summary Force-end a room
+// @Description Stops the game in a room without declaring a winner
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Operator token"
+// @Param code path string true "Room Code"
+// @Param reason query string false "Reason shown to players"
+// @Success 200 {object} Response{data=RoomStatus}
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/admin/rooms/{code}/end [post]
+func (h *AdminHandler) EndRoomHandler(c *gin.Context) {
+	rx, ok := h.rm.Get(c.Param("code"))
+	if !ok {
+		respondError(c, http.StatusNotFound, "room not found")
+		return
+	}
+
+	reason := c.DefaultQuery("reason", "ended by operator")
+	if err := h.rm.ForceEnd(rx, reason); err != nil {
+		respondError(c, http.StatusConflict, err.Error())
+		return
+	}
+
+	respondOK(c, RoomStatus{Code: rx.Code, Status: rx.Status})
+}
+
+// SetDefaultWeightsHandler replaces the server-wide default weights
+// @Summary Change default heuristic weights
+// @Description Replaces the default weights used by bots and new rooms
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Operator token"
+// @Param weights body config.HeuristicWeights true "New default weights"
+// @Success 200 {object} Response{data=WeightsView}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/admin/weights/default [put]
+func (h *AdminHandler) SetDefaultWeightsHandler(c *gin.Context) {
+	var weights config.HeuristicWeights
+	if err := c.ShouldBindJSON(&weights); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !weights.ValidateWeights() {
+		respondError(c, http.StatusBadRequest, "weights must be non-negative")
+		return
+	}
+
+	h.rm.SetDefaultWeights(weights)
+	respondOK(c, WeightsView{Weights: weights})
+}
+
+// ReloadConfigHandler re-reads the config file like SIGHUP does
+// @Summary Reload the configuration
+// @Description Reads the config file and HEURISTIC_* overrides again; the new defaults apply to rooms created from now on
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Operator token"
+// @Success 200 {object} Response{data=ConfigReload}
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/admin/config/reload [post]
+func (h *AdminHandler) ReloadConfigHandler(c *gin.Context) {
+	defaults, err := h.rm.ReloadConfig()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondOK(c, ConfigReload{File: config.ConfigFile(), Defaults: defaults})
+}
@@ -0,0 +1,347 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Explain a move's heuristic score
+// @Description Returns the per-feature heuristic breakdown (f_win, f_threat, f_replace, f_blocks, f_formation, f_fork, f_value, f_proximity, f_refute) for a move on a room's current board or a supplied board
+// @Tags Analysis
+// @Accept json
+// @Produce json
+// @Param request body AnalyzeMoveRequest true "Board state and move"
+// @Success 200 {object} Response{data=MoveAnalysis}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/analyze/move [post]
+func AnalyzeMoveHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AnalyzeMoveRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if req.PlayerID == "" {
+			respondError(c, http.StatusBadRequest, "player_id is required")
+			return
+		}
+
+		var board game.Board
+		weights := config.Get().DefaultWeights
+		switch {
+		case req.RoomCode != "":
+			rx, ok := rm.Get(req.RoomCode)
+			if !ok {
+				respondError(c, http.StatusNotFound, "room not found")
+				return
+			}
+			board = rx.Board.Clone()
+			if rx.RoomConfig != nil {
+				weights = rx.RoomConfig.GetWeights()
+			}
+		case req.Board != nil:
+			parsed, err := boardFromRequest(req.Board)
+			if err != nil {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			board = parsed
+		default:
+			respondError(c, http.StatusBadRequest, "room_code or board is required")
+			return
+		}
+		if req.Weights != nil {
+			if !req.Weights.ValidateWeights() {
+				respondError(c, http.StatusBadRequest, "weights must be non-negative")
+				return
+			}
+			weights = *req.Weights
+		}
+
+		coord, err := board.Locate(req.Cell, req.X, req.Y)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if req.Value < 1 || req.Value > board.TopCard() {
+			respondError(c, http.StatusBadRequest, fmt.Sprintf("value must be between 1 and %d", board.TopCard()))
+			return
+		}
+
+		var reason string
+		mv := game.Move{X: coord.X, Y: coord.Y, Card: req.Value, PlayerID: req.PlayerID}
+		if err := game.ValidateMove(&board, mv, board.Placement()); err != nil {
+			reason = err.Error()
+		}
+
+		breakdown := game.EvaluateMoveBreakdown(&board, coord.X, coord.Y, req.Value, req.PlayerID, &weights)
+		respondOK(c, MoveAnalysis{
+			X:         coord.X,
+			Y:         coord.Y,
+			Cell:      coord.Algebraic(),
+			Value:     req.Value,
+			PlayerID:  req.PlayerID,
+			Legal:     reason == "",
+			Reason:    reason,
+			Breakdown: breakdown,
+		})
+	}
+}
+
+// @Summary Score every legal move in a position
+// @Description Evaluates a handcrafted board and hand without a room and returns all legal moves with their heuristic scores, best first
+// @Tags Analysis
+// @Accept json
+// @Produce json
+// @Param request body AnalyzePositionRequest true "Board, hand and optional weights"
+// @Success 200 {object} Response{data=PositionAnalysis}
+// @Failure 400 {object} ErrorResponse
+// @Router /api/analyze/position [post]
+func AnalyzePositionHandler(c *gin.Context) {
+	var req AnalyzePositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.PlayerID == "" {
+		respondError(c, http.StatusBadRequest, "player_id is required")
+		return
+	}
+	board, err := boardFromRequest(&req.Board)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateHand(req.Hand, &board); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	weights := config.Get().DefaultWeights
+	if req.Weights != nil {
+		if !req.Weights.ValidateWeights() {
+			respondError(c, http.StatusBadRequest, "weights must be non-negative")
+			return
+		}
+		weights = *req.Weights
+	}
+
+	moves := game.RankMoves(&board, req.Hand, req.PlayerID, &weights)
+	respondOK(c, PositionAnalysis{
+		PlayerID: req.PlayerID,
+		Hash:     board.Hash,
+		Count:    len(moves),
+		Moves:    moves,
+	})
+}
+
+// CustomRankedMove is a move ranked with custom weights, together with where
+// the default weights would have put it
+type CustomRankedMove struct {
+	game.RankedMove
+	DefaultRank  int `json:"default_rank"` // 1-based
+	DefaultTotal int `json:"default_total"`
+}
+
+// @Summary Rank a position with custom weights
+// @Description Weight playground: evaluates a handcrafted board and hand with caller-supplied heuristic weights (validated, never persisted) and returns the ranked moves, each with its rank and score under the default weights for comparison
+// @Tags Analysis
+// @Accept json
+// @Produce json
+// @Param request body AnalyzeCustomRequest true "Board, hand and weights"
+// @Success 200 {object} Response{data=CustomAnalysis}
+// @Failure 400 {object} ErrorResponse
+// @Router /api/analyze/custom [post]
+func AnalyzeCustomHandler(c *gin.Context) {
+	var req AnalyzeCustomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.PlayerID == "" {
+		respondError(c, http.StatusBadRequest, "player_id is required")
+		return
+	}
+	if req.Weights == nil {
+		respondError(c, http.StatusBadRequest, "weights are required")
+		return
+	}
+	if !req.Weights.ValidateWeights() {
+		respondError(c, http.StatusBadRequest, "weights must be non-negative")
+		return
+	}
+	if req.Limit < 0 {
+		respondError(c, http.StatusBadRequest, "limit must not be negative")
+		return
+	}
+	board, err := boardFromRequest(&req.Board)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateHand(req.Hand, &board); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	defaults := config.Get().DefaultWeights
+	baseline := make(map[game.Move]int)
+	baselineTotal := make(map[game.Move]int)
+	for i, rm := range game.RankMoves(&board, req.Hand, req.PlayerID, &defaults) {
+		baseline[rm.Move] = i + 1
+		baselineTotal[rm.Move] = rm.Breakdown.Total
+	}
+
+	ranked := game.RankMoves(&board, req.Hand, req.PlayerID, req.Weights)
+	count := len(ranked)
+	if req.Limit > 0 && len(ranked) > req.Limit {
+		ranked = ranked[:req.Limit]
+	}
+
+	moves := make([]CustomRankedMove, 0, len(ranked))
+	for _, rm := range ranked {
+		moves = append(moves, CustomRankedMove{
+			RankedMove:   rm,
+			DefaultRank:  baseline[rm.Move],
+			DefaultTotal: baselineTotal[rm.Move],
+		})
+	}
+
+	respondOK(c, CustomAnalysis{
+		PlayerID: req.PlayerID,
+		Hash:     board.Hash,
+		Weights:  req.Weights,
+		Count:    count,
+		Moves:    moves,
+	})
+}
+
+// validateHand checks a client-supplied hand against the board's highest card
+func validateHand(hand []int, b *game.Board) error {
+	if len(hand) == 0 {
+		return errors.New("hand is required")
+	}
+	if len(hand) > config.AnalysisHandMax {
+		return fmt.Errorf("hand holds more than %d cards", config.AnalysisHandMax)
+	}
+	for _, card := range hand {
+		if card < 1 || card > b.TopCard() {
+			return fmt.Errorf("hand cards must be between 1 and %d", b.TopCard())
+		}
+	}
+	return nil
+}
+
+// boardFromRequest checks a client-supplied board and recomputes its cell
+// states so they cannot disagree with the card layout
+func boardFromRequest(b *game.Board) (game.Board, error) {
+	if b.Size < 1 || b.Size > config.DefaultBoardSize {
+		return game.Board{}, fmt.Errorf("board size %d out of range (1 to %d)", b.Size, config.DefaultBoardSize)
+	}
+	if len(b.Cells) != b.Size {
+		return game.Board{}, errors.New("board cells do not match board size")
+	}
+	if b.MaxCard < 0 || b.MaxCard > config.MaxCardValue {
+		return game.Board{}, fmt.Errorf("max_card must be between 1 and %d", config.MaxCardValue)
+	}
+	for _, row := range b.Cells {
+		if len(row) != b.Size {
+			return game.Board{}, errors.New("board cells do not match board size")
+		}
+		for _, cell := range row {
+			if cell.Value < 0 || cell.Value > b.TopCard() || (cell.Value == 0) != (cell.OwnerID == "") {
+				return game.Board{}, fmt.Errorf("board cells must hold a card 1-%d with an owner, or be empty", b.TopCard())
+			}
+			if cell.Locked && cell.Value == 0 {
+				return game.Board{}, errors.New("only cells holding a card can be locked")
+			}
+		}
+	}
+
+	// The hash is derived from the cells, never taken from the client
+	board := b.Clone()
+	game.UpdateVState(&board)
+	board.Rehash()
+	return board, nil
+}
+
+// @Summary Suggest moves for a human player
+// @Description Runs the bot evaluation on the player's hand and returns the top-N moves with their heuristic breakdowns. Only available in rooms created with hints enabled.
+// @Tags Analysis
+// @Produce json
+// @Param code path string true "Room Code"
+// @Param player_id query string true "Player ID"
+// @Param seat_secret query string false "Seat secret, required for seats without an account"
+// @Param n query int false "Number of moves to return (default 3)"
+// @Success 200 {object} Response{data=HintResult}
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/rooms/{code}/hint [get]
+func HintHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rx, ok := rm.Get(c.Param("code"))
+		if !ok {
+			respondError(c, http.StatusNotFound, "room not found")
+			return
+		}
+		if !rx.Hints {
+			respondError(c, http.StatusForbidden, "hints are disabled in this room")
+			return
+		}
+
+		playerID := c.Query("player_id")
+		if err := rm.AuthorizeSeat(rx, playerID, auth.UserID(c), c.Query("seat_secret")); err != nil {
+			respondError(c, http.StatusForbidden, err.Error())
+			return
+		}
+
+		n := 3
+		if q := c.Query("n"); q != "" {
+			parsed, err := strconv.Atoi(q)
+			if err != nil || parsed <= 0 {
+				respondError(c, http.StatusBadRequest, "n must be a positive integer")
+				return
+			}
+			n = parsed
+		}
+
+		var hand []int
+		for _, p := range rx.Players {
+			if p.ID == playerID {
+				if p.IsBot {
+					respondError(c, http.StatusBadRequest, "hints are for human players")
+					return
+				}
+				hand = p.Hand
+			}
+		}
+
+		weights := config.Get().DefaultWeights
+		if rx.RoomConfig != nil {
+			weights = rx.RoomConfig.GetWeights()
+		}
+
+		board := rx.Board.Clone()
+		moves := game.RankMoves(&board, hand, playerID, &weights)
+		if len(moves) > n {
+			moves = moves[:n]
+		}
+
+		respondOK(c, HintResult{
+			PlayerID: playerID,
+			YourTurn: rx.Players[rx.TurnIdx].ID == playerID,
+			Moves:    moves,
+		})
+	}
+}
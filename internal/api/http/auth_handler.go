@@ -0,0 +1,102 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"javanese-chess/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CredentialsRequest represents the payload for /api/auth/register and /api/auth/login.
+type CredentialsRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type AuthHandler struct {
+	svc *auth.Service
+}
+
+func NewAuthHandler(svc *auth.Service) *AuthHandler {
+	return &AuthHandler{svc: svc}
+}
+
+// RegisterHandler creates a player account
+// @Summary Register a player account
+// @Description Create an account and return a login token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body CredentialsRequest true "Credentials"
+// @Success 200 {object} Response{data=AuthResult}
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/auth/register [post]
+func (h *AuthHandler) RegisterHandler(c *gin.Context) {
+	var req CredentialsRequest
+	if err := c.BindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid payload")
+		return
+	}
+
+	user, err := h.svc.Register(req.Username, req.Password)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, auth.ErrUserExists) {
+			status = http.StatusConflict
+		}
+		respondError(c, status, err.Error())
+		return
+	}
+
+	token, err := h.svc.IssueToken(user)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "could not issue token")
+		return
+	}
+
+	respondOK(c, AuthResult{User: user, Token: token})
+}
+
+// LoginHandler exchanges credentials for a token
+// @Summary Log in
+// @Description Verify credentials and return a JWT for the Authorization header (or the token query parameter on /ws)
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body CredentialsRequest true "Credentials"
+// @Success 200 {object} Response{data=AuthResult}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/auth/login [post]
+func (h *AuthHandler) LoginHandler(c *gin.Context) {
+	var req CredentialsRequest
+	if err := c.BindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid payload")
+		return
+	}
+
+	token, user, err := h.svc.Login(req.Username, req.Password)
+	if err != nil {
+		respondError(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	respondOK(c, AuthResult{User: user, Token: token})
+}
+
+// MeHandler returns the authenticated user
+// @Summary Current user
+// @Description Returns the claims of the authenticated user
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} Response{data=auth.Claims}
+// @Failure 401 {object} ErrorResponse
+// @Router /api/auth/me [get]
+func (h *AuthHandler) MeHandler(c *gin.Context) {
+	claims, _ := auth.UserFrom(c)
+	respondOK(c, claims)
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/engine"
+	"javanese-chess/internal/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Choose a bot move without a room
+// @Description Bot API for offline and practice modes that keep the game client-side: takes a full board, a hand, the player to move and optional weights, and returns the move a bot would play with its heuristic breakdown. Bots choose this way in rooms without sampling, opening books or personas. Nothing is stored.
+// @Tags Bot
+// @Accept json
+// @Produce json
+// @Param request body BestMoveRequest true "Board, hand, player and optional weights"
+// @Success 200 {object} Response{data=BestMove}
+// @Failure 400 {object} ErrorResponse
+// @Router /api/bot/best-move [post]
+func BestMoveHandler(c *gin.Context) {
+	var req BestMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.PlayerID == "" {
+		respondError(c, http.StatusBadRequest, "player_id is required")
+		return
+	}
+	eng, err := engine.Get(req.Engine)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	board, err := boardFromRequest(&req.Board)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateHand(req.Hand, &board); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	weights := config.Get().DefaultWeights
+	if req.Weights != nil {
+		if !req.Weights.ValidateWeights() {
+			respondError(c, http.StatusBadRequest, "weights must be non-negative")
+			return
+		}
+		weights = *req.Weights
+	}
+
+	ranked := game.RankCandidates(&board, eng.LegalMoves(&board, req.Hand, req.PlayerID), req.PlayerID, &weights)
+	best := BestMove{
+		PlayerID:   req.PlayerID,
+		Engine:     eng.Name(),
+		Hash:       board.Hash,
+		Candidates: len(ranked),
+	}
+	if len(ranked) > 0 {
+		best.Move = &ranked[0]
+	}
+	if len(ranked) > 1 {
+		best.RunnerUp = &ranked[1]
+	}
+	respondOK(c, best)
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get a room's chat log
+// @Description Returns the most recent in-room chat messages, oldest first, so late joiners and spectators can catch up. Messages are sent with the chat WebSocket action.
+// @Tags Room
+// @Produce json
+// @Param code path string true "Room Code"
+// @Success 200 {object} Response{data=ChatLog}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/rooms/{code}/chat [get]
+func ChatLogHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rx, ok := rm.Get(c.Param("code"))
+		if !ok {
+			respondError(c, http.StatusNotFound, "room not found")
+			return
+		}
+
+		messages := rm.ChatLog(rx)
+		respondOK(c, ChatLog{
+			RoomCode: rx.Code,
+			Count:    len(messages),
+			Messages: messages,
+		})
+	}
+}
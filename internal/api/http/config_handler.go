@@ -27,14 +27,12 @@ func NewConfigHandler(s room.Store, hub *ws.Hub) *ConfigHandler {
 // @Description Returns the default heuristic weights based on research paper (Section 2.4)
 // @Tags Config
 // @Produce json
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} Response{data=WeightsView}
 // @Router /api/config/weights/default [get]
 func (h *ConfigHandler) GetDefaultWeightsHandler(c *gin.Context) {
 	weights := config.Get().DefaultWeights
 
-	c.JSON(http.StatusOK, gin.H{
-		"weights": weights,
-	})
+	respondOK(c, WeightsView{Weights: weights})
 }
 
 // GetRoomWeightsHandler returns the weights for a specific room
@@ -43,18 +41,20 @@ func (h *ConfigHandler) GetDefaultWeightsHandler(c *gin.Context) {
 // @Tags Config
 // @Produce json
 // @Param roomCode query string true "Room Code"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} Response{data=WeightsView}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Router /api/config/weights/room [get]
 func (h *ConfigHandler) GetRoomWeightsHandler(c *gin.Context) {
 	roomCode := c.Query("roomCode")
 	if roomCode == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "roomCode is required"})
+		respondError(c, http.StatusBadRequest, "roomCode is required")
 		return
 	}
 
 	rm, ok := h.store.GetRoom(roomCode)
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "room not found"})
+		respondError(c, http.StatusNotFound, "room not found")
 		return
 	}
 
@@ -70,10 +70,10 @@ func (h *ConfigHandler) GetRoomWeightsHandler(c *gin.Context) {
 		isCustomized = false
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"room_code":     roomCode,
-		"weights":       weights,
-		"is_customized": isCustomized,
+	respondOK(c, WeightsView{
+		RoomCode:     roomCode,
+		Weights:      weights,
+		IsCustomized: isCustomized,
 	})
 }
 
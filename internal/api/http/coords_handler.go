@@ -0,0 +1,69 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Convert a board coordinate
+// @Description Converts between the canonical 0-based (x, y), 1-based (row, col) and algebraic ("E5") notations. Provide either cell, x and y, or row and col.
+// @Tags Board
+// @Produce json
+// @Param cell query string false "Algebraic cell, e.g. E5"
+// @Param x query int false "0-based column"
+// @Param y query int false "0-based row"
+// @Param row query int false "1-based row"
+// @Param col query int false "1-based column"
+// @Success 200 {object} Response{data=CoordView}
+// @Failure 400 {object} ErrorResponse
+// @Router /api/board/coords [get]
+func CoordsHandler(c *gin.Context) {
+	var coord game.Coord
+	input := ""
+
+	switch {
+	case c.Query("cell") != "":
+		input = c.Query("cell")
+		parsed, err := game.ParseAlgebraic(input)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		coord = parsed
+	case c.Query("row") != "" || c.Query("col") != "":
+		row, errRow := strconv.Atoi(c.Query("row"))
+		col, errCol := strconv.Atoi(c.Query("col"))
+		if errRow != nil || errCol != nil {
+			respondError(c, http.StatusBadRequest, "row and col must both be integers")
+			return
+		}
+		coord = game.FromRowCol(row, col)
+	default:
+		x, errX := strconv.Atoi(c.Query("x"))
+		y, errY := strconv.Atoi(c.Query("y"))
+		if errX != nil || errY != nil {
+			respondError(c, http.StatusBadRequest, "provide cell, x and y, or row and col")
+			return
+		}
+		coord = game.Coord{X: x, Y: y}
+	}
+
+	if err := game.ValidateCoord(coord, config.Get().BoardSize, input); err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	row, col := coord.RowCol()
+	respondOK(c, CoordView{
+		X:    coord.X,
+		Y:    coord.Y,
+		Row:  row,
+		Col:  col,
+		Cell: coord.Algebraic(),
+	})
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Start a demo game
+// @Description Creates a room where 2 to 4 bots play each other with a pause before every move, for showcasing the game without a human player. Watch it over WebSocket (/ws?room_code=<room_code>): every move is broadcast as bot_move, and game_over ends the demo.
+// @Tags Room
+// @Accept json
+// @Produce json
+// @Param request body DemoRequest false "Number of bots and the pause between moves"
+// @Success 200 {object} Response{data=DemoResult}
+// @Failure 400 {object} ErrorResponse
+// @Router /api/demo [post]
+func DemoHandler(rm *room.Manager, hub *ws.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DemoRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				respondError(c, http.StatusBadRequest, "invalid payload")
+				return
+			}
+		}
+		if req.Bots == 0 {
+			req.Bots = config.MaxPlayers
+		}
+		delay := config.DefaultDemoDelay
+		if req.DelayMs != 0 {
+			delay = time.Duration(req.DelayMs) * time.Millisecond
+		}
+
+		rx, err := rm.CreateDemoRoom(req.Bots, delay)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		hub.ResumeBots(rx.Code)
+
+		respondOK(c, DemoResult{RoomState: roomState(rx), DelayMs: int(delay / time.Millisecond)})
+	}
+}
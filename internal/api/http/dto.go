@@ -1,6 +1,10 @@
 package http
 
-import "javanese-chess/internal/config"
+import (
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+)
 
 // CreateRoomRequest represents the payload for /create-room.
 type CreateRoomRequest struct {
@@ -11,6 +15,14 @@ type CreateRoomRequest struct {
 type JoinRoomRequest struct {
 	RoomCode   string `json:"room_code"`
 	PlayerName string `json:"player_name"`
+	Password   string `json:"password,omitempty"` // Required for password-protected rooms
+}
+
+// TakeoverRequest represents a new player taking over an abandoned seat.
+type TakeoverRequest struct {
+	RoomCode   string `json:"room_code"`
+	SeatID     string `json:"seat_id"`
+	PlayerName string `json:"player_name"`
 }
 
 // PlayRequest represents the payload for /play.
@@ -20,15 +32,70 @@ type PlayRequest struct {
 	RoomID       string                   `json:"room_id"`
 	PlayerName   []string                 `json:"player_name"` // Changed to array
 	Weights      *config.HeuristicWeights `json:"weights"`
+	BestOf       int                      `json:"best_of"`       // Optional: 1, 3 or 5 games in the match
+	HiddenHands  bool                     `json:"hidden_hands"`  // Optional: shorthand for a policy hiding hands and drawn cards
+	Seed         int64                    `json:"seed"`          // Optional: fixed seed for reproducible dealing
+	Ranked       bool                     `json:"ranked"`        // Optional: ranked games move ratings and disallow seat takeovers
+	Temperature  *float64                 `json:"temperature"`   // Optional: bot sampling temperature for opening moves
+	TurnSeconds  int                      `json:"turn_seconds"`  // Optional: enables timed turns with a time bank
+	TimeBankCap  *int                     `json:"time_bank_cap"` // Optional: max banked seconds per player
+	ClockSeconds int                      `json:"clock_seconds"` // Optional: chess clock, total seconds per player for the game; a player who runs out loses
+	ClockInc     int                      `json:"clock_inc"`     // Optional: seconds added to the chess clock after each move
+	Abandon      *shared.AbandonRule      `json:"abandon"`       // Optional: when a disconnected player is given up on, and whether a bot takes over or they forfeit
+	FirstPlayer  string                   `json:"first_player"`  // Optional: who opens each game: random (default), master or loser
+	Rotation     string                   `json:"rotation"`      // Optional: clockwise (default) or counterclockwise
+	Hints        bool                     `json:"hints"`         // Optional: allow the suggest move endpoint
+	DeckRule     string                   `json:"deck_rule"`     // Optional: continue, communal or endgame once a deck is empty
+	SharedDeck   int                      `json:"shared_deck"`   // Optional: sets of cards in one deck shared by all players
+	Deck         *config.DeckSpec         `json:"deck"`          // Optional: deck composition; overrides shared_deck
+	Bots         []BotSpec                `json:"bots"`          // Optional: per-bot settings, in seating order
+	CellLock     bool                     `json:"cell_lock"`     // Optional: cells captured twice become permanent
+	OwnOverwrite bool                     `json:"own_overwrite"` // Optional: players may cover their own cards with higher ones
+	OpeningBook  bool                     `json:"opening_book"`  // Optional: bots play their first moves from the opening book
+	Hold         bool                     `json:"hold"`          // Optional: bots hold a card instead of making a weak placement
+	BotBudgetMs  int                      `json:"bot_budget_ms"` // Optional: time bots may spend evaluating a move, 0 for the default
+	ThinkMs      int                      `json:"bot_think_ms"`  // Optional: shortest pause before each bot move, 0 for the default
+	ThinkMaxMs   int                      `json:"bot_think_max"` // Optional: longest pause in ms; bots pause a random time between the two
+	Engine       string                   `json:"engine"`        // Optional: rules engine by name, defaults to classic
+	Policy       *shared.BroadcastPolicy  `json:"policy"`        // Optional: what public broadcasts reveal
+	Locale       string                   `json:"locale"`        // Optional: en or id, the language of errors for clients that do not ask for one
+	Features     map[string]bool          `json:"features"`      // Optional: experimental rule flags, e.g. {"last_move_adjacency": true}
+	CaptureTie   bool                     `json:"capture_tie"`   // Optional: ties on line and total sums go to the player with more captures, then the higher captured value
+	Manual       bool                     `json:"manual"`        // Optional: testing room where POST /api/hands may set the dealt hands; cannot be ranked
+}
+
+// BotSpec configures one bot added by a play request
+type BotSpec struct {
+	Personality string `json:"personality"` // aggressive, defensive or greedy; empty uses the default weights
 }
 
 // MoveRequest represents a player move.
+// Positions use 0-based (x, y) with x as the column; Cell accepts algebraic
+// notation such as "E5" instead and takes precedence over x/y.
 type MoveRequest struct {
 	RoomCode string `json:"room_code"`
 	X        int    `json:"x"`
 	Y        int    `json:"y"`
+	Cell     string `json:"cell,omitempty"`
 	Value    int    `json:"value"`
 	PlayerID string `json:"player_id"`
+
+	// SeatSecret proves a seat without an account is the caller's; account
+	// seats are proven by the caller's token instead
+	SeatSecret string `json:"seat_secret,omitempty"`
+}
+
+// ResignRequest represents a player leaving the game.
+type ResignRequest struct {
+	RoomCode   string `json:"room_code"`
+	PlayerID   string `json:"player_id"`
+	SeatSecret string `json:"seat_secret,omitempty"` // Required for seats without an account
+}
+
+// DemoRequest represents a bot-only demo game. Zero values use the defaults.
+type DemoRequest struct {
+	Bots    int `json:"bots"`     // 2 to 4 bots, 4 by default
+	DelayMs int `json:"delay_ms"` // Pause before each move
 }
 
 // MoveBotRequest represents a bot move.
@@ -38,13 +105,61 @@ type MoveBotRequest struct {
 	Hold     []int  `json:"hold_cards"`
 }
 
-// SetHandsRequest represents FE-shuffled cards.
+// SetHandsRequest replaces dealt hands in a manual room before the first
+// move. Players left out keep their hands.
 type SetHandsRequest struct {
 	RoomCode string       `json:"room_code"`
 	Hands    []PlayerHand `json:"hands"`
 }
 
+// PlayerHand is the hand one player is given
 type PlayerHand struct {
 	PlayerID string `json:"player_id"`
 	Cards    []int  `json:"cards"`
 }
+
+// AnalyzeMoveRequest asks for the heuristic breakdown of a move.
+// Either RoomCode (use the room's current board and weights) or Board must be given.
+type AnalyzeMoveRequest struct {
+	RoomCode string                   `json:"room_code,omitempty"`
+	Board    *game.Board              `json:"board,omitempty"`
+	Weights  *config.HeuristicWeights `json:"weights,omitempty"` // Optional: overrides the room/default weights
+	PlayerID string                   `json:"player_id"`
+	X        int                      `json:"x"`
+	Y        int                      `json:"y"`
+	Cell     string                   `json:"cell,omitempty"`
+	Value    int                      `json:"value"`
+}
+
+// AnalyzePositionRequest asks for every legal move in a handcrafted position.
+type AnalyzePositionRequest struct {
+	Board    game.Board               `json:"board"`
+	Hand     []int                    `json:"hand"`
+	PlayerID string                   `json:"player_id"`
+	Weights  *config.HeuristicWeights `json:"weights,omitempty"` // Optional: defaults to the server weights
+}
+
+// AnalyzeCustomRequest ranks a handcrafted position with caller-supplied
+// weights. The weights only apply to this request and are never stored.
+type AnalyzeCustomRequest struct {
+	Board    game.Board               `json:"board"`
+	Hand     []int                    `json:"hand"`
+	PlayerID string                   `json:"player_id"`
+	Weights  *config.HeuristicWeights `json:"weights"`
+	Limit    int                      `json:"limit,omitempty"` // Optional: number of moves to return (0 = all)
+}
+
+// BestMoveRequest asks which move a bot would play in a position the client
+// keeps itself. Nothing is stored.
+type BestMoveRequest struct {
+	Board    game.Board               `json:"board"`
+	Hand     []int                    `json:"hand"`
+	PlayerID string                   `json:"player_id"`
+	Weights  *config.HeuristicWeights `json:"weights,omitempty"` // Optional: defaults to the server weights
+	Engine   string                   `json:"engine,omitempty"`  // Optional: rules engine by name, defaults to classic
+}
+
+// CloseRoomsRequest closes every open room of the authenticated owner
+type CloseRoomsRequest struct {
+	Reason string `json:"reason,omitempty"` // Optional: shown to connected players
+}
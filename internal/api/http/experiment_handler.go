@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/experiment"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StartExperimentRequest represents the payload for /api/admin/experiments.
+type StartExperimentRequest struct {
+	Name     string                  `json:"name"`
+	WeightsA config.HeuristicWeights `json:"weights_a"`
+	WeightsB config.HeuristicWeights `json:"weights_b"`
+}
+
+// @Summary Start a weight experiment
+// @Description Starts an A/B test of two heuristic weight sets, stopping the running one. New rooms are assigned to arm A or B in turn and their bots play with that arm's weights
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Operator token"
+// @Param request body StartExperimentRequest true "Experiment"
+// @Success 200 {object} Response{data=experiment.Experiment}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/admin/experiments [post]
+func StartExperimentHandler(xs *experiment.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req StartExperimentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		x, err := xs.Start(req.Name, req.WeightsA, req.WeightsB)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondOK(c, x)
+	}
+}
+
+// @Summary Stop a weight experiment
+// @Description New rooms are no longer assigned to the experiment; rooms already assigned keep playing their arm and still count
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Operator token"
+// @Param id path string true "Experiment ID"
+// @Success 200 {object} Response{data=experiment.Experiment}
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/admin/experiments/{id}/stop [post]
+func StopExperimentHandler(xs *experiment.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		x, err := xs.Stop(c.Param("id"))
+		if err != nil {
+			respondExperimentError(c, err)
+			return
+		}
+		respondOK(c, x)
+	}
+}
+
+// @Summary Get weight experiment results
+// @Description Per arm: rooms assigned, and games between humans and bots with the bots' wins, the humans' wins, draws and the bots' win rate
+// @Tags Experiments
+// @Produce json
+// @Param id path string true "Experiment ID"
+// @Success 200 {object} Response{data=experiment.Results}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/experiments/{id}/results [get]
+func ExperimentResultsHandler(xs *experiment.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res, err := xs.Results(c.Param("id"))
+		if err != nil {
+			respondExperimentError(c, err)
+			return
+		}
+		respondOK(c, res)
+	}
+}
+
+// respondExperimentError maps experiment errors to a status: 404 for an
+// unknown experiment, 400 otherwise
+func respondExperimentError(c *gin.Context, err error) {
+	if errors.Is(err, experiment.ErrNotFound) {
+		respondError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	respondError(c, http.StatusBadRequest, err.Error())
+}
@@ -2,27 +2,32 @@ package http
 
 import (
 	"net/http"
+	"time"
 
 	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/auth"
 	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
 	"javanese-chess/internal/room"
+	"javanese-chess/internal/shared"
 
 	"github.com/gin-gonic/gin"
 )
 
 // @Summary Add bots to a room or create room and apply config
-// @Description Initialize room (create if missing), add bots and apply provided heuristic weights in one request
+// @Description Initialize room (create if missing), add bots and apply provided heuristic weights in one request. Every human besides the room master must have sent the ready WebSocket action.
 // @Tags Room
 // @Accept json
 // @Produce json
 // @Param request body PlayRequest true "Room info"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} Response{data=RoomState}
+// @Failure 400 {object} ErrorResponse
 // @Router /api/play [post]
-func PlayHandler(rm *room.Manager, hub *ws.Hub) gin.HandlerFunc {
+func PlayHandler(rm *room.Manager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var playRequest PlayRequest
 		if err := c.BindJSON(&playRequest); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+			respondError(c, http.StatusBadRequest, "invalid payload")
 			return
 		}
 
@@ -32,68 +37,219 @@ func PlayHandler(rm *room.Manager, hub *ws.Hub) gin.HandlerFunc {
 
 		// Validate RoomID is provided
 		if playRequest.RoomID == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "room_id is required"})
+			respondError(c, http.StatusBadRequest, "room_id is required")
 			return
 		}
 
 		// Get existing room (must exist from room_created event)
 		rx, ok := rm.Get(playRequest.RoomID)
 		if !ok {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "room not found"})
+			respondError(c, http.StatusBadRequest, "room not found")
 			return
 		}
 
 		// Validate room is in lobby state
 		if rx.Status != "lobby" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "game has already started"})
+			respondError(c, http.StatusBadRequest, "game has already started")
+			return
+		}
+
+		// Every human besides the room master must be ready
+		if err := rm.CheckReady(rx); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := rm.CheckFeatures(rx); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
 			return
 		}
 
 		// Validate player names are provided
 		if len(playRequest.PlayerName) == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "player_name array is required"})
+			respondError(c, http.StatusBadRequest, "player_name array is required")
+			return
+		}
+
+		// Bot settings imply the bot count when number_bot is omitted
+		if playRequest.NumberBot == 0 {
+			playRequest.NumberBot = len(playRequest.Bots)
+		}
+		if len(playRequest.Bots) > playRequest.NumberBot {
+			respondError(c, http.StatusBadRequest, "more bot settings than bots")
+			return
+		}
+
+		// Seats are capped at 4 and a game needs at least 2
+		total := len(rx.Players) + playRequest.NumberBot
+		if total > config.MaxPlayers {
+			respondError(c, http.StatusBadRequest, "a room holds at most 4 players")
+			return
+		}
+		if total < config.MinPlayers {
+			respondError(c, http.StatusBadRequest, "a game needs at least 2 players")
 			return
 		}
 
 		// Add bots if requested
 		if playRequest.NumberBot > 0 {
-			rm.AddBots(rx, playRequest.NumberBot)
+			personalities := make([]string, 0, len(playRequest.Bots))
+			for _, b := range playRequest.Bots {
+				personalities = append(personalities, b.Personality)
+			}
+			if err := rm.AddBots(rx, playRequest.NumberBot, personalities...); err != nil {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
 		}
 
 		// Apply weights if provided
 		if playRequest.Weights != nil {
 			if !playRequest.Weights.ValidateWeights() {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "weights must be non-negative"})
+				respondError(c, http.StatusBadRequest, "weights must be non-negative")
 				return
 			}
-			if rx.RoomConfig == nil {
-				rx.RoomConfig = config.NewRoomConfig(rx.Code)
+			rm.SetWeights(rx, *playRequest.Weights)
+		}
+
+		// Set up a best-of-N series if requested
+		if playRequest.BestOf > 0 {
+			if err := rm.StartMatch(rx, playRequest.BestOf); err != nil {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
 			}
-			rx.RoomConfig.SetWeights(*playRequest.Weights)
 		}
 
-		// Start the game (change status from lobby to playing)
-		rm.StartGame(rx)
+		// Apply bot sampling temperature if provided
+		if playRequest.Temperature != nil {
+			if *playRequest.Temperature < 0 {
+				respondError(c, http.StatusBadRequest, "temperature must be non-negative")
+				return
+			}
+			rm.SetTemperature(rx, *playRequest.Temperature, config.Get().BotTemperatureMoves)
+		}
 
-		// Broadcast game started to all clients
-		hub.Broadcast(rx.Code, "game_started", gin.H{
-			"room_code":  rx.Code,
-			"turn_order": rx.TurnOrder,
-			"players":    rx.Players,
-			"board":      rx.Board,
-			"status":     "playing",
-		})
+		// Enable timed turns with banked unused time if requested
+		if playRequest.TurnSeconds > 0 {
+			capSeconds := config.DefaultTimeBankCap
+			if playRequest.TimeBankCap != nil {
+				capSeconds = *playRequest.TimeBankCap
+			}
+			if err := rm.SetTimeBank(rx, playRequest.TurnSeconds, capSeconds); err != nil {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"data": gin.H{
-				"room_code":  rx.Code,
-				"turn_order": rx.TurnOrder, // Shuffled player IDs
-				"players":    rx.Players,   // Detailed player information
-				"board":      rx.Board,
-				"status":     "playing",
-			},
-		})
+		// Or a chess clock with a total time per player
+		if playRequest.ClockSeconds > 0 {
+			if playRequest.TurnSeconds > 0 {
+				respondError(c, http.StatusBadRequest, "turn_seconds and clock_seconds cannot be combined")
+				return
+			}
+			if err := rm.SetGameClock(rx, playRequest.ClockSeconds, playRequest.ClockInc); err != nil {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		} else if playRequest.ClockInc != 0 {
+			respondError(c, http.StatusBadRequest, "clock_inc needs clock_seconds")
+			return
+		}
+		if playRequest.Abandon != nil {
+			if err := rm.SetAbandonRule(rx, *playRequest.Abandon); err != nil {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		if err := rm.SetTurnRule(rx, shared.TurnOrderRule{First: playRequest.FirstPlayer, Rotation: playRequest.Rotation}); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := rm.SetEngine(rx, playRequest.Engine); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := rm.SetDeckRule(rx, playRequest.DeckRule); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := rm.SetSharedDeck(rx, playRequest.SharedDeck); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if playRequest.Deck != nil {
+			if err := rm.SetDeckSpec(rx, *playRequest.Deck); err != nil {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		if err := rm.SetCellLock(rx, playRequest.CellLock); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := rm.SetOwnOverwrite(rx, playRequest.OwnOverwrite); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := rm.SetCaptureTie(rx, playRequest.CaptureTie); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		rm.SetOpeningBook(rx, playRequest.OpeningBook)
+		rm.SetHold(rx, playRequest.Hold)
+		if playRequest.Features != nil {
+			if err := rm.SetFeatures(rx, playRequest.Features); err != nil {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if err := rm.SetBotBudget(rx, time.Duration(playRequest.BotBudgetMs)*time.Millisecond); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Pace bot moves; a lone bot_think_ms is a fixed pause
+		if playRequest.ThinkMs != 0 || playRequest.ThinkMaxMs != 0 {
+			maxMs := playRequest.ThinkMaxMs
+			if maxMs == 0 {
+				maxMs = playRequest.ThinkMs
+			}
+			if err := rm.SetBotThinkTime(rx, time.Duration(playRequest.ThinkMs)*time.Millisecond, time.Duration(maxMs)*time.Millisecond); err != nil {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if err := rm.SetLocale(rx, playRequest.Locale); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// hidden_hands is shorthand for the hidden hands broadcast policy
+		policy := rx.Policy
+		if playRequest.Policy != nil {
+			policy = *playRequest.Policy
+		}
+		if playRequest.HiddenHands {
+			policy.HideHands = true
+			policy.PrivateDraws = true
+		}
+		rm.SetOptions(rx, policy, playRequest.Ranked, playRequest.Hints)
+		if err := rm.SetManual(rx, playRequest.Manual); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Re-deal and re-shuffle from the requested seed so the game is reproducible
+		if playRequest.Seed != 0 {
+			rm.Reseed(rx, playRequest.Seed)
+		}
+
+		// Deal, draw the turn order and announce the game to all clients
+		rm.StartGame(rx)
+
+		respondOK(c, roomState(rx))
 	}
 }
 
@@ -103,60 +259,173 @@ func PlayHandler(rm *room.Manager, hub *ws.Hub) gin.HandlerFunc {
 // @Accept json
 // @Produce json
 // @Param request body JoinRoomRequest true "Join room info"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} Response{data=JoinResult}
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Router /api/join [post]
 func JoinRoomHandler(rm *room.Manager, hub *ws.Hub) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var joinRequest JoinRoomRequest
 		if err := c.BindJSON(&joinRequest); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+			respondError(c, http.StatusBadRequest, "invalid payload")
 			return
 		}
 
 		if joinRequest.RoomCode == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "room_code is required"})
+			respondError(c, http.StatusBadRequest, "room_code is required")
 			return
 		}
 
 		if joinRequest.PlayerName == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "player_name is required"})
+			respondError(c, http.StatusBadRequest, "player_name is required")
 			return
 		}
 
 		// Validate room exists
 		rx, ok := rm.Get(joinRequest.RoomCode)
 		if !ok {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "room not found"})
+			respondError(c, http.StatusBadRequest, "room not found")
 			return
 		}
 
 		// Validate room is in lobby state
 		if rx.Status != "lobby" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "game has already started"})
+			respondError(c, http.StatusBadRequest, "game has already started")
+			return
+		}
+
+		if err := rm.CheckRoomPassword(rx, joinRequest.Password); err != nil {
+			respondError(c, http.StatusForbidden, err.Error())
+			return
+		}
+
+		// Join the room; the manager tells the room about the new roster
+		rx, seat, err := rm.JoinRoom(joinRequest.RoomCode, joinRequest.PlayerName)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Tie the new seat to the caller's account when authenticated
+		rm.BindUser(rx, seat.ID, auth.UserID(c))
+
+		respondOK(c, JoinResult{
+			RoomState:  roomState(rx),
+			PlayerID:   seat.ID,
+			SeatSecret: rm.SeatSecret(rx, seat.ID),
+		})
+	}
+}
+
+// @Summary Take over an abandoned seat
+// @Description Join a game in progress by taking over a resigned or abandoned seat, inheriting its board presence, hand and deck
+// @Tags Room
+// @Accept json
+// @Produce json
+// @Param request body TakeoverRequest true "Takeover info"
+// @Success 200 {object} Response{data=TakeoverResult}
+// @Failure 400 {object} ErrorResponse
+// @Router /api/takeover [post]
+func TakeoverHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req TakeoverRequest
+		if err := c.BindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid payload")
+			return
+		}
+
+		rx, ok := rm.Get(req.RoomCode)
+		if !ok {
+			respondError(c, http.StatusBadRequest, "room not found")
 			return
 		}
 
-		// Join the room
-		rx, err := rm.JoinRoom(joinRequest.RoomCode, joinRequest.PlayerName)
+		seat, err := rm.TakeOverSeat(rx, req.SeatID, req.PlayerName)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondError(c, http.StatusBadRequest, err.Error())
 			return
 		}
+		rm.BindUser(rx, seat.ID, auth.UserID(c))
 
-		// Broadcast only the new player's name
-		hub.Broadcast(rx.Code, "new_player_joined", gin.H{
-			"player_name": joinRequest.PlayerName,
+		respondOK(c, TakeoverResult{
+			RoomState:  roomState(rx),
+			PlayerID:   seat.ID,
+			SeatSecret: rm.SeatSecret(rx, seat.ID),
+			Hand:       seat.Hand,
 		})
+	}
+}
 
-		c.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"data": gin.H{
-				"room_code":  rx.Code,
-				"turn_order": rx.TurnOrder,
-				"players":    rx.Players,
-				"board":      rx.Board,
-				"status":     "playing",
-			},
+// @Summary Play a move
+// @Description Validates and applies a human card placement through the room manager and broadcasts it to connected clients, so games can be driven without a WebSocket
+// @Tags Room
+// @Accept json
+// @Produce json
+// @Param request body MoveRequest true "Move"
+// @Success 200 {object} Response{data=MoveResult}
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/move [post]
+func MoveHandler(rm *room.Manager, hub *ws.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req MoveRequest
+		if err := c.BindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid payload")
+			return
+		}
+		if req.PlayerID == "" {
+			respondError(c, http.StatusBadRequest, "player_id is required")
+			return
+		}
+
+		rx, ok := rm.Get(req.RoomCode)
+		if !ok {
+			respondError(c, http.StatusNotFound, "room not found")
+			return
+		}
+		if err := rm.AuthorizeSeat(rx, req.PlayerID, auth.UserID(c), req.SeatSecret); err != nil {
+			respondError(c, http.StatusForbidden, err.Error())
+			return
+		}
+
+		coord, err := rx.Board.Locate(req.Cell, req.X, req.Y)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		req.X, req.Y = coord.X, coord.Y
+
+		if err := rm.ApplyMove(c.Request.Context(), rx, req.PlayerID, req.X, req.Y, req.Value); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Bots reply over the WebSocket like after any other human move
+		if rx.WinnerID == nil && !rx.Draw && rx.Players[rx.TurnIdx].IsBot {
+			hub.ResumeBots(rx.Code)
+		}
+
+		// The caller proved the seat is theirs, so its hand is theirs to see
+		// even when the room hides hands
+		var hand []int
+		for _, p := range rx.Players {
+			if p.ID == req.PlayerID {
+				hand = p.Hand
+			}
+		}
+		respondOK(c, MoveResult{
+			RoomCode: rx.Code,
+			X:        req.X,
+			Y:        req.Y,
+			Cell:     game.Coord{X: req.X, Y: req.Y}.Algebraic(),
+			Value:    req.Value,
+			Hand:     hand,
+			Board:    rx.Board,
+			Status:   rx.Status,
+			WinnerID: rx.WinnerID,
+			Draw:     rx.Draw,
+			NextTurn: rx.Players[rx.TurnIdx].ID,
 		})
 	}
 }
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Set dealt hands in a manual room
+// @Description Replaces the dealt hands of a manual testing room after the game starts and before the first move. Each hand holds 3 cards of the room's deck, and no card value may be dealt more often than the deck holds; the remaining cards are reshuffled into the player's deck, or the shared pile. Competitive rooms reject it.
+// @Tags Room
+// @Accept json
+// @Produce json
+// @Param request body SetHandsRequest true "Hands by player"
+// @Success 200 {object} Response{data=RoomState}
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/hands [post]
+func SetHandsHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req SetHandsRequest
+		if err := c.BindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid payload")
+			return
+		}
+		if req.RoomCode == "" {
+			respondError(c, http.StatusBadRequest, "room_code is required")
+			return
+		}
+
+		rx, ok := rm.Get(req.RoomCode)
+		if !ok {
+			respondError(c, http.StatusNotFound, "room not found")
+			return
+		}
+		if !rx.Manual {
+			respondError(c, http.StatusForbidden, "hands can only be set in manual rooms")
+			return
+		}
+
+		hands := make(map[string][]int, len(req.Hands))
+		for _, h := range req.Hands {
+			if h.PlayerID == "" {
+				respondError(c, http.StatusBadRequest, "player_id is required")
+				return
+			}
+			if _, dup := hands[h.PlayerID]; dup {
+				respondError(c, http.StatusBadRequest, fmt.Sprintf("player %q is given two hands", h.PlayerID))
+				return
+			}
+			hands[h.PlayerID] = h.Cards
+		}
+
+		if err := rm.SetHands(rx, hands); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondOK(c, roomState(rx))
+	}
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"javanese-chess/internal/room"
+	"javanese-chess/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Leaderboard
+// @Description Rated players ordered by ELO rating, paginated
+// @Tags Ratings
+// @Produce json
+// @Param bots query bool false "Only bot personas (true) or only accounts (false)"
+// @Param q query string false "Name contains this text, ignoring case"
+// @Param page query int false "Page number (1-based)"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} Response{data=LeaderboardPage}
+// @Failure 400 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/leaderboard [get]
+func LeaderboardHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rs := rm.Ratings()
+		if rs == nil {
+			respondError(c, http.StatusServiceUnavailable, "ratings are disabled")
+			return
+		}
+
+		page, err := pageQuery(c)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		bots, err := boolFilter(c, "bots")
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		text := strings.ToLower(c.Query("q"))
+
+		// Ranks stay the overall ranks when filters leave players out
+		type ranked struct {
+			rank int
+			shared.PlayerRating
+		}
+		var all []ranked
+		for i, r := range rs.ListRatings() {
+			if !matches(bots, strings.HasPrefix(r.PlayerID, room.BotRatingPrefix)) {
+				continue
+			}
+			if text != "" && !strings.Contains(strings.ToLower(r.Name), text) {
+				continue
+			}
+			all = append(all, ranked{i + 1, r})
+		}
+		start, end := page.window(len(all))
+
+		entries := make([]LeaderboardEntry, 0, end-start)
+		for _, r := range all[start:end] {
+			entries = append(entries, LeaderboardEntry{
+				Rank:     r.rank,
+				PlayerID: r.PlayerID,
+				Name:     r.Name,
+				Rating:   r.Rating,
+				Games:    r.Games,
+				Wins:     r.Wins,
+			})
+		}
+
+		respondOK(c, LeaderboardPage{Entries: entries, Page: page})
+	}
+}
+
+// @Summary Player statistics
+// @Description Rating, wins, losses, draws, average game length and per-game metrics totals of a rated player
+// @Tags Ratings
+// @Produce json
+// @Param id path string true "Player (account) ID, or bot:<persona>"
+// @Success 200 {object} Response{data=PlayerStats}
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/players/{id}/stats [get]
+func PlayerStatsHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rs := rm.Ratings()
+		if rs == nil {
+			respondError(c, http.StatusServiceUnavailable, "ratings are disabled")
+			return
+		}
+
+		r, ok := rs.GetRating(c.Param("id"))
+		if !ok {
+			respondError(c, http.StatusNotFound, "player not found")
+			return
+		}
+
+		respondOK(c, PlayerStats{
+			PlayerID:      r.PlayerID,
+			Name:          r.Name,
+			Rating:        r.Rating,
+			Games:         r.Games,
+			Wins:          r.Wins,
+			Losses:        r.Losses,
+			Draws:         r.Draws,
+			AvgMoves:      r.AverageMoves(),
+			AvgGameLength: r.AverageSeconds(),
+			AvgThinkTime:  r.AverageThinkSeconds(),
+			Captures:      r.TotalCaptures,
+			Skips:         r.TotalSkips,
+			BestLine:      r.BestLine,
+		})
+	}
+}
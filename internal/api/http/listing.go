@@ -0,0 +1,72 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Page sizes of listing endpoints
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Page describes one page of a listing: which page it is (1-based), the page
+// size and how many items matched the filters in total
+type Page struct {
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+	Total int `json:"total"`
+}
+
+// pageQuery reads ?page and ?limit, defaulting to the first page of
+// defaultPageSize items
+func pageQuery(c *gin.Context) (Page, error) {
+	p := Page{Page: 1, Limit: defaultPageSize}
+	if q := c.Query("page"); q != "" {
+		n, err := strconv.Atoi(q)
+		if err != nil || n < 1 {
+			return Page{}, errors.New("page must be a positive integer")
+		}
+		p.Page = n
+	}
+	if q := c.Query("limit"); q != "" {
+		n, err := strconv.Atoi(q)
+		if err != nil || n < 1 || n > maxPageSize {
+			return Page{}, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+		}
+		p.Limit = n
+	}
+	return p, nil
+}
+
+// window records total and returns the bounds of the page's items in a
+// listing that long; pages past the end are empty
+func (p *Page) window(total int) (start, end int) {
+	p.Total = total
+	start = min((p.Page-1)*p.Limit, total)
+	end = min(start+p.Limit, total)
+	return start, end
+}
+
+// boolFilter reads an optional true/false query filter; nil means the
+// filter was not given
+func boolFilter(c *gin.Context, name string) (*bool, error) {
+	q := c.Query(name)
+	if q == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseBool(q)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be true or false", name)
+	}
+	return &v, nil
+}
+
+// matches reports whether filter is unset or equal to v
+func matches(filter *bool, v bool) bool {
+	return filter == nil || *filter == v
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Browse rooms
+// @Description Lists rooms with the given status (open lobbies by default), newest first and paginated, so players can find public games to join
+// @Tags Room
+// @Produce json
+// @Param status query string false "Room status: lobby (default), playing, ended, aborted or closed"
+// @Param engine query string false "Only rooms playing this rules engine"
+// @Param password query bool false "Only rooms with (true) or without (false) a password"
+// @Param demo query bool false "Only demo games (true) or only real ones (false)"
+// @Param q query string false "Room code or host name contains this text, ignoring case"
+// @Param page query int false "Page number (1-based)"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} Response{data=RoomPage}
+// @Failure 400 {object} ErrorResponse
+// @Router /api/rooms [get]
+func ListRoomsHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := c.DefaultQuery("status", "lobby")
+		page, err := pageQuery(c)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		password, err := boolFilter(c, "password")
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		demo, err := boolFilter(c, "demo")
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		eng := c.Query("engine")
+		text := strings.ToLower(c.Query("q"))
+
+		// Newest first; the store returns rooms oldest first
+		all := rm.ListRooms()
+		rooms := make([]RoomSummary, 0, len(all))
+		for i := len(all) - 1; i >= 0; i-- {
+			rx := all[i]
+			if rx.Status != status {
+				continue
+			}
+			if status == "lobby" && len(rx.Players) >= config.MaxPlayers {
+				continue // Full lobbies cannot be joined
+			}
+			if eng != "" && engineName(rx) != eng {
+				continue
+			}
+
+			summary := roomSummary(rx)
+			if !matches(password, summary.PasswordProtected) || !matches(demo, summary.Demo) {
+				continue
+			}
+			if text != "" && !strings.Contains(strings.ToLower(summary.RoomCode), text) && !strings.Contains(strings.ToLower(summary.Host), text) {
+				continue
+			}
+			rooms = append(rooms, summary)
+		}
+
+		start, end := page.window(len(rooms))
+		respondOK(c, RoomPage{Rooms: rooms[start:end], Page: page})
+	}
+}
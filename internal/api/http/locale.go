@@ -0,0 +1,43 @@
+package http
+
+import (
+	"javanese-chess/internal/apierr"
+	"javanese-chess/internal/i18n"
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// localeMiddleware picks the language error responses are written in: the
+// ?lang query parameter, then the Accept-Language header, then the locale
+// of the room named in the path or by ?room_code, then the server default
+func localeMiddleware(mgr *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.Query("lang"), c.GetHeader("Accept-Language"))
+		if lang == "" {
+			lang = roomLocale(mgr, c)
+		}
+		if lang == "" {
+			lang = i18n.Default
+		}
+		c.Set(apierr.LangKey, lang)
+		c.Header("Content-Language", lang)
+		c.Next()
+	}
+}
+
+// roomLocale is the locale of the room a request names, "" when it names
+// none or the room has no locale
+func roomLocale(mgr *room.Manager, c *gin.Context) string {
+	code := c.Param("code")
+	if code == "" {
+		code = c.Query("room_code")
+	}
+	if code == "" {
+		return ""
+	}
+	if rx, ok := mgr.Get(code); ok {
+		return rx.Locale
+	}
+	return ""
+}
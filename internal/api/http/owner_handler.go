@@ -0,0 +1,38 @@
+package http
+
+import (
+	"net/http"
+
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Close all of my rooms
+// @Description Closes every still-open room created by the authenticated user (requires a bearer token), e.g. a batch of classroom rooms. Connected clients receive room_closed; games in progress end without a result and stay available for export.
+// @Tags Room
+// @Accept json
+// @Produce json
+// @Param request body CloseRoomsRequest false "Reason shown to players"
+// @Success 200 {object} Response{data=ClosedRooms}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/rooms/close [post]
+func CloseOwnedRoomsHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CloseRoomsRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				respondError(c, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		if req.Reason == "" {
+			req.Reason = "closed by room owner"
+		}
+
+		closed := rm.CloseOwnedRooms(auth.UserID(c), req.Reason)
+		respondOK(c, ClosedRooms{Count: len(closed), Rooms: closed})
+	}
+}
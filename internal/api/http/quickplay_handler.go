@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+
+	"javanese-chess/internal/matchmaking"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuickPlayRequest represents the payload for /api/quickplay.
+type QuickPlayRequest struct {
+	PlayerName string `json:"player_name"`
+}
+
+// @Summary Join the quick play queue
+// @Description Queue for an automatic match. Subscribe to the returned ticket over WebSocket (/ws?room_code=<ticket_id>) to receive match_found
+// @Tags Matchmaking
+// @Accept json
+// @Produce json
+// @Param request body QuickPlayRequest true "Player info"
+// @Success 200 {object} Response{data=QuickPlayTicket}
+// @Failure 400 {object} ErrorResponse
+// @Router /api/quickplay [post]
+func QuickPlayHandler(q *matchmaking.Queue) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req QuickPlayRequest
+		if err := c.BindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid payload")
+			return
+		}
+
+		ticket, err := q.Enqueue(req.PlayerName)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		respondOK(c, QuickPlayTicket{
+			TicketID:   ticket.ID,
+			PlayerName: ticket.PlayerName,
+			QueuedAt:   ticket.QueuedAt,
+			Waiting:    q.Waiting(),
+		})
+	}
+}
+
+// @Summary Leave the quick play queue
+// @Description Cancel a waiting quick play ticket
+// @Tags Matchmaking
+// @Produce json
+// @Param ticket path string true "Ticket ID"
+// @Success 200 {object} Response
+// @Failure 404 {object} ErrorResponse
+// @Router /api/quickplay/{ticket} [delete]
+func CancelQuickPlayHandler(q *matchmaking.Queue) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !q.Cancel(c.Param("ticket")) {
+			respondError(c, http.StatusNotFound, "ticket not found")
+			return
+		}
+		respondOK(c, nil)
+	}
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/room"
+	"javanese-chess/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Export a game record
+// @Description Returns a versioned, portable game record (players, seed, weights, moves with timestamps and bot decisions, final board and result) of a finished game. The record holds the seed and every draw, so games still in play are refused.
+// @Tags Room
+// @Produce json
+// @Param code path string true "Room Code"
+// @Success 200 {object} record.GameRecord
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/rooms/{code}/export [get]
+func ExportRoomHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rx, ok := finishedRoom(c, rm)
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, record.Export(rx))
+	}
+}
+
+// finishedRoom looks up the room of a record request and answers 404 or 409
+// unless its game is over. Records carry the seed and every drawn card, so a
+// game in play would have its deal, and every future draw, given away.
+func finishedRoom(c *gin.Context, rm *room.Manager) (*shared.Room, bool) {
+	rx, ok := rm.Get(c.Param("code"))
+	if !ok {
+		respondError(c, http.StatusNotFound, "room not found")
+		return nil, false
+	}
+	if !rx.Over() {
+		respondError(c, http.StatusConflict, "game is not over")
+		return nil, false
+	}
+	return rx, true
+}
+
+// @Summary Diff two points of a game
+// @Description Returns the cells changed, captures and hand-size changes between move numbers from and to (0 is the empty board; from may exceed to) of a finished game
+// @Tags Room
+// @Produce json
+// @Param code path string true "Room Code"
+// @Param from query int true "Move number to diff from"
+// @Param to query int true "Move number to diff to"
+// @Success 200 {object} Response{data=record.StateDiff}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/rooms/{code}/diff [get]
+func DiffRoomHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rx, ok := finishedRoom(c, rm)
+		if !ok {
+			return
+		}
+
+		from, errFrom := strconv.Atoi(c.Query("from"))
+		to, errTo := strconv.Atoi(c.Query("to"))
+		if errFrom != nil || errTo != nil {
+			respondError(c, http.StatusBadRequest, "from and to must be integers")
+			return
+		}
+
+		rec := record.Export(rx)
+		diff, err := record.Diff(&rec, from, to)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		respondOK(c, diff)
+	}
+}
+
+// @Summary Verify a game record against the current engine
+// @Description Replays every move of an exported game record through the engine as it plays today, comparing each recorded position hash, and reports the first divergence. A record that diverges is still a 200 with ok false.
+// @Tags Room
+// @Accept json
+// @Produce json
+// @Param record body record.GameRecord true "Exported game record"
+// @Success 200 {object} Response{data=record.Verification}
+// @Failure 400 {object} ErrorResponse
+// @Router /api/records/verify [post]
+func VerifyRecordHandler(c *gin.Context) {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	rec, err := record.Decode(data)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondOK(c, record.Verify(rec))
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+
+	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Resign from a game
+// @Description The player leaves the game: their cards stay on the board but they take no more turns. With one opponent left that opponent wins at once; otherwise the game goes on and the resignation places the player below everyone still playing in the final standings.
+// @Tags Room
+// @Accept json
+// @Produce json
+// @Param request body ResignRequest true "Resigning player"
+// @Success 200 {object} Response{data=ResignResult}
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/resign [post]
+func ResignHandler(rm *room.Manager, hub *ws.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ResignRequest
+		if err := c.BindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid payload")
+			return
+		}
+		if req.PlayerID == "" {
+			respondError(c, http.StatusBadRequest, "player_id is required")
+			return
+		}
+
+		rx, ok := rm.Get(req.RoomCode)
+		if !ok {
+			respondError(c, http.StatusNotFound, "room not found")
+			return
+		}
+		if err := rm.AuthorizeSeat(rx, req.PlayerID, auth.UserID(c), req.SeatSecret); err != nil {
+			respondError(c, http.StatusForbidden, err.Error())
+			return
+		}
+
+		if err := rm.Resign(rx, req.PlayerID); err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// The turn may have passed to a bot
+		if rx.WinnerID == nil && !rx.Draw && rx.Players[rx.TurnIdx].IsBot {
+			hub.ResumeBots(rx.Code)
+		}
+
+		res := ResignResult{RoomState: roomState(rx), WinnerID: rx.WinnerID}
+		if rx.Metrics != nil {
+			res.Standings = rx.Metrics.Standings
+		}
+		respondOK(c, res)
+	}
+}
@@ -0,0 +1,347 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"javanese-chess/internal/apierr"
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/engine"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/room"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Response is the envelope of every successful JSON response. Data holds the
+// endpoint's typed payload and is omitted when there is nothing to return.
+type Response struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// ErrorResponse is the body of every failed request: success is false and
+// error holds a machine-readable code and a message (see package apierr)
+type ErrorResponse = apierr.Body
+
+// respondOK writes data in the success envelope
+func respondOK(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusOK, Response{Success: true, Data: data})
+}
+
+// respondError writes the error envelope, the message translated into the
+// request's language
+func respondError(c *gin.Context, status int, message string) {
+	apierr.Respond(c, status, message)
+}
+
+// abortError writes the translated error envelope and stops the handler chain
+func abortError(c *gin.Context, status int, message string) {
+	apierr.Abort(c, status, message)
+}
+
+// RoomState is the view of a room returned when setting up, joining or
+// resuming a game
+type RoomState struct {
+	RoomCode  string                `json:"room_code"`
+	Status    string                `json:"status"` // lobby, playing, ended, aborted or closed
+	Engine    string                `json:"engine"`
+	TurnOrder []string              `json:"turn_order"`
+	Players   []shared.PublicPlayer `json:"players"` // As the room's broadcast policy allows
+	Board     game.Board            `json:"board"`
+	Match     *shared.Match         `json:"match"`
+	NextTurn  string                `json:"next_turn,omitempty"` // Only while the game is being played
+}
+
+// roomState builds the room view from the room's actual state
+func roomState(rx *shared.Room) RoomState {
+	st := RoomState{
+		RoomCode:  rx.Code,
+		Status:    rx.Status,
+		Engine:    engineName(rx),
+		TurnOrder: rx.TurnOrder,
+		Players:   rx.PlayerView(),
+		Board:     rx.Board,
+		Match:     rx.Match,
+	}
+	if rx.Status == "playing" && rx.WinnerID == nil && !rx.Draw && len(rx.Players) > 0 {
+		st.NextTurn = rx.Players[rx.TurnIdx].ID
+	}
+	return st
+}
+
+// engineName is the rules engine a room plays by, naming the default when
+// the room never chose one
+func engineName(rx *shared.Room) string {
+	if rx.Engine == "" {
+		return engine.Default
+	}
+	return rx.Engine
+}
+
+// JoinResult is the room view for a player who joined a lobby. The seat
+// secret lets their client identify as the new seat over the WebSocket.
+type JoinResult struct {
+	RoomState
+	PlayerID   string `json:"player_id"`
+	SeatSecret string `json:"seat_secret"`
+}
+
+// TakeoverResult is the room view for a player who took over a seat
+type TakeoverResult struct {
+	RoomState
+	PlayerID   string `json:"player_id"`
+	SeatSecret string `json:"seat_secret"`
+	Hand       []int  `json:"hand"`
+}
+
+// RestoreResult is the room view of a game resumed from a snapshot
+type RestoreResult struct {
+	RoomState
+	Moves int `json:"moves"`
+}
+
+// MoveResult reports a move played over REST
+type MoveResult struct {
+	RoomCode string     `json:"room_code"`
+	X        int        `json:"x"`
+	Y        int        `json:"y"`
+	Cell     string     `json:"cell"`
+	Value    int        `json:"value"`
+	Hand     []int      `json:"hand"` // The mover's hand after drawing
+	Board    game.Board `json:"board"`
+	Status   string     `json:"status"`
+	WinnerID *string    `json:"winner_id"`
+	Draw     bool       `json:"draw"`
+	NextTurn string     `json:"next_turn"`
+}
+
+// ResignResult is the room after a player resigned. Standings are set once
+// the resignation ended the game.
+type ResignResult struct {
+	RoomState
+	WinnerID  *string  `json:"winner_id"`
+	Standings []string `json:"standings,omitempty"` // Player IDs from first place to last
+}
+
+// RoomSummary is a room in the public lobby listing
+type RoomSummary struct {
+	RoomCode          string    `json:"room_code"`
+	Host              string    `json:"host"`
+	PlayerCount       int       `json:"player_count"`
+	MaxPlayers        int       `json:"max_players"`
+	CreatedAt         time.Time `json:"created_at"`
+	PasswordProtected bool      `json:"password_protected"`
+	Demo              bool      `json:"demo"` // Bots playing each other for spectators
+}
+
+// roomSummary builds the lobby listing entry of a room
+func roomSummary(rx *shared.Room) RoomSummary {
+	host := ""
+	if len(rx.Players) > 0 {
+		host = rx.Players[0].Name
+	}
+	return RoomSummary{
+		RoomCode:          rx.Code,
+		Host:              host,
+		PlayerCount:       len(rx.Players),
+		MaxPlayers:        config.MaxPlayers,
+		CreatedAt:         rx.CreatedAt,
+		PasswordProtected: rx.HasPassword(),
+		Demo:              rx.Demo,
+	}
+}
+
+// DemoResult is the view of a new demo game and the pause between its moves
+type DemoResult struct {
+	RoomState
+	DelayMs int `json:"delay_ms"`
+}
+
+// RoomPage is one page of the lobby listing
+type RoomPage struct {
+	Rooms []RoomSummary `json:"rooms"`
+	Page
+}
+
+// AdminRoomPage is one page of the operator room list, oldest room first
+type AdminRoomPage struct {
+	Rooms []AdminRoomSummary `json:"rooms"`
+	Page
+}
+
+// AdminRoomSummary is the operator view of a room in the room list
+type AdminRoomSummary struct {
+	Code      string    `json:"code"`
+	Status    string    `json:"status"`
+	Players   []string  `json:"players"`
+	Moves     int       `json:"moves"`
+	WinnerID  *string   `json:"winner_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdminRoomDump is a room's complete state, including hands and history
+type AdminRoomDump struct {
+	Room    *shared.Room      `json:"room"`
+	History []game.MoveRecord `json:"history"`
+}
+
+// RoomEventLog is a room's event log, oldest first. Consistent reports
+// whether folding the log reproduces the live room; Error says why not.
+type RoomEventLog struct {
+	Code       string         `json:"code"`
+	Events     []record.Event `json:"events"`
+	Consistent bool           `json:"consistent"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// RoomDebugLog is a room's recent events, oldest first. Next is the number
+// to pass as since to get only later events; Truncated reports that events
+// after since were dropped before they could be read.
+type RoomDebugLog struct {
+	Code      string          `json:"code"`
+	Events    []roomlog.Entry `json:"events"`
+	Next      uint64          `json:"next"`
+	Truncated bool            `json:"truncated"`
+}
+
+// RoomStatus reports a room's status after an operator action
+type RoomStatus struct {
+	Code   string `json:"code"`
+	Status string `json:"status"`
+}
+
+// ClosedRooms lists the rooms closed by their owner
+type ClosedRooms struct {
+	Count int               `json:"count"`
+	Rooms []room.ClosedRoom `json:"rooms"`
+}
+
+// ChatLog is a room's recent chat, oldest first
+type ChatLog struct {
+	RoomCode string               `json:"room_code"`
+	Count    int                  `json:"count"`
+	Messages []shared.ChatMessage `json:"messages"`
+}
+
+// ConfigReload reports the room defaults in effect after a reload and the
+// file they were read from ("" when the server has no config file)
+type ConfigReload struct {
+	File     string          `json:"file"`
+	Defaults config.Defaults `json:"defaults"`
+}
+
+// WeightsView reports heuristic weights, either the defaults or a room's
+type WeightsView struct {
+	RoomCode     string                  `json:"room_code,omitempty"`
+	Weights      config.HeuristicWeights `json:"weights"`
+	IsCustomized bool                    `json:"is_customized"`
+}
+
+// CoordView is a board coordinate in every supported notation
+type CoordView struct {
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+	Row  int    `json:"row"`
+	Col  int    `json:"col"`
+	Cell string `json:"cell"`
+}
+
+// MoveAnalysis is the heuristic breakdown of a single move
+type MoveAnalysis struct {
+	X         int                `json:"x"`
+	Y         int                `json:"y"`
+	Cell      string             `json:"cell"`
+	Value     int                `json:"value"`
+	PlayerID  string             `json:"player_id"`
+	Legal     bool               `json:"legal"`
+	Reason    string             `json:"reason,omitempty"` // Why the move is illegal
+	Breakdown game.MoveBreakdown `json:"breakdown"`
+}
+
+// PositionAnalysis ranks every legal move in a position, best first
+type PositionAnalysis struct {
+	PlayerID string            `json:"player_id"`
+	Hash     game.PositionHash `json:"hash"` // Position hash of the analysed board
+	Count    int               `json:"count"`
+	Moves    []game.RankedMove `json:"moves"`
+}
+
+// BestMove is the move a bot would play in a client's position, with its
+// heuristic breakdown. Move is null when the hand has no legal placement.
+type BestMove struct {
+	PlayerID   string            `json:"player_id"`
+	Engine     string            `json:"engine"`
+	Hash       game.PositionHash `json:"hash"`       // Position hash of the board played on
+	Candidates int               `json:"candidates"` // Legal moves evaluated
+	Move       *game.RankedMove  `json:"move"`
+	RunnerUp   *game.RankedMove  `json:"runner_up,omitempty"` // Best move other than the one chosen
+}
+
+// CustomAnalysis ranks a position with caller-supplied weights. Count is the
+// number of legal moves before the limit is applied.
+type CustomAnalysis struct {
+	PlayerID string                   `json:"player_id"`
+	Hash     game.PositionHash        `json:"hash"` // Position hash of the analysed board
+	Weights  *config.HeuristicWeights `json:"weights"`
+	Count    int                      `json:"count"`
+	Moves    []CustomRankedMove       `json:"moves"`
+}
+
+// HintResult holds the suggested moves for a human player
+type HintResult struct {
+	PlayerID string            `json:"player_id"`
+	YourTurn bool              `json:"your_turn"`
+	Moves    []game.RankedMove `json:"moves"`
+}
+
+// AuthResult is an account together with a fresh login token
+type AuthResult struct {
+	User  *auth.User `json:"user"`
+	Token string     `json:"token"`
+}
+
+// LeaderboardEntry is a rated player's position on the leaderboard
+type LeaderboardEntry struct {
+	Rank     int     `json:"rank"`
+	PlayerID string  `json:"player_id"`
+	Name     string  `json:"name"`
+	Rating   float64 `json:"rating"`
+	Games    int     `json:"games"`
+	Wins     int     `json:"wins"`
+}
+
+// LeaderboardPage is one page of the leaderboard
+type LeaderboardPage struct {
+	Entries []LeaderboardEntry `json:"entries"`
+	Page
+}
+
+// PlayerStats is a rated player's lifetime record
+type PlayerStats struct {
+	PlayerID      string  `json:"player_id"`
+	Name          string  `json:"name"`
+	Rating        float64 `json:"rating"`
+	Games         int     `json:"games"`
+	Wins          int     `json:"wins"`
+	Losses        int     `json:"losses"`
+	Draws         int     `json:"draws"`
+	AvgMoves      float64 `json:"avg_moves"`
+	AvgGameLength float64 `json:"avg_game_length"`
+	AvgThinkTime  float64 `json:"avg_think_time"`
+	Captures      int     `json:"captures"`
+	Skips         int     `json:"skips"`
+	BestLine      int     `json:"best_line"`
+}
+
+// QuickPlayTicket is a waiting quick play queue entry
+type QuickPlayTicket struct {
+	TicketID   string    `json:"ticket_id"`
+	PlayerName string    `json:"player_name"`
+	QueuedAt   time.Time `json:"queued_at"`
+	Waiting    int       `json:"waiting"`
+}
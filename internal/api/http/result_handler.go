@@ -0,0 +1,35 @@
+package http
+
+import (
+	"net/http"
+
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get a finished game's result
+// @Description Returns the final score of the room's last game: each player's placement, best line and its card sum, total owned sum, captures and cards left in hand and deck, from first place to last
+// @Tags Room
+// @Produce json
+// @Param code path string true "Room Code"
+// @Success 200 {object} Response{data=room.GameResult}
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/rooms/{code}/result [get]
+func RoomResultHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rx, ok := rm.Get(c.Param("code"))
+		if !ok {
+			respondError(c, http.StatusNotFound, "room not found")
+			return
+		}
+
+		result, err := rm.Result(rx)
+		if err != nil {
+			respondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		respondOK(c, result)
+	}
+}
@@ -1,8 +1,19 @@
 package http
 
 import (
+	"log"
+	"net/http"
+	"slices"
+
 	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/experiment"
+	"javanese-chess/internal/matchmaking"
+	"javanese-chess/internal/ratelimit"
 	"javanese-chess/internal/room"
+	"javanese-chess/internal/tournament"
+	"javanese-chess/internal/tracing"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -10,19 +21,111 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter(mgr *room.Manager, s room.Store, hub *ws.Hub) *gin.Engine {
-	r := gin.Default()
+// SetupRouter builds the HTTP API from cfg: its profile, proxies, CORS
+// origins, rate limits and admin token
+func SetupRouter(cfg *config.Config, mgr *room.Manager, s room.Store, hub *ws.Hub, queue *matchmaking.Queue, tournaments *tournament.Service, experiments *experiment.Service, authSvc *auth.Service) *gin.Engine {
+	profile := cfg.Profile
+	gin.SetMode(profile.GinMode)
+
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		abortError(c, http.StatusInternalServerError, "internal server error")
+	}))
+	r.Use(tracing.Middleware())
+	r.Use(localeMiddleware(mgr))
+	r.NoRoute(func(c *gin.Context) {
+		respondError(c, http.StatusNotFound, "no such endpoint")
+	})
+	if logger := requestLogger(profile.RequestLogging); logger != nil {
+		r.Use(logger)
+	}
+
+	// Client addresses, used by rate limiting and tracing, come from
+	// X-Forwarded-For only when the request passed through a trusted proxy
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Printf("Trusting no proxies: %v", err)
+		r.SetTrustedProxies(nil)
+	}
 
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://98.70.41.170:5000", "http://localhost:5173"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept"},
-		AllowCredentials: true,
+		AllowOriginFunc: func(origin string) bool {
+			return config.OriginAllowed(cfg.CORSOrigins, origin)
+		},
+		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders: cfg.CORSHeaders,
+		// Credentials are never shared with every origin, even when "*"
+		// lets every origin read the API
+		AllowCredentials: !slices.Contains(cfg.CORSOrigins, "*"),
 	}))
+	hub.SetAllowedOrigins(cfg.CORSOrigins)
+
+	// Throttle request and action spam before it reaches handlers and broadcasts
+	r.Use(ratelimit.Middleware(ratelimit.New(cfg.HTTPRateLimit, cfg.HTTPRateBurst)))
+	hub.SetActionLimiter(ratelimit.New(cfg.WSRateLimit, cfg.WSRateBurst))
+
+	// Attach the authenticated user (if any) to every request, including /ws upgrades
+	r.Use(auth.Middleware(authSvc))
+
+	// Accounts
+	authHandler := NewAuthHandler(authSvc)
+	authGroup := r.Group("/api/auth")
+	{
+		authGroup.POST("/register", authHandler.RegisterHandler)
+		authGroup.POST("/login", authHandler.LoginHandler)
+		authGroup.GET("/me", auth.RequireAuth(), authHandler.MeHandler)
+	}
 
 	// Existing handlers (not using store directly)
-	r.POST("/api/play", PlayHandler(mgr, hub))
+	r.POST("/api/play", PlayHandler(mgr))
 	r.POST("/api/join", JoinRoomHandler(mgr, hub))
+	r.POST("/api/takeover", TakeoverHandler(mgr))
+	r.POST("/api/move", MoveHandler(mgr, hub))
+	r.POST("/api/resign", ResignHandler(mgr, hub))
+	r.POST("/api/hands", SetHandsHandler(mgr))
+	r.POST("/api/demo", DemoHandler(mgr, hub))
+	r.GET("/api/rooms", ListRoomsHandler(mgr))
+	r.POST("/api/rooms/close", auth.RequireAuth(), CloseOwnedRoomsHandler(mgr))
+	r.POST("/api/rooms/restore", auth.RequireAuth(), RestoreRoomHandler(mgr, hub))
+	r.POST("/api/rooms/:code/snapshot", auth.RequireAuth(), SnapshotRoomHandler(mgr))
+	r.GET("/api/rooms/:code/export", ExportRoomHandler(mgr))
+	r.GET("/api/rooms/:code/diff", DiffRoomHandler(mgr))
+	r.GET("/api/rooms/:code/hint", HintHandler(mgr))
+	r.GET("/api/rooms/:code/chat", ChatLogHandler(mgr))
+	r.GET("/api/rooms/:code/rules", RoomRulesHandler(mgr))
+	r.GET("/api/rooms/:code/result", RoomResultHandler(mgr))
+	r.GET("/api/rooms/:code/stats", RoomStatsHandler(mgr))
+
+	// Engine regression check for exported games
+	r.POST("/api/records/verify", VerifyRecordHandler)
+
+	// Coordinate conversion helper
+	r.GET("/api/board/coords", CoordsHandler)
+
+	// Heuristic explanation
+	r.POST("/api/analyze/move", AnalyzeMoveHandler(mgr))
+	r.POST("/api/analyze/position", AnalyzePositionHandler)
+	r.POST("/api/analyze/custom", AnalyzeCustomHandler)
+
+	// Stateless bot for clients that keep the game themselves
+	r.POST("/api/bot/best-move", BestMoveHandler)
+
+	// Ratings
+	r.GET("/api/leaderboard", LeaderboardHandler(mgr))
+	r.GET("/api/players/:id/stats", PlayerStatsHandler(mgr))
+
+	// Matchmaking
+	r.POST("/api/quickplay", QuickPlayHandler(queue))
+	r.DELETE("/api/quickplay/:ticket", CancelQuickPlayHandler(queue))
+
+	// Tournaments
+	r.POST("/api/tournaments", auth.RequireAuth(), CreateTournamentHandler(tournaments))
+	r.GET("/api/tournaments/:id", GetTournamentHandler(tournaments))
+	r.POST("/api/tournaments/:id/players", RegisterEntrantHandler(tournaments))
+	r.POST("/api/tournaments/:id/start", auth.RequireAuth(), StartTournamentHandler(tournaments))
+	r.GET("/api/tournaments/:id/standings", TournamentStandingsHandler(tournaments))
+
+	// Heuristic weight experiments
+	r.GET("/api/experiments/:id/results", ExperimentResultsHandler(experiments))
 
 	// Config routes (room-based)
 	configHandler := NewConfigHandler(s, hub)
@@ -32,10 +135,27 @@ func SetupRouter(mgr *room.Manager, s room.Store, hub *ws.Hub) *gin.Engine {
 		configGroup.GET("/weights/room", configHandler.GetRoomWeightsHandler)
 	}
 
-	// Debug route to view logs
-	r.GET("/api/debug/logs", func(c *gin.Context) {
-		c.File("javanese-chess.log")
-	})
+	// Operator API (disabled unless ADMIN_TOKEN is set)
+	if token := cfg.AdminToken; token != "" {
+		admin := NewAdminHandler(mgr)
+		adminGroup := r.Group("/api/admin", requireAdminToken(token))
+		{
+			adminGroup.GET("/rooms", admin.ListRoomsHandler)
+			adminGroup.GET("/rooms/:code", admin.GetRoomHandler)
+			adminGroup.GET("/rooms/:code/events", admin.RoomEventsHandler)
+			adminGroup.POST("/rooms/:code/end", admin.EndRoomHandler)
+			adminGroup.PUT("/weights/default", admin.SetDefaultWeightsHandler)
+			adminGroup.POST("/experiments", StartExperimentHandler(experiments))
+			adminGroup.POST("/experiments/:id/stop", StopExperimentHandler(experiments))
+			adminGroup.POST("/config/reload", admin.ReloadConfigHandler)
+		}
+	}
+
+	// Debug routes for operators (never exposed in production)
+	if token := cfg.AdminToken; profile.DebugEndpoints && token != "" {
+		debug := NewAdminHandler(mgr)
+		r.GET("/api/debug/rooms/:code/events", requireAdminToken(token), debug.RoomDebugEventsHandler)
+	}
 
 	// WebSocket
 	r.GET("/ws", hub.HandleWS)
@@ -44,3 +164,19 @@ func SetupRouter(mgr *room.Manager, s room.Store, hub *ws.Hub) *gin.Engine {
 
 	return r
 }
+
+// requestLogger returns the access log middleware for a verbosity level
+func requestLogger(level string) gin.HandlerFunc {
+	switch level {
+	case "none":
+		return nil
+	case "errors":
+		return gin.LoggerWithConfig(gin.LoggerConfig{
+			Skip: func(c *gin.Context) bool {
+				return c.Writer.Status() < http.StatusBadRequest
+			},
+		})
+	default:
+		return gin.Logger()
+	}
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"net/http"
+
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get a room's rules
+// @Description Returns the fully resolved rule set in effect for the room (board size, win length, adjacency, overwrite rule, clock, deck and variants) so clients can render rule summaries and check moves locally
+// @Tags Room
+// @Produce json
+// @Param code path string true "Room Code"
+// @Success 200 {object} Response{data=room.Rules}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/rooms/{code}/rules [get]
+func RoomRulesHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rx, ok := rm.Get(c.Param("code"))
+		if !ok {
+			respondError(c, http.StatusNotFound, "room not found")
+			return
+		}
+
+		respondOK(c, room.RulesOf(rx))
+	}
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Snapshot a room
+// @Description Returns the complete room state (board, decks, hands, turn order, move history and random state) so the game can be stored externally and resumed with /api/rooms/restore. Only the authenticated room owner may take snapshots, since they reveal every hand and deck; games in progress that hide hands cannot be snapshotted until they are over.
+// @Tags Room
+// @Produce json
+// @Param code path string true "Room Code"
+// @Success 200 {object} record.Snapshot
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/rooms/{code}/snapshot [post]
+func SnapshotRoomHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rx, ok := rm.Get(c.Param("code"))
+		if !ok {
+			respondError(c, http.StatusNotFound, "room not found")
+			return
+		}
+		if rx.OwnerID == "" || rx.OwnerID != auth.UserID(c) {
+			respondError(c, http.StatusForbidden, "only the room owner can take snapshots")
+			return
+		}
+		// A seated owner would read their opponents' hands
+		if rx.Status == "playing" && !rx.Over() && rx.Policy.PrivateHands() {
+			respondError(c, http.StatusConflict, "cannot snapshot a game in progress that hides hands")
+			return
+		}
+
+		c.JSON(http.StatusOK, record.TakeSnapshot(rx))
+	}
+}
+
+// @Summary Restore a room from a snapshot
+// @Description Recreates a room from a snapshot taken with /api/rooms/{code}/snapshot and resumes the game where it stopped. The snapshot's room code is used unless code is given; the code must not be in use. The caller becomes the room owner. Restored rooms are unrated, since nothing proves the snapshot was taken on this server.
+// @Tags Room
+// @Accept json
+// @Produce json
+// @Param code query string false "Room code for the restored room"
+// @Param snapshot body record.Snapshot true "Room snapshot"
+// @Success 200 {object} Response{data=RestoreResult}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/rooms/restore [post]
+func RestoreRoomHandler(rm *room.Manager, hub *ws.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		rx, err := record.Restore(data)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if code := c.Query("code"); code != "" {
+			rx.Code = code
+			if rx.RoomConfig != nil {
+				rx.RoomConfig.RoomCode = code
+			}
+		}
+
+		if err := rm.RestoreRoom(rx, auth.UserID(c)); err != nil {
+			respondError(c, http.StatusConflict, err.Error())
+			return
+		}
+
+		// Bots pick up the game if it is their turn
+		if rx.Status == "playing" && rx.WinnerID == nil && !rx.Draw && rx.Players[rx.TurnIdx].IsBot {
+			hub.ResumeBots(rx.Code)
+		}
+
+		respondOK(c, RestoreResult{RoomState: roomState(rx), Moves: len(rx.History)})
+	}
+}
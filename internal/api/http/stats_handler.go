@@ -0,0 +1,28 @@
+package http
+
+import (
+	"net/http"
+
+	"javanese-chess/internal/room"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get players' in-game stats
+// @Description Returns each player's stats for the room's current or last game: placements, total and average think time, cards placed by value and their average, captures and blocked threes. The post-game screen shows them; game_over carries the same report.
+// @Tags Room
+// @Produce json
+// @Param code path string true "Room Code"
+// @Success 200 {object} Response{data=room.GameStats}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/rooms/{code}/stats [get]
+func RoomStatsHandler(rm *room.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rx, ok := rm.Get(c.Param("code"))
+		if !ok {
+			respondError(c, http.StatusNotFound, "room not found")
+			return
+		}
+		respondOK(c, rm.Stats(rx))
+	}
+}
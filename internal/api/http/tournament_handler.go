@@ -0,0 +1,157 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/tournament"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateTournamentRequest represents the payload for /api/tournaments.
+type CreateTournamentRequest struct {
+	Name   string `json:"name"`
+	Format string `json:"format"` // "swiss" (default) or "round_robin"
+	Rounds int    `json:"rounds"` // Swiss only; 0 plays ceil(log2 entrants) rounds
+}
+
+// RegisterEntrantRequest represents the payload for /api/tournaments/{id}/players.
+type RegisterEntrantRequest struct {
+	Name string `json:"name"`
+	Bot  bool   `json:"bot"`
+}
+
+// respondTournamentError maps tournament errors to a status: 404 for an
+// unknown tournament, 403 for someone else's, 409 once registration is
+// closed, 400 otherwise
+func respondTournamentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, tournament.ErrNotFound):
+		respondError(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, tournament.ErrNotOwner):
+		respondError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, tournament.ErrNotRegistering):
+		respondError(c, http.StatusConflict, err.Error())
+	default:
+		respondError(c, http.StatusBadRequest, err.Error())
+	}
+}
+
+// @Summary Create a tournament
+// @Description Open a swiss or round robin tournament for registration. The authenticated caller owns it and is the only one who may start it. Subscribe to the tournament ID over WebSocket (/ws?room_code=<id>) to receive tournament_round and tournament_over
+// @Tags Tournament
+// @Accept json
+// @Produce json
+// @Param request body CreateTournamentRequest true "Tournament settings"
+// @Success 200 {object} Response{data=tournament.Tournament}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /api/tournaments [post]
+func CreateTournamentHandler(ts *tournament.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateTournamentRequest
+		if err := c.BindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid payload")
+			return
+		}
+
+		t, err := ts.Create(auth.UserID(c), req.Name, req.Format, req.Rounds)
+		if err != nil {
+			respondTournamentError(c, err)
+			return
+		}
+		respondOK(c, t)
+	}
+}
+
+// @Summary Register for a tournament
+// @Description Enter a player or a bot before the tournament starts. Human entrants subscribe to the returned entrant ID over WebSocket (/ws?room_code=<entrant id>) to receive tournament_pairing with the room and player ID of each game
+// @Tags Tournament
+// @Accept json
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Param request body RegisterEntrantRequest true "Entrant"
+// @Success 200 {object} Response{data=tournament.Entrant}
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/tournaments/{id}/players [post]
+func RegisterEntrantHandler(ts *tournament.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req RegisterEntrantRequest
+		if err := c.BindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid payload")
+			return
+		}
+
+		e, err := ts.Register(c.Param("id"), req.Name, req.Bot)
+		if err != nil {
+			respondTournamentError(c, err)
+			return
+		}
+		respondOK(c, e)
+	}
+}
+
+// @Summary Start a tournament
+// @Description Close registration and pair the first round; every game gets a room of its own. Only the tournament owner may start it.
+// @Tags Tournament
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} Response{data=tournament.Tournament}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /api/tournaments/{id}/start [post]
+func StartTournamentHandler(ts *tournament.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, err := ts.Start(c.Param("id"), auth.UserID(c))
+		if err != nil {
+			respondTournamentError(c, err)
+			return
+		}
+		respondOK(c, t)
+	}
+}
+
+// @Summary Get a tournament bracket
+// @Description Returns the entrants and every round paired so far, with each game's room and result
+// @Tags Tournament
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} Response{data=tournament.Tournament}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/tournaments/{id} [get]
+func GetTournamentHandler(ts *tournament.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, err := ts.Get(c.Param("id"))
+		if err != nil {
+			respondTournamentError(c, err)
+			return
+		}
+		respondOK(c, t)
+	}
+}
+
+// @Summary Get tournament standings
+// @Description Ranks the entrants on finished games: a win or bye scores 1, a draw 0.5; ties are broken by Buchholz, then wins
+// @Tags Tournament
+// @Produce json
+// @Param id path string true "Tournament ID"
+// @Success 200 {object} Response{data=[]tournament.Standing}
+// @Failure 404 {object} ErrorResponse
+// @Router /api/tournaments/{id}/standings [get]
+func TournamentStandingsHandler(ts *tournament.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		table, err := ts.Standings(c.Param("id"))
+		if err != nil {
+			respondTournamentError(c, err)
+			return
+		}
+		respondOK(c, table)
+	}
+}
@@ -0,0 +1,134 @@
+package ws
+
+import (
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Connections opened with /ws?batch=<ms> have their broadcasts held for up
+// to that many milliseconds and sent as one "batch" frame listing the held
+// events in order, so a burst such as several bot moves in a row costs a
+// mobile client one wakeup. Replies to the client's own requests are not
+// held; they go out at once, after any events already held.
+
+// MaxBatchWindow caps how long a connection may ask for events to be held
+const MaxBatchWindow = 500 * time.Millisecond
+
+// BatchData holds events sent in one frame, oldest first. Each event has the
+// shape of a single broadcast.
+type BatchData struct {
+	Events []interface{} `json:"events"`
+}
+
+// batchWindow reads the window a connection asked for; zero turns batching off
+func batchWindow(query string) time.Duration {
+	ms, err := strconv.Atoi(query)
+	if err != nil || ms <= 0 {
+		return 0
+	}
+	return min(time.Duration(ms)*time.Millisecond, MaxBatchWindow)
+}
+
+// batcher holds one connection's broadcasts for its window. It is the only
+// writer of its connection, so held and direct messages never interleave.
+type batcher struct {
+	conn   *websocket.Conn
+	window time.Duration
+
+	mu     sync.Mutex
+	held   []interface{}
+	timer  *time.Timer
+	closed bool
+}
+
+func newBatcher(conn *websocket.Conn, window time.Duration) *batcher {
+	return &batcher{conn: conn, window: window}
+}
+
+// push holds a broadcast, starting the window if none is open
+func (b *batcher) push(msg interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.held = append(b.held, msg)
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.window, b.flush)
+	}
+}
+
+// flush sends what is held once the window ends. A connection that cannot
+// be written to is closed; its read loop then cleans up.
+func (b *batcher) flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.flushLocked(); err != nil {
+		log.Printf("Failed to send batched messages: %v", err)
+		b.conn.Close()
+	}
+}
+
+// flushLocked sends the held events, a lone event as it is
+func (b *batcher) flushLocked() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	held := b.held
+	b.held = nil
+	switch len(held) {
+	case 0:
+		return nil
+	case 1:
+		return b.conn.WriteJSON(held[0])
+	}
+	return b.conn.WriteJSON(reply{V: ProtocolVersion, Action: "batch", Data: BatchData{Events: held}})
+}
+
+// send writes a message at once, after the events already held
+func (b *batcher) send(msg interface{}) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.flushLocked(); err != nil {
+		return err
+	}
+	return b.conn.WriteJSON(msg)
+}
+
+// stop drops what is held once the connection is gone
+func (b *batcher) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.held = nil
+}
+
+// write sends a broadcast to one connection, held by its batcher when it
+// batches. Callers hold h.mu.
+func (h *Hub) write(conn *websocket.Conn, msg interface{}) error {
+	if b := h.batchers[conn]; b != nil {
+		b.push(msg)
+		return nil
+	}
+	return conn.WriteJSON(msg)
+}
+
+// send writes a direct message to one connection at once
+func (h *Hub) send(conn *websocket.Conn, msg interface{}) error {
+	h.mu.RLock()
+	b := h.batchers[conn]
+	h.mu.RUnlock()
+	if b != nil {
+		return b.send(msg)
+	}
+	return conn.WriteJSON(msg)
+}
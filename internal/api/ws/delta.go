@@ -0,0 +1,168 @@
+package ws
+
+import (
+	"javanese-chess/internal/game"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// Every broadcast that carries a room's board gets the next state version of
+// that room. Connections opened with /ws?board=delta receive only the cells
+// changed since the previous version instead of the whole board; a client
+// that misses a version asks for the full board with sync_request.
+
+// Board modes a connection can ask for
+const (
+	BoardModeFull  = "full"
+	BoardModeDelta = "delta"
+)
+
+// Payload keys of versioned board broadcasts
+const (
+	keyBoard      = "board"
+	keyBoardDelta = "board_delta"
+	keyVersion    = "version"
+)
+
+// boardState is the board a room last broadcast and its version
+type boardState struct {
+	version int
+	board   game.Board
+}
+
+// CellChange is the new state of one cell
+type CellChange struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	game.Cell
+}
+
+// BoardDelta turns the board of version From into the board of version
+// Version
+type BoardDelta struct {
+	From    int               `json:"from"`
+	Version int               `json:"version"`
+	Cells   []CellChange      `json:"cells"`
+	Hash    game.PositionHash `json:"hash"`
+}
+
+// BoardSyncData is the full board of a room at a version
+type BoardSyncData struct {
+	RoomCode string     `json:"room_code"`
+	Version  int        `json:"version"`
+	Board    game.Board `json:"board"`
+}
+
+// SyncRequestData asks for the room's full board
+type SyncRequestData struct{}
+
+func (d *SyncRequestData) Validate() error {
+	return nil
+}
+
+// versionBoard stamps a broadcast carrying a board with the room's next
+// state version. It returns the payload for full board clients and, when the
+// board can be sent as a delta, the payload for delta clients; otherwise
+// delta clients get the full payload too.
+func (h *Hub) versionBoard(roomCode string, data interface{}) (full, delta interface{}) {
+	var payload map[string]interface{}
+	switch p := data.(type) {
+	case gin.H:
+		payload = p
+	case map[string]interface{}:
+		payload = p
+	}
+	board, ok := payload[keyBoard].(game.Board)
+	if !ok {
+		return data, nil
+	}
+
+	h.boardsMu.Lock()
+	prev, seen := h.boards[roomCode]
+	next := &boardState{version: prev.versionOrZero() + 1, board: board.Clone()}
+	h.boards[roomCode] = next
+	h.boardsMu.Unlock()
+
+	fullPayload := withKeys(payload, map[string]interface{}{keyVersion: next.version})
+	if !seen || !sameShape(prev.board, board) {
+		return fullPayload, nil
+	}
+
+	deltaPayload := withKeys(payload, map[string]interface{}{
+		keyVersion:    next.version,
+		keyBoardDelta: diffBoards(prev.board, board, prev.version, next.version),
+	})
+	delete(deltaPayload, keyBoard)
+	return fullPayload, deltaPayload
+}
+
+func (s *boardState) versionOrZero() int {
+	if s == nil {
+		return 0
+	}
+	return s.version
+}
+
+// boardSync returns the room's board as last broadcast. Rooms that have not
+// broadcast a board yet start at version 0 with their current board.
+func (h *Hub) boardSync(roomCode string) (BoardSyncData, bool) {
+	h.boardsMu.Lock()
+	defer h.boardsMu.Unlock()
+
+	state, ok := h.boards[roomCode]
+	if !ok {
+		room, found := h.roomManager.Get(roomCode)
+		if !found {
+			return BoardSyncData{}, false
+		}
+		state = &boardState{board: room.Board.Clone()}
+		h.boards[roomCode] = state
+	}
+	return BoardSyncData{RoomCode: roomCode, Version: state.version, Board: state.board}, true
+}
+
+// sendBoardSync sends the room's full board to one connection
+func (h *Hub) sendBoardSync(conn *websocket.Conn, roomCode string) bool {
+	sync, ok := h.boardSync(roomCode)
+	if !ok {
+		return false
+	}
+	h.send(conn, reply{V: ProtocolVersion, Action: "board_sync", Data: sync})
+	return true
+}
+
+// wantsDelta reports whether the connection asked for delta boards
+func (h *Hub) wantsDelta(conn *websocket.Conn) bool {
+	return h.boardModes[conn] == BoardModeDelta
+}
+
+// sameShape reports whether b can be reached from a by changing cells only
+func sameShape(a, b game.Board) bool {
+	return a.Size == b.Size && a.LockAfter == b.LockAfter && a.MaxCard == b.MaxCard &&
+		a.OwnOverwrite == b.OwnOverwrite && len(a.Cells) == len(b.Cells) && slices.Equal(a.Seats, b.Seats)
+}
+
+// diffBoards lists the cells of b that differ from a
+func diffBoards(a, b game.Board, from, version int) BoardDelta {
+	delta := BoardDelta{From: from, Version: version, Cells: []CellChange{}, Hash: b.Hash}
+	b.Each(func(c game.Coord, cell game.Cell) {
+		if !a.Contains(c) || a.At(c) != cell {
+			delta.Cells = append(delta.Cells, CellChange{X: c.X, Y: c.Y, Cell: cell})
+		}
+	})
+	return delta
+}
+
+// withKeys copies payload with extra keys set
+func withKeys(payload map[string]interface{}, extra map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(payload)+len(extra))
+	for k, v := range payload {
+		out[k] = v
+	}
+	for k, v := range extra {
+		out[k] = v
+	}
+	return out
+}
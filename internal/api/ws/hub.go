@@ -1,10 +1,22 @@
 package ws
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/i18n"
+	"javanese-chess/internal/ratelimit"
+	"javanese-chess/internal/shared"
+	"javanese-chess/internal/tracing"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -13,21 +25,79 @@ import (
 type Hub struct {
 	mu          sync.RWMutex
 	rooms       map[string]map[*websocket.Conn]struct{}
+	players     map[*websocket.Conn]string   // Connection -> identified player ID
+	secrets     map[*websocket.Conn]string   // Connection -> seat secret shown when identifying
+	users       map[*websocket.Conn]string   // Connection -> authenticated user ID
+	boardModes  map[*websocket.Conn]string   // Connection -> BoardModeFull or BoardModeDelta
+	langs       map[*websocket.Conn]string   // Connection -> language asked for on connect, see locale.go
+	batchers    map[*websocket.Conn]*batcher // Connection -> held broadcasts, see batch.go
 	roomManager RoomManager
+	limiter     *ratelimit.Limiter // Per-player action limit; nil means unlimited
+	upgrader    websocket.Upgrader
+
+	links       map[string]*linkStats  // Player ID -> RTT aggregate
+	graceTimers map[string]*time.Timer // "room/player" -> pending seat hand-over
+
+	boardsMu sync.Mutex
+	boards   map[string]*boardState // Room code -> last broadcast board, see delta.go
+
+	subs subscribers // Listeners outside WebSocket connections, see subscribe.go
 }
 
 func NewHub(roomManager RoomManager) *Hub {
-	log.Printf("Initializing Hub with RoomManager: %+v", roomManager)
+	// Never print the manager: it holds the config and with it the JWT secret
+	log.Printf("Initializing Hub")
 	return &Hub{
 		rooms:       make(map[string]map[*websocket.Conn]struct{}),
+		players:     make(map[*websocket.Conn]string),
+		secrets:     make(map[*websocket.Conn]string),
+		users:       make(map[*websocket.Conn]string),
+		boardModes:  make(map[*websocket.Conn]string),
+		langs:       make(map[*websocket.Conn]string),
+		batchers:    make(map[*websocket.Conn]*batcher),
 		roomManager: roomManager,
+		links:       make(map[string]*linkStats),
+		graceTimers: make(map[string]*time.Timer),
+		boards:      make(map[string]*boardState),
+		// Without a CheckOrigin the upgrader allows only the server's own
+		// origin, until SetAllowedOrigins names others
+		upgrader: websocket.Upgrader{
+			// Negotiate permessage-deflate with clients that offer it; board
+			// broadcasts are repetitive JSON and compress well
+			EnableCompression: true,
+		},
+	}
+}
+
+// SetAllowedOrigins lets WebSocket upgrades come from the given origins,
+// which may use the wildcards of config.OriginAllowed, besides the server's
+// own origin and clients that send none. Call it before serving.
+func (h *Hub) SetAllowedOrigins(origins []string) {
+	h.upgrader.CheckOrigin = func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" || config.OriginAllowed(origins, origin) {
+			return true
+		}
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
 	}
 }
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins
-	},
+// SetActionLimiter rate limits incoming WebSocket actions per player (per
+// connection until the connection identifies as a player)
+func (h *Hub) SetActionLimiter(l *ratelimit.Limiter) {
+	h.limiter = l
+}
+
+// allowAction reports whether the connection may perform another action
+func (h *Hub) allowAction(conn *websocket.Conn) bool {
+	h.mu.RLock()
+	key := "player:" + h.players[conn]
+	if h.players[conn] == "" {
+		key = "conn:" + conn.RemoteAddr().String()
+	}
+	h.mu.RUnlock()
+	return h.limiter.Allow(key)
 }
 
 func (h *Hub) HandleWS(c *gin.Context) {
@@ -36,7 +106,7 @@ func (h *Hub) HandleWS(c *gin.Context) {
 	roomCode := c.Query("room_code")
 	// Room code is now optional - it can be provided later via room_created action
 
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("Failed to upgrade connection: %v", err)
 		return
@@ -54,63 +124,168 @@ func (h *Hub) HandleWS(c *gin.Context) {
 		h.mu.Unlock()
 	}
 
+	// Remember who authenticated the upgrade (empty for anonymous clients)
+	if userID := auth.UserID(c); userID != "" {
+		h.mu.Lock()
+		h.users[conn] = userID
+		h.mu.Unlock()
+	}
+
+	// Clients on slow links can ask for board deltas instead of full boards
+	boardMode := BoardModeFull
+	if c.Query("board") == BoardModeDelta {
+		boardMode = BoardModeDelta
+	}
+	h.mu.Lock()
+	h.boardModes[conn] = boardMode
+	h.mu.Unlock()
+
+	// Clients on mobile links can have bursts of broadcasts batched
+	if window := batchWindow(c.Query("batch")); window > 0 {
+		h.mu.Lock()
+		h.batchers[conn] = newBatcher(conn, window)
+		h.mu.Unlock()
+	}
+
+	// Errors go out in the language the client asks for, if we speak it
+	if lang := i18n.Negotiate(c.Query("lang"), c.GetHeader("Accept-Language")); lang != "" {
+		h.mu.Lock()
+		h.langs[conn] = lang
+		h.mu.Unlock()
+	}
+
 	// Track current room for this connection
 	currentRoom := roomCode
 
+	// Optionally bind the connection to a player for private messages
+	if playerID := c.Query("player_id"); playerID != "" {
+		if err := h.identify(conn, currentRoom, playerID, c.Query("seat_secret")); err != nil {
+			h.sendError(conn, err.Error())
+		}
+	}
+
+	// Delta clients start from the room's current board
+	if boardMode == BoardModeDelta && currentRoom != "" {
+		h.sendBoardSync(conn, currentRoom)
+	}
+
 	defer func() {
 		h.mu.Lock()
 		if currentRoom != "" {
 			delete(h.rooms[currentRoom], conn)
 		}
+		playerID := h.players[conn]
+		delete(h.players, conn)
+		delete(h.secrets, conn)
+		delete(h.users, conn)
+		delete(h.boardModes, conn)
+		delete(h.langs, conn)
+		if b := h.batchers[conn]; b != nil {
+			b.stop()
+			delete(h.batchers, conn)
+		}
 		h.mu.Unlock()
 		_ = conn.Close()
+
+		h.playerLeft(currentRoom, playerID)
 	}()
 
 	for {
-		var msg struct {
-			Action string      `json:"action"`
-			Data   interface{} `json:"data"`
-		}
-		if err := conn.ReadJSON(&msg); err != nil {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
 			log.Printf("Error reading WebSocket message: %v", err)
 			break
 		}
 
-		// Process the action
-		switch msg.Action {
-		case "room_created":
-			// Extract room code from data
-			newRoomCode := h.handleRoomCreated(conn, &currentRoom, msg.Data)
-			if newRoomCode != "" {
-				currentRoom = newRoomCode
-			}
-		case "human_move":
-			h.handleHumanMove(currentRoom, msg.Data)
-		case "bot_move":
-			// Trigger bot move explicitly if requested (optional feature)
-			room, ok := h.roomManager.Get(currentRoom)
-			if !ok {
-				log.Printf("Room not found: %s", currentRoom)
-				continue
-			}
-			currentPlayer := room.Players[room.TurnIdx]
-			if currentPlayer.IsBot {
-				if botMove, err := h.roomManager.BotMove(room, currentPlayer.ID); err == nil {
-					h.Broadcast(currentRoom, "bot_move", gin.H{
-						"bot_id": currentPlayer.ID,
-						"x":      botMove.X,
-						"y":      botMove.Y,
-						"card":   botMove.Card,
-						"board":  room.Board,
-					})
-				} else {
-					log.Printf("Failed to process bot move: %v", err)
-				}
-			}
-		default:
-			log.Printf("Unknown action: %s", msg.Action)
+		env, payload, err := decodeMessage(raw)
+		if err != nil {
+			log.Printf("Rejected WebSocket message: %v", err)
+			h.sendReplyError(conn, env, err)
+			continue
+		}
+
+		if !h.allowAction(conn) {
+			h.send(conn, reply{V: ProtocolVersion, Action: "rate_limited", Data: AckData{
+				RequestID: env.RequestID,
+				Action:    env.Action,
+			}})
+			continue
+		}
+
+		// Pings are answered with a pong instead of an ack
+		if ping, ok := payload.(*PingData); ok {
+			h.handlePing(conn, currentRoom, env, ping)
+			continue
+		}
+
+		ctx, span := tracing.StartKind(context.Background(), "ws "+env.Action, tracing.KindServer,
+			tracing.String("ws.action", env.Action),
+			tracing.String("room.code", currentRoom),
+		)
+		err = h.dispatch(ctx, conn, &currentRoom, env.Action, payload)
+		span.RecordError(err)
+		span.End()
+		if err != nil {
+			log.Printf("ERROR: %s failed: %v", env.Action, err)
+			h.sendReplyError(conn, env, err)
+			continue
+		}
+		h.send(conn, reply{V: ProtocolVersion, Action: "ack", Data: AckData{
+			RequestID: env.RequestID,
+			Action:    env.Action,
+		}})
+	}
+}
+
+// dispatch runs a validated client action. The returned error is reported
+// to the sender only.
+func (h *Hub) dispatch(ctx context.Context, conn *websocket.Conn, currentRoom *string, action string, payload Payload) error {
+	switch data := payload.(type) {
+	case *RoomCreatedData:
+		newRoomCode, err := h.handleRoomCreated(conn, *currentRoom, data)
+		if err != nil {
+			return err
+		}
+		*currentRoom = newRoomCode
+		return nil
+	case *HumanMoveData:
+		return h.handleHumanMove(ctx, conn, *currentRoom, data)
+	case *TypedMoveData:
+		moveTypes := map[string]game.MoveType{
+			"skip_turn": game.MoveSkip,
+			"resign":    game.MoveResign,
+			"swap_card": game.MoveSwap,
+			"hold_card": game.MoveSwap,
+		}
+		return h.handleTypedMove(ctx, conn, *currentRoom, data, moveTypes[action])
+	case *IdentifyData:
+		return h.identify(conn, *currentRoom, data.PlayerID, data.SeatSecret)
+	case *PlayerData:
+		switch action {
+		case "rematch":
+			return h.handleRematch(conn, *currentRoom, data)
+		case "abort":
+			return h.handleAbort(conn, *currentRoom, data)
+		case "request_undo":
+			return h.handleRequestUndo(conn, *currentRoom, data)
 		}
+	case *ReadyData:
+		return h.handleReady(conn, *currentRoom, data)
+	case *RespondUndoData:
+		return h.handleRespondUndo(conn, *currentRoom, data)
+	case *ChatData:
+		return h.handleChat(conn, *currentRoom, data)
+	case *ReactionData:
+		return h.handleReaction(conn, *currentRoom, data)
+	case *BotMoveData:
+		return h.handleBotMoveRequest(ctx, *currentRoom)
+	case *SyncRequestData:
+		if !h.sendBoardSync(conn, *currentRoom) {
+			return errors.New("Room not found")
+		}
+		return nil
 	}
+	return fmt.Errorf("unhandled action %q", action)
 }
 
 func (h *Hub) Broadcast(roomCode string, action string, data interface{}) {
@@ -118,6 +293,8 @@ func (h *Hub) Broadcast(roomCode string, action string, data interface{}) {
 		log.Printf("Hub instance is nil")
 		return
 	}
+	data, delta := h.versionBoard(roomCode, h.project(roomCode, data))
+	h.publish(roomCode, action, data)
 
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -131,8 +308,16 @@ func (h *Hub) Broadcast(roomCode string, action string, data interface{}) {
 		"action": action,
 		"data":   data,
 	}
+	deltaMessage := map[string]interface{}{
+		"action": action,
+		"data":   delta,
+	}
 	for conn := range clients {
-		if err := conn.WriteJSON(message); err != nil {
+		msg := message
+		if delta != nil && h.wantsDelta(conn) {
+			msg = deltaMessage
+		}
+		if err := h.write(conn, msg); err != nil {
 			log.Printf("Failed to send message: %v", err)
 			conn.Close()
 			delete(clients, conn)
@@ -140,61 +325,168 @@ func (h *Hub) Broadcast(roomCode string, action string, data interface{}) {
 	}
 }
 
-func (h *Hub) handleHumanMove(roomCode string, data interface{}) {
-	// Parse the move data
-	var move struct {
-		PlayerID string `json:"player_id"`
-		X        int    `json:"x"`
-		Y        int    `json:"y"`
-		Card     int    `json:"card"`
+// project redacts a broadcast payload with the room's broadcast policy. Rooms
+// that no longer exist and non-map payloads are sent as they are.
+func (h *Hub) project(roomCode string, data interface{}) interface{} {
+	if h.roomManager == nil {
+		return data
+	}
+	room, ok := h.roomManager.Get(roomCode)
+	if !ok {
+		return data
 	}
 
-	rawData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("ERROR: Failed to marshal move data: %v", err)
+	switch payload := data.(type) {
+	case gin.H:
+		return room.Project(payload)
+	case map[string]interface{}:
+		return room.Project(payload)
+	}
+	return data
+}
+
+// SendToPlayer delivers a message only to the connections identified as playerID in a room
+func (h *Hub) SendToPlayer(roomCode string, playerID string, action string, data interface{}) {
+	if h == nil {
+		log.Printf("Hub instance is nil")
 		return
 	}
 
-	if err := json.Unmarshal(rawData, &move); err != nil {
-		log.Printf("ERROR: Invalid move data: %v", err)
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	clients, ok := h.rooms[roomCode]
+	if !ok {
 		return
 	}
 
-	log.Printf("=== WEBSOCKET HUMAN MOVE ===")
-	log.Printf("Room: %s, PlayerID: %s, Position: (%d,%d), Card: %d", roomCode, move.PlayerID, move.X, move.Y, move.Card)
+	message := map[string]interface{}{
+		"action": action,
+		"data":   data,
+	}
+	for conn := range clients {
+		if h.players[conn] != playerID {
+			continue
+		}
+		if err := h.write(conn, message); err != nil {
+			log.Printf("Failed to send private message: %v", err)
+		}
+	}
+}
+
+// authorize checks that the connection owns the seat it acts for: the seat's
+// account, or the secret it identified as the seat with. Seats are checked
+// again on every action, since a takeover changes the seat's secret.
+func (h *Hub) authorize(conn *websocket.Conn, room *shared.Room, playerID string) error {
+	h.mu.RLock()
+	userID := h.users[conn]
+	var secret string
+	if h.players[conn] == playerID {
+		secret = h.secrets[conn]
+	}
+	h.mu.RUnlock()
+	return h.roomManager.AuthorizeSeat(room, playerID, userID, secret)
+}
 
-	// Get the room
+// sendError reports an error to a single connection, in its language
+func (h *Hub) sendError(conn *websocket.Conn, message string) {
+	h.send(conn, reply{V: ProtocolVersion, Action: "error", Data: ErrorData{Message: h.translate(conn, message)}})
+}
+
+// sendReplyError reports a failed request to its sender, echoing the request ID
+func (h *Hub) sendReplyError(conn *websocket.Conn, env Envelope, err error) {
+	h.send(conn, reply{V: ProtocolVersion, Action: "error", Data: ErrorData{
+		Message:   h.translate(conn, err.Error()),
+		RequestID: env.RequestID,
+		Action:    env.Action,
+	}})
+}
+
+// identify binds a connection to a player and sends them their private hand.
+// The connection must own the seat: its account, or the seat secret.
+func (h *Hub) identify(conn *websocket.Conn, roomCode string, playerID, secret string) error {
+	room, ok := h.roomManager.Get(roomCode)
+	if ok {
+		h.mu.RLock()
+		userID := h.users[conn]
+		h.mu.RUnlock()
+		if err := h.roomManager.AuthorizeSeat(room, playerID, userID, secret); err != nil {
+			return err
+		}
+	}
+
+	h.mu.Lock()
+	h.players[conn] = playerID
+	h.secrets[conn] = secret
+	h.mu.Unlock()
+	if ok {
+		h.playerConnected(roomCode, playerID)
+	}
+
+	if !ok || !room.Policy.PrivateHands() {
+		return nil
+	}
+	for _, p := range room.Players {
+		if p.ID == playerID {
+			h.SendToPlayer(roomCode, playerID, "hand_update", map[string]interface{}{
+				"hand":       p.Hand,
+				"deck_count": len(p.Deck),
+			})
+			return nil
+		}
+	}
+	return nil
+}
+
+// roomFor looks up the connection's room and checks that the connection may
+// act for playerID in it
+func (h *Hub) roomFor(conn *websocket.Conn, roomCode, playerID string) (*shared.Room, error) {
 	room, ok := h.roomManager.Get(roomCode)
 	if !ok {
-		log.Printf("ERROR: Room not found: %s", roomCode)
-		h.Broadcast(roomCode, "error", map[string]interface{}{
-			"message": "Room not found",
-		})
-		return
+		return nil, errors.New("Room not found")
+	}
+	if err := h.authorize(conn, room, playerID); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+func (h *Hub) handleHumanMove(ctx context.Context, conn *websocket.Conn, roomCode string, move *HumanMoveData) error {
+	// Non-placement moves are handled by the typed move flow
+	if move.Type.Normalize() != game.MovePlace {
+		return h.handleTypedMove(ctx, conn, roomCode, &TypedMoveData{PlayerID: move.PlayerID, Card: move.Card}, move.Type)
 	}
 
+	log.Printf("=== WEBSOCKET HUMAN MOVE ===")
+	log.Printf("Room: %s, PlayerID: %s, Position: (%d,%d), Card: %d", roomCode, move.PlayerID, move.X, move.Y, move.Card)
+
+	room, err := h.roomFor(conn, roomCode, move.PlayerID)
+	if err != nil {
+		return err
+	}
+	coord, err := room.Board.Locate(move.Cell, move.X, move.Y)
+	if err != nil {
+		return err
+	}
+	move.X, move.Y = coord.X, coord.Y
+
 	// Log board state for debugging
 	boardEmpty := true
 	placedCount := 0
-	for y := 0; y < room.Board.Size; y++ {
-		for x := 0; x < room.Board.Size; x++ {
-			if room.Board.Cells[y][x].Value != 0 {
-				boardEmpty = false
-				placedCount++
-				log.Printf("DEBUG: Card found at (%d,%d): value=%d, owner=%s",
-					x, y, room.Board.Cells[y][x].Value, room.Board.Cells[y][x].OwnerID)
-			}
+	room.Board.Each(func(c game.Coord, cell game.Cell) {
+		if cell.Value != 0 {
+			boardEmpty = false
+			placedCount++
+			log.Printf("DEBUG: Card found at (%d,%d): value=%d, owner=%s",
+				c.X, c.Y, cell.Value, cell.OwnerID)
 		}
-	}
+	})
 	log.Printf("DEBUG: Board size=%d, isEmpty=%v, placedCards=%d", room.Board.Size, boardEmpty, placedCount)
-	log.Printf("DEBUG: Center position should be: (%d,%d)", room.Board.Size/2, room.Board.Size/2)
+	center := room.Board.Center()
+	log.Printf("DEBUG: Center position should be: (%d,%d)", center.X, center.Y)
 	log.Printf("DEBUG: Received position: (%d,%d)", move.X, move.Y) // Apply the human move
-	if err := h.roomManager.ApplyMove(room, move.PlayerID, move.X, move.Y, move.Card); err != nil {
-		log.Printf("ERROR: Failed to apply move: %v", err)
-		h.Broadcast(roomCode, "error", map[string]interface{}{
-			"message": err.Error(),
-		})
-		return
+	if err := h.roomManager.ApplyMove(ctx, room, move.PlayerID, move.X, move.Y, move.Card); err != nil {
+		return err
 	}
 
 	log.Printf("SUCCESS: Move applied successfully")
@@ -205,6 +497,7 @@ func (h *Hub) handleHumanMove(roomCode string, data interface{}) {
 		"player_id": move.PlayerID,
 		"x":         move.X,
 		"y":         move.Y,
+		"cell":      game.Coord{X: move.X, Y: move.Y}.Algebraic(),
 		"card":      move.Card,
 		"board":     room.Board,
 		"next_turn": room.Players[room.TurnIdx].ID,
@@ -217,67 +510,152 @@ func (h *Hub) handleHumanMove(roomCode string, data interface{}) {
 			h.handleBotMove(roomCode)
 		}()
 	}
+	return nil
 }
 
-func (h *Hub) handleRoomCreated(conn *websocket.Conn, currentRoom *string, data interface{}) string {
-	// Extract room code and player name from data
-	var roomData struct {
-		RoomCode   string `json:"room_code"`
-		PlayerName string `json:"player_name"`
+// handleTypedMove applies a skip, resign or swap (hold) move. The manager broadcasts
+// the resulting event; the hub only reports errors and resumes bot turns.
+func (h *Hub) handleTypedMove(ctx context.Context, conn *websocket.Conn, roomCode string, move *TypedMoveData, moveType game.MoveType) error {
+	room, err := h.roomFor(conn, roomCode, move.PlayerID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.roomManager.PlayMove(ctx, room, game.Move{
+		Type:     moveType,
+		PlayerID: move.PlayerID,
+		Card:     move.Card,
+	}); err != nil {
+		return err
+	}
+
+	if room.WinnerID == nil && room.Players[room.TurnIdx].IsBot {
+		go h.handleBotMove(roomCode)
 	}
+	return nil
+}
 
-	rawData, err := json.Marshal(data)
+func (h *Hub) handleRematch(conn *websocket.Conn, roomCode string, req *PlayerData) error {
+	room, err := h.roomFor(conn, roomCode, req.PlayerID)
 	if err != nil {
-		log.Printf("ERROR: Failed to marshal room data: %v", err)
-		conn.WriteJSON(map[string]interface{}{
-			"action": "error",
-			"data":   map[string]interface{}{"message": "Invalid room data"},
-		})
-		return ""
+		return err
 	}
 
-	if err := json.Unmarshal(rawData, &roomData); err != nil {
-		log.Printf("ERROR: Invalid room data: %v", err)
-		conn.WriteJSON(map[string]interface{}{
-			"action": "error",
-			"data":   map[string]interface{}{"message": "Invalid room data format"},
-		})
-		return ""
+	started, err := h.roomManager.RequestRematch(room, req.PlayerID)
+	if err != nil {
+		return err
 	}
 
-	roomCode := roomData.RoomCode
-	if roomCode == "" {
-		log.Printf("ERROR: Room code not provided in data")
-		conn.WriteJSON(map[string]interface{}{
-			"action": "error",
-			"data":   map[string]interface{}{"message": "room_code is required"},
-		})
-		return ""
+	// Bots may be first to move in the new game
+	if started && room.Players[room.TurnIdx].IsBot {
+		go h.handleBotMove(roomCode)
 	}
+	return nil
+}
 
-	playerName := roomData.PlayerName
-	if playerName == "" {
-		log.Printf("ERROR: Player name not provided in data")
-		conn.WriteJSON(map[string]interface{}{
-			"action": "error",
-			"data":   map[string]interface{}{"message": "player_name is required"},
-		})
-		return ""
+func (h *Hub) handleAbort(conn *websocket.Conn, roomCode string, req *PlayerData) error {
+	room, err := h.roomFor(conn, roomCode, req.PlayerID)
+	if err != nil {
+		return err
 	}
+	return h.roomManager.Abort(room, req.PlayerID)
+}
+
+func (h *Hub) handleChat(conn *websocket.Conn, roomCode string, req *ChatData) error {
+	room, err := h.roomFor(conn, roomCode, req.PlayerID)
+	if err != nil {
+		return err
+	}
+	_, err = h.roomManager.PostChat(room, req.PlayerID, req.Text)
+	return err
+}
+
+func (h *Hub) handleReaction(conn *websocket.Conn, roomCode string, req *ReactionData) error {
+	room, err := h.roomFor(conn, roomCode, req.PlayerID)
+	if err != nil {
+		return err
+	}
+	_, err = h.roomManager.React(room, req.PlayerID, req.Code)
+	return err
+}
+
+func (h *Hub) handleRequestUndo(conn *websocket.Conn, roomCode string, req *PlayerData) error {
+	room, err := h.roomFor(conn, roomCode, req.PlayerID)
+	if err != nil {
+		return err
+	}
+	_, err = h.roomManager.RequestUndo(room, req.PlayerID)
+	return err
+}
+
+func (h *Hub) handleRespondUndo(conn *websocket.Conn, roomCode string, resp *RespondUndoData) error {
+	room, err := h.roomFor(conn, roomCode, resp.PlayerID)
+	if err != nil {
+		return err
+	}
+	return h.roomManager.RespondUndo(room, resp.PlayerID, resp.Accept)
+}
+
+// handleBotMoveRequest plays the current bot's turn on a client's request
+func (h *Hub) handleBotMoveRequest(ctx context.Context, roomCode string) error {
+	room, ok := h.roomManager.Get(roomCode)
+	if !ok {
+		return errors.New("Room not found")
+	}
+	currentPlayer := room.Players[room.TurnIdx]
+	if !currentPlayer.IsBot {
+		return errors.New("it is not a bot's turn")
+	}
+
+	botMove, err := h.roomManager.BotMove(ctx, room, currentPlayer.ID)
+	if err != nil {
+		return err
+	}
+	if botMove.Type == game.MoveSwap {
+		return nil // The manager announced the hold as card_swapped
+	}
+	h.Broadcast(roomCode, "bot_move", gin.H{
+		"bot_id":     currentPlayer.ID,
+		"x":          botMove.X,
+		"y":          botMove.Y,
+		"card":       botMove.Card,
+		"board":      room.Board,
+		"evaluation": botMove.Score,
+	})
+	return nil
+}
+
+// handleRoomCreated creates a lobby room and moves the connection into it,
+// returning the new room code
+func (h *Hub) handleRoomCreated(conn *websocket.Conn, currentRoom string, roomData *RoomCreatedData) (string, error) {
+	roomCode := roomData.RoomCode
+	playerName := roomData.PlayerName
 
 	log.Printf("=== ROOM CREATED VIA WEBSOCKET ===")
 	log.Printf("Room Code: %s, Room Master: %s", roomCode, playerName)
 
 	// Create lobby room with room master as first player
-	room := h.roomManager.CreateLobbyRoom(roomCode, playerName)
-	if room == nil {
-		log.Printf("ERROR: Failed to create lobby room")
-		h.Broadcast(roomCode, "error", map[string]interface{}{
-			"message": "Failed to create room",
-		})
-		return ""
+	room, err := h.roomManager.CreateLobbyRoom(roomCode, playerName)
+	if err != nil {
+		return "", err
 	}
 
+	if roomData.Password != "" {
+		if err := h.roomManager.SetRoomPassword(room, roomData.Password); err != nil {
+			log.Printf("ERROR: Failed to set room password: %v", err)
+			return "", errors.New("Failed to set room password")
+		}
+	}
+
+	// The room master's seat belongs to the authenticated user, if any
+	h.mu.RLock()
+	userID := h.users[conn]
+	h.mu.RUnlock()
+	if len(room.Players) > 0 {
+		h.roomManager.BindUser(room, room.Players[0].ID, userID)
+	}
+	h.roomManager.ClaimRoom(room, userID)
+
 	// Add this connection to the room
 	h.mu.Lock()
 	if _, ok := h.rooms[roomCode]; !ok {
@@ -286,21 +664,38 @@ func (h *Hub) handleRoomCreated(conn *websocket.Conn, currentRoom *string, data
 	h.rooms[roomCode][conn] = struct{}{}
 
 	// Remove from old room if it existed
-	if *currentRoom != "" && *currentRoom != roomCode {
-		delete(h.rooms[*currentRoom], conn)
+	if currentRoom != "" && currentRoom != roomCode {
+		delete(h.rooms[currentRoom], conn)
 	}
 	h.mu.Unlock()
 
 	// Broadcast room created confirmation
 	h.Broadcast(roomCode, "room_created", map[string]interface{}{
-		"room_code": roomCode,
-		"status":    "lobby",
+		"room_code":          roomCode,
+		"status":             "lobby",
+		"password_protected": room.HasPassword(),
 	})
 
+	// Only the creator learns the secret of the room master's seat
+	if len(room.Players) > 0 {
+		master := room.Players[0].ID
+		h.send(conn, reply{V: ProtocolVersion, Action: "seat_assigned", Data: map[string]interface{}{
+			"room_code":   roomCode,
+			"player_id":   master,
+			"seat_secret": h.roomManager.SeatSecret(room, master),
+		}})
+	}
+
 	log.Printf("SUCCESS: Lobby room created with code: %s", roomCode)
 	log.Printf("===================================")
 
-	return roomCode
+	return roomCode, nil
+}
+
+// ResumeBots plays any bot turns that are due after a server-initiated turn
+// change, such as a turn timeout
+func (h *Hub) ResumeBots(roomCode string) {
+	go h.handleBotMove(roomCode)
 }
 
 func (h *Hub) handleBotMove(roomCode string) {
@@ -328,20 +723,24 @@ func (h *Hub) handleBotMove(roomCode string) {
 		}
 
 		// Trigger the bot's move
-		botMove, err := h.roomManager.BotMove(room, currentPlayer.ID)
+		botMove, err := h.roomManager.BotMove(context.Background(), room, currentPlayer.ID)
 		if err != nil {
 			log.Printf("Failed to process bot move: %v", err)
 			return
 		}
 
-		// Broadcast the bot's move
+		// Broadcast the bot's move; holds were announced as card_swapped
+		if botMove.Type == game.MoveSwap {
+			continue
+		}
 		h.Broadcast(roomCode, "bot_move", map[string]interface{}{
-			"bot_id":    currentPlayer.ID,
-			"x":         botMove.X,
-			"y":         botMove.Y,
-			"card":      botMove.Card,
-			"board":     room.Board,
-			"next_turn": room.Players[room.TurnIdx].ID,
+			"bot_id":     currentPlayer.ID,
+			"x":          botMove.X,
+			"y":          botMove.Y,
+			"card":       botMove.Card,
+			"board":      room.Board,
+			"next_turn":  room.Players[room.TurnIdx].ID,
+			"evaluation": botMove.Score,
 		})
 
 		// Check again if game is over after this bot move
@@ -0,0 +1,65 @@
+package ws
+
+import (
+	"github.com/gorilla/websocket"
+)
+
+// LobbyPlayer is a seat as the lobby shows it
+type LobbyPlayer struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Color  string `json:"color"`
+	IsBot  bool   `json:"is_bot"`
+	Master bool   `json:"master"` // Starts the game, so needs no ready check
+	Ready  bool   `json:"ready"`  // Bots and the room master always are
+	Online bool   `json:"online"` // Has a connection identified as this player; bots always do
+}
+
+// LobbyStateData is the whole lobby, sent whenever who is ready or connected
+// changes so a client that missed updates, or has just reconnected, is
+// brought up to date by the next one
+type LobbyStateData struct {
+	RoomCode string        `json:"room_code"`
+	Players  []LobbyPlayer `json:"players"`
+	AllReady bool          `json:"all_ready"` // The room master may start the game
+}
+
+// handleReady marks the sender ready or not and shows the lobby the change
+func (h *Hub) handleReady(conn *websocket.Conn, roomCode string, req *ReadyData) error {
+	room, err := h.roomFor(conn, roomCode, req.PlayerID)
+	if err != nil {
+		return err
+	}
+	ready := req.Ready == nil || *req.Ready
+	if err := h.roomManager.SetReady(room, req.PlayerID, ready); err != nil {
+		return err
+	}
+	h.BroadcastLobby(roomCode)
+	return nil
+}
+
+// BroadcastLobby sends the room's lobby state to the room. Rooms that are
+// not in the lobby are left alone.
+func (h *Hub) BroadcastLobby(roomCode string) {
+	if h == nil || h.roomManager == nil {
+		return
+	}
+	room, ok := h.roomManager.Get(roomCode)
+	if !ok || room.Status != "lobby" {
+		return
+	}
+
+	state := LobbyStateData{RoomCode: room.Code, AllReady: len(room.NotReady()) == 0}
+	for i, p := range room.Players {
+		state.Players = append(state.Players, LobbyPlayer{
+			ID:     p.ID,
+			Name:   p.Name,
+			Color:  p.Color,
+			IsBot:  p.IsBot,
+			Master: i == 0,
+			Ready:  p.Ready || p.IsBot || i == 0,
+			Online: p.IsBot || h.Connected(roomCode, p.ID),
+		})
+	}
+	h.Broadcast(roomCode, "lobby_state", state)
+}
@@ -0,0 +1,39 @@
+package ws
+
+import (
+	"javanese-chess/internal/i18n"
+
+	"github.com/gorilla/websocket"
+)
+
+// translate returns message in the connection's language: the one it asked
+// for on connect, else the locale of the room it watches, else the default
+func (h *Hub) translate(conn *websocket.Conn, message string) string {
+	return i18n.T(h.connLang(conn), message)
+}
+
+// connLang is the language errors are sent to a connection in
+func (h *Hub) connLang(conn *websocket.Conn) string {
+	h.mu.RLock()
+	lang := h.langs[conn]
+	roomCode := ""
+	if lang == "" {
+		for code, clients := range h.rooms {
+			if _, ok := clients[conn]; ok {
+				roomCode = code
+				break
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	if lang != "" {
+		return lang
+	}
+	if roomCode != "" {
+		if room, ok := h.roomManager.Get(roomCode); ok && room.Locale != "" {
+			return room.Locale
+		}
+	}
+	return i18n.Default
+}
@@ -0,0 +1,209 @@
+package ws
+
+import (
+	"javanese-chess/internal/config"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Coarse connection quality levels shown in presence broadcasts
+const (
+	QualityUnknown = "unknown"
+	QualityGood    = "good"
+	QualityFair    = "fair"
+	QualityPoor    = "poor"
+)
+
+// linkStats aggregates a player's RTT reports as exponentially weighted
+// averages of the round trip time and its jitter
+type linkStats struct {
+	mu      sync.Mutex
+	rtt     float64 // ms
+	jitter  float64 // ms
+	samples int
+}
+
+// rttWeight is how much each new sample moves the averages
+const rttWeight = 0.2
+
+func (s *linkStats) add(rttMs float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.samples == 0 {
+		s.rtt = rttMs
+	} else {
+		s.jitter += rttWeight * (math.Abs(rttMs-s.rtt) - s.jitter)
+		s.rtt += rttWeight * (rttMs - s.rtt)
+	}
+	s.samples++
+}
+
+func (s *linkStats) quality() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.samples == 0 {
+		return QualityUnknown
+	}
+	latency := time.Duration((s.rtt + 2*s.jitter) * float64(time.Millisecond))
+	switch {
+	case latency <= config.QualityGoodRTT:
+		return QualityGood
+	case latency <= config.QualityFairRTT:
+		return QualityFair
+	}
+	return QualityPoor
+}
+
+func (s *linkStats) rttMs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int(math.Round(s.rtt))
+}
+
+// handlePing records the sender's RTT and answers with a pong. A presence
+// update is broadcast when the player's connection quality level changes.
+func (h *Hub) handlePing(conn *websocket.Conn, roomCode string, env Envelope, ping *PingData) {
+	h.mu.RLock()
+	playerID := h.players[conn]
+	h.mu.RUnlock()
+
+	quality := QualityUnknown
+	if playerID != "" {
+		stats := h.linkStats(playerID)
+		before := stats.quality()
+		if ping.RTTMs != nil {
+			stats.add(*ping.RTTMs)
+		}
+		quality = stats.quality()
+		if quality != before {
+			h.broadcastPresence(roomCode, playerID, true)
+		}
+	}
+
+	h.send(conn, reply{V: ProtocolVersion, Action: "pong", Data: PongData{
+		RequestID:  env.RequestID,
+		ClientTime: ping.ClientTime,
+		ServerTime: time.Now().UnixMilli(),
+		Quality:    quality,
+	}})
+}
+
+// linkStats returns the player's RTT aggregate, creating it on first use
+func (h *Hub) linkStats(playerID string) *linkStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stats, ok := h.links[playerID]
+	if !ok {
+		stats = &linkStats{}
+		h.links[playerID] = stats
+	}
+	return stats
+}
+
+// reconnectGrace is how long a dropped player may take to come back before
+// the room gives up on them. A room's own abandon limit wins; otherwise
+// shaky connections get more time.
+func (h *Hub) reconnectGrace(roomCode, playerID string) time.Duration {
+	if room, ok := h.roomManager.Get(roomCode); ok && room.Abandon.Minutes > 0 {
+		return time.Duration(room.Abandon.Minutes) * time.Minute
+	}
+	switch h.linkStats(playerID).quality() {
+	case QualityFair:
+		return 2 * config.ReconnectGrace
+	case QualityPoor:
+		return 3 * config.ReconnectGrace
+	}
+	return config.ReconnectGrace
+}
+
+// broadcastPresence tells the room whether a player is connected, with their
+// connection quality and, when offline, the reconnect grace period
+func (h *Hub) broadcastPresence(roomCode, playerID string, online bool) {
+	if roomCode == "" {
+		return
+	}
+	stats := h.linkStats(playerID)
+	data := map[string]interface{}{
+		"player_id": playerID,
+		"online":    online,
+		"quality":   stats.quality(),
+		"rtt_ms":    stats.rttMs(),
+	}
+	if !online {
+		data["grace_seconds"] = int(h.reconnectGrace(roomCode, playerID).Seconds())
+	}
+	h.Broadcast(roomCode, "presence", data)
+}
+
+// playerConnected cancels a pending abandonment for a player who is back
+func (h *Hub) playerConnected(roomCode, playerID string) {
+	h.mu.Lock()
+	if t, ok := h.graceTimers[roomCode+"/"+playerID]; ok {
+		t.Stop()
+		delete(h.graceTimers, roomCode+"/"+playerID)
+	}
+	h.mu.Unlock()
+
+	h.broadcastPresence(roomCode, playerID, true)
+	h.BroadcastLobby(roomCode)
+}
+
+// playerLeft starts the reconnect grace period once a player's last
+// connection to a game in progress has closed
+func (h *Hub) playerLeft(roomCode, playerID string) {
+	if roomCode == "" || playerID == "" || h.Connected(roomCode, playerID) {
+		return
+	}
+	h.broadcastPresence(roomCode, playerID, false)
+	h.BroadcastLobby(roomCode)
+
+	room, ok := h.roomManager.Get(roomCode)
+	if !ok || room.Status != "playing" || room.Over() {
+		return
+	}
+
+	key := roomCode + "/" + playerID
+	grace := h.reconnectGrace(roomCode, playerID)
+	h.mu.Lock()
+	if t, ok := h.graceTimers[key]; ok {
+		t.Stop()
+	}
+	h.graceTimers[key] = time.AfterFunc(grace, func() {
+		h.mu.Lock()
+		delete(h.graceTimers, key)
+		h.mu.Unlock()
+
+		if h.Connected(roomCode, playerID) {
+			return
+		}
+		if room, ok := h.roomManager.Get(roomCode); ok {
+			if err := h.roomManager.AbandonPlayer(room, playerID); err != nil {
+				log.Printf("Player %s in room %s not abandoned: %v", playerID, roomCode, err)
+			}
+		}
+	})
+	h.mu.Unlock()
+}
+
+// Connected reports whether any connection in the room is identified as playerID
+func (h *Hub) Connected(roomCode, playerID string) bool {
+	if h == nil {
+		return false
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for conn := range h.rooms[roomCode] {
+		if h.players[conn] == playerID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package ws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"javanese-chess/internal/game"
+)
+
+// ProtocolVersion is the WebSocket protocol spoken by this server. Messages
+// without "v" are treated as the current version so older clients keep working.
+const ProtocolVersion = 1
+
+// Envelope wraps every client message. RequestID is optional and echoed back
+// in the ack or error reply so clients can correlate responses.
+type Envelope struct {
+	V         int             `json:"v"`
+	Action    string          `json:"action"`
+	Data      json.RawMessage `json:"data"`
+	RequestID string          `json:"request_id,omitempty"`
+}
+
+// Payload is the typed data of a client action
+type Payload interface {
+	Validate() error
+}
+
+// RoomCreatedData creates a lobby room with the sender as room master
+type RoomCreatedData struct {
+	RoomCode   string `json:"room_code"`
+	PlayerName string `json:"player_name"`
+	Password   string `json:"password,omitempty"` // Optional: required to join the lobby
+}
+
+func (d *RoomCreatedData) Validate() error {
+	if d.RoomCode == "" {
+		return errors.New("room_code is required")
+	}
+	if d.PlayerName == "" {
+		return errors.New("player_name is required")
+	}
+	return nil
+}
+
+// HumanMoveData places a card. Cell (algebraic, e.g. "E5") overrides X/Y.
+// A non-place Type is handled like the matching typed move action.
+type HumanMoveData struct {
+	PlayerID string        `json:"player_id"`
+	X        int           `json:"x"`
+	Y        int           `json:"y"`
+	Card     int           `json:"card"`
+	Type     game.MoveType `json:"type,omitempty"`
+	Cell     string        `json:"cell,omitempty"`
+}
+
+func (d *HumanMoveData) Validate() error {
+	if d.PlayerID == "" {
+		return errors.New("player_id is required")
+	}
+	return nil
+}
+
+// TypedMoveData is a skip, resign or swap. Card is only used by swaps, which
+// hold_card sends under its other name.
+type TypedMoveData struct {
+	PlayerID string `json:"player_id"`
+	Card     int    `json:"card,omitempty"`
+}
+
+func (d *TypedMoveData) Validate() error {
+	if d.PlayerID == "" {
+		return errors.New("player_id is required")
+	}
+	return nil
+}
+
+// PlayerData identifies the acting player for actions without other fields
+type PlayerData struct {
+	PlayerID string `json:"player_id"`
+}
+
+func (d *PlayerData) Validate() error {
+	if d.PlayerID == "" {
+		return errors.New("player_id is required")
+	}
+	return nil
+}
+
+// IdentifyData binds the connection to a seat. Seats without an account
+// need the seat secret handed out when the seat was created.
+type IdentifyData struct {
+	PlayerID   string `json:"player_id"`
+	SeatSecret string `json:"seat_secret,omitempty"`
+}
+
+func (d *IdentifyData) Validate() error {
+	if d.PlayerID == "" {
+		return errors.New("player_id is required")
+	}
+	return nil
+}
+
+// ReadyData marks a lobby player as ready to start, or no longer ready.
+// Ready defaults to true.
+type ReadyData struct {
+	PlayerID string `json:"player_id"`
+	Ready    *bool  `json:"ready,omitempty"`
+}
+
+func (d *ReadyData) Validate() error {
+	if d.PlayerID == "" {
+		return errors.New("player_id is required")
+	}
+	return nil
+}
+
+// RespondUndoData accepts or declines a pending undo request
+type RespondUndoData struct {
+	PlayerID string `json:"player_id"`
+	Accept   bool   `json:"accept"`
+}
+
+func (d *RespondUndoData) Validate() error {
+	if d.PlayerID == "" {
+		return errors.New("player_id is required")
+	}
+	return nil
+}
+
+// ChatData is an in-room chat message
+type ChatData struct {
+	PlayerID string `json:"player_id"`
+	Text     string `json:"text"`
+}
+
+func (d *ChatData) Validate() error {
+	if d.PlayerID == "" {
+		return errors.New("player_id is required")
+	}
+	return nil
+}
+
+// ReactionData is a quick reaction, one of config.Reactions
+type ReactionData struct {
+	PlayerID string `json:"player_id"`
+	Code     string `json:"code"`
+}
+
+func (d *ReactionData) Validate() error {
+	if d.PlayerID == "" {
+		return errors.New("player_id is required")
+	}
+	if d.Code == "" {
+		return errors.New("code is required")
+	}
+	return nil
+}
+
+// BotMoveData asks the server to play the current bot's turn
+type BotMoveData struct{}
+
+func (d *BotMoveData) Validate() error {
+	return nil
+}
+
+// PingData reports the client's last measured round trip time. ClientTime is
+// echoed in the pong so the client can measure the next one.
+type PingData struct {
+	RTTMs      *float64 `json:"rtt_ms,omitempty"`
+	ClientTime int64    `json:"client_time,omitempty"`
+}
+
+func (d *PingData) Validate() error {
+	if d.RTTMs != nil && (*d.RTTMs < 0 || *d.RTTMs > 60000) {
+		return errors.New("rtt_ms must be between 0 and 60000")
+	}
+	return nil
+}
+
+// PongData answers a ping
+type PongData struct {
+	RequestID  string `json:"request_id,omitempty"`
+	ClientTime int64  `json:"client_time,omitempty"`
+	ServerTime int64  `json:"server_time"` // Unix milliseconds
+	Quality    string `json:"quality"`
+}
+
+// payloads maps every client action to its typed data
+var payloads = map[string]func() Payload{
+	"room_created": func() Payload { return &RoomCreatedData{} },
+	"human_move":   func() Payload { return &HumanMoveData{} },
+	"skip_turn":    func() Payload { return &TypedMoveData{} },
+	"resign":       func() Payload { return &TypedMoveData{} },
+	"swap_card":    func() Payload { return &TypedMoveData{} },
+	"hold_card":    func() Payload { return &TypedMoveData{} },
+	"identify":     func() Payload { return &IdentifyData{} },
+	"ready":        func() Payload { return &ReadyData{} },
+	"rematch":      func() Payload { return &PlayerData{} },
+	"abort":        func() Payload { return &PlayerData{} },
+	"request_undo": func() Payload { return &PlayerData{} },
+	"respond_undo": func() Payload { return &RespondUndoData{} },
+	"chat":         func() Payload { return &ChatData{} },
+	"reaction":     func() Payload { return &ReactionData{} },
+	"bot_move":     func() Payload { return &BotMoveData{} },
+	"ping":         func() Payload { return &PingData{} },
+	"sync_request": func() Payload { return &SyncRequestData{} },
+}
+
+// decodeMessage parses a client message and validates it against the
+// protocol. The envelope is returned even on error when it could be read,
+// so the error reply can carry the request ID.
+func decodeMessage(raw []byte) (Envelope, Payload, error) {
+	var env Envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return env, nil, errors.New("malformed message")
+	}
+	if env.V == 0 {
+		env.V = ProtocolVersion
+	}
+	if env.V != ProtocolVersion {
+		return env, nil, fmt.Errorf("unsupported protocol version %d (server speaks %d)", env.V, ProtocolVersion)
+	}
+
+	newPayload, ok := payloads[env.Action]
+	if !ok {
+		return env, nil, fmt.Errorf("unknown action %q", env.Action)
+	}
+	payload := newPayload()
+	if len(env.Data) > 0 && string(env.Data) != "null" {
+		if err := json.Unmarshal(env.Data, payload); err != nil {
+			return env, nil, fmt.Errorf("invalid %s data: %v", env.Action, err)
+		}
+	}
+	if err := payload.Validate(); err != nil {
+		return env, nil, err
+	}
+	return env, payload, nil
+}
+
+// AckData confirms that a request was processed
+type AckData struct {
+	RequestID string `json:"request_id,omitempty"`
+	Action    string `json:"action"`
+}
+
+// ErrorData reports why a request failed
+type ErrorData struct {
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+	Action    string `json:"action,omitempty"`
+}
+
+// reply is a server message sent to a single connection
+type reply struct {
+	V      int         `json:"v"`
+	Action string      `json:"action"`
+	Data   interface{} `json:"data"`
+}
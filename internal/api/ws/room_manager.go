@@ -1,12 +1,30 @@
 package ws
 
-import "javanese-chess/internal/shared"
+import (
+	"context"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+)
 
 type RoomManager interface {
 	Get(roomCode string) (*shared.Room, bool)
-	ApplyMove(room *shared.Room, playerID string, x, y, card int) error
-	BotMove(room *shared.Room, botID string) (shared.Move, error)
-	CreateLobbyRoom(roomCode string, roomMasterName string) *shared.Room
-	JoinRoom(roomCode string, playerName string) (*shared.Room, error)
+	ApplyMove(ctx context.Context, room *shared.Room, playerID string, x, y, card int) error
+	PlayMove(ctx context.Context, room *shared.Room, mv game.Move) error
+	BotMove(ctx context.Context, room *shared.Room, botID string) (shared.Move, error)
+	CreateLobbyRoom(roomCode string, roomMasterName string) (*shared.Room, error)
+	SetRoomPassword(room *shared.Room, password string) error
+	JoinRoom(roomCode string, playerName string) (*shared.Room, shared.Player, error)
+	SetReady(room *shared.Room, playerID string, ready bool) error
 	StartGame(room *shared.Room)
+	RequestUndo(room *shared.Room, playerID string) (bool, error)
+	RespondUndo(room *shared.Room, playerID string, accept bool) error
+	RequestRematch(room *shared.Room, playerID string) (bool, error)
+	Abort(room *shared.Room, playerID string) error
+	AbandonPlayer(room *shared.Room, playerID string) error
+	PostChat(room *shared.Room, playerID, text string) (shared.ChatMessage, error)
+	React(room *shared.Room, playerID, code string) (shared.Reaction, error)
+	BindUser(room *shared.Room, playerID, userID string)
+	ClaimRoom(room *shared.Room, userID string)
+	AuthorizeSeat(room *shared.Room, playerID, userID, secret string) error
+	SeatSecret(room *shared.Room, playerID string) string
 }
@@ -0,0 +1,90 @@
+package ws
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+)
+
+// Event is a broadcast as seen by a subscriber: its action and its data as
+// JSON, with the full board like WebSocket clients in full board mode get
+type Event struct {
+	Action string
+	Data   json.RawMessage
+}
+
+// Broadcasts a subscriber may fall behind by before it is dropped
+const subscriberBuffer = 256
+
+// subscribers receive a room's broadcasts without a WebSocket connection
+type subscribers struct {
+	mu    sync.Mutex
+	rooms map[string]map[chan Event]struct{}
+}
+
+// Subscribe delivers every broadcast to the room from now on to the returned
+// channel, until cancel is called. A subscriber that falls too far behind is
+// dropped and its channel closed, so a stalled reader cannot hold up the room.
+func (h *Hub) Subscribe(roomCode string) (events <-chan Event, cancel func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.subs.mu.Lock()
+	if h.subs.rooms == nil {
+		h.subs.rooms = make(map[string]map[chan Event]struct{})
+	}
+	if h.subs.rooms[roomCode] == nil {
+		h.subs.rooms[roomCode] = make(map[chan Event]struct{})
+	}
+	h.subs.rooms[roomCode][ch] = struct{}{}
+	h.subs.mu.Unlock()
+
+	return ch, func() { h.unsubscribe(roomCode, ch) }
+}
+
+// unsubscribe removes a subscriber and closes its channel, unless it is gone
+// already
+func (h *Hub) unsubscribe(roomCode string, ch chan Event) {
+	h.subs.mu.Lock()
+	defer h.subs.mu.Unlock()
+	h.dropSubscriber(roomCode, ch)
+}
+
+// dropSubscriber must be called with subs.mu held
+func (h *Hub) dropSubscriber(roomCode string, ch chan Event) {
+	subs := h.subs.rooms[roomCode]
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+	delete(subs, ch)
+	if len(subs) == 0 {
+		delete(h.subs.rooms, roomCode)
+	}
+	close(ch)
+}
+
+// publish hands a broadcast to the room's subscribers. The data is encoded
+// right away, while the caller still holds whatever guards the room.
+func (h *Hub) publish(roomCode, action string, data interface{}) {
+	h.subs.mu.Lock()
+	defer h.subs.mu.Unlock()
+
+	subs := h.subs.rooms[roomCode]
+	if len(subs) == 0 {
+		return
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Failed to encode %s for subscribers: %v", action, err)
+		return
+	}
+
+	ev := Event{Action: action, Data: raw}
+	for ch := range subs {
+		select {
+		case ch <- ev:
+		default:
+			log.Printf("Dropping a subscriber of room %s that fell behind", roomCode)
+			h.dropSubscriber(roomCode, ch)
+		}
+	}
+}
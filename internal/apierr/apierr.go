@@ -0,0 +1,173 @@
+// Package apierr is the error half of the REST envelope. Every failed
+// request, whether a handler or a middleware rejects it, answers
+//
+//	{"success": false, "error": {"code": "room_not_found", "message": "room not found"}}
+//
+// Codes are stable and machine-readable; messages are English, translated
+// into the request's language, and meant for people. Handlers keep
+// reporting plain English messages and the code is looked up from the
+// message, falling back to one code per HTTP status.
+package apierr
+
+import (
+	"net/http"
+	"strings"
+
+	"javanese-chess/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LangKey is the gin context key holding the language of error messages
+const LangKey = "lang"
+
+// Error codes by HTTP status, for errors without a more specific code
+const (
+	InvalidRequest = "invalid_request" // 400
+	Unauthorized   = "unauthorized"    // 401
+	Forbidden      = "forbidden"       // 403
+	NotFound       = "not_found"       // 404
+	Conflict       = "conflict"        // 409
+	RateLimited    = "rate_limited"    // 429
+	Internal       = "internal"        // 500
+	Unavailable    = "unavailable"     // 503
+)
+
+// Specific error codes
+const (
+	InvalidPayload     = "invalid_payload"
+	MissingField       = "missing_field"
+	InvalidCell        = "invalid_cell"
+	InvalidRecord      = "invalid_record"
+	IllegalMove        = "illegal_move"
+	NotYourTurn        = "not_your_turn"
+	CardNotInHand      = "card_not_in_hand"
+	RoomNotFound       = "room_not_found"
+	PlayerNotFound     = "player_not_found"
+	SeatNotFound       = "seat_not_found"
+	TournamentNotFound = "tournament_not_found"
+	ExperimentNotFound = "experiment_not_found"
+	TicketNotFound     = "ticket_not_found"
+	RoomFull           = "room_full"
+	WrongPassword      = "wrong_password"
+	GameStarted        = "game_started"
+	GameNotStarted     = "game_not_started"
+	GameOver           = "game_over"
+	GameNotOver        = "game_not_over"
+	AuthRequired       = "auth_required"
+	InvalidToken       = "invalid_token"
+	TokenExpired       = "token_expired"
+	InvalidAdminToken  = "invalid_admin_token"
+	InvalidCredentials = "invalid_credentials"
+	UsernameTaken      = "username_taken"
+	RatingsDisabled    = "ratings_disabled"
+	HintsDisabled      = "hints_disabled"
+)
+
+// Error is the error object of a failed request
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Body is the envelope of a failed request
+type Body struct {
+	Success bool  `json:"success"` // Always false
+	Error   Error `json:"error"`
+}
+
+// exact maps messages to their code
+var exact = map[string]string{
+	"invalid payload":                 InvalidPayload,
+	"room not found":                  RoomNotFound,
+	"Room not found":                  RoomNotFound,
+	"player not found":                PlayerNotFound,
+	"player not in room":              PlayerNotFound,
+	"seat not found":                  SeatNotFound,
+	"tournament not found":            TournamentNotFound,
+	"experiment not found":            ExperimentNotFound,
+	"ticket not found":                TicketNotFound,
+	"room is full":                    RoomFull,
+	"wrong room password":             WrongPassword,
+	"not your turn or player invalid": NotYourTurn,
+	"card not in hand":                CardNotInHand,
+	"game has already started":        GameStarted,
+	"game has not started":            GameNotStarted,
+	"game is already over":            GameOver,
+	"room has ended":                  GameOver,
+	"room has already ended":          GameOver,
+	"game is not over":                GameNotOver,
+	"game is not over yet":            GameNotOver,
+	"authentication required":         AuthRequired,
+	"invalid token":                   InvalidToken,
+	"token expired":                   TokenExpired,
+	"invalid admin token":             InvalidAdminToken,
+	"invalid username or password":    InvalidCredentials,
+	"username already taken":          UsernameTaken,
+	"ratings are disabled":            RatingsDisabled,
+	"hints are disabled in this room": HintsDisabled,
+	"rate limit exceeded":             RateLimited,
+}
+
+// prefixes maps the start of formatted messages to their code
+var prefixes = []struct{ prefix, code string }{
+	{"illegal move", IllegalMove},
+	{"cell is outside", InvalidCell},
+	{"cell must", InvalidCell},
+	{"invalid cell notation", InvalidCell},
+	{"invalid game record", InvalidRecord},
+	{"unsupported game record version", InvalidRecord},
+}
+
+// byStatus is the code of errors no message identifies
+var byStatus = map[int]string{
+	http.StatusBadRequest:          InvalidRequest,
+	http.StatusUnauthorized:        Unauthorized,
+	http.StatusForbidden:           Forbidden,
+	http.StatusNotFound:            NotFound,
+	http.StatusConflict:            Conflict,
+	http.StatusTooManyRequests:     RateLimited,
+	http.StatusInternalServerError: Internal,
+	http.StatusServiceUnavailable:  Unavailable,
+}
+
+// CodeFor returns the code of an error answered with status and message
+func CodeFor(status int, message string) string {
+	if code, ok := exact[message]; ok {
+		return code
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(message, p.prefix) {
+			return p.code
+		}
+	}
+	if strings.HasSuffix(message, " is required") {
+		return MissingField
+	}
+	if code, ok := byStatus[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return Internal
+	}
+	return InvalidRequest
+}
+
+// New builds the envelope of a failed request, the message translated into
+// the request's language
+func New(c *gin.Context, status int, message string) Body {
+	return Body{Error: Error{
+		Code:    CodeFor(status, message),
+		Message: i18n.T(c.GetString(LangKey), message),
+	}}
+}
+
+// Respond writes the error envelope
+func Respond(c *gin.Context, status int, message string) {
+	c.JSON(status, New(c, status, message))
+}
+
+// Abort writes the error envelope and stops the handler chain
+func Abort(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, New(c, status, message))
+}
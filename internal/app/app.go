@@ -0,0 +1,274 @@
+// Package app wires the server together once: the store, the room manager
+// and WebSocket hub, matchmaking, accounts and the HTTP and gRPC APIs.
+// cmd/server runs an App; wstest starts one in process.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	grpcapi "javanese-chess/internal/api/grpc"
+	httpapi "javanese-chess/internal/api/http"
+	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/experiment"
+	"javanese-chess/internal/matchmaking"
+	"javanese-chess/internal/nn"
+	_ "javanese-chess/internal/nn/onnx" // Registers the "onnx" model runtime
+	"javanese-chess/internal/room"
+	"javanese-chess/internal/store"
+	"javanese-chess/internal/tournament"
+	"javanese-chess/internal/training"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/jackc/pgx/v5/stdlib" // Registers the "pgx" driver for the postgres store
+	"google.golang.org/grpc"
+)
+
+// ShutdownTimeout bounds how long Run waits for requests in flight once its
+// context is cancelled
+const ShutdownTimeout = 10 * time.Second
+
+// Backend is what a store must provide to back rooms and accounts
+type Backend interface {
+	room.Store
+	auth.UserStore
+}
+
+// App is the whole server. The fields are exposed so tools and tests can
+// inspect or adjust what the server holds before it serves.
+type App struct {
+	Config      *config.Config
+	Store       Backend
+	Manager     *room.Manager
+	Hub         *ws.Hub
+	Queue       *matchmaking.Queue
+	Tournaments *tournament.Service
+	Experiments *experiment.Service
+	Auth        *auth.Service
+	Router      *gin.Engine
+	GRPC        *grpc.Server       // nil unless Config.GRPCAddr is set
+	Training    *training.Recorder // nil unless Config.TrainingDir is set
+	Policy      nn.Model           // nil unless neural bots loaded their model
+
+	closeStore func()
+	http       *http.Server
+	stopOnce   sync.Once
+}
+
+// New opens the store selected by the config and wires every component
+// around it. Nothing is served until Run or Serve.
+func New(cfg *config.Config) (*App, error) {
+	// Tokens and seat secrets are signed with it; an empty key signs nothing
+	if cfg.JWTSecret == "" {
+		return nil, errors.New("no JWT secret configured")
+	}
+
+	s, closeStore, err := OpenStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The manager and the hub need each other: the hub is handed the
+	// manager, then the manager learns about the hub
+	rm := room.NewManager(s, *cfg, nil)
+	hub := ws.NewHub(rm)
+	rm.SetHub(hub)
+
+	// Persist ratings for the leaderboard, in the database when the store
+	// has one and otherwise in the ratings file
+	if rs, ok := s.(room.RatingStore); ok {
+		rm.SetRatings(rs)
+	} else if cfg.RatingsFile != "" {
+		rm.SetRatings(store.NewFileRatingStore(cfg.RatingsFile))
+	}
+
+	// Neural bots, when a model loads; otherwise bots keep the heuristic
+	var policy nn.Model
+	if cfg.BotEngine == config.BotEngineNN {
+		if model, err := nn.Open(cfg.NNRuntime, cfg.NNModelPath); err != nil {
+			log.Printf("Neural bots unavailable, falling back to the heuristic: %v", err)
+		} else {
+			policy = model
+			rm.SetPolicyModel(model, cfg.NNBlend)
+			log.Printf("Bots play with the %s model %s (blend %.2f)", cfg.NNRuntime, cfg.NNModelPath, cfg.NNBlend)
+		}
+	}
+
+	// Export every move for training models, when a directory is configured
+	var recorder *training.Recorder
+	if cfg.TrainingDir != "" {
+		sink, err := training.NewFileSink(cfg.TrainingDir)
+		if err != nil {
+			closeStore()
+			return nil, err
+		}
+		recorder = training.NewRecorder(sink)
+		rm.SetTrainingRecorder(recorder)
+		log.Printf("Exporting training data to %s", cfg.TrainingDir)
+	}
+
+	// Matchmaking queue for quick play
+	queue := matchmaking.NewQueue(rm, hub, matchmaking.Options{
+		MinPlayers:     config.QuickplayMinPlayers,
+		MaxPlayers:     config.QuickplayMaxPlayers,
+		GatherWindow:   config.QuickplayGatherWindow,
+		BotFillTimeout: config.QuickplayBotFillTimeout,
+		TickInterval:   config.QuickplayTickInterval,
+	})
+
+	// Tournaments pair their next round as the manager reports games over
+	tournaments := tournament.NewService(rm, hub)
+	rm.OnGameOver(tournaments.GameOver)
+
+	// Weight experiments assign new rooms to their arms and tally results
+	experiments := experiment.NewService()
+	rm.SetExperiments(experiments)
+	rm.OnGameOver(experiments.GameOver)
+
+	// Player accounts and token issuance
+	authSvc := auth.NewService(s, cfg.JWTSecret, cfg.TokenTTL)
+
+	router := httpapi.SetupRouter(cfg, rm, s, hub, queue, tournaments, experiments, authSvc)
+	router.GET("/", func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
+	})
+
+	a := &App{
+		Config:      cfg,
+		Store:       s,
+		Manager:     rm,
+		Hub:         hub,
+		Queue:       queue,
+		Tournaments: tournaments,
+		Experiments: experiments,
+		Auth:        authSvc,
+		Router:      router,
+		Training:    recorder,
+		Policy:      policy,
+		closeStore:  closeStore,
+		http:        &http.Server{Handler: router},
+	}
+	// gRPC game service for other backends, when an address is configured
+	if cfg.GRPCAddr != "" {
+		a.GRPC = grpcapi.New(rm, hub, authSvc)
+	}
+	return a, nil
+}
+
+// OpenStore returns the store selected by STORE_BACKEND and a function that
+// releases it
+func OpenStore(cfg *config.Config) (Backend, func(), error) {
+	switch cfg.StoreBackend {
+	case "memory":
+		return store.NewMemoryStore(), func() {}, nil
+	case "postgres":
+		pg, err := store.OpenPostgres(cfg.DatabaseDriver, cfg.DatabaseURL)
+		if err != nil {
+			return nil, nil, fmt.Errorf("postgres store: %w", err)
+		}
+		log.Printf("Using postgres store")
+		return pg, func() { pg.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown STORE_BACKEND %q (want memory or postgres)", cfg.StoreBackend)
+	}
+}
+
+// Run serves on the configured addresses until ctx is cancelled, then shuts
+// down gracefully
+func (a *App) Run(ctx context.Context) error {
+	lis, err := net.Listen("tcp", a.Config.HTTPAddr)
+	if err != nil {
+		return err
+	}
+	log.Printf("listening on %s", a.Config.HTTPAddr)
+	return a.Serve(ctx, lis)
+}
+
+// Serve is Run with the HTTP listener given, so callers can pick a free port.
+// It returns nil after a shutdown and the error when serving fails.
+func (a *App) Serve(ctx context.Context, lis net.Listener) error {
+	a.Queue.Start()
+
+	if a.GRPC != nil {
+		glis, err := net.Listen("tcp", a.Config.GRPCAddr)
+		if err != nil {
+			lis.Close()
+			a.shutdown(context.Background())
+			return fmt.Errorf("grpc: %w", err)
+		}
+		log.Printf("gRPC listening on %s", a.Config.GRPCAddr)
+		go func() {
+			if err := a.GRPC.Serve(glis); err != nil {
+				log.Printf("gRPC server stopped: %v", err)
+			}
+		}()
+	}
+
+	served := make(chan error, 1)
+	go func() { served <- a.http.Serve(lis) }()
+
+	select {
+	case err := <-served:
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+		a.shutdown(shutdownCtx)
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+		defer cancel()
+		return a.Shutdown(shutdownCtx)
+	}
+}
+
+// Shutdown stops accepting requests, waits for those in flight until ctx
+// ends, then stops matchmaking and releases the store. WebSocket connections
+// are not waited for.
+func (a *App) Shutdown(ctx context.Context) error {
+	err := a.http.Shutdown(ctx)
+	a.shutdown(ctx)
+	return err
+}
+
+// shutdown stops everything but the HTTP server, once
+func (a *App) shutdown(ctx context.Context) {
+	a.stopOnce.Do(func() {
+		if a.GRPC != nil {
+			stopGRPC(ctx, a.GRPC)
+		}
+		a.Queue.Stop()
+		if err := a.Training.Close(); err != nil {
+			log.Printf("Training export: %v", err)
+		}
+		if a.Policy != nil {
+			a.Policy.Close()
+		}
+		a.closeStore()
+	})
+}
+
+// stopGRPC lets running calls finish until ctx ends, then cuts them off.
+// Open watch streams only end with their clients, so they hold the stop up
+// until ctx ends.
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		srv.Stop()
+	}
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"javanese-chess/internal/apierr"
+
+	"github.com/gin-gonic/gin"
+)
+
+const contextKey = "auth.claims"
+
+// Middleware attaches the authenticated user to the request when a valid
+// token is supplied, either as "Authorization: Bearer <token>" or, for
+// WebSocket upgrades where headers cannot be set, as the "token" query
+// parameter. Requests without a token continue anonymously; requests with
+// an invalid token are rejected.
+func Middleware(s *Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := tokenFromRequest(c)
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		claims, err := s.ParseToken(token)
+		if err != nil {
+			apierr.Abort(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		c.Set(contextKey, claims)
+		c.Next()
+	}
+}
+
+// RequireAuth rejects requests that did not authenticate via Middleware
+func RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := UserFrom(c); !ok {
+			apierr.Abort(c, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		c.Next()
+	}
+}
+
+// UserFrom returns the authenticated user's claims, if any
+func UserFrom(c *gin.Context) (*Claims, bool) {
+	v, ok := c.Get(contextKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := v.(*Claims)
+	return claims, ok
+}
+
+// UserID returns the authenticated user's ID or "" for anonymous requests
+func UserID(c *gin.Context) string {
+	if claims, ok := UserFrom(c); ok {
+		return claims.UserID
+	}
+	return ""
+}
+
+func tokenFromRequest(c *gin.Context) string {
+	if h := c.GetHeader("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		return strings.TrimPrefix(h, "Bearer ")
+	}
+	return c.Query("token")
+}
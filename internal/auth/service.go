@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// User is a registered player account
+type User struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash []byte    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UserStore persists accounts
+type UserStore interface {
+	GetUserByName(username string) (*User, bool)
+	SaveUser(u *User)
+}
+
+var (
+	ErrUserExists         = errors.New("username already taken")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+)
+
+// Service registers users and issues and verifies their tokens
+type Service struct {
+	users  UserStore
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewService(users UserStore, secret string, ttl time.Duration) *Service {
+	return &Service{users: users, secret: []byte(secret), ttl: ttl}
+}
+
+// Register creates an account with a bcrypt-hashed password
+func (s *Service) Register(username, password string) (*User, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, errors.New("username is required")
+	}
+	if len(password) < 8 {
+		return nil, errors.New("password must be at least 8 characters")
+	}
+	if _, exists := s.users.GetUserByName(username); exists {
+		return nil, ErrUserExists
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &User{
+		ID:           uuid.NewString(),
+		Username:     username,
+		PasswordHash: hash,
+		CreatedAt:    time.Now(),
+	}
+	s.users.SaveUser(u)
+	return u, nil
+}
+
+// Login checks credentials and returns a signed token for the user
+func (s *Service) Login(username, password string) (string, *User, error) {
+	u, ok := s.users.GetUserByName(strings.TrimSpace(username))
+	if !ok {
+		return "", nil, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(password)); err != nil {
+		return "", nil, ErrInvalidCredentials
+	}
+
+	token, err := s.IssueToken(u)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, u, nil
+}
+
+// IssueToken signs a token for the user valid for the service TTL
+func (s *Service) IssueToken(u *User) (string, error) {
+	now := time.Now()
+	return signToken(Claims{
+		UserID:    u.ID,
+		Username:  u.Username,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.ttl).Unix(),
+	}, s.secret)
+}
+
+// ParseToken verifies a token and returns its claims
+func (s *Service) ParseToken(token string) (*Claims, error) {
+	return parseToken(token, s.secret, time.Now())
+}
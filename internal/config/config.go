@@ -1,9 +1,18 @@
 package config
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
 	"os"
 	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Constants from the research paper "The Mechanics and Heuristics of Javanese Chess" Section 2.4
@@ -11,6 +20,8 @@ import (
 const (
 	// Game Constants
 	DefaultBoardSize = 9 // Standard Javanese Chess board is 9x9
+	MinPlayers       = 2 // A game needs at least two seats
+	MaxPlayers       = 4 // One seat per player color
 
 	// Base heuristic values from the research table
 
@@ -34,23 +45,148 @@ const (
 	// Block opponent's path values (context-dependent)
 	DefaultBlockWhenThreat = 100 // Blocking 3-in-a-row completion
 	DefaultBlockPotential  = 70  // Blocking 2-in-a-row extension
+	DefaultBlockOpen3      = 90  // Blocking 2-in-a-row extension into an open three
 
 	// Formation building (our cards in a row)
 	DefaultBuildAlignment2 = 50  // 2 of our cards in a row
-	DefaultBuildAlignment3 = 100 // 3 of our cards in a row
+	DefaultBuildAlignment3 = 100 // 3 of our cards in a row, open on both ends
+	DefaultBuildHalfOpen3  = 60  // 3 of our cards in a row, open on one end
+
+	// Double threat: two cells that would each complete 4-in-a-row
+	DefaultWFork = 120
+
+	// Lookahead: penalty when any opponent reply wins at once
+	DefaultWRefute = 1000
 
 	// Card management bonuses
 	DefaultPlaySmallestCard = 60 // Bonus for playing smallest card in hand
 	DefaultKeepNearCard     = 60 // Bonus for placing card close to our own cards
+
+	// Hold rooms: bots hold a card when no placement scores this much
+	DefaultHoldBelow = 120
+)
+
+// Bot engines
+const (
+	BotEngineHeuristic = "heuristic"
+	BotEngineNN        = "nn"
+)
+
+// DefaultNNBlend lets the network choose on its own
+const DefaultNNBlend = 1.0
+
+// Matchmaking defaults for quick play
+const (
+	QuickplayMinPlayers     = 2
+	QuickplayMaxPlayers     = 4
+	QuickplayGatherWindow   = 10 * time.Second // Wait for more humans before starting a smaller game
+	QuickplayBotFillTimeout = 30 * time.Second // Fill a lone player's game with bots
+	QuickplayTickInterval   = 1 * time.Second
+)
+
+// Room event log kept in memory for the debug endpoint: events per room and
+// rooms tracked before the oldest is forgotten
+const (
+	RoomLogSize  = 200
+	RoomLogRooms = 1000
+)
+
+// Rooms the postgres store keeps cached in memory; the least recently used
+// is dropped beyond this and read back from the database when needed
+const PostgresCacheRooms = 1000
+
+// Tournament limits
+const (
+	TournamentMaxEntrants = 32
+	TournamentNameMax     = 64 // Characters
 )
 
+// AnalysisHandMax bounds the hands clients supply to the analysis and
+// stateless bot endpoints; hands in play hold 3 cards
+const AnalysisHandMax = 10
+
 // Config holds all configuration values
 type Config struct {
 	HTTPAddr  string
 	BoardSize int
 
+	// GRPCAddr is where the gRPC game service listens; empty disables it
+	GRPCAddr string
+
+	// Environment profile (gin mode, logging, debug routes, default CORS
+	// origins)
+	Profile Profile
+
+	// CORSOrigins are the browser origins allowed to call the REST API and
+	// open WebSockets. "*" allows every origin and "https://*.example.com"
+	// any subdomain of example.com; an empty list allows only the server's
+	// own origin.
+	// CORSHeaders are the request headers browsers may send cross-origin.
+	CORSOrigins []string
+	CORSHeaders []string
+
+	// TrustedProxies are the addresses or CIDR ranges of reverse proxies
+	// whose X-Forwarded-For header names the client; an empty list trusts
+	// none, so the client is the connection's peer
+	TrustedProxies []string
+
+	// RatingsFile is where player ratings are persisted by stores that do
+	// not keep them, such as the memory store
+	RatingsFile string
+
+	// StoreBackend selects room persistence: "memory" or "postgres"
+	StoreBackend   string
+	DatabaseURL    string
+	DatabaseDriver string // database/sql driver name linked into the binary
+
+	// Authentication
+	JWTSecret string
+	TokenTTL  time.Duration
+
+	// AdminToken guards the admin API; empty disables it
+	AdminToken string
+
+	// LogFile receives the server log alongside stdout
+	LogFile string
+
+	// TrainingDir receives a JSONL record of every move for training models
+	// on real games; empty disables the export
+	TrainingDir string
+
 	// Default heuristic weights (global)
 	DefaultWeights HeuristicWeights
+
+	// Default bot move sampling for new rooms
+	BotTemperature      float64
+	BotTemperatureMoves int
+
+	// BotWorkers is how many goroutines, shared by all rooms, help score bot
+	// candidate moves besides the bot's own (0 scores them one by one)
+	BotWorkers int
+
+	// BotEngine picks how bots choose moves: BotEngineHeuristic, or
+	// BotEngineNN for a policy network loaded from NNModelPath by the
+	// NNRuntime linked into the binary. NNBlend weighs the network against
+	// the heuristic, from 0 (heuristic only) to 1 (network only). Bots fall
+	// back to the heuristic when the model cannot be loaded.
+	BotEngine   string
+	NNModelPath string
+	NNRuntime   string
+	NNBlend     float64
+
+	// Token bucket rate limits: REST requests per client IP and WebSocket
+	// actions per player, in events per second (0 disables the limit)
+	HTTPRateLimit float64
+	HTTPRateBurst int
+	WSRateLimit   float64
+	WSRateBurst   int
+
+	// Tracing exports spans to an OTLP/HTTP collector; an empty endpoint
+	// disables it. Read from the standard OTEL_* variables.
+	TracingEndpoint    string
+	TracingHeaders     map[string]string
+	TracingService     string
+	TracingSampleRatio float64
 }
 
 // HeuristicWeights represents AI evaluation parameters
@@ -81,75 +217,207 @@ type HeuristicWeights struct {
 	// Block opponent's path values (context-dependent)
 	BlockWhenThreat int `json:"block_when_threat"` // 100 for blocking 3-in-a-row
 	BlockPotential  int `json:"block_potential"`   // 70 for blocking 2-in-a-row
+	BlockOpen3      int `json:"block_open_3"`      // 90 for blocking 2-in-a-row from an open three
 
 	// Formation building (our cards in a row)
 	BuildAlignment2 int `json:"build_alignment_2"` // 50 for 2-in-a-row
-	BuildAlignment3 int `json:"build_alignment_3"` // 100 for 3-in-a-row
+	BuildAlignment3 int `json:"build_alignment_3"` // 100 for 3-in-a-row open on both ends
+	BuildHalfOpen3  int `json:"build_half_open_3"` // 60 for 3-in-a-row open on one end
+
+	// Double threat (fork): the move leaves two ways to complete 4-in-a-row
+	WFork int `json:"w_fork"` // 120
+
+	// Lookahead: subtracted when an opponent could win on the very next move
+	WRefute int `json:"w_refute"` // 1000
 
 	// Card management bonuses
 	PlaySmallestCard int `json:"play_smallest_card"` // 60 for playing smallest card
 	KeepNearCard     int `json:"keep_near_card"`     // 60 for placing near own cards
+
+	// Hold rooms: hold a card instead when no placement scores this much; 0 never holds
+	HoldBelow int `json:"hold_below"` // 120
 }
 
 // RoomConfig holds configuration for a specific room
 type RoomConfig struct {
 	RoomCode string           `json:"room_code"`
 	Weights  HeuristicWeights `json:"weights"`
+
+	// Temperature makes bots sample among near-best moves (0 = always the best)
+	// during the first TemperatureMoves moves of a game
+	Temperature      float64 `json:"temperature"`
+	TemperatureMoves int     `json:"temperature_moves"`
+
+	// BotDelayMs is the pause before each bot move (0 = the server default).
+	// A BotDelayMaxMs above it makes the pause vary between the two.
+	BotDelayMs    int `json:"bot_delay_ms,omitempty"`
+	BotDelayMaxMs int `json:"bot_delay_max_ms,omitempty"`
+
+	// BotBudgetMs is how long a bot may spend scoring its candidate moves
+	// (0 = DefaultBotBudget)
+	BotBudgetMs int `json:"bot_budget_ms,omitempty"`
+
+	// Deck is the card composition players are dealt from
+	Deck DeckSpec `json:"deck"`
+
+	// Features toggles experimental rules (see FeaturePermanentNine and the
+	// other flags); rooms apply them when a game starts
+	Features map[string]bool `json:"features,omitempty"`
 	mu       sync.RWMutex
 }
 
+// DeckSpec describes the cards in a deck: Copies of every value from MinCard
+// to MaxCard. The highest card is permanent once placed. Shared deals every
+// player from one deck instead of a personal deck each.
+type DeckSpec struct {
+	Copies  int  `json:"copies"`
+	MinCard int  `json:"min_card"`
+	MaxCard int  `json:"max_card"`
+	Shared  bool `json:"shared"`
+}
+
+// DefaultDeckSpec is the classic personal deck of two sets of 1-9
+func DefaultDeckSpec() DeckSpec {
+	return DeckSpec{Copies: DefaultDeckCopies, MinCard: DefaultMinCard, MaxCard: DefaultMaxCard}
+}
+
+// WithDefaults fills in the classic value of every unset field, so rooms
+// saved before decks were configurable keep their decks
+func (d DeckSpec) WithDefaults() DeckSpec {
+	if d.Copies == 0 {
+		d.Copies = DefaultDeckCopies
+	}
+	if d.MinCard == 0 {
+		d.MinCard = DefaultMinCard
+	}
+	if d.MaxCard == 0 {
+		d.MaxCard = DefaultMaxCard
+	}
+	return d
+}
+
+// Values is the number of distinct card values in the deck
+func (d DeckSpec) Values() int {
+	return d.MaxCard - d.MinCard + 1
+}
+
+// Size is the number of cards in one deck
+func (d DeckSpec) Size() int {
+	return d.Copies * d.Values()
+}
+
 var globalConfig *Config
 var once sync.Once
 
-// Load initializes the global configuration with default values from paper
+// Load initializes the global configuration: the defaults from the paper,
+// overridden by the config file and then by environment variables
 func Load() *Config {
 	once.Do(func() {
+		f, fileErr := readFile(ConfigFile())
+		srv := f.Server
+		defaults, defaultsErr := resolveDefaults(f.Defaults)
+		if defaultsErr != nil {
+			defaults = builtinDefaults()
+		}
+
+		profile := getProfile()
+		secret, secretErr := getJWTSecret(profile)
+
 		globalConfig = &Config{
-			HTTPAddr:  getHTTPAddr(),
-			BoardSize: DefaultBoardSize,
-			DefaultWeights: HeuristicWeights{
-				// Base values from heuristic table
-				LegalMove: DefaultLegalMoveValue, // 30
-				WWin:      DefaultWWin,           // 10000
-				WThreat:   DefaultWThreat,        // 200
-
-				// Card values when blocking threat (high cards preferred: 1→20, 9→100)
-				ReplaceValuesThreat: map[int]int{
-					1: 20, 2: 30, 3: 40, 4: 50, 5: 60,
-					6: 70, 7: 80, 8: 90, 9: 100,
-				},
-
-				// Card values for defensive play (low cards preferred: 1→100, 9→20)
-				ReplaceValuesPotential: map[int]int{
-					1: 100, 2: 90, 3: 80, 4: 70, 5: 60,
-					6: 50, 7: 40, 8: 30, 9: 20,
-				},
-
-				// Replace opponent's card values
-				ReplaceWhenThreat: DefaultReplaceWhenThreat, // 200
-				ReplacePotential:  DefaultReplacePotential,  // 125
-
-				// Position bonuses when replacing
-				ReplacePosCenter: DefaultReplacePosCenter, // 75
-				ReplacePosSide:   DefaultReplacePosSide,   // 50
-
-				// Block opponent's path values
-				BlockWhenThreat: DefaultBlockWhenThreat, // 100
-				BlockPotential:  DefaultBlockPotential,  // 70
-
-				// Formation building
-				BuildAlignment2: DefaultBuildAlignment2, // 50
-				BuildAlignment3: DefaultBuildAlignment3, // 100
-
-				// Card management bonuses
-				PlaySmallestCard: DefaultPlaySmallestCard, // 60
-				KeepNearCard:     DefaultKeepNearCard,     // 60
-			},
+			HTTPAddr:    getHTTPAddr(valueOr(srv.HTTPAddr, ":9000")),
+			GRPCAddr:    getEnv("GRPC_ADDR", valueOr(srv.GRPCAddr, "")),
+			BoardSize:   DefaultBoardSize,
+			Profile:     profile,
+			RatingsFile: getEnv("RATINGS_FILE", valueOr(srv.RatingsFile, "ratings.json")),
+			AdminToken:  os.Getenv("ADMIN_TOKEN"),
+
+			CORSOrigins:    getEnvList("CORS_ORIGINS", valueOr(srv.CORSOrigins, profile.AllowedOrigins)),
+			CORSHeaders:    getEnvList("CORS_HEADERS", valueOr(srv.CORSHeaders, DefaultCORSHeaders)),
+			TrustedProxies: getEnvList("TRUSTED_PROXIES", valueOr(srv.TrustedProxies, nil)),
+
+			StoreBackend:   getEnv("STORE_BACKEND", valueOr(srv.StoreBackend, "memory")),
+			DatabaseURL:    os.Getenv("DATABASE_URL"),
+			DatabaseDriver: getEnv("DATABASE_DRIVER", "pgx"),
+			LogFile:        getEnv("LOG_FILE", valueOr(srv.LogFile, "javanese-chess.log")),
+			TrainingDir:    getEnv("TRAINING_DIR", valueOr(srv.TrainingDir, "")),
+
+			BotTemperature:      defaults.BotTemperature,
+			BotTemperatureMoves: defaults.BotTemperatureMoves,
+			BotWorkers:          getEnvInt("BOT_WORKERS", valueOr(srv.BotWorkers, runtime.NumCPU())),
+			BotEngine:           getEnv("BOT_ENGINE", valueOr(srv.BotEngine, BotEngineHeuristic)),
+			NNModelPath:         getEnv("NN_MODEL", valueOr(srv.NNModel, "")),
+			NNRuntime:           getEnv("NN_RUNTIME", valueOr(srv.NNRuntime, "onnx")),
+			NNBlend:             getEnvFloat("NN_BLEND", valueOr(srv.NNBlend, DefaultNNBlend)),
+			HTTPRateLimit:       getEnvFloat("HTTP_RATE_LIMIT", valueOr(srv.HTTPRateLimit, DefaultHTTPRateLimit)),
+			HTTPRateBurst:       getEnvInt("HTTP_RATE_BURST", valueOr(srv.HTTPRateBurst, DefaultHTTPRateBurst)),
+			WSRateLimit:         getEnvFloat("WS_RATE_LIMIT", valueOr(srv.WSRateLimit, DefaultWSRateLimit)),
+			WSRateBurst:         getEnvInt("WS_RATE_BURST", valueOr(srv.WSRateBurst, DefaultWSRateBurst)),
+			JWTSecret:           secret,
+			TokenTTL:            DefaultTokenTTL,
+			TracingEndpoint:     getTracingEndpoint(),
+			TracingHeaders:      getEnvPairs("OTEL_EXPORTER_OTLP_HEADERS"),
+			TracingService:      getEnv("OTEL_SERVICE_NAME", "javanese-chess"),
+			TracingSampleRatio:  getEnvFloat("OTEL_TRACES_SAMPLER_ARG", 1),
+			DefaultWeights:      defaults.Weights,
 		}
+		loadErr = errors.Join(fileErr, defaultsErr, secretErr, globalConfig.validate())
 	})
 	return globalConfig
 }
 
+// builtinWeights returns the heuristic weights from the paper
+func builtinWeights() HeuristicWeights {
+	return HeuristicWeights{
+		// Base values from heuristic table
+		LegalMove: DefaultLegalMoveValue, // 30
+		WWin:      DefaultWWin,           // 10000
+		WThreat:   DefaultWThreat,        // 200
+
+		// Card values when blocking threat (high cards preferred: 1→20, 9→100)
+		ReplaceValuesThreat: map[int]int{
+			1: 20, 2: 30, 3: 40, 4: 50, 5: 60,
+			6: 70, 7: 80, 8: 90, 9: 100,
+		},
+
+		// Card values for defensive play (low cards preferred: 1→100, 9→20)
+		ReplaceValuesPotential: map[int]int{
+			1: 100, 2: 90, 3: 80, 4: 70, 5: 60,
+			6: 50, 7: 40, 8: 30, 9: 20,
+		},
+
+		// Replace opponent's card values
+		ReplaceWhenThreat: DefaultReplaceWhenThreat, // 200
+		ReplacePotential:  DefaultReplacePotential,  // 125
+
+		// Position bonuses when replacing
+		ReplacePosCenter: DefaultReplacePosCenter, // 75
+		ReplacePosSide:   DefaultReplacePosSide,   // 50
+
+		// Block opponent's path values
+		BlockWhenThreat: DefaultBlockWhenThreat, // 100
+		BlockPotential:  DefaultBlockPotential,  // 70
+		BlockOpen3:      DefaultBlockOpen3,      // 90
+
+		// Formation building
+		BuildAlignment2: DefaultBuildAlignment2, // 50
+		BuildAlignment3: DefaultBuildAlignment3, // 100
+		BuildHalfOpen3:  DefaultBuildHalfOpen3,  // 60
+
+		// Double threats
+		WFork: DefaultWFork, // 120
+
+		// Lookahead
+		WRefute: DefaultWRefute, // 1000
+
+		// Card management bonuses
+		PlaySmallestCard: DefaultPlaySmallestCard, // 60
+		KeepNearCard:     DefaultKeepNearCard,     // 60
+
+		// Holding
+		HoldBelow: DefaultHoldBelow, // 120
+	}
+}
+
 // Get returns the global configuration
 func Get() *Config {
 	if globalConfig == nil {
@@ -160,12 +428,104 @@ func Get() *Config {
 
 // NewRoomConfig creates a new room configuration with default weights
 func NewRoomConfig(roomCode string) *RoomConfig {
+	cfg := Get()
 	return &RoomConfig{
-		RoomCode: roomCode,
-		Weights:  Get().DefaultWeights,
+		RoomCode:         roomCode,
+		Weights:          cfg.DefaultWeights,
+		Temperature:      cfg.BotTemperature,
+		TemperatureMoves: cfg.BotTemperatureMoves,
+		Deck:             DefaultDeckSpec(),
 	}
 }
 
+// GetTemperature returns the bot sampling temperature (thread-safe)
+func (rc *RoomConfig) GetTemperature() float64 {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	return rc.Temperature
+}
+
+// GetTemperatureMoves returns how many opening moves use sampling (thread-safe)
+func (rc *RoomConfig) GetTemperatureMoves() int {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	return rc.TemperatureMoves
+}
+
+// SetTemperature updates bot sampling for this room (thread-safe)
+func (rc *RoomConfig) SetTemperature(temperature float64, moves int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.Temperature = temperature
+	rc.TemperatureMoves = moves
+}
+
+// GetBotDelay returns the pause before each bot move, or 0 when the room
+// uses the server default (thread-safe)
+func (rc *RoomConfig) GetBotDelay() time.Duration {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	return time.Duration(rc.BotDelayMs) * time.Millisecond
+}
+
+// SetBotDelay updates the pause before each bot move (thread-safe)
+func (rc *RoomConfig) SetBotDelay(d time.Duration) {
+	rc.SetBotDelayRange(d, d)
+}
+
+// GetBotDelayRange returns the shortest and longest pause before each bot
+// move; both are 0 when the room uses the server default (thread-safe)
+func (rc *RoomConfig) GetBotDelayRange() (min, max time.Duration) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	min = time.Duration(rc.BotDelayMs) * time.Millisecond
+	max = time.Duration(rc.BotDelayMaxMs) * time.Millisecond
+	return min, max
+}
+
+// SetBotDelayRange makes the pause before each bot move vary between min
+// and max (thread-safe)
+func (rc *RoomConfig) SetBotDelayRange(min, max time.Duration) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.BotDelayMs = int(min / time.Millisecond)
+	rc.BotDelayMaxMs = int(max / time.Millisecond)
+}
+
+// GetBotBudget returns how long a bot may spend scoring its moves
+// (thread-safe)
+func (rc *RoomConfig) GetBotBudget() time.Duration {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	if rc.BotBudgetMs == 0 {
+		return DefaultBotBudget
+	}
+	return time.Duration(rc.BotBudgetMs) * time.Millisecond
+}
+
+// SetBotBudget updates how long a bot may spend scoring its moves, 0 for the
+// default (thread-safe)
+func (rc *RoomConfig) SetBotBudget(d time.Duration) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.BotBudgetMs = int(d / time.Millisecond)
+}
+
+// GetDeck returns the room's deck composition with defaults filled in
+// (thread-safe)
+func (rc *RoomConfig) GetDeck() DeckSpec {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	return rc.Deck.WithDefaults()
+}
+
+// SetDeck updates the room's deck composition (thread-safe)
+func (rc *RoomConfig) SetDeck(deck DeckSpec) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.Deck = deck
+}
+
 // GetWeights returns the current weights for this room (thread-safe)
 func (rc *RoomConfig) GetWeights() HeuristicWeights {
 	rc.mu.RLock()
@@ -189,15 +549,32 @@ func (rc *RoomConfig) IsCustomized() bool {
 	return !reflect.DeepEqual(rc.Weights, defaults)
 }
 
+// Clone returns a copy of the weights that shares no maps with w
+func (w HeuristicWeights) Clone() HeuristicWeights {
+	copyMap := func(m map[int]int) map[int]int {
+		if m == nil {
+			return nil
+		}
+		out := make(map[int]int, len(m))
+		for k, v := range m {
+			out[k] = v
+		}
+		return out
+	}
+	w.ReplaceValuesThreat = copyMap(w.ReplaceValuesThreat)
+	w.ReplaceValuesPotential = copyMap(w.ReplaceValuesPotential)
+	return w
+}
+
 // ValidateWeights checks if weights are within reasonable ranges
 func (w *HeuristicWeights) ValidateWeights() bool {
 	// All weights should be non-negative
 	if w.LegalMove < 0 || w.WWin < 0 || w.WThreat < 0 ||
 		w.ReplaceWhenThreat < 0 || w.ReplacePotential < 0 ||
 		w.ReplacePosCenter < 0 || w.ReplacePosSide < 0 ||
-		w.BlockWhenThreat < 0 || w.BlockPotential < 0 ||
-		w.BuildAlignment2 < 0 || w.BuildAlignment3 < 0 ||
-		w.PlaySmallestCard < 0 || w.KeepNearCard < 0 {
+		w.BlockWhenThreat < 0 || w.BlockPotential < 0 || w.BlockOpen3 < 0 ||
+		w.BuildAlignment2 < 0 || w.BuildAlignment3 < 0 || w.BuildHalfOpen3 < 0 || w.WFork < 0 || w.WRefute < 0 ||
+		w.PlaySmallestCard < 0 || w.KeepNearCard < 0 || w.HoldBelow < 0 {
 		return false
 	}
 	for _, v := range w.ReplaceValuesThreat {
@@ -213,14 +590,285 @@ func (w *HeuristicWeights) ValidateWeights() bool {
 	return true
 }
 
-// getHTTPAddr returns the HTTP address from environment or default
+// getHTTPAddr returns the HTTP address from environment or fallback
 // This is kept configurable for deployment flexibility (dev/staging/prod)
-func getHTTPAddr() string {
+func getHTTPAddr(fallback string) string {
 	if addr := os.Getenv("HTTP_ADDR"); addr != "" {
 		return addr
 	}
-	return ":9000" // Default port
+	return fallback
+}
+
+// getEnv returns an environment variable or a fallback when it is unset
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// getEnvFloat returns a float environment variable or a fallback when it is
+// unset or malformed
+func getEnvFloat(key string, fallback float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return fallback
+}
+
+// getEnvInt returns an integer environment variable or a fallback when it is
+// unset or malformed
+func getEnvInt(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+// Default rate limits (events per second and burst size)
+const (
+	DefaultHTTPRateLimit = 10.0
+	DefaultHTTPRateBurst = 30
+	DefaultWSRateLimit   = 5.0
+	DefaultWSRateBurst   = 15
+)
+
+// Shared deck variant limits and the bot's penalty for placing a card that an
+// unseen card could overwrite (scaled by the chance of that happening)
+const (
+	MaxSharedDeckCopies       = 8
+	SharedDeckExposurePenalty = 60
+)
+
+// Deck composition defaults and limits (see DeckSpec)
+const (
+	DefaultDeckCopies = 2
+	DefaultMinCard    = 1
+	DefaultMaxCard    = 9
+	MaxDeckCopies     = 8
+	MaxCardValue      = 20
+)
+
+// CellLockCaptures is how many captures lock a cell in the cell-lock variant
+const CellLockCaptures = 2
+
+// AbortMaxPlies is how many moves may be played before a game can no longer
+// be aborted without a result
+const AbortMaxPlies = 2
+
+// In-room chat limits: message length, log size kept per room, and at most
+// ChatRateLimit messages per player within ChatRateWindow
+const (
+	ChatMaxLength  = 280
+	ChatLogSize    = 100
+	ChatRateLimit  = 5
+	ChatRateWindow = 10 * time.Second
+)
+
+// Quick reactions: at most ReactionRateLimit per player within
+// ReactionRateWindow, from the fixed set of Reactions clients can show
+const (
+	ReactionRateLimit  = 3
+	ReactionRateWindow = 5 * time.Second
+)
+
+// Reactions are the reaction codes players may send
+var Reactions = []string{"thumbs_up", "clap", "laugh", "wow", "thinking", "sad", "gg"}
+
+// Connection quality thresholds, applied to a player's smoothed RTT plus
+// twice its jitter, and the base grace period a dropped player gets to
+// reconnect before the room gives up on them, unless the room sets its own.
+// Fair and poor connections get two and three times the base grace period.
+const (
+	QualityGoodRTT = 150 * time.Millisecond
+	QualityFairRTT = 400 * time.Millisecond
+	ReconnectGrace = 30 * time.Second
+)
+
+// Longest a room may wait before giving up on a disconnected player
+const (
+	MaxAbandonTurns   = 10
+	MaxAbandonMinutes = 60
+)
+
+// DefaultTimeBankCap is the most turn time (seconds) a player can bank in
+// timed rooms when the room does not set its own cap
+const DefaultTimeBankCap = 60
+
+// Bot move sampling defaults. A temperature of 0 keeps bots deterministic.
+const (
+	DefaultBotTemperature      = 0.0
+	DefaultBotTemperatureMoves = 8
+)
+
+// Time a bot may spend scoring its candidate moves. Moves not scored when the
+// budget runs out are passed over; the default is far above what a turn
+// takes, so it only cuts in on an overloaded server.
+const (
+	DefaultBotBudget = 2 * time.Second
+	MaxBotBudget     = 30 * time.Second
+)
+
+// Move scores the bot evaluation pool remembers by position, shared by all
+// rooms; the table overwrites older entries once full
+const EvalCacheEntries = 1 << 18
+
+// Longest pause a room may set before its bots move
+const MaxBotDelay = 10 * time.Second
+
+// Pause between moves in demo rooms, where bots play each other for
+// spectators
+const (
+	DefaultDemoDelay = 1500 * time.Millisecond
+	MinDemoDelay     = 200 * time.Millisecond
+	MaxDemoDelay     = 10 * time.Second
+)
+
+// DefaultTokenTTL is how long issued login tokens stay valid
+const DefaultTokenTTL = 24 * time.Hour
+
+// getJWTSecret returns the token signing secret from the environment.
+// Production refuses to start without one; other profiles sign with a random
+// secret that lasts as long as the process, so tokens and seat secrets do not
+// survive a restart.
+func getJWTSecret(profile Profile) (string, error) {
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		return secret, nil
+	}
+	if profile.Name == EnvProduction {
+		return "", errors.New("JWT_SECRET must be set in production")
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating a JWT secret: %w", err)
+	}
+	log.Printf("Warning: JWT_SECRET is not set, signing with a random secret for this process")
+	return hex.EncodeToString(buf), nil
+}
+
+// getTracingEndpoint returns the OTLP/HTTP traces URL, either given in full or
+// derived from the collector's base URL
+func getTracingEndpoint() string {
+	if url := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); url != "" {
+		return url
+	}
+	if base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); base != "" {
+		return strings.TrimSuffix(base, "/") + "/v1/traces"
+	}
+	return ""
+}
+
+// getEnvList reads a comma-separated list, or returns fallback when the
+// variable is unset. A variable set but empty is an empty list.
+func getEnvList(key string, fallback []string) []string {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	list := []string{}
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getEnvPairs reads a comma-separated list of key=value pairs
+func getEnvPairs(key string) map[string]string {
+	pairs := map[string]string{}
+	for _, kv := range strings.Split(os.Getenv(key), ",") {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			pairs[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return pairs
 }
 
 // DefaultPlayerColors defines the available colors for players
 var DefaultPlayerColors = []string{"red", "green", "blue", "purple"}
+
+// BotPersona is a named bot character with its own baseline difficulty
+type BotPersona struct {
+	Name           string  `json:"name"`
+	BaseDifficulty float64 `json:"base_difficulty"` // 1.0 always plays the best move
+}
+
+// Bounds for adaptive persona difficulty
+const (
+	MinPersonaDifficulty = 0.3
+	MaxPersonaDifficulty = 1.0
+)
+
+// DefaultBotPersonas defines the personas assigned to bots in round-robin order
+var DefaultBotPersonas = []BotPersona{
+	{Name: "Semar", BaseDifficulty: 0.9},
+	{Name: "Gareng", BaseDifficulty: 0.7},
+	{Name: "Petruk", BaseDifficulty: 0.6},
+	{Name: "Bagong", BaseDifficulty: 0.5},
+}
+
+// BotPersonality scales groups of heuristic weights to give a bot a play
+// style. A factor of 1.0 keeps the base weight.
+type BotPersonality struct {
+	Name      string  `json:"name"`
+	Replace   float64 `json:"replace"`   // Replacing (capturing) opponent cards
+	Block     float64 `json:"block"`     // Blocking opponent lines and threats
+	Formation float64 `json:"formation"` // Building our own lines
+	Economy   float64 `json:"economy"`   // Card management and proximity
+}
+
+// BotPersonalities are the named play styles selectable per bot
+var BotPersonalities = map[string]BotPersonality{
+	"aggressive": {Name: "aggressive", Replace: 1.6, Block: 0.7, Formation: 1.2, Economy: 1.0},
+	"defensive":  {Name: "defensive", Replace: 1.0, Block: 1.8, Formation: 0.8, Economy: 1.0},
+	"greedy":     {Name: "greedy", Replace: 0.8, Block: 0.6, Formation: 1.6, Economy: 1.4},
+}
+
+// GetBotPersonality looks up a personality by name
+func GetBotPersonality(name string) (BotPersonality, bool) {
+	p, ok := BotPersonalities[name]
+	return p, ok
+}
+
+// Apply returns a copy of base with the personality's factors applied. The
+// legal move and winning move weights are never changed.
+func (p BotPersonality) Apply(base HeuristicWeights) HeuristicWeights {
+	scale := func(v int, f float64) int {
+		return int(float64(v)*f + 0.5)
+	}
+	scaleMap := func(m map[int]int, f float64) map[int]int {
+		out := make(map[int]int, len(m))
+		for k, v := range m {
+			out[k] = scale(v, f)
+		}
+		return out
+	}
+
+	w := base
+	w.ReplaceValuesThreat = scaleMap(base.ReplaceValuesThreat, p.Replace)
+	w.ReplaceValuesPotential = scaleMap(base.ReplaceValuesPotential, p.Replace)
+	w.ReplaceWhenThreat = scale(base.ReplaceWhenThreat, p.Replace)
+	w.ReplacePotential = scale(base.ReplacePotential, p.Replace)
+	w.ReplacePosCenter = scale(base.ReplacePosCenter, p.Replace)
+	w.ReplacePosSide = scale(base.ReplacePosSide, p.Replace)
+
+	w.WThreat = scale(base.WThreat, p.Block)
+	w.BlockWhenThreat = scale(base.BlockWhenThreat, p.Block)
+	w.BlockPotential = scale(base.BlockPotential, p.Block)
+	w.BlockOpen3 = scale(base.BlockOpen3, p.Block)
+	w.WRefute = scale(base.WRefute, p.Block)
+
+	w.BuildAlignment2 = scale(base.BuildAlignment2, p.Formation)
+	w.BuildAlignment3 = scale(base.BuildAlignment3, p.Formation)
+	w.BuildHalfOpen3 = scale(base.BuildHalfOpen3, p.Formation)
+	w.WFork = scale(base.WFork, p.Formation)
+
+	w.PlaySmallestCard = scale(base.PlaySmallestCard, p.Economy)
+	w.KeepNearCard = scale(base.KeepNearCard, p.Economy)
+	return w
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// DefaultCORSHeaders are the request headers browsers may send cross-origin
+// when neither CORS_HEADERS nor the config file names any
+var DefaultCORSHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+
+// wildcardHost marks an origin allowing any subdomain, as in
+// "https://*.example.com"
+const wildcardHost = "://*."
+
+// OriginAllowed reports whether a browser origin matches one of origins.
+// An empty list allows no cross-origin requests; the server's own origin is
+// left to the caller.
+func OriginAllowed(origins []string, origin string) bool {
+	for _, o := range origins {
+		if o == "*" || strings.EqualFold(o, origin) || matchWildcard(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcard matches "scheme://*.domain[:port]" against an origin on any
+// subdomain of domain, with the same scheme and port
+func matchWildcard(pattern, origin string) bool {
+	scheme, domain, ok := strings.Cut(pattern, wildcardHost)
+	if !ok {
+		return false
+	}
+	prefix, suffix := strings.ToLower(scheme+"://"), strings.ToLower("."+domain)
+	origin = strings.ToLower(origin)
+	if len(origin) <= len(prefix)+len(suffix) || !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+		return false
+	}
+	sub := origin[len(prefix) : len(origin)-len(suffix)]
+	return !strings.ContainsAny(sub, "/:@")
+}
+
+// checkOrigin rejects origins that are not "*" or scheme://host[:port],
+// the host optionally starting with "*."
+func checkOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(strings.Replace(origin, wildcardHost, "://wildcard.", 1))
+	if err != nil || u.Scheme == "" || u.Host == "" || u.Opaque != "" || u.User != nil ||
+		u.Path != "" || u.RawQuery != "" || u.Fragment != "" || strings.Contains(u.Host, "*") {
+		return fmt.Errorf("invalid CORS origin %q (want scheme://host[:port], the host optionally starting with *.)", origin)
+	}
+	return nil
+}
+
+// checkProxy rejects trusted proxies that are neither an IP address nor a
+// CIDR range
+func checkProxy(proxy string) error {
+	if net.ParseIP(proxy) != nil {
+		return nil
+	}
+	if _, _, err := net.ParseCIDR(proxy); err == nil {
+		return nil
+	}
+	return fmt.Errorf("invalid trusted proxy %q (want an IP address or CIDR range)", proxy)
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Room feature flags toggle experimental rules per room. A room's flags only
+// name the features it changes; the others keep the room's settings.
+const (
+	FeaturePermanentNine     = "permanent_nine"      // The highest card can never be covered (on in classic rooms)
+	FeatureOwnOverwrite      = "own_overwrite"       // Players may cover their own cards with higher ones
+	FeatureLastMoveAdjacency = "last_move_adjacency" // Cards go on the cells around the card placed last
+	FeatureHoldAction        = "hold_action"         // Bots hold a card instead of making a weak placement
+)
+
+// Features lists the known feature flags in alphabetical order
+func Features() []string {
+	names := []string{FeaturePermanentNine, FeatureOwnOverwrite, FeatureLastMoveAdjacency, FeatureHoldAction}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateFeatures rejects flags for features this server does not know
+func ValidateFeatures(flags map[string]bool) error {
+	known := Features()
+	for name := range flags {
+		i := sort.SearchStrings(known, name)
+		if i == len(known) || known[i] != name {
+			return fmt.Errorf("unknown feature %q (available: %v)", name, known)
+		}
+	}
+	return nil
+}
+
+// GetFeatures returns a copy of the room's feature flags (thread-safe)
+func (rc *RoomConfig) GetFeatures() map[string]bool {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	flags := make(map[string]bool, len(rc.Features))
+	for name, on := range rc.Features {
+		flags[name] = on
+	}
+	return flags
+}
+
+// SetFeatures replaces the room's feature flags (thread-safe)
+func (rc *RoomConfig) SetFeatures(flags map[string]bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.Features = make(map[string]bool, len(flags))
+	for name, on := range flags {
+		rc.Features[name] = on
+	}
+}
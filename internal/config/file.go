@@ -0,0 +1,284 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-yaml"
+)
+
+// The server can read its settings from a YAML or JSON file named by
+// CONFIG_FILE. Environment variables win over the file, which wins over the
+// built-in defaults. Heuristic weights can also be set one at a time with
+// HEURISTIC_<WEIGHT> variables named after the weight's JSON key, e.g.
+// HEURISTIC_W_THREAT=250; card value tables take card=value pairs, e.g.
+// HEURISTIC_REPLACE_VALUES_THREAT=1=25,9=110.
+//
+// Server settings are read once at startup. The defaults section, which new
+// rooms start from, is read again on every Reload.
+
+// FileSettings is the layout of the config file. Every setting is optional.
+type FileSettings struct {
+	Server   ServerSettings  `json:"server"`
+	Defaults DefaultSettings `json:"defaults"`
+}
+
+// ServerSettings are read at startup only
+type ServerSettings struct {
+	HTTPAddr      *string  `json:"http_addr"`
+	GRPCAddr      *string  `json:"grpc_addr"`
+	RatingsFile   *string  `json:"ratings_file"`
+	StoreBackend  *string  `json:"store_backend"`
+	LogFile       *string  `json:"log_file"`
+	HTTPRateLimit *float64 `json:"http_rate_limit"`
+	HTTPRateBurst *int     `json:"http_rate_burst"`
+	WSRateLimit   *float64 `json:"ws_rate_limit"`
+	WSRateBurst   *int     `json:"ws_rate_burst"`
+	BotWorkers    *int     `json:"bot_workers"`
+	TrainingDir   *string  `json:"training_dir"`
+	BotEngine     *string  `json:"bot_engine"`
+	NNModel       *string  `json:"nn_model"`
+	NNRuntime     *string  `json:"nn_runtime"`
+	NNBlend       *float64 `json:"nn_blend"`
+
+	CORSOrigins    *[]string `json:"cors_origins"`
+	CORSHeaders    *[]string `json:"cors_headers"`
+	TrustedProxies *[]string `json:"trusted_proxies"`
+}
+
+// DefaultSettings override what new rooms start with. Weights left out of
+// the file keep their built-in value.
+type DefaultSettings struct {
+	Weights             json.RawMessage `json:"weights"`
+	BotTemperature      *float64        `json:"bot_temperature"`
+	BotTemperatureMoves *int            `json:"bot_temperature_moves"`
+}
+
+// Defaults are the settings new rooms start from, the part of the
+// configuration Reload can change
+type Defaults struct {
+	Weights             HeuristicWeights `json:"weights"`
+	BotTemperature      float64          `json:"bot_temperature"`
+	BotTemperatureMoves int              `json:"bot_temperature_moves"`
+}
+
+// Prefix of the heuristic weight environment variables
+const weightEnvPrefix = "HEURISTIC_"
+
+var (
+	loadErr  error
+	reloadMu sync.Mutex
+)
+
+// LoadErr returns why the config file or the environment overrides could not
+// be used when the configuration was loaded. The server refuses to start on
+// such an error; other tools carry on with the built-in room defaults.
+func LoadErr() error {
+	Load()
+	return loadErr
+}
+
+// ConfigFile returns the config file path from CONFIG_FILE, or "" when the
+// server runs without one
+func ConfigFile() string {
+	return os.Getenv("CONFIG_FILE")
+}
+
+// readFile parses the config file at path. YAML files (.yaml, .yml) are
+// converted to JSON first, so both formats use the JSON keys and unknown keys
+// are rejected either way. An empty path yields empty settings.
+func readFile(path string) (FileSettings, error) {
+	var f FileSettings
+	if path == "" {
+		return f, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return f, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return f, fmt.Errorf("%s: %w", path, err)
+		}
+	case ".json":
+	default:
+		return f, fmt.Errorf("%s: config file must be .json, .yaml or .yml", path)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return f, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// resolveDefaults layers the file's defaults section and the environment
+// over the built-in defaults, and validates the result
+func resolveDefaults(f DefaultSettings) (Defaults, error) {
+	d := builtinDefaults()
+	d.BotTemperature = getEnvFloat("BOT_TEMPERATURE", valueOr(f.BotTemperature, d.BotTemperature))
+	d.BotTemperatureMoves = valueOr(f.BotTemperatureMoves, d.BotTemperatureMoves)
+
+	// Decoding over the built-in weights leaves the ones the file omits, and
+	// merges card value tables entry by entry
+	if len(f.Weights) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(f.Weights))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&d.Weights); err != nil {
+			return d, fmt.Errorf("defaults.weights: %w", err)
+		}
+	}
+	if err := applyWeightEnv(&d.Weights); err != nil {
+		return d, err
+	}
+
+	return d, d.validate()
+}
+
+func builtinDefaults() Defaults {
+	return Defaults{
+		Weights:             builtinWeights(),
+		BotTemperature:      DefaultBotTemperature,
+		BotTemperatureMoves: DefaultBotTemperatureMoves,
+	}
+}
+
+func (d Defaults) validate() error {
+	if !d.Weights.ValidateWeights() {
+		return errors.New("heuristic weights must be non-negative")
+	}
+	if d.BotTemperature < 0 {
+		return errors.New("bot_temperature must not be negative")
+	}
+	if d.BotTemperatureMoves < 0 {
+		return errors.New("bot_temperature_moves must not be negative")
+	}
+	return nil
+}
+
+// validate checks the server settings that have no safe fallback
+func (c *Config) validate() error {
+	if c.HTTPRateLimit < 0 || c.WSRateLimit < 0 {
+		return errors.New("rate limits must not be negative")
+	}
+	if c.HTTPRateBurst < 0 || c.WSRateBurst < 0 {
+		return errors.New("rate bursts must not be negative")
+	}
+	if c.BotWorkers < 0 {
+		return errors.New("bot_workers must not be negative")
+	}
+	if c.StoreBackend != "memory" && c.StoreBackend != "postgres" {
+		return fmt.Errorf("unknown store backend %q (want memory or postgres)", c.StoreBackend)
+	}
+	if c.BotEngine != BotEngineHeuristic && c.BotEngine != BotEngineNN {
+		return fmt.Errorf("unknown bot engine %q (want heuristic or nn)", c.BotEngine)
+	}
+	if c.NNBlend < 0 || c.NNBlend > 1 {
+		return errors.New("nn_blend must be between 0 and 1")
+	}
+	for _, origin := range c.CORSOrigins {
+		if err := checkOrigin(origin); err != nil {
+			return err
+		}
+	}
+	for _, proxy := range c.TrustedProxies {
+		if err := checkProxy(proxy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyWeightEnv sets every weight that has a HEURISTIC_<WEIGHT> variable
+func applyWeightEnv(w *HeuristicWeights) error {
+	v := reflect.ValueOf(w).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		name := weightEnvPrefix + strings.ToUpper(key)
+		raw := os.Getenv(name)
+		if raw == "" {
+			continue
+		}
+
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.Int:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not an integer", name, raw)
+			}
+			field.SetInt(int64(n))
+		case reflect.Map:
+			// Copy the table, it may be shared with the built-in weights
+			table := map[int]int{}
+			for card, value := range field.Interface().(map[int]int) {
+				table[card] = value
+			}
+			for _, pair := range strings.Split(raw, ",") {
+				card, value, ok := strings.Cut(pair, "=")
+				c, errCard := strconv.Atoi(strings.TrimSpace(card))
+				n, errValue := strconv.Atoi(strings.TrimSpace(value))
+				if !ok || errCard != nil || errValue != nil {
+					return fmt.Errorf("%s: %q is not a card=value pair", name, pair)
+				}
+				table[c] = n
+			}
+			field.Set(reflect.ValueOf(table))
+		}
+	}
+	return nil
+}
+
+// Reload reads the config file and the environment again and applies the
+// defaults section to the global configuration, so rooms created from now on
+// use it. Nothing changes when the new settings are invalid. Server settings
+// need a restart.
+func Reload() (Defaults, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	f, err := readFile(ConfigFile())
+	if err != nil {
+		return Defaults{}, err
+	}
+	d, err := resolveDefaults(f.Defaults)
+	if err != nil {
+		return Defaults{}, err
+	}
+
+	cfg := Get()
+	cfg.DefaultWeights = d.Weights
+	cfg.BotTemperature = d.BotTemperature
+	cfg.BotTemperatureMoves = d.BotTemperatureMoves
+	log.Printf("Configuration reloaded from %s", sourceName())
+	return d, nil
+}
+
+// sourceName describes where the configuration comes from, for logs
+func sourceName() string {
+	if path := ConfigFile(); path != "" {
+		return path
+	}
+	return "the environment"
+}
+
+// valueOr returns *p, or fallback when p is nil
+func valueOr[T any](p *T, fallback T) T {
+	if p == nil {
+		return fallback
+	}
+	return *p
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// Profile groups the server settings that differ between environments
+type Profile struct {
+	Name string
+
+	// GinMode is passed to gin.SetMode ("debug", "test" or "release")
+	GinMode string
+
+	// RequestLogging is "all", "errors" (only 4xx/5xx) or "none"
+	RequestLogging string
+
+	// DebugEndpoints exposes /api/debug/* routes such as the room event
+	// logs, to holders of the admin token
+	DebugEndpoints bool
+
+	// AllowedOrigins are the CORS and WebSocket origins used when neither
+	// CORS_ORIGINS nor the config file names any. An empty list allows
+	// only the server's own origin.
+	AllowedOrigins []string
+}
+
+// Environment names accepted in GO_ENV
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+var profiles = map[string]Profile{
+	EnvDevelopment: {
+		Name:           EnvDevelopment,
+		GinMode:        "debug",
+		RequestLogging: "all",
+		DebugEndpoints: true,
+		AllowedOrigins: []string{"http://localhost:5173", "http://127.0.0.1:5173"}, // The Vite dev server
+	},
+	EnvStaging: {
+		Name:           EnvStaging,
+		GinMode:        "release",
+		RequestLogging: "all",
+		DebugEndpoints: true,
+		AllowedOrigins: []string{"http://98.70.41.170:5000", "http://localhost:5173"},
+	},
+	EnvProduction: {
+		Name:           EnvProduction,
+		GinMode:        "release",
+		RequestLogging: "errors",
+		DebugEndpoints: false,
+		AllowedOrigins: []string{"http://98.70.41.170:5000"},
+	},
+}
+
+// ProfileFor returns the profile for an environment name. Unknown or empty
+// names resolve to production so permissive settings are always opt-in.
+func ProfileFor(env string) Profile {
+	switch strings.ToLower(strings.TrimSpace(env)) {
+	case "dev", EnvDevelopment, "local":
+		return profiles[EnvDevelopment]
+	case "stage", EnvStaging:
+		return profiles[EnvStaging]
+	default:
+		return profiles[EnvProduction]
+	}
+}
+
+// getProfile selects the profile from the GO_ENV environment variable
+func getProfile() Profile {
+	return ProfileFor(os.Getenv("GO_ENV"))
+}
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"javanese-chess/internal/game"
+	"strconv"
+	"strings"
+)
+
+// Classic implements the server's standard rules: the first card goes in the
+// center, later cards next to existing ones, higher cards overwrite opponent
+// cards except the highest card (9 with the default deck), and four in a row
+// wins. Rooms may let players overwrite their own cards as well.
+type Classic struct{}
+
+func (Classic) Name() string {
+	return Default
+}
+
+func (Classic) NewGame(size int) game.Board {
+	b := game.NewBoard(size)
+	b.CellAt(b.Center()).VState = game.CellBlocked
+	return b
+}
+
+func (Classic) Rules() Rules {
+	return Rules{
+		Opening:       "center",
+		Adjacency:     "moore",
+		WinLength:     game.WinLength,
+		WinDirections: []string{"horizontal", "vertical", "diagonal", "anti_diagonal"},
+		Overwrite: Overwrite{
+			Allowed:    true,
+			HigherOnly: true,
+			OwnCards:   false,
+			Permanent:  []int{9},
+		},
+		CardMin: 1,
+		CardMax: 9,
+	}
+}
+
+func (Classic) LegalMoves(b *game.Board, hand []int, playerID string) []game.Move {
+	return game.GenerateLegalMoves(b, hand, playerID)
+}
+
+func (Classic) Validate(b *game.Board, mv game.Move) error {
+	return game.ValidateMove(b, mv, b.Placement())
+}
+
+func (Classic) Apply(b *game.Board, mv game.Move) {
+	game.ApplyMove(b, mv.X, mv.Y, mv.PlayerID, mv.Card)
+	game.UpdateVState(b)
+}
+
+func (Classic) Winner(b *game.Board, x, y int, playerID string) []game.Coord {
+	return game.WinningLine(*b, x, y, playerID)
+}
+
+// Export writes one row per line from the top, separated by "/". Runs of
+// empty cells are counted, cards are the owner letter followed by the card
+// value, and a "!" marks a locked cell. The center opening reads "4a54".
+func (Classic) Export(b *game.Board, players []string) string {
+	letters := make(map[string]byte, len(players))
+	for i, id := range players {
+		letters[id] = byte('a' + i)
+	}
+
+	var sb strings.Builder
+	for y, row := range b.Cells {
+		if y > 0 {
+			sb.WriteByte('/')
+		}
+		empty := 0
+		for _, cell := range row {
+			if cell.Value == 0 {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+			letter, ok := letters[cell.OwnerID]
+			if !ok {
+				letter = '?'
+			}
+			sb.WriteByte(letter)
+			sb.WriteString(strconv.Itoa(cell.Value))
+			if cell.Locked {
+				sb.WriteByte('!')
+			}
+		}
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"javanese-chess/internal/game"
+	"slices"
+	"testing"
+)
+
+// placement is a card put on the board while setting up a case
+type placement struct {
+	X, Y  int
+	Owner string
+	Card  int
+}
+
+// conformanceCase is one rules check that every engine must pass. A case
+// checks either the legal moves of player holding hand, or whether the
+// last setup placement wins.
+type conformanceCase struct {
+	name  string
+	rule  string
+	size  int
+	setup []placement
+
+	// Legal move expectations for player holding hand
+	player  string
+	hand    []int
+	allowed []game.Move // Moves that must be legal
+	denied  []game.Move // Moves that must not be legal
+	noMoves bool        // No legal move at all
+
+	// Win expectation for the last setup placement
+	checkWin bool
+	win      bool
+}
+
+func mv(x, y, card int) game.Move {
+	return game.Move{X: x, Y: y, Card: card}
+}
+
+func row(owner string, y int, xs []int, card int) []placement {
+	out := make([]placement, 0, len(xs))
+	for _, x := range xs {
+		out = append(out, placement{X: x, Y: y, Owner: owner, Card: card})
+	}
+	return out
+}
+
+func fullBoard(owner string, card int) []placement {
+	var out []placement
+	for y := 0; y < 3; y++ {
+		out = append(out, row(owner, y, []int{0, 1, 2}, card)...)
+	}
+	return out
+}
+
+// conformanceCases encode the official rules as enforced by the server
+var conformanceCases = []conformanceCase{
+	{
+		name:    "first move only at center",
+		rule:    "center-first",
+		size:    9,
+		player:  "a",
+		hand:    []int{1, 5, 9},
+		allowed: []game.Move{mv(4, 4, 1), mv(4, 4, 5), mv(4, 4, 9)},
+		denied:  []game.Move{mv(0, 0, 1), mv(3, 4, 5), mv(4, 5, 9)},
+	},
+	{
+		name:    "placement next to an existing card",
+		rule:    "adjacency",
+		size:    9,
+		setup:   []placement{{X: 4, Y: 4, Owner: "a", Card: 5}},
+		player:  "b",
+		hand:    []int{2},
+		allowed: []game.Move{mv(3, 3, 2), mv(5, 5, 2), mv(4, 3, 2), mv(3, 5, 2)},
+		denied:  []game.Move{mv(2, 2, 2), mv(6, 4, 2), mv(0, 0, 2)},
+	},
+	{
+		name:    "overwrite opponent with a higher card",
+		rule:    "overwrite",
+		size:    9,
+		setup:   []placement{{X: 4, Y: 4, Owner: "a", Card: 5}},
+		player:  "b",
+		hand:    []int{4, 5, 6},
+		allowed: []game.Move{mv(4, 4, 6)},
+		denied:  []game.Move{mv(4, 4, 4), mv(4, 4, 5)},
+	},
+	{
+		name:   "cannot overwrite own card",
+		rule:   "overwrite",
+		size:   9,
+		setup:  []placement{{X: 4, Y: 4, Owner: "a", Card: 2}},
+		player: "a",
+		hand:   []int{8},
+		denied: []game.Move{mv(4, 4, 8)},
+	},
+	{
+		name:   "card 9 is permanent",
+		rule:   "nine-permanence",
+		size:   9,
+		setup:  []placement{{X: 4, Y: 4, Owner: "a", Card: 9}},
+		player: "b",
+		hand:   []int{9},
+		denied: []game.Move{mv(4, 4, 9)},
+	},
+	{
+		name:    "no legal move when every target is too strong",
+		rule:    "overwrite",
+		size:    3,
+		setup:   fullBoard("a", 9),
+		player:  "b",
+		hand:    []int{1, 2, 3},
+		noMoves: true,
+	},
+	{
+		name:     "four in a row horizontally wins",
+		rule:     "win",
+		size:     9,
+		setup:    row("a", 4, []int{1, 2, 3, 4}, 3),
+		checkWin: true,
+		win:      true,
+	},
+	{
+		name: "four in a row diagonally wins",
+		rule: "win",
+		size: 9,
+		setup: []placement{
+			{X: 1, Y: 1, Owner: "a", Card: 2}, {X: 2, Y: 2, Owner: "a", Card: 2},
+			{X: 3, Y: 3, Owner: "a", Card: 2}, {X: 4, Y: 4, Owner: "a", Card: 2},
+		},
+		checkWin: true,
+		win:      true,
+	},
+	{
+		name:     "three in a row does not win",
+		rule:     "win",
+		size:     9,
+		setup:    row("a", 4, []int{2, 3, 4}, 3),
+		checkWin: true,
+		win:      false,
+	},
+	{
+		name: "broken line does not win",
+		rule: "win",
+		size: 9,
+		setup: append(row("a", 4, []int{1, 2, 4}, 3),
+			placement{X: 3, Y: 4, Owner: "b", Card: 5},
+			placement{X: 5, Y: 4, Owner: "a", Card: 3}),
+		checkWin: true,
+		win:      false,
+	},
+}
+
+// Every registered engine enforces the official rules
+func TestConformance(t *testing.T) {
+	for _, name := range Names() {
+		e, err := Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range conformanceCases {
+			t.Run(name+"/"+c.rule+"/"+c.name, func(t *testing.T) {
+				checkCase(t, e, c)
+			})
+		}
+	}
+}
+
+// checkCase sets up a case on a fresh board and checks its expectations
+func checkCase(t *testing.T, e Engine, c conformanceCase) {
+	b := e.NewGame(c.size)
+	for _, p := range c.setup {
+		e.Apply(&b, game.Move{X: p.X, Y: p.Y, Card: p.Card, PlayerID: p.Owner})
+	}
+
+	if c.player != "" {
+		legal := e.LegalMoves(&b, c.hand, c.player)
+		if c.noMoves && len(legal) > 0 {
+			t.Errorf("expected no legal moves, got %d", len(legal))
+		}
+		for _, want := range c.allowed {
+			if !containsMove(legal, want) {
+				t.Errorf("expected (%d,%d) card %d to be legal", want.X, want.Y, want.Card)
+			}
+			if err := e.Validate(&b, game.Move{X: want.X, Y: want.Y, Card: want.Card, PlayerID: c.player}); err != nil {
+				t.Errorf("Validate rejects legal (%d,%d) card %d: %v", want.X, want.Y, want.Card, err)
+			}
+		}
+		for _, deny := range c.denied {
+			if containsMove(legal, deny) {
+				t.Errorf("expected (%d,%d) card %d to be illegal", deny.X, deny.Y, deny.Card)
+			}
+			if e.Validate(&b, game.Move{X: deny.X, Y: deny.Y, Card: deny.Card, PlayerID: c.player}) == nil {
+				t.Errorf("Validate accepts illegal (%d,%d) card %d", deny.X, deny.Y, deny.Card)
+			}
+		}
+	}
+
+	if c.checkWin && len(c.setup) > 0 {
+		last := c.setup[len(c.setup)-1]
+		if got := e.Winner(&b, last.X, last.Y, last.Owner) != nil; got != c.win {
+			t.Errorf("expected win=%v after (%d,%d), got %v", c.win, last.X, last.Y, got)
+		}
+	}
+}
+
+func containsMove(moves []game.Move, want game.Move) bool {
+	return slices.ContainsFunc(moves, func(m game.Move) bool {
+		return m.X == want.X && m.Y == want.Y && m.Card == want.Card
+	})
+}
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"fmt"
+	"javanese-chess/internal/game"
+	"sort"
+)
+
+// Default is the engine used by rooms that do not choose one
+const Default = "classic"
+
+// Engine is a rules implementation. Engines are stateless: the position is
+// a game.Board owned by the room, so switching engines never loses state.
+type Engine interface {
+	// Name is the key rooms select the engine by
+	Name() string
+	// NewGame returns the empty starting board of the given size
+	NewGame(size int) game.Board
+	// LegalMoves lists every legal placement for the player's hand
+	LegalMoves(b *game.Board, hand []int, playerID string) []game.Move
+	// Validate explains why a placement is illegal, or returns nil. It
+	// accepts exactly the moves LegalMoves lists.
+	Validate(b *game.Board, mv game.Move) error
+	// Apply places a card without validation and updates the cell states
+	Apply(b *game.Board, mv game.Move)
+	// Winner returns the winning line through the card just placed at
+	// (x, y), or nil when the placement does not win
+	Winner(b *game.Board, x, y int, playerID string) []game.Coord
+	// Export encodes the position in the engine's text notation. Owners are
+	// written as letters by their index in players (a = first).
+	Export(b *game.Board, players []string) string
+	// Rules describes the rules the engine enforces
+	Rules() Rules
+}
+
+// Rules is a machine-readable description of an engine's rules, detailed
+// enough for clients to check moves themselves
+type Rules struct {
+	Opening       string    `json:"opening"`   // Where the first card goes, e.g. "center"
+	Adjacency     string    `json:"adjacency"` // Which empty cells take a card, e.g. "moore": next to a card in any of the 8 directions, or "last_move": around the card placed last
+	WinLength     int       `json:"win_length"`
+	WinDirections []string  `json:"win_directions"`
+	Overwrite     Overwrite `json:"overwrite"`
+	CardMin       int       `json:"card_min"`
+	CardMax       int       `json:"card_max"`
+}
+
+// Overwrite describes when a card may be placed on an occupied cell
+type Overwrite struct {
+	Allowed    bool  `json:"allowed"`
+	HigherOnly bool  `json:"higher_only"` // The new card must be strictly higher than the one it covers
+	OwnCards   bool  `json:"own_cards"`   // Players may cover their own cards
+	Permanent  []int `json:"permanent"`   // Card values that can never be covered
+}
+
+var registry = map[string]Engine{
+	Default: Classic{},
+}
+
+// Register adds an engine so rooms can select it by name
+func Register(e Engine) {
+	registry[e.Name()] = e
+}
+
+// Get returns the engine registered under name. An empty name selects the
+// default engine.
+func Get(name string) (Engine, error) {
+	if name == "" {
+		name = Default
+	}
+	e, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown engine %q (available: %v)", name, Names())
+	}
+	return e, nil
+}
+
+// Names lists the registered engines in alphabetical order
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,239 @@
+// Package experiment runs A/B tests of heuristic weights. While an
+// experiment runs, new rooms are assigned to its arms in turn and their bots
+// play with the arm's weights; finished games between humans and bots are
+// tallied per arm so the two weight sets can be compared on win rate.
+package experiment
+
+import (
+	"errors"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Arms
+const (
+	ArmA = "A"
+	ArmB = "B"
+)
+
+// Experiment statuses
+const (
+	StatusRunning = "running"
+	StatusStopped = "stopped"
+)
+
+var ErrNotFound = errors.New("experiment not found")
+
+// Experiment compares two weight sets. Only one experiment runs at a time.
+type Experiment struct {
+	ID        string                  `json:"id"`
+	Name      string                  `json:"name"`
+	Status    string                  `json:"status"`
+	WeightsA  config.HeuristicWeights `json:"weights_a"`
+	WeightsB  config.HeuristicWeights `json:"weights_b"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+// ArmResult is how the bots of one arm fared. Only games between humans and
+// bots count: WinRate is BotWins over Games.
+type ArmResult struct {
+	Arm       string  `json:"arm"`
+	Rooms     int     `json:"rooms"` // Rooms assigned to the arm
+	Games     int     `json:"games"`
+	BotWins   int     `json:"bot_wins"`
+	HumanWins int     `json:"human_wins"`
+	Draws     int     `json:"draws"`
+	WinRate   float64 `json:"win_rate"`
+}
+
+// Results are an experiment's tallies so far
+type Results struct {
+	ExperimentID string      `json:"experiment_id"`
+	Name         string      `json:"name"`
+	Status       string      `json:"status"`
+	Arms         []ArmResult `json:"arms"`
+}
+
+// run is an experiment with its tallies
+type run struct {
+	Experiment
+	assigned int
+	arms     map[string]*ArmResult
+}
+
+// Service holds the experiments in memory
+type Service struct {
+	mu          sync.Mutex
+	experiments map[string]*run
+	running     *run
+}
+
+func NewService() *Service {
+	return &Service{experiments: make(map[string]*run)}
+}
+
+// Start begins an experiment between two weight sets, stopping the one
+// running before
+func (s *Service) Start(name string, a, b config.HeuristicWeights) (Experiment, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Experiment{}, errors.New("name is required")
+	}
+	if !a.ValidateWeights() || !b.ValidateWeights() {
+		return Experiment{}, errors.New("weights must be non-negative")
+	}
+
+	x := &run{
+		Experiment: Experiment{
+			ID:        "x-" + uuid.NewString(),
+			Name:      name,
+			Status:    StatusRunning,
+			WeightsA:  a.Clone(),
+			WeightsB:  b.Clone(),
+			CreatedAt: time.Now(),
+		},
+		arms: map[string]*ArmResult{ArmA: {Arm: ArmA}, ArmB: {Arm: ArmB}},
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running != nil {
+		s.running.Status = StatusStopped
+		log.Printf("Experiment %s stopped for %s", s.running.ID, x.ID)
+	}
+	s.experiments[x.ID] = x
+	s.running = x
+	log.Printf("Experiment %s (%s) started", x.ID, x.Name)
+	return x.Experiment, nil
+}
+
+// Stop ends an experiment: new rooms are no longer assigned to it, while
+// rooms already assigned keep their arm and still count
+func (s *Service) Stop(id string) (Experiment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	x, ok := s.experiments[id]
+	if !ok {
+		return Experiment{}, ErrNotFound
+	}
+	x.Status = StatusStopped
+	if s.running == x {
+		s.running = nil
+	}
+	return x.Experiment, nil
+}
+
+// Results returns the experiment's tallies, arm A first
+func (s *Service) Results(id string) (Results, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	x, ok := s.experiments[id]
+	if !ok {
+		return Results{}, ErrNotFound
+	}
+	res := Results{ExperimentID: x.ID, Name: x.Name, Status: x.Status}
+	for _, arm := range []string{ArmA, ArmB} {
+		ar := *x.arms[arm]
+		if ar.Games > 0 {
+			ar.WinRate = float64(ar.BotWins) / float64(ar.Games)
+		}
+		res.Arms = append(res.Arms, ar)
+	}
+	return res, nil
+}
+
+// Assign puts a new room in the next arm of the running experiment,
+// alternating so the arms stay even. It returns nil when none runs.
+func (s *Service) Assign() *shared.ArmAssignment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	x := s.running
+	if x == nil {
+		return nil
+	}
+	arm := ArmA
+	if x.assigned%2 == 1 {
+		arm = ArmB
+	}
+	x.assigned++
+	x.arms[arm].Rooms++
+	return &shared.ArmAssignment{ExperimentID: x.ID, Arm: arm}
+}
+
+// ArmWeights returns the weights bots play with in an arm
+func (s *Service) ArmWeights(a shared.ArmAssignment) (config.HeuristicWeights, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	x, ok := s.experiments[a.ExperimentID]
+	if !ok {
+		return config.HeuristicWeights{}, false
+	}
+	switch a.Arm {
+	case ArmA:
+		return x.WeightsA, true
+	case ArmB:
+		return x.WeightsB, true
+	}
+	return config.HeuristicWeights{}, false
+}
+
+// GameOver tallies a finished game of an experiment room. The server
+// registers it as a room manager game over hook. Aborted games and games
+// without both humans and bots are left out.
+func (s *Service) GameOver(r *shared.Room) {
+	if r.Experiment == nil || r.Status == "aborted" {
+		return
+	}
+	humans, bots := 0, 0
+	for _, p := range r.Players {
+		if p.IsBot {
+			bots++
+		} else {
+			humans++
+		}
+	}
+	if humans == 0 || bots == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	x, ok := s.experiments[r.Experiment.ExperimentID]
+	if !ok {
+		return
+	}
+	ar, ok := x.arms[r.Experiment.Arm]
+	if !ok {
+		return
+	}
+	ar.Games++
+	switch {
+	case r.WinnerID == nil:
+		ar.Draws++
+	case isBot(r, *r.WinnerID):
+		ar.BotWins++
+	default:
+		ar.HumanWins++
+	}
+}
+
+// isBot reports whether the player is a bot
+func isBot(r *shared.Room, playerID string) bool {
+	for _, p := range r.Players {
+		if p.ID == playerID {
+			return p.IsBot
+		}
+	}
+	return false
+}
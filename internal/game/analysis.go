@@ -15,19 +15,64 @@ type Threat struct {
 	Dir  [2]int
 }
 
+// LineClass is how much room a run of cards has left to grow, judged by the
+// cells just past each end of it
+type LineClass int
+
+const (
+	LineClosed   LineClass = iota // Both ends blocked: the run cannot grow
+	LineHalfOpen                  // One end empty
+	LineOpen                      // Both ends empty
+)
+
+// classifyRun measures the run of playerID's cards through (x,y) along dir,
+// counting the cell at (x,y) as playerID's, and classifies its ends. An end
+// is open when it is an empty cell on the board.
+func classifyRun(b *Board, x, y int, dir [2]int, playerID string) (int, LineClass) {
+	forward := countConsecutive(b, x, y, dir[0], dir[1], playerID)
+	back := countConsecutive(b, x, y, -dir[0], -dir[1], playerID)
+
+	class := LineClosed
+	for _, end := range []Coord{
+		{X: x + dir[0]*(forward+1), Y: y + dir[1]*(forward+1)},
+		{X: x - dir[0]*(back+1), Y: y - dir[1]*(back+1)},
+	} {
+		if in(end.X, end.Y, b.Size) && b.At(end).OwnerID == "" {
+			class++
+		}
+	}
+	return forward + back + 1, class
+}
+
+// bestThree returns the most open class among the runs of three or more that
+// placing at (x,y) gives playerID, and false when it gives none
+func bestThree(b *Board, x, y int, playerID string) (LineClass, bool) {
+	best, found := LineClosed, false
+	for _, dir := range [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}} {
+		if n, class := classifyRun(b, x, y, dir, playerID); n >= 3 {
+			if !found || class > best {
+				best = class
+			}
+			found = true
+		}
+	}
+	return best, found
+}
+
 func TieBreakerLineSum(b Board, playerID string) int {
 	maxSum := 0
 	dirs := [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
 	for y := 0; y < b.Size; y++ {
 		for x := 0; x < b.Size; x++ {
-			if b.Cells[y][x].OwnerID != playerID {
+			here := b.At(Coord{X: x, Y: y})
+			if here.OwnerID != playerID {
 				continue
 			}
 			for _, d := range dirs {
-				sum := b.Cells[y][x].Value
+				sum := here.Value
 				px, py := x+d[0], y+d[1]
-				for in(px, py, b.Size) && b.Cells[py][px].OwnerID == playerID {
-					sum += b.Cells[py][px].Value
+				for b.Owns(Coord{X: px, Y: py}, playerID) {
+					sum += b.At(Coord{X: px, Y: py}).Value
 					px += d[0]
 					py += d[1]
 				}
@@ -40,89 +85,83 @@ func TieBreakerLineSum(b Board, playerID string) int {
 	return maxSum
 }
 
-func TotalOwnedSum(b Board, playerID string) int {
-	sum := 0
+// BestSegment returns the cells of the player's line with the highest card
+// sum, the one TieBreakerLineSum scores, ordered from one end to the other,
+// along with that sum. The first such line in board order wins a tie.
+func BestSegment(b Board, playerID string) ([]Coord, int) {
+	var best []Coord
+	maxSum := 0
+	dirs := [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
 	for y := 0; y < b.Size; y++ {
 		for x := 0; x < b.Size; x++ {
-			if b.Cells[y][x].OwnerID == playerID {
-				sum += b.Cells[y][x].Value
+			if b.At(Coord{X: x, Y: y}).OwnerID != playerID {
+				continue
+			}
+			for _, d := range dirs {
+				// Only lines starting here; the rest are counted from their start
+				if b.Owns(Coord{X: x - d[0], Y: y - d[1]}, playerID) {
+					continue
+				}
+				var line []Coord
+				sum := 0
+				for c := (Coord{X: x, Y: y}); b.Owns(c, playerID); c = (Coord{X: c.X + d[0], Y: c.Y + d[1]}) {
+					line = append(line, c)
+					sum += b.At(c).Value
+				}
+				if sum > maxSum {
+					best, maxSum = line, sum
+				}
 			}
 		}
 	}
+	return best, maxSum
+}
+
+// BlocksThreat reports whether placing at (x,y) fills the open cell of an
+// opponent's three in a row, the threat the heuristic rewards blocking
+func BlocksThreat(b *Board, x, y int, playerID string) bool {
+	return f_threat(b, x, y, playerID)
+}
+
+func TotalOwnedSum(b Board, playerID string) int {
+	sum := 0
+	b.Each(func(_ Coord, cell Cell) {
+		if cell.OwnerID == playerID {
+			sum += cell.Value
+		}
+	})
 	return sum
 }
 
-// GenerateLegalMoves generates all legal moves for a player
+// GenerateLegalMoves generates all legal moves for a player. A move is legal
+// exactly when ValidateMove accepts it under the board's placement rules.
 func GenerateLegalMoves(b *Board, hand []int, playerID string) []Move {
 	var moves []Move
-
-	// Check if this is the first move of the game (board is empty)
-	boardEmpty := true
-	for y := 0; y < b.Size; y++ {
-		for x := 0; x < b.Size; x++ {
-			if b.Cells[y][x].Value != 0 {
-				boardEmpty = false
-				break
-			}
-		}
-		if !boardEmpty {
-			break
-		}
-	}
+	rules := b.Placement()
 
 	// RULE: First move must be at center position [4,4] (0-indexed)
-	if boardEmpty {
-		centerX, centerY := b.Size/2, b.Size/2 // For 9x9 board: [4,4]
+	if b.IsEmpty() {
+		center := b.Center() // For 9x9 board: [4,4]
 		for _, card := range hand {
-			moves = append(moves, Move{X: centerX, Y: centerY, Card: card, PlayerID: playerID})
+			moves = append(moves, Move{X: center.X, Y: center.Y, Card: card, PlayerID: playerID})
 		}
 		// Debug log
 		if len(moves) > 0 {
-			log.Printf("DEBUG: First move detected. Board empty. Center: (%d,%d). Generated %d moves", centerX, centerY, len(moves))
+			log.Printf("DEBUG: First move detected. Board empty. Center: (%d,%d). Generated %d moves", center.X, center.Y, len(moves))
 		}
 		return moves
 	}
 
-	// Regular move generation (after first move)
-	for y := 0; y < b.Size; y++ {
-		for x := 0; x < b.Size; x++ {
-			cell := b.Cells[y][x]
-
-			// Skip cells that are not adjacent to any placed card
-			// Only allow placement on:
-			// 1. Empty cells adjacent to filled cells (CellBlocked = 1)
-			// 2. Filled cells that can be replaced (CellReplaceable = 2)
-			if cell.VState == CellAccessible && cell.Value == 0 {
-				// Empty cell with no neighbors - NOT ALLOWED
-				continue
-			}
-
-			// Skip permanent card 9 (cannot overwrite)
-			if cell.Value == 9 {
-				continue
-			}
-
-			for _, card := range hand {
-				// If cell is empty (CellBlocked), any card can be placed
-				if cell.Value == 0 {
-					moves = append(moves, Move{X: x, Y: y, Card: card, PlayerID: playerID})
-					continue
-				}
-
-				// If cell is filled (CellReplaceable):
-				// - Card must be higher than current value
-				// - Cannot overwrite own card
-				if cell.Value >= card {
-					continue
-				}
-				if cell.OwnerID == playerID {
-					continue
-				}
-
+	// Regular move generation (after first move), over the cells next to or
+	// under a card
+	moves = make([]Move, 0, b.placeableCount()*len(hand))
+	b.eachPlaceable(func(x, y int) {
+		for _, card := range hand {
+			if checkPlacement(b, x, y, card, playerID, rules) == nil {
 				moves = append(moves, Move{X: x, Y: y, Card: card, PlayerID: playerID})
 			}
 		}
-	}
+	})
 
 	return moves
 }
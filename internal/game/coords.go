@@ -0,0 +1,157 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Coordinate convention used by every API payload:
+//
+//   - x is the column and y is the row, both 0-based from the top-left corner
+//   - cells are stored row-major, so a cell is read as Board.Cells[y][x]
+//   - algebraic notation names the column with a letter (A = x 0) and the row
+//     with a 1-based number (1 = y 0), so "E5" is x=4, y=4, the 9x9 center
+//   - clients that count (row, col) from 1 can convert with FromRowCol
+//
+// Code outside this file reads and writes cells through the Board accessors
+// below (At, CellAt, Owns, Each) rather than indexing Cells, so the order of
+// the indexes is written down once.
+
+// Coord is a board position in the canonical (x, y) convention
+type Coord struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// CoordError reports an invalid position together with how it was interpreted
+type CoordError struct {
+	Input  string
+	Coord  Coord
+	Reason string
+}
+
+func (e *CoordError) Error() string {
+	if e.Input != "" {
+		return fmt.Sprintf("%s: %q interpreted as %s", e.Reason, e.Input, e.Coord)
+	}
+	return fmt.Sprintf("%s: %s", e.Reason, e.Coord)
+}
+
+// String describes the coordinate in both notations, e.g. "E5 (x=4, y=4)"
+func (c Coord) String() string {
+	return fmt.Sprintf("%s (x=%d, y=%d)", c.Algebraic(), c.X, c.Y)
+}
+
+// Algebraic returns the letter-number name of the cell
+func (c Coord) Algebraic() string {
+	if c.X < 0 || c.X >= 26 || c.Y < 0 {
+		return "?"
+	}
+	return fmt.Sprintf("%c%d", 'A'+c.X, c.Y+1)
+}
+
+// RowCol returns the 1-based (row, col) pair for the cell
+func (c Coord) RowCol() (row, col int) {
+	return c.Y + 1, c.X + 1
+}
+
+// Touches reports whether o is one of the 8 cells around c
+func (c Coord) Touches(o Coord) bool {
+	dx, dy := c.X-o.X, c.Y-o.Y
+	return c != o && dx >= -1 && dx <= 1 && dy >= -1 && dy <= 1
+}
+
+// FromRowCol converts a 1-based (row, col) pair to a canonical coordinate
+func FromRowCol(row, col int) Coord {
+	return Coord{X: col - 1, Y: row - 1}
+}
+
+// ParseAlgebraic parses notation such as "E5" (case-insensitive)
+func ParseAlgebraic(s string) (Coord, error) {
+	in := strings.ToUpper(strings.TrimSpace(s))
+	if len(in) < 2 {
+		return Coord{}, &CoordError{Input: s, Reason: "invalid cell notation"}
+	}
+
+	col := in[0]
+	if col < 'A' || col > 'Z' {
+		return Coord{}, &CoordError{Input: s, Reason: "cell must start with a column letter"}
+	}
+	row, err := strconv.Atoi(in[1:])
+	if err != nil {
+		return Coord{}, &CoordError{Input: s, Reason: "cell must end with a row number"}
+	}
+
+	return Coord{X: int(col - 'A'), Y: row - 1}, nil
+}
+
+// ValidateCoord checks that the coordinate lies on a board of the given size
+func ValidateCoord(c Coord, size int, input string) error {
+	if !in(c.X, c.Y, size) {
+		return &CoordError{
+			Input:  input,
+			Coord:  c,
+			Reason: fmt.Sprintf("cell is outside the %dx%d board", size, size),
+		}
+	}
+	return nil
+}
+
+// Center is the cell the first card must go to
+func (b *Board) Center() Coord {
+	return Coord{X: b.Size / 2, Y: b.Size / 2}
+}
+
+// Contains reports whether c lies on the board
+func (b *Board) Contains(c Coord) bool {
+	return in(c.X, c.Y, b.Size)
+}
+
+// CheckCoord is ValidateCoord for this board
+func (b *Board) CheckCoord(c Coord, input string) error {
+	return ValidateCoord(c, b.Size, input)
+}
+
+// Locate resolves the position a request names and checks it lies on the
+// board. A non-empty cell in algebraic notation takes precedence over the x
+// and y fields. Handlers taking a position should go through Locate rather
+// than reading the fields themselves.
+func (b *Board) Locate(cell string, x, y int) (Coord, error) {
+	c := Coord{X: x, Y: y}
+	if cell != "" {
+		parsed, err := ParseAlgebraic(cell)
+		if err != nil {
+			return Coord{}, err
+		}
+		c = parsed
+	}
+	if err := b.CheckCoord(c, cell); err != nil {
+		return Coord{}, err
+	}
+	return c, nil
+}
+
+// At returns the cell at c, which must lie on the board
+func (b *Board) At(c Coord) Cell {
+	return b.Cells[c.Y][c.X]
+}
+
+// CellAt returns the cell at c for changing in place; c must lie on the board
+func (b *Board) CellAt(c Coord) *Cell {
+	return &b.Cells[c.Y][c.X]
+}
+
+// Owns reports whether c lies on the board and holds a card of owner
+func (b *Board) Owns(c Coord, owner string) bool {
+	return b.Contains(c) && b.Cells[c.Y][c.X].OwnerID == owner
+}
+
+// Each calls fn for every cell, row by row from the top-left corner
+func (b *Board) Each(fn func(c Coord, cell Cell)) {
+	for y := range b.Cells {
+		for x := range b.Cells[y] {
+			fn(Coord{X: x, Y: y}, b.Cells[y][x])
+		}
+	}
+}
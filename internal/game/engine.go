@@ -6,22 +6,40 @@ import (
 )
 
 func ApplyMove(b *Board, x, y int, owner string, card int) {
-	cell := &b.Cells[y][x]
+	cell := b.CellAt(Coord{X: x, Y: y})
+	b.toggleCell(x, y)
+
+	// Track captures for the cell-lock variant
+	if cell.Value != 0 && cell.OwnerID != owner {
+		if b.LocksOnCapture(x, y) {
+			cell.Locked = true
+		}
+		cell.Captures++
+	}
+
 	cell.OwnerID = owner
 	cell.Value = card
+	b.toggleCell(x, y)
+
+	b.LastMove = &Coord{X: x, Y: y}
 
 	// Update virtual states after placement
 	UpdateLocalVState(b, x, y)
 }
 
-// UpdateVState updates virtual states for all cells on the board
+// UpdateVState updates virtual states for all cells on the board and
+// rebuilds the frontier
 func UpdateVState(b *Board) {
+	b.resetFrontier()
 	for y := 0; y < b.Size; y++ {
 		for x := 0; x < b.Size; x++ {
-			cell := &b.Cells[y][x]
+			cell := b.CellAt(Coord{X: x, Y: y})
+			if cell.Value != 0 {
+				b.addToFrontier(x, y)
+			}
 
-			// Rule 3: Card 9 is permanent
-			if cell.Value == 9 {
+			// Rule 3: The highest card and locked cells are permanent
+			if b.Permanent(x, y) {
 				cell.VState = CellAccessible // v(x,y) = 0
 				continue
 			}
@@ -46,14 +64,14 @@ func UpdateVState(b *Board) {
 
 // UpdateLocalVState updates virtual state after a move at position (x,y)
 func UpdateLocalVState(b *Board, x, y int) {
-	cell := &b.Cells[y][x]
+	cell := b.CellAt(Coord{X: x, Y: y})
+	b.addToFrontier(x, y)
 
 	// Block all empty neighboring cells (Rule 1)
 	for q := -1; q <= 1; q++ {
 		for p := -1; p <= 1; p++ {
-			nx, ny := x+p, y+q
-			if nx >= 0 && nx < b.Size && ny >= 0 && ny < b.Size {
-				neighborCell := &b.Cells[ny][nx]
+			if n := (Coord{X: x + p, Y: y + q}); b.Contains(n) {
+				neighborCell := b.CellAt(n)
 				if neighborCell.Value == 0 {
 					neighborCell.VState = CellBlocked // v = 1
 				}
@@ -62,7 +80,7 @@ func UpdateLocalVState(b *Board, x, y int) {
 	}
 
 	// Set the placed cell's virtual state (Rules 2 & 3)
-	if cell.Value == 9 {
+	if b.Permanent(x, y) {
 		cell.VState = CellAccessible // v(x,y) = 0 (permanent)
 	} else {
 		cell.VState = CellReplaceable // v(x,y) = 2
@@ -76,11 +94,8 @@ func hasFilledNeighbor(b *Board, x, y int) bool {
 			if p == 0 && q == 0 {
 				continue // Skip the cell itself
 			}
-			nx, ny := x+p, y+q
-			if nx >= 0 && nx < b.Size && ny >= 0 && ny < b.Size {
-				if b.Cells[ny][nx].Value != 0 {
-					return true
-				}
+			if n := (Coord{X: x + p, Y: y + q}); b.Contains(n) && b.At(n).Value != 0 {
+				return true
 			}
 		}
 	}
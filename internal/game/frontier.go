@@ -0,0 +1,73 @@
+package game
+
+import "math/bits"
+
+// The frontier is the set of cells that can take a card: every filled cell
+// and every empty cell next to one. It only grows as cards are placed, so
+// ApplyMove keeps it up to date by adding the placed cell and its neighbors,
+// and move generation looks at those cells instead of the whole board.
+//
+// The set is a fixed-size bitset so boards copied by value carry their own.
+// Boards decoded from JSON and boards too large for the bitset have no
+// frontier until UpdateVState rebuilds it; they are scanned whole meanwhile.
+
+// Cells the frontier bitset can hold (a 16x16 board)
+const frontierCells = frontierWords * 64
+
+const frontierWords = 4
+
+type frontier struct {
+	set   [frontierWords]uint64
+	valid bool
+}
+
+// resetFrontier empties the frontier, or drops it when the board is too large
+func (b *Board) resetFrontier() {
+	b.frontier = frontier{valid: b.Size*b.Size <= frontierCells}
+}
+
+// addToFrontier adds the cell at (x,y) and its on-board neighbors
+func (b *Board) addToFrontier(x, y int) {
+	if !b.frontier.valid {
+		return
+	}
+	for ny := max(y-1, 0); ny <= min(y+1, b.Size-1); ny++ {
+		for nx := max(x-1, 0); nx <= min(x+1, b.Size-1); nx++ {
+			i := ny*b.Size + nx
+			b.frontier.set[i/64] |= 1 << (i % 64)
+		}
+	}
+}
+
+// placeableCount is the number of cells eachPlaceable visits
+func (b *Board) placeableCount() int {
+	if !b.frontier.valid {
+		return b.Size * b.Size
+	}
+	n := 0
+	for _, word := range b.frontier.set {
+		n += bits.OnesCount64(word)
+	}
+	return n
+}
+
+// eachPlaceable calls fn for every cell that may take a card, row by row.
+// Without a frontier that is every cell on the board.
+func (b *Board) eachPlaceable(fn func(x, y int)) {
+	if !b.frontier.valid {
+		for y := 0; y < b.Size; y++ {
+			for x := 0; x < b.Size; x++ {
+				fn(x, y)
+			}
+		}
+		return
+	}
+
+	for w, word := range b.frontier.set {
+		for word != 0 {
+			i := w*64 + bits.TrailingZeros64(word)
+			word &= word - 1
+			fn(i%b.Size, i/b.Size)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package game
+
+import (
+	"fmt"
+	"javanese-chess/internal/config"
+	"math/rand"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+// benchPosition is a board with the hand of the player to move
+type benchPosition struct {
+	board  Board
+	hand   []int
+	player string
+}
+
+// benchPositions plays seeded games of random legal moves and keeps the board
+// before every move after the first, so the boards range from nearly empty
+// to crowded and only depend on the seed
+func benchPositions(games int, seed int64) []benchPosition {
+	rng := rand.New(rand.NewSource(seed))
+	players := []string{"a", "b"}
+	deal := func() []int {
+		return []int{1 + rng.Intn(9), 1 + rng.Intn(9), 1 + rng.Intn(9)}
+	}
+
+	var out []benchPosition
+	for g := 0; g < games; g++ {
+		b := NewBoard(config.DefaultBoardSize)
+		b.CellAt(b.Center()).VState = CellBlocked
+		center := b.Center()
+		ApplyMove(&b, center.X, center.Y, players[0], 1+rng.Intn(9))
+		UpdateVState(&b)
+
+		for ply := 1; ply < b.Size*b.Size; ply++ {
+			p := benchPosition{board: b.Clone(), hand: deal(), player: players[ply%2]}
+			moves := GenerateLegalMoves(&p.board, p.hand, p.player)
+			if len(moves) == 0 {
+				break
+			}
+			out = append(out, p)
+
+			mv := moves[rng.Intn(len(moves))]
+			ApplyMove(&b, mv.X, mv.Y, p.player, mv.Card)
+			UpdateVState(&b)
+			if WinningLine(b, mv.X, mv.Y, p.player) != nil {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// scanModes runs fn on the positions with their frontier, then on copies
+// without one, which are scanned whole as before the frontier existed
+func scanModes(b *testing.B, positions []benchPosition, fn func(b *testing.B, positions []benchPosition)) {
+	fullScan := make([]benchPosition, len(positions))
+	for i, p := range positions {
+		p.board = p.board.Clone()
+		p.board.frontier.valid = false
+		fullScan[i] = p
+	}
+	b.Run("frontier", func(b *testing.B) { fn(b, positions) })
+	b.Run("full_scan", func(b *testing.B) { fn(b, fullScan) })
+}
+
+func BenchmarkGenerateLegalMoves(b *testing.B) {
+	scanModes(b, benchPositions(20, 1), func(b *testing.B, positions []benchPosition) {
+		for i := 0; i < b.N; i++ {
+			p := &positions[i%len(positions)]
+			GenerateLegalMoves(&p.board, p.hand, p.player)
+		}
+	})
+}
+
+// BenchmarkEvalPool scores every legal move of a position the way a bot
+// picks its move, for several pool sizes
+func BenchmarkEvalPool(b *testing.B) {
+	weights := config.Get().DefaultWeights
+	sizes := []int{0, 1, 2, 4}
+	if !slices.Contains(sizes, runtime.NumCPU()) {
+		sizes = append(sizes, runtime.NumCPU())
+	}
+	scanModes(b, benchPositions(5, 1), func(b *testing.B, positions []benchPosition) {
+		for _, workers := range sizes {
+			pool := NewEvalPool(workers)
+			b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					p := &positions[i%len(positions)]
+					moves := GenerateLegalMoves(&p.board, p.hand, p.player)
+					pool.Score(moves, time.Time{}, func(mv Move) int {
+						return EvaluateMoveBreakdown(&p.board, mv.X, mv.Y, mv.Card, p.player, &weights).Total
+					})
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package game
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+// PositionHash identifies a board position. It is a Zobrist-style hash: the
+// XOR of one key per occupied cell, so placing or reverting a card updates it
+// in constant time. Keys are derived from the cell contents rather than a
+// random table, which keeps hashes stable across servers and restarts.
+//
+// Owners are keyed by their seat in Board.Seats, so the same position reached
+// in different games hashes the same. Captures only count towards the hash
+// while the cell-lock variant is on, where they change which moves are legal.
+type PositionHash uint64
+
+// String formats the hash as 16 hex digits
+func (h PositionHash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// MarshalJSON writes the hash as a hex string; JSON numbers lose precision
+// above 2^53 in most clients
+func (h PositionHash) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + h.String() + `"`), nil
+}
+
+func (h *PositionHash) UnmarshalJSON(data []byte) error {
+	s, err := strconv.Unquote(string(data))
+	if err != nil {
+		return fmt.Errorf("position hash must be a hex string: %w", err)
+	}
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return fmt.Errorf("invalid position hash %q", s)
+	}
+	*h = PositionHash(v)
+	return nil
+}
+
+// SetSeats sets the seat order owners are hashed by and recomputes the hash
+func (b *Board) SetSeats(ids []string) {
+	b.Seats = append([]string(nil), ids...)
+	b.Rehash()
+}
+
+// Rehash recomputes the hash from scratch. Boards built cell by cell, or
+// loaded from data that predates hashing, need this once.
+func (b *Board) Rehash() {
+	b.Hash = 0
+	for y := range b.Cells {
+		for x := range b.Cells[y] {
+			b.Hash ^= b.cellKey(x, y)
+		}
+	}
+}
+
+// toggleCell XORs the key of the cell at (x,y) in or out of the hash. Call it
+// once before and once after changing a cell.
+func (b *Board) toggleCell(x, y int) {
+	b.Hash ^= b.cellKey(x, y)
+}
+
+// cellKey is the hash contribution of the cell at (x,y); empty cells add nothing
+func (b *Board) cellKey(x, y int) PositionHash {
+	c := b.At(Coord{X: x, Y: y})
+	if c.Value == 0 {
+		return 0
+	}
+
+	content := uint64(y*b.Size+x)<<32 | uint64(c.Value)<<8
+	if c.Locked {
+		content |= 1 << 7
+	}
+	if b.LockAfter > 0 {
+		content |= uint64(c.Captures & 0x7f)
+	}
+	return PositionHash(splitmix64(splitmix64(b.ownerKey(c.OwnerID)) ^ content))
+}
+
+// ownerKey is the seat number (1-based) of an owner, or a hash of the ID for
+// owners that are not seated
+func (b *Board) ownerKey(id string) uint64 {
+	for i, seat := range b.Seats {
+		if seat == id {
+			return uint64(i + 1)
+		}
+	}
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return h.Sum64() | 1<<63
+}
+
+// splitmix64 is a fast, well-distributed 64-bit mixing function
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -5,80 +5,94 @@ import (
 	"log"
 )
 
+// MoveBreakdown is the per-feature contribution to a move's heuristic score
+type MoveBreakdown struct {
+	LegalMove int `json:"legal_move"`
+	Win       int `json:"f_win"`
+	Threat    int `json:"f_threat"`
+	Replace   int `json:"f_replace"`
+	Blocks    int `json:"f_blocks"`
+	Formation int `json:"f_formation"`
+	Fork      int `json:"f_fork"`
+	Value     int `json:"f_value"`
+	Proximity int `json:"f_proximity"`
+	Refute    int `json:"f_refute"`
+	Total     int `json:"total"`
+}
+
 // EvaluateMove calculates the heuristic score for a move
 // Based on the heuristic value table provided
 func EvaluateMove(b *Board, x, y int, card int, playerID string, cfg *config.Config) int {
-	weights := cfg.DefaultWeights
-	score := 0
+	bd := EvaluateMoveBreakdown(b, x, y, card, playerID, &cfg.DefaultWeights)
+
+	if bd.Win > 0 {
+		log.Printf("Move (%d,%d) card=%d | f_win=%d", x, y, card, bd.Win)
+		return bd.Total
+	}
+
+	log.Printf("Move (%d,%d) card=%d | threat=%d replace=%d blocks=%d formation=%d value=%d proximity=%d | TOTAL=%d",
+		x, y, card, bd.Threat, bd.Replace, bd.Blocks, bd.Formation, bd.Value, bd.Proximity, bd.Total)
+
+	return bd.Total
+}
 
+// EvaluateMoveBreakdown scores a move feature by feature with the given weights.
+// It only reads the board, so candidates on the same board can be scored
+// concurrently as long as nothing moves meanwhile.
+func EvaluateMoveBreakdown(b *Board, x, y int, card int, playerID string, weights *config.HeuristicWeights) MoveBreakdown {
 	// Base value: Legal move
-	score += weights.LegalMove // 30
+	bd := MoveBreakdown{LegalMove: weights.LegalMove}
 
 	// 1. f_win: Winning move (4-in-a-row)
-	if f_win(b, x, y, playerID, card) {
-		winScore := weights.WWin // 10000
-		score += winScore
-		log.Printf("Move (%d,%d) card=%d | f_win=%d", x, y, card, winScore)
-		return score // If winning, return immediately
+	if f_win(b, x, y, playerID) {
+		bd.Win = weights.WWin
+		bd.Total = bd.LegalMove + bd.Win
+		return bd // If winning, return immediately
 	}
 
 	// 2. f_threat: Detect if opponent has 3-in-a-row and this blocks it
 	isThreat := f_threat(b, x, y, playerID)
-	threatScore := 0
 	if isThreat {
-		threatScore = weights.WThreat // 200
-		score += threatScore
+		bd.Threat = weights.WThreat
 	}
 
 	// 3. f_replace: Replace opponent's card
-	replaceScore := f_replace(b, x, y, playerID, isThreat, &weights)
-	score += replaceScore
+	bd.Replace = f_replace(b, x, y, playerID, isThreat, weights)
 
 	// 4. f_blocks: Block opponent's path
-	blocksScore := f_blocks(b, x, y, playerID, isThreat, &weights)
-	score += blocksScore
+	bd.Blocks = f_blocks(b, x, y, playerID, isThreat, weights)
 
 	// 5. f_formation: Build our own alignments
-	formationScore := f_formation(b, x, y, playerID, card, &weights)
-	score += formationScore
+	bd.Formation = f_formation(b, x, y, playerID, weights)
 
-	// 6. f_value: Card value management
-	valueScore := f_value(b, x, y, card, playerID, isThreat, &weights)
-	score += valueScore
+	// 5b. f_fork: Leave two ways to complete 4-in-a-row, more than one reply can block
+	if f_fork(b, x, y, playerID) {
+		bd.Fork = weights.WFork
+	}
 
-	// 7. Play smallest card bonus
-	// This is handled inside f_value
+	// 6. f_value: Card value management (includes the smallest card bonus)
+	bd.Value = f_value(b, x, y, card, playerID, isThreat, weights)
 
-	// 8. Place card close to our own cards
-	proximityScore := f_proximity(b, x, y, playerID, &weights)
-	score += proximityScore
+	// 7. Place card close to our own cards
+	bd.Proximity = f_proximity(b, x, y, playerID, weights)
 
-	log.Printf("Move (%d,%d) card=%d | threat=%d replace=%d blocks=%d formation=%d value=%d proximity=%d | TOTAL=%d",
-		x, y, card, threatScore, replaceScore, blocksScore, formationScore, valueScore, proximityScore, score)
+	// 8. f_refute: Don't play into an opponent's immediate win
+	if f_refute(b, x, y, card, playerID) {
+		bd.Refute = -weights.WRefute
+	}
 
-	return score
+	bd.Total = bd.LegalMove + bd.Threat + bd.Replace + bd.Blocks + bd.Formation + bd.Fork + bd.Value + bd.Proximity + bd.Refute
+	return bd
 }
 
-// f_win: Returns true if placing card at (x,y) creates 4-in-a-row
-func f_win(b *Board, x, y int, playerID string, card int) bool {
-	// Temporarily place the card
-	originalOwner := b.Cells[y][x].OwnerID
-	originalValue := b.Cells[y][x].Value
-
-	b.Cells[y][x].OwnerID = playerID
-	b.Cells[y][x].Value = card
-
-	// Check if this creates 4-in-a-row
-	hasWin := check4InARow(b, x, y, playerID)
-
-	// Restore original state
-	b.Cells[y][x].OwnerID = originalOwner
-	b.Cells[y][x].Value = originalValue
-
-	return hasWin
+// f_win: Returns true if placing a card at (x,y) creates 4-in-a-row
+func f_win(b *Board, x, y int, playerID string) bool {
+	return check4InARow(b, x, y, playerID)
 }
 
-// check4InARow checks if there are 4 cards in a row for playerID at position (x,y)
+// check4InARow checks if there are 4 cards in a row for playerID at position (x,y).
+// The cell at (x,y) counts as playerID's whoever holds it, so a candidate
+// placement is checked without putting the card on the board.
 func check4InARow(b *Board, x, y int, playerID string) bool {
 	directions := [][2]int{
 		{1, 0},  // Horizontal
@@ -92,7 +106,7 @@ func check4InARow(b *Board, x, y int, playerID string) bool {
 
 		// Check forward direction
 		nx, ny := x+dir[0], y+dir[1]
-		for in(nx, ny, b.Size) && b.Cells[ny][nx].OwnerID == playerID {
+		for b.Owns(Coord{X: nx, Y: ny}, playerID) {
 			count++
 			nx += dir[0]
 			ny += dir[1]
@@ -100,7 +114,7 @@ func check4InARow(b *Board, x, y int, playerID string) bool {
 
 		// Check backward direction
 		nx, ny = x-dir[0], y-dir[1]
-		for in(nx, ny, b.Size) && b.Cells[ny][nx].OwnerID == playerID {
+		for b.Owns(Coord{X: nx, Y: ny}, playerID) {
 			count++
 			nx -= dir[0]
 			ny -= dir[1]
@@ -157,7 +171,7 @@ func blocks3InARow(b *Board, x, y int, opponentID string) bool {
 					continue
 				}
 
-				cell := b.Cells[py][px]
+				cell := b.At(Coord{X: px, Y: py})
 				if cell.OwnerID == opponentID {
 					opponentCount++
 				} else if cell.OwnerID == "" {
@@ -177,7 +191,7 @@ func blocks3InARow(b *Board, x, y int, opponentID string) bool {
 
 // f_replace: Score for replacing opponent's card
 func f_replace(b *Board, x, y int, playerID string, isThreat bool, weights *config.HeuristicWeights) int {
-	cell := b.Cells[y][x]
+	cell := b.At(Coord{X: x, Y: y})
 
 	// If empty or own card, no replacement score
 	if cell.OwnerID == "" || cell.OwnerID == playerID {
@@ -211,9 +225,10 @@ func getPositionBonus(b *Board, x, y int, opponentID string, weights *config.Heu
 		backCount := countConsecutive(b, x, y, -dir[0], -dir[1], opponentID)
 		forwardCount := countConsecutive(b, x, y, dir[0], dir[1], opponentID)
 
-		lineLength := backCount + forwardCount + 1
+		lineLength, class := classifyRun(b, x, y, dir, opponentID)
 
-		if lineLength >= 3 {
+		// A three blocked on both ends is no threat, whichever card breaks it
+		if lineLength >= 3 && class != LineClosed {
 			// Determine if center or side
 			if backCount >= 1 && forwardCount >= 1 {
 				// Center position (cards on both sides)
@@ -239,7 +254,7 @@ func countConsecutive(b *Board, x, y int, dx, dy int, ownerID string) int {
 	count := 0
 	nx, ny := x+dx, y+dy
 
-	for in(nx, ny, b.Size) && b.Cells[ny][nx].OwnerID == ownerID {
+	for b.Owns(Coord{X: nx, Y: ny}, ownerID) {
 		count++
 		nx += dx
 		ny += dy
@@ -255,70 +270,58 @@ func f_blocks(b *Board, x, y int, playerID string, isThreat bool, weights *confi
 	opponents := getOpponentIDs(b, playerID)
 
 	for _, opponentID := range opponents {
+		blockScore := 0
+
 		// Check if this blocks a 3-in-a-row (immediate threat)
 		if blocks3InARow(b, x, y, opponentID) {
-			blockScore := weights.BlockWhenThreat // 100
-			if blockScore > maxBlockScore {
-				maxBlockScore = blockScore
-			}
-		} else if blocks2InARow(b, x, y, opponentID) {
-			// Check if this blocks a 2-in-a-row (potential threat)
-			blockScore := weights.BlockPotential // 70
-			if blockScore > maxBlockScore {
-				maxBlockScore = blockScore
+			blockScore = weights.BlockWhenThreat // 100
+		} else if class, ok := blocks2InARow(b, x, y, opponentID); ok {
+			// Check if this blocks a 2-in-a-row from becoming a three. An
+			// open three would leave two cells to win on; a closed one
+			// could never grow to four, so it is no threat.
+			switch class {
+			case LineOpen:
+				blockScore = weights.BlockOpen3 // 90
+			case LineHalfOpen:
+				blockScore = weights.BlockPotential // 70
 			}
 		}
+
+		if blockScore > maxBlockScore {
+			maxBlockScore = blockScore
+		}
 	}
 
 	return maxBlockScore
 }
 
-// blocks2InARow checks if placing at (x,y) blocks opponent's 2-in-a-row extension
-func blocks2InARow(b *Board, x, y int, opponentID string) bool {
-	directions := [][2]int{
-		{1, 0}, {0, 1}, {1, 1}, {1, -1},
-	}
-
-	for _, dir := range directions {
-		// Check if opponent has 2 cards in a line and (x,y) could extend it
-		backCount := countConsecutive(b, x, y, -dir[0], -dir[1], opponentID)
-		forwardCount := countConsecutive(b, x, y, dir[0], dir[1], opponentID)
-
-		totalCount := backCount + forwardCount
+// blocks2InARow checks if placing at (x,y) blocks opponent's 2-in-a-row
+// extension, and returns the class of the three the opponent would get there
+func blocks2InARow(b *Board, x, y int, opponentID string) (LineClass, bool) {
+	return bestThree(b, x, y, opponentID)
+}
 
-		if totalCount >= 2 {
-			return true
+// f_formation: Score for building our own alignments. A three only scores
+// as a three while it can still grow to four; a closed one counts as a two.
+func f_formation(b *Board, x, y int, playerID string, weights *config.HeuristicWeights) int {
+	if class, ok := bestThree(b, x, y, playerID); ok {
+		switch class {
+		case LineOpen:
+			return weights.BuildAlignment3 // 100
+		case LineHalfOpen:
+			return weights.BuildHalfOpen3 // 60
 		}
 	}
 
-	return false
-}
-
-// f_formation: Score for building our own alignments
-func f_formation(b *Board, x, y int, playerID string, card int, weights *config.HeuristicWeights) int {
-	// Temporarily place the card
-	originalOwner := b.Cells[y][x].OwnerID
-	originalValue := b.Cells[y][x].Value
-
-	b.Cells[y][x].OwnerID = playerID
-	b.Cells[y][x].Value = card
-
-	maxAlignment := getMaxAlignment(b, x, y, playerID)
-
-	// Restore original state
-	b.Cells[y][x].OwnerID = originalOwner
-	b.Cells[y][x].Value = originalValue
-
-	if maxAlignment >= 3 {
-		return weights.BuildAlignment3 // 100
-	} else if maxAlignment >= 2 {
+	if getMaxAlignment(b, x, y, playerID) >= 2 {
 		return weights.BuildAlignment2 // 50
 	}
 
 	return 0
 }
 
-// getMaxAlignment returns the maximum consecutive cards in any direction
+// getMaxAlignment returns the maximum consecutive cards in any direction,
+// counting the cell at (x,y) as playerID's
 func getMaxAlignment(b *Board, x, y int, playerID string) int {
 	directions := [][2]int{
 		{1, 0}, {0, 1}, {1, 1}, {1, -1},
@@ -339,19 +342,101 @@ func getMaxAlignment(b *Board, x, y int, playerID string) int {
 	return maxCount
 }
 
+// f_fork: Returns true if placing at (x,y) creates a double threat: two or
+// more empty cells that would each complete a 4-in-a-row through (x,y). Both
+// an open three (empty on both ends) and two threes crossing at (x,y) count.
+func f_fork(b *Board, x, y int, playerID string) bool {
+	return len(completionCells(b, x, y, playerID)) >= 2
+}
+
+// completionCells returns the empty cells that would complete a 4-in-a-row
+// through (x,y), counting the cell at (x,y) as playerID's
+func completionCells(b *Board, x, y int, playerID string) map[Coord]bool {
+	directions := [][2]int{
+		{1, 0}, {0, 1}, {1, 1}, {1, -1},
+	}
+
+	cells := make(map[Coord]bool)
+	for _, dir := range directions {
+		// Every window of 4 cells along the line that contains (x,y)
+		for offset := -3; offset <= 0; offset++ {
+			own := 0
+			var gaps []Coord
+			valid := true
+
+			for i := 0; i < 4; i++ {
+				c := Coord{X: x + dir[0]*(offset+i), Y: y + dir[1]*(offset+i)}
+
+				if !in(c.X, c.Y, b.Size) {
+					valid = false
+					break
+				}
+
+				switch {
+				case c.X == x && c.Y == y, b.Owns(c, playerID):
+					own++
+				case b.At(c).OwnerID == "":
+					gaps = append(gaps, c)
+				default:
+					valid = false
+				}
+			}
+
+			// 3 own cards and a single empty cell: that cell wins next turn
+			if valid && own == 3 && len(gaps) == 1 {
+				cells[gaps[0]] = true
+			}
+		}
+	}
+
+	return cells
+}
+
+// f_refute: Returns true if, after placing card at (x,y), some opponent
+// could complete a 4-in-a-row with their next card. Opponents' hands are
+// hidden, so any of them is assumed to hold the highest card and can cover
+// every card that is not permanent, including the one just placed.
+func f_refute(b *Board, x, y int, card int, playerID string) bool {
+	opponents := getOpponentIDs(b, playerID)
+	if len(opponents) == 0 {
+		return false
+	}
+
+	after := b.Clone()
+	ApplyMove(&after, x, y, playerID, card)
+	rules := after.Placement()
+	top := after.TopCard()
+
+	refuted := false
+	after.eachPlaceable(func(rx, ry int) {
+		if refuted {
+			return
+		}
+		for _, opponentID := range opponents {
+			if checkPlacement(&after, rx, ry, top, opponentID, rules) == nil && check4InARow(&after, rx, ry, opponentID) {
+				refuted = true
+				return
+			}
+		}
+	})
+
+	return refuted
+}
+
 // f_value: Card value management based on context
 func f_value(b *Board, x, y int, card int, playerID string, isThreat bool, weights *config.HeuristicWeights) int {
-	cell := b.Cells[y][x]
+	cell := b.At(Coord{X: x, Y: y})
 	isReplacingOpponent := cell.OwnerID != "" && cell.OwnerID != playerID
 
 	// Determine card value based on context
 	cardValue := 0
-	if isThreat && isReplacingOpponent {
-		// Blocking threat: prefer high cards (Card 9 = 100, Card 1 = 20)
-		cardValue = weights.ReplaceValuesThreat[card]
+	if isThreat && isReplacingOpponent && !b.LocksOnCapture(x, y) {
+		// Blocking threat: prefer high cards (Card 9 = 100, Card 1 = 20).
+		// A capture that locks the cell is permanent with any card.
+		cardValue = weights.ReplaceValuesThreat[b.CardRank(card)]
 	} else {
 		// Defensive play: prefer low cards (Card 1 = 100, Card 9 = 20)
-		cardValue = weights.ReplaceValuesPotential[card]
+		cardValue = weights.ReplaceValuesPotential[b.CardRank(card)]
 	}
 
 	return cardValue
@@ -367,7 +452,7 @@ func f_proximity(b *Board, x, y int, playerID string, weights *config.HeuristicW
 
 	for _, dir := range directions {
 		nx, ny := x+dir[0], y+dir[1]
-		if in(nx, ny, b.Size) && b.Cells[ny][nx].OwnerID == playerID {
+		if b.Owns(Coord{X: nx, Y: ny}, playerID) {
 			return weights.KeepNearCard // 60
 		}
 	}
@@ -380,15 +465,13 @@ func getOpponentIDs(b *Board, playerID string) []string {
 	seen := make(map[string]bool)
 	var opponents []string
 
-	for y := 0; y < b.Size; y++ {
-		for x := 0; x < b.Size; x++ {
-			ownerID := b.Cells[y][x].OwnerID
-			if ownerID != "" && ownerID != playerID && !seen[ownerID] {
-				seen[ownerID] = true
-				opponents = append(opponents, ownerID)
-			}
+	b.Each(func(_ Coord, cell Cell) {
+		ownerID := cell.OwnerID
+		if ownerID != "" && ownerID != playerID && !seen[ownerID] {
+			seen[ownerID] = true
+			opponents = append(opponents, ownerID)
 		}
-	}
+	})
 
 	return opponents
 }
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"javanese-chess/internal/config"
+	"testing"
+)
+
+// placement is a card put on a test board
+type placement struct {
+	x, y  int
+	owner string
+	card  int
+}
+
+// row places the same card for owner on every x of row y
+func row(owner string, y int, xs []int, card int) []placement {
+	out := make([]placement, 0, len(xs))
+	for _, x := range xs {
+		out = append(out, placement{x: x, y: y, owner: owner, card: card})
+	}
+	return out
+}
+
+// testBoard builds a 9x9 board from placements, applied the way the classic
+// engine applies moves
+func testBoard(placements []placement) Board {
+	b := NewBoard(9)
+	b.CellAt(b.Center()).VState = CellBlocked
+	for _, p := range placements {
+		ApplyMove(&b, p.x, p.y, p.owner, p.card)
+		UpdateVState(&b)
+	}
+	return b
+}
+
+func TestForkTerm(t *testing.T) {
+	weights := config.HeuristicWeights{WFork: config.DefaultWFork}
+
+	// Two pairs, each blocked on one end, that (5,4) turns into two threes
+	crossing := []placement{
+		{3, 4, "bot", 4}, {4, 4, "bot", 5}, {2, 4, "opp", 9},
+		{5, 2, "bot", 3}, {5, 3, "bot", 6}, {5, 1, "opp", 9},
+		{3, 5, "opp", 9}, {6, 1, "opp", 9},
+	}
+
+	tests := []struct {
+		name  string
+		setup []placement
+		x, y  int
+		fork  bool
+	}{
+		{"open three", row("bot", 4, []int{3, 4}, 5), 5, 4, true},
+		{"crossing threes", crossing, 5, 4, true},
+		{"half-open three", append(row("bot", 4, []int{3, 4}, 5), placement{2, 4, "opp", 9}), 5, 4, false},
+		{"closed three", append(row("bot", 4, []int{3, 4}, 5), row("opp", 4, []int{2, 6}, 9)...), 5, 4, false},
+		{"split three", append(row("bot", 4, []int{2, 3}, 5), placement{5, 5, "opp", 9}), 5, 4, false},
+		{"three on the edge", row("bot", 4, []int{1, 2}, 5), 0, 4, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := testBoard(tt.setup)
+			if err := checkPlacement(&b, tt.x, tt.y, 2, "bot", b.Placement()); err != nil {
+				t.Fatalf("2 at (%d,%d) is not legal: %v", tt.x, tt.y, err)
+			}
+
+			want := 0
+			if tt.fork {
+				want = weights.WFork
+			}
+			bd := EvaluateMoveBreakdown(&b, tt.x, tt.y, 2, "bot", &weights)
+			if bd.Fork != want {
+				t.Errorf("Fork = %d, want %d", bd.Fork, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package game
+
+// HoldMoves lists the holds open to a player: putting a card from hand under
+// the deck and drawing the top card instead of placing, which uses up the
+// turn. Holds are swap moves; each card value is listed once.
+func HoldMoves(hand []int, playerID string) []Move {
+	seen := make(map[int]bool, len(hand))
+	var moves []Move
+	for _, card := range hand {
+		if seen[card] {
+			continue
+		}
+		seen[card] = true
+		moves = append(moves, Move{Card: card, PlayerID: playerID, Type: MoveSwap})
+	}
+	return moves
+}
+
+// ChooseHold decides whether a bot should hold a card rather than play one of
+// its scored placements. It holds only when no placement scores holdBelow or
+// more, and then gives up the card whose best placement is worth least; a
+// card that cannot be placed at all goes first. ok is false when the bot
+// should place, including whenever holdBelow is 0.
+func ChooseHold(hand []int, scored []ScoredMove, holdBelow int) (card int, ok bool) {
+	if holdBelow <= 0 || len(hand) == 0 {
+		return 0, false
+	}
+
+	best := make(map[int]int, len(hand))
+	for _, s := range scored {
+		if s.Score >= holdBelow {
+			return 0, false
+		}
+		if v, seen := best[s.Move.Card]; !seen || s.Score > v {
+			best[s.Move.Card] = s.Score
+		}
+	}
+
+	worst := 0
+	for _, c := range hand {
+		v, placeable := best[c]
+		if !placeable {
+			return c, true
+		}
+		if !ok || v < worst {
+			card, worst, ok = c, v, true
+		}
+	}
+	return card, ok
+}
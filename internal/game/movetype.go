@@ -0,0 +1,52 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MoveType distinguishes placing a card from the other turn actions
+type MoveType string
+
+const (
+	MovePlace  MoveType = "place"  // Place a card from hand on the board
+	MoveSkip   MoveType = "skip"   // Pass the turn (only when no legal placement exists)
+	MoveResign MoveType = "resign" // Leave the game; remaining players continue
+	MoveSwap   MoveType = "swap"   // Put a hand card under the deck and draw the top card
+)
+
+// Normalize returns the move type, treating an empty type as a placement
+func (t MoveType) Normalize() MoveType {
+	if t == "" {
+		return MovePlace
+	}
+	return t
+}
+
+// Validate checks that the move's fields are consistent with its type.
+// It does not check the move against the position; see GenerateLegalMoves.
+func (m Move) Validate(b *Board) error {
+	switch m.Type.Normalize() {
+	case MovePlace:
+		if m.Card < 1 || m.Card > b.TopCard() {
+			return fmt.Errorf("card must be between 1 and %d", b.TopCard())
+		}
+		if err := ValidateCoord(Coord{X: m.X, Y: m.Y}, b.Size, ""); err != nil {
+			return err
+		}
+	case MoveSwap:
+		if m.Card < 1 || m.Card > b.TopCard() {
+			return fmt.Errorf("card must be between 1 and %d", b.TopCard())
+		}
+	case MoveSkip, MoveResign:
+		if m.Card != 0 {
+			return errors.New("skip and resign moves do not carry a card")
+		}
+	default:
+		return errors.New("unknown move type")
+	}
+	if m.PlayerID == "" {
+		return errors.New("player id is required")
+	}
+	return nil
+}
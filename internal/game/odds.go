@@ -0,0 +1,48 @@
+package game
+
+// UnseenCards counts, per card value (index minCard to the board's highest
+// card), the copies a player has not seen yet in a deck holding `copies` of
+// every value: everything except the cards on the board, the cards
+// overwritten during the game and their own hand.
+func UnseenCards(copies, minCard int, b *Board, overwritten []int, hand []int) []int {
+	unseen := make([]int, b.TopCard()+1)
+	for v := minCard; v < len(unseen); v++ {
+		unseen[v] = copies
+	}
+
+	seen := func(v int) {
+		if v >= 1 && v < len(unseen) && unseen[v] > 0 {
+			unseen[v]--
+		}
+	}
+	b.Each(func(_ Coord, cell Cell) {
+		seen(cell.Value)
+	})
+	for _, v := range overwritten {
+		seen(v)
+	}
+	for _, v := range hand {
+		seen(v)
+	}
+	return unseen
+}
+
+// OverwriteChance is the probability that a random unseen card can overwrite
+// a placed card. The highest card is permanent and can never be overwritten.
+func OverwriteChance(unseen []int, card int) float64 {
+	if card >= len(unseen)-1 {
+		return 0
+	}
+
+	total, higher := 0, 0
+	for v := 1; v < len(unseen); v++ {
+		total += unseen[v]
+		if v > card {
+			higher += unseen[v]
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(higher) / float64(total)
+}
@@ -0,0 +1,131 @@
+package game
+
+import (
+	"fmt"
+	"javanese-chess/internal/config"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The opening book holds preferred moves for the first plies of a game on the
+// classic board, where the rules leave little to evaluate: the first card goes
+// on the center and the next ones next to it. Bots in rooms that enable the
+// book play from it while the position is in it and fall back to the
+// heuristic evaluation afterwards.
+//
+// Positions are looked up by their hash and the seat to move. Hashes key
+// owners by seat, so a book position is found whichever players reached it.
+
+// openingLines are the book's lines from the empty board, one move per ply in
+// turn order, written as cell:card. Where lines share a start the next moves
+// are alternatives, preferred in the order they first appear; a bot plays the
+// first one its hand allows.
+var openingLines = []string{
+	// The opener puts its lowest card on the center: whatever is placed there
+	// can be covered, so the cheapest card risks the least. The reply covers
+	// it as cheaply as the hand allows, or develops diagonally when it cannot.
+	"E5:1 E5:2 E5:3",
+	"E5:1 E5:2 E5:4",
+	"E5:1 E5:3 E5:4",
+	"E5:1 E5:3 E5:5",
+	"E5:1 D4:1 D4:2",
+	"E5:2 E5:3 E5:4",
+	"E5:2 E5:3 E5:5",
+	"E5:2 E5:4 E5:5",
+	"E5:2 D4:1 D4:2",
+	"E5:3 E5:4 E5:5",
+	"E5:3 E5:4 E5:6",
+	"E5:3 D4:1 D4:2",
+}
+
+// bookKey identifies a book position: the board and the seat to move (1-based)
+type bookKey struct {
+	hash PositionHash
+	seat int
+}
+
+var (
+	bookOnce sync.Once
+	book     map[bookKey][]Move
+)
+
+// BookMoves returns the book moves for playerID in the position that the hand
+// allows, best first. It returns nil when the position is not in the book or
+// the board is not the classic one.
+func BookMoves(b *Board, hand []int, playerID string) []Move {
+	if b.Size != config.DefaultBoardSize || b.TopCard() != config.DefaultMaxCard {
+		return nil
+	}
+	seat := slices.Index(b.Seats, playerID)
+	if seat < 0 {
+		return nil
+	}
+
+	bookOnce.Do(func() { book = buildBook(openingLines) })
+	rules := b.Placement()
+	var moves []Move
+	for _, mv := range book[bookKey{hash: b.Hash, seat: seat + 1}] {
+		mv.PlayerID = playerID
+		if slices.Contains(hand, mv.Card) && ValidateMove(b, mv, rules) == nil {
+			moves = append(moves, mv)
+		}
+	}
+	return moves
+}
+
+// buildBook replays every line and files each move under the position it was
+// played from. Any seat may open a game, so lines are replayed for every
+// table size and every opening seat.
+func buildBook(lines []string) map[bookKey][]Move {
+	seats := make([]string, config.MaxPlayers)
+	for i := range seats {
+		seats[i] = fmt.Sprintf("seat-%d", i+1)
+	}
+
+	out := map[bookKey][]Move{}
+	for _, line := range lines {
+		var moves []Move
+		for _, token := range strings.Fields(line) {
+			mv, err := parseBookMove(token)
+			if err != nil {
+				panic(fmt.Sprintf("opening book line %q: %v", line, err))
+			}
+			moves = append(moves, mv)
+		}
+
+		for n := config.MinPlayers; n <= config.MaxPlayers; n++ {
+			for opener := 0; opener < n; opener++ {
+				b := NewBoard(config.DefaultBoardSize)
+				b.SetSeats(seats[:n])
+				for ply, mv := range moves {
+					seat := (opener + ply) % n
+					key := bookKey{hash: b.Hash, seat: seat + 1}
+					if !slices.Contains(out[key], mv) {
+						out[key] = append(out[key], mv)
+					}
+					ApplyMove(&b, mv.X, mv.Y, seats[seat], mv.Card)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// parseBookMove reads a book move such as "E5:1"
+func parseBookMove(s string) (Move, error) {
+	cell, card, ok := strings.Cut(s, ":")
+	if !ok {
+		return Move{}, fmt.Errorf("move %q is not cell:card", s)
+	}
+	c, err := ParseAlgebraic(cell)
+	if err != nil {
+		return Move{}, err
+	}
+	value, err := strconv.Atoi(card)
+	if err != nil || value < 1 || value > config.DefaultMaxCard {
+		return Move{}, fmt.Errorf("move %q has no valid card", s)
+	}
+	return Move{X: c.X, Y: c.Y, Card: value}, nil
+}
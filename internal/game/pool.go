@@ -0,0 +1,101 @@
+package game
+
+import (
+	"javanese-chess/internal/config"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EvalPool scores candidate moves on a bounded number of goroutines shared
+// by every caller, so a busy server runs at most that many evaluations at
+// once whatever the number of rooms with a bot to move. ScoreBoard also
+// reuses scores of positions scored before, from a transposition table
+// shared by every caller.
+type EvalPool struct {
+	slots chan struct{}
+	table *TranspositionTable
+}
+
+// NewEvalPool returns a pool of workers helper goroutines. With fewer than
+// one, callers score their moves alone.
+func NewEvalPool(workers int) *EvalPool {
+	return &EvalPool{
+		slots: make(chan struct{}, max(workers, 0)),
+		table: NewTranspositionTable(config.EvalCacheEntries),
+	}
+}
+
+// ScoreBoard is Score for the moves of playerID on b, looking each move up
+// in the pool's transposition table first and storing the scores it
+// computes. score must depend only on the board, the player, the move and
+// what salt fingerprints (see PositionKey).
+func (p *EvalPool) ScoreBoard(b *Board, playerID string, salt uint64, moves []Move, deadline time.Time, score func(Move) int) (scored []ScoredMove, complete bool) {
+	position := PositionKey(b, playerID, salt)
+	return p.Score(moves, deadline, func(mv Move) int {
+		key := MoveKey(position, mv)
+		if s, ok := p.table.Get(key); ok {
+			return s
+		}
+		s := score(mv)
+		p.table.Put(key, s)
+		return s
+	})
+}
+
+// Score evaluates the moves with the free workers of the pool and the calling
+// goroutine, which always takes part so it never waits for a slot. score must
+// only read the board (see EvaluateMoveBreakdown).
+//
+// The result keeps the order of moves whatever worker scored them, so ties
+// are broken the same way as when scoring one by one. Once the deadline has
+// passed no further move is started and the moves not scored are left out;
+// the first move is always scored. A zero deadline never expires.
+func (p *EvalPool) Score(moves []Move, deadline time.Time, score func(Move) int) (scored []ScoredMove, complete bool) {
+	scores := make([]int, len(moves))
+	done := make([]bool, len(moves))
+
+	var next atomic.Int64
+	work := func() {
+		for {
+			i := int(next.Add(1) - 1)
+			if i >= len(moves) {
+				return
+			}
+			if i > 0 && !deadline.IsZero() && time.Now().After(deadline) {
+				return
+			}
+			scores[i] = score(moves[i])
+			done[i] = true
+		}
+	}
+
+	// Borrow free workers, one for each move beyond the caller's first
+	var wg sync.WaitGroup
+borrow:
+	for helpers := 1; helpers < len(moves); helpers++ {
+		select {
+		case p.slots <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-p.slots
+					wg.Done()
+				}()
+				work()
+			}()
+		default:
+			break borrow
+		}
+	}
+	work()
+	wg.Wait()
+
+	scored = make([]ScoredMove, 0, len(moves))
+	for i, mv := range moves {
+		if done[i] {
+			scored = append(scored, ScoredMove{Move: mv, Score: scores[i]})
+		}
+	}
+	return scored, len(scored) == len(moves)
+}
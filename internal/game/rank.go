@@ -0,0 +1,38 @@
+package game
+
+import (
+	"javanese-chess/internal/config"
+	"sort"
+)
+
+// RankedMove is a legal move together with its heuristic breakdown
+type RankedMove struct {
+	Move      Move          `json:"move"`
+	Cell      string        `json:"cell"`
+	Breakdown MoveBreakdown `json:"breakdown"`
+}
+
+// RankMoves scores every legal move for the hand, best first. Moves with
+// equal scores keep the legal move generation order.
+func RankMoves(b *Board, hand []int, playerID string, weights *config.HeuristicWeights) []RankedMove {
+	return RankCandidates(b, GenerateLegalMoves(b, hand, playerID), playerID, weights)
+}
+
+// RankCandidates scores the given moves, best first, for callers whose legal
+// moves come from a rules engine. The first move is the one a bot playing
+// without sampling would choose.
+func RankCandidates(b *Board, cands []Move, playerID string, weights *config.HeuristicWeights) []RankedMove {
+	ranked := make([]RankedMove, 0, len(cands))
+	for _, mv := range cands {
+		ranked = append(ranked, RankedMove{
+			Move:      mv,
+			Cell:      Coord{X: mv.X, Y: mv.Y}.Algebraic(),
+			Breakdown: EvaluateMoveBreakdown(b, mv.X, mv.Y, mv.Card, playerID, weights),
+		})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Breakdown.Total > ranked[j].Breakdown.Total
+	})
+	return ranked
+}
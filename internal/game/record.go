@@ -0,0 +1,72 @@
+package game
+
+import "time"
+
+// MoveRecord holds everything needed to revert a move that was applied to the board
+type MoveRecord struct {
+	Type      MoveType     `json:"type"`
+	X         int          `json:"x"`
+	Y         int          `json:"y"`
+	Card      int          `json:"card"`
+	PlayerID  string       `json:"player_id"`
+	PrevCell  Cell         `json:"prev_cell"`           // Cell content before the card was placed
+	HandIdx   int          `json:"hand_idx"`            // Position of the played card in the hand
+	DrawnCard int          `json:"drawn_card"`          // Card drawn after the move (0 if deck was empty)
+	FromPile  bool         `json:"from_pile,omitempty"` // DrawnCard came from the communal pile
+	TurnIdx   int          `json:"turn_idx"`            // Turn index before the move
+	Hash      PositionHash `json:"hash,omitempty"`      // Position hash after a placement
+	At        time.Time    `json:"at"`                  // When the move was played
+	ThinkMs   int64        `json:"think_ms,omitempty"`  // Time the player took over a placement
+	Block     bool         `json:"block,omitempty"`     // The placement blocked an opponent's three in a row
+	Bot       *BotDecision `json:"bot,omitempty"`       // How a bot chose the move
+}
+
+// Captured returns the value of the opponent card the move covered, or 0
+// when it was not a placement on an opponent's card
+func (rec MoveRecord) Captured() int {
+	if rec.Type.Normalize() != MovePlace || rec.PrevCell.OwnerID == "" || rec.PrevCell.OwnerID == rec.PlayerID {
+		return 0
+	}
+	return rec.PrevCell.Value
+}
+
+// BotDecision records how a bot chose its move, for analysing decision
+// quality after the game
+type BotDecision struct {
+	Score         int     `json:"score"`                     // Evaluation of the move played
+	Candidates    int     `json:"candidates"`                // Legal moves evaluated
+	RunnerUp      *Move   `json:"runner_up,omitempty"`       // Best evaluated move other than the one played
+	RunnerUpScore int     `json:"runner_up_score,omitempty"` // Evaluation of RunnerUp
+	LatencyMs     float64 `json:"latency_ms"`                // Time spent choosing, not counting the thinking pause
+	Book          bool    `json:"book,omitempty"`            // Played from the opening book without evaluation
+	OutOfTime     bool    `json:"out_of_time,omitempty"`     // The time budget ran out before every legal move was evaluated
+}
+
+// LastPlaced is the cell of the last placement in history, nil when there
+// is none. Boards use it to restore LastMove after moves are reverted.
+func LastPlaced(history []MoveRecord) *Coord {
+	for i := len(history) - 1; i >= 0; i-- {
+		if rec := history[i]; rec.Type.Normalize() == MovePlace {
+			return &Coord{X: rec.X, Y: rec.Y}
+		}
+	}
+	return nil
+}
+
+// RevertMove restores the cell touched by rec and recomputes virtual states.
+// The center cell is re-blocked when the board becomes empty again.
+// Records that did not touch the board are ignored.
+func RevertMove(b *Board, rec MoveRecord) {
+	if rec.Type.Normalize() != MovePlace {
+		return
+	}
+	b.toggleCell(rec.X, rec.Y)
+	*b.CellAt(Coord{X: rec.X, Y: rec.Y}) = rec.PrevCell
+	b.toggleCell(rec.X, rec.Y)
+	UpdateVState(b)
+
+	if !b.IsEmpty() {
+		return
+	}
+	b.CellAt(b.Center()).VState = CellBlocked
+}
@@ -0,0 +1,56 @@
+package game
+
+import (
+	"math"
+	"math/rand"
+)
+
+// ScoredMove is a candidate move with its heuristic score
+type ScoredMove struct {
+	Move  Move `json:"move"`
+	Score int  `json:"score"`
+}
+
+// SampleMove picks a move by softmax over scores: a move scoring d points
+// below the best is chosen with relative weight exp(-d/temperature). Only
+// moves within 3*temperature of the best are considered. A temperature of
+// zero or less always returns the first top-scored move.
+func SampleMove(scored []ScoredMove, temperature float64, rng *rand.Rand) (ScoredMove, bool) {
+	if len(scored) == 0 {
+		return ScoredMove{}, false
+	}
+
+	best := scored[0]
+	for _, s := range scored[1:] {
+		if s.Score > best.Score {
+			best = s
+		}
+	}
+	if temperature <= 0 || rng == nil {
+		return best, true
+	}
+
+	window := 3 * temperature
+	weights := make([]float64, len(scored))
+	total := 0.0
+	for i, s := range scored {
+		d := float64(best.Score - s.Score)
+		if d > window {
+			continue
+		}
+		weights[i] = math.Exp(-d / temperature)
+		total += weights[i]
+	}
+
+	pick := rng.Float64() * total
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		pick -= w
+		if pick <= 0 {
+			return scored[i], true
+		}
+	}
+	return best, true
+}
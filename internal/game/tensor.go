@@ -0,0 +1,44 @@
+package game
+
+// Board tensors feed positions to learned models. A tensor holds
+// TensorPlanes planes of Size x Size values, indexed [plane][y][x], and sees
+// the board from one player's side so the same position encodes the same
+// way whoever is to move.
+const (
+	PlaneOwn       = iota // The player's cards, valued card/top card
+	PlaneOpponent         // Every other player's cards, valued the same way
+	PlanePermanent        // 1 where a card can no longer be overwritten
+	PlaneEmpty            // 1 where no card has been placed
+	TensorPlanes
+)
+
+// EncodeBoard returns the board as seen by playerID
+func EncodeBoard(b *Board, playerID string) [][][]float32 {
+	top := float32(b.TopCard())
+	t := make([][][]float32, TensorPlanes)
+	for p := range t {
+		t[p] = make([][]float32, b.Size)
+		for y := range t[p] {
+			t[p][y] = make([]float32, b.Size)
+		}
+	}
+
+	for y := 0; y < b.Size; y++ {
+		for x := 0; x < b.Size; x++ {
+			c := b.At(Coord{X: x, Y: y})
+			if c.Value == 0 {
+				t[PlaneEmpty][y][x] = 1
+				continue
+			}
+			plane := PlaneOpponent
+			if c.OwnerID == playerID {
+				plane = PlaneOwn
+			}
+			t[plane][y][x] = float32(c.Value) / top
+			if b.Permanent(x, y) {
+				t[PlanePermanent][y][x] = 1
+			}
+		}
+	}
+	return t
+}
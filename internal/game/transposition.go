@@ -0,0 +1,85 @@
+package game
+
+import (
+	"fmt"
+	"hash/fnv"
+	"javanese-chess/internal/config"
+	"sync"
+)
+
+// TranspositionTable remembers move scores by position, so a position met
+// again, after an undo, in a rematch or in another room, is not evaluated
+// twice. An entry is keyed by the board's position hash together with
+// everything else the score depends on: the move, the seat of the player,
+// the placement rules and the last move, and a salt the caller derives from
+// its evaluation settings (see WeightsKey). The table holds a fixed number
+// of entries and a new entry replaces whatever shared its slot, as in a
+// chess engine. It is safe for concurrent use.
+type TranspositionTable struct {
+	entries []ttEntry
+	locks   [64]sync.Mutex // Striped over the entries
+}
+
+type ttEntry struct {
+	key   uint64
+	score int
+	set   bool
+}
+
+// NewTranspositionTable returns a table of size entries, at least one
+func NewTranspositionTable(size int) *TranspositionTable {
+	return &TranspositionTable{entries: make([]ttEntry, max(size, 1))}
+}
+
+// Get returns the score stored under key
+func (t *TranspositionTable) Get(key uint64) (int, bool) {
+	i := key % uint64(len(t.entries))
+	mu := &t.locks[i%uint64(len(t.locks))]
+	mu.Lock()
+	defer mu.Unlock()
+	if e := t.entries[i]; e.set && e.key == key {
+		return e.score, true
+	}
+	return 0, false
+}
+
+// Put stores a score under key
+func (t *TranspositionTable) Put(key uint64, score int) {
+	i := key % uint64(len(t.entries))
+	mu := &t.locks[i%uint64(len(t.locks))]
+	mu.Lock()
+	t.entries[i] = ttEntry{key: key, score: score, set: true}
+	mu.Unlock()
+}
+
+// PositionKey is the part of a table key shared by every move of playerID
+// on the board: the position, the player's seat, the rules that decide
+// which replies are legal and the caller's salt
+func PositionKey(b *Board, playerID string, salt uint64) uint64 {
+	rules := uint64(b.LockAfter)<<8 | uint64(b.MaxCard)
+	if b.OwnOverwrite {
+		rules |= 1 << 16
+	}
+	if b.CoverTop {
+		rules |= 1 << 17
+	}
+	if b.NextToLast && b.LastMove != nil {
+		rules |= 1<<18 | uint64(b.LastMove.Y*b.Size+b.LastMove.X)<<20
+	}
+	k := splitmix64(uint64(b.Hash) ^ salt)
+	k = splitmix64(k ^ b.ownerKey(playerID))
+	return splitmix64(k ^ rules)
+}
+
+// MoveKey is the table key of a move from the position key
+func MoveKey(position uint64, mv Move) uint64 {
+	return splitmix64(position ^ uint64(mv.Y)<<40 ^ uint64(mv.X)<<24 ^ uint64(mv.Card))
+}
+
+// WeightsKey fingerprints heuristic weights, so scores under different
+// weights never share table entries
+func WeightsKey(w *config.HeuristicWeights) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%v", *w) // Maps print in key order
+	return h.Sum64()
+}
@@ -0,0 +1,103 @@
+package game
+
+import (
+	"javanese-chess/internal/config"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingScore scores moves with the default weights and counts the
+// evaluations it runs
+func countingScore(b *Board, player string, calls *atomic.Int64) func(Move) int {
+	weights := config.Get().DefaultWeights
+	return func(mv Move) int {
+		calls.Add(1)
+		return EvaluateMoveBreakdown(b, mv.X, mv.Y, mv.Card, player, &weights).Total
+	}
+}
+
+func TestScoreBoardReusesScores(t *testing.T) {
+	// The same cards placed in a different order reach the same position
+	first := testBoard([]placement{{4, 4, "bot", 5}, {5, 4, "opp", 3}, {5, 5, "bot", 7}})
+	second := testBoard([]placement{{4, 4, "bot", 5}, {5, 5, "bot", 7}, {5, 4, "opp", 3}})
+	if first.Hash != second.Hash {
+		t.Fatal("boards differ")
+	}
+
+	pool := NewEvalPool(2)
+	hand := []int{2, 6, 9}
+	moves := GenerateLegalMoves(&first, hand, "bot")
+
+	var calls atomic.Int64
+	want, _ := pool.ScoreBoard(&first, "bot", 1, moves, time.Time{}, countingScore(&first, "bot", &calls))
+	if calls.Load() != int64(len(moves)) {
+		t.Fatalf("first scoring ran %d evaluations, want %d", calls.Load(), len(moves))
+	}
+
+	calls.Store(0)
+	got, _ := pool.ScoreBoard(&second, "bot", 1, moves, time.Time{}, countingScore(&second, "bot", &calls))
+	if calls.Load() != 0 {
+		t.Errorf("repeated position ran %d evaluations, want 0", calls.Load())
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("reused scores %v, want %v", got, want)
+	}
+
+	// Another salt or another player is another entry
+	calls.Store(0)
+	pool.ScoreBoard(&first, "bot", 2, moves, time.Time{}, countingScore(&first, "bot", &calls))
+	if calls.Load() != int64(len(moves)) {
+		t.Errorf("new salt ran %d evaluations, want %d", calls.Load(), len(moves))
+	}
+	calls.Store(0)
+	oppMoves := GenerateLegalMoves(&first, hand, "opp")
+	pool.ScoreBoard(&first, "opp", 1, oppMoves, time.Time{}, countingScore(&first, "opp", &calls))
+	if calls.Load() != int64(len(oppMoves)) {
+		t.Errorf("other player ran %d evaluations, want %d", calls.Load(), len(oppMoves))
+	}
+}
+
+// Under the next-to-last rule the last move decides the legal replies, so it
+// is part of the key
+func TestPositionKeyNextToLast(t *testing.T) {
+	b := testBoard([]placement{{4, 4, "bot", 5}, {5, 4, "opp", 3}})
+	other := b.Clone()
+	other.LastMove = &Coord{X: 4, Y: 4}
+
+	if PositionKey(&b, "bot", 0) != PositionKey(&other, "bot", 0) {
+		t.Error("last move changed the key without the next-to-last rule")
+	}
+	b.NextToLast, other.NextToLast = true, true
+	if PositionKey(&b, "bot", 0) == PositionKey(&other, "bot", 0) {
+		t.Error("last move did not change the key under the next-to-last rule")
+	}
+}
+
+func TestTranspositionTableReplaces(t *testing.T) {
+	tt := NewTranspositionTable(1)
+	tt.Put(1, 10)
+	if s, ok := tt.Get(1); !ok || s != 10 {
+		t.Fatalf("Get(1) = %d, %v", s, ok)
+	}
+	tt.Put(2, 20)
+	if _, ok := tt.Get(1); ok {
+		t.Error("replaced entry still found")
+	}
+	if s, ok := tt.Get(2); !ok || s != 20 {
+		t.Errorf("Get(2) = %d, %v", s, ok)
+	}
+}
+
+func TestWeightsKey(t *testing.T) {
+	a := config.Get().DefaultWeights
+	b := config.Get().DefaultWeights
+	if WeightsKey(&a) != WeightsKey(&b) {
+		t.Fatal("equal weights have different keys")
+	}
+	b.WFork++
+	if WeightsKey(&a) == WeightsKey(&b) {
+		t.Error("different weights share a key")
+	}
+}
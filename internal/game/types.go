@@ -1,9 +1,11 @@
 package game
 
+import "javanese-chess/internal/config"
+
 type CellVState int
 
 const (
-	CellAccessible  CellVState = 0 // Empty and accessible, or permanent (card 9)
+	CellAccessible  CellVState = 0 // Empty and accessible, or permanent (the highest card)
 	CellBlocked     CellVState = 1 // Empty but blocked (has filled neighbors)
 	CellReplaceable CellVState = 2 // Filled and can be overwritten
 )
@@ -12,11 +14,68 @@ type Cell struct {
 	Value   int        `json:"value"`   // Card value (0 if empty)
 	VState  CellVState `json:"vState"`  // State of the cell (e.g., placeable or not)
 	OwnerID string     `json:"ownerId"` // ID of the player who owns the cell
+	// Captures counts how often the cell was taken from another player
+	Captures int `json:"captures,omitempty"`
+	// Locked cells are permanent like the highest card (cell-lock variant)
+	Locked bool `json:"locked,omitempty"`
 }
 
 type Board struct {
 	Size  int      `json:"size"`
 	Cells [][]Cell `json:"cells"`
+	// LockAfter is the number of captures after which a cell locks; 0
+	// disables the cell-lock variant
+	LockAfter int `json:"lock_after,omitempty"`
+	// MaxCard is the highest card in play, which can never be overwritten
+	// unless CoverTop is set; 0 means the classic 9
+	MaxCard int `json:"max_card,omitempty"`
+	// OwnOverwrite lets players cover their own cards (see PlacementRules)
+	OwnOverwrite bool `json:"own_overwrite,omitempty"`
+	// CoverTop makes the highest card coverable by another highest card
+	// instead of permanent; NextToLast only takes cards next to LastMove
+	CoverTop   bool `json:"cover_top,omitempty"`
+	NextToLast bool `json:"next_to_last,omitempty"`
+	// LastMove is the cell of the card placed last, nil before the first
+	LastMove *Coord `json:"last_move,omitempty"`
+	// Hash identifies the position and is kept up to date as cards are
+	// placed; Seats is the player order owners are hashed by
+	Hash  PositionHash `json:"hash"`
+	Seats []string     `json:"seats,omitempty"`
+
+	// frontier holds the cells move generation looks at (see frontier.go)
+	frontier frontier
+}
+
+// LocksOnCapture reports whether capturing the cell at (x,y) would lock it
+func (b *Board) LocksOnCapture(x, y int) bool {
+	return b.LockAfter > 0 && b.At(Coord{X: x, Y: y}).Captures+1 >= b.LockAfter
+}
+
+// TopCard is the highest card in play
+func (b *Board) TopCard() int {
+	if b.MaxCard > 0 {
+		return b.MaxCard
+	}
+	return config.DefaultMaxCard
+}
+
+// Permanent reports whether the cell at (x,y) can no longer be overwritten:
+// it holds the highest card (unless CoverTop) or was locked
+func (b *Board) Permanent(x, y int) bool {
+	c := b.At(Coord{X: x, Y: y})
+	return (c.Value == b.TopCard() && !b.CoverTop) || c.Locked
+}
+
+// CardRank scales a card onto the classic 1-9 range, so tables keyed by
+// card value (see config.HeuristicWeights) fit any deck. The highest card
+// always ranks 9.
+func (b *Board) CardRank(card int) int {
+	top := b.TopCard()
+	if top == config.DefaultMaxCard {
+		return card
+	}
+	rank := (card*config.DefaultMaxCard + top - 1) / top
+	return max(1, min(rank, config.DefaultMaxCard))
 }
 
 func NewBoard(size int) Board {
@@ -35,15 +94,36 @@ func NewBoard(size int) Board {
 		}
 	}
 
-	return Board{
+	b := Board{
 		Size:  size,
 		Cells: c,
 	}
+	b.resetFrontier()
+	return b
+}
+
+// KeepRules copies the rule settings of a room's board (cell lock, top card,
+// own overwrite and the feature flags) onto b, for boards set up from scratch
+// mid-room
+func (b *Board) KeepRules(from *Board) {
+	b.LockAfter, b.MaxCard, b.OwnOverwrite = from.LockAfter, from.MaxCard, from.OwnOverwrite
+	b.CoverTop, b.NextToLast = from.CoverTop, from.NextToLast
+}
+
+// Clone returns a deep copy of the board
+func (b Board) Clone() Board {
+	out := Board{Size: b.Size, Cells: make([][]Cell, len(b.Cells)), Hash: b.Hash, Seats: b.Seats, LastMove: b.LastMove, frontier: b.frontier}
+	out.KeepRules(&b)
+	for y := range b.Cells {
+		out.Cells[y] = append([]Cell(nil), b.Cells[y]...)
+	}
+	return out
 }
 
 type Move struct {
-	X        int    `json:"x"`
-	Y        int    `json:"y"`
-	Card     int    `json:"value"`
-	PlayerID string `json:"playerId"`
+	X        int      `json:"x"`
+	Y        int      `json:"y"`
+	Card     int      `json:"value"`
+	PlayerID string   `json:"playerId"`
+	Type     MoveType `json:"type,omitempty"` // Empty means place
 }
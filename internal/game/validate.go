@@ -0,0 +1,98 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PlacementRules are the room options that change where a card may go
+type PlacementRules struct {
+	// OwnOverwrite lets players cover their own cards with a higher card
+	OwnOverwrite bool
+	// CoverTop lets the highest card cover another highest card
+	CoverTop bool
+	// NextToLast only takes cards on the cells around the card placed last
+	NextToLast bool
+}
+
+// Placement returns the placement rules the board is played with
+func (b *Board) Placement() PlacementRules {
+	return PlacementRules{OwnOverwrite: b.OwnOverwrite, CoverTop: b.CoverTop, NextToLast: b.NextToLast}
+}
+
+// Why a placement is illegal
+var (
+	ErrNotCenter     = errors.New("the first card must go in the center")
+	ErrNotAdjacent   = errors.New("cell is not next to a card")
+	ErrPermanent     = errors.New("the card on this cell is permanent")
+	ErrNotHigher     = errors.New("card must be higher than the card it covers")
+	ErrOwnOverwrite  = errors.New("cannot overwrite your own card")
+	ErrNotNextToLast = errors.New("card must go next to the last card placed")
+)
+
+// ValidateMove checks a placement against the position: the first card goes
+// in the center, later cards next to a card or on a lower card that is not
+// permanent and, unless the rules allow it, not the player's own. Rooms with
+// the last move rule also keep cards around the card placed last. It is the
+// single definition of a legal placement; GenerateLegalMoves lists exactly
+// the moves it accepts.
+func ValidateMove(b *Board, mv Move, rules PlacementRules) error {
+	if err := b.CheckCoord(Coord{X: mv.X, Y: mv.Y}, ""); err != nil {
+		return err
+	}
+	if mv.Card < 1 || mv.Card > b.TopCard() {
+		return fmt.Errorf("card must be between 1 and %d", b.TopCard())
+	}
+	if b.IsEmpty() {
+		if (Coord{X: mv.X, Y: mv.Y}) != b.Center() {
+			return ErrNotCenter
+		}
+		return nil
+	}
+	return checkPlacement(b, mv.X, mv.Y, mv.Card, mv.PlayerID, rules)
+}
+
+// checkPlacement is ValidateMove for an on-board cell of a board that
+// already holds cards. Its errors do not allocate, as move generation calls
+// it for every cell and card.
+func checkPlacement(b *Board, x, y, card int, playerID string, rules PlacementRules) error {
+	cell := b.At(Coord{X: x, Y: y})
+
+	// Empty cells take a card only next to an existing one
+	if cell.Value == 0 && cell.VState == CellAccessible {
+		return ErrNotAdjacent
+	}
+	if rules.NextToLast && b.LastMove != nil && !b.LastMove.Touches(Coord{X: x, Y: y}) {
+		return ErrNotNextToLast
+	}
+
+	// The highest card and locked cells can never be covered
+	if b.Permanent(x, y) {
+		return ErrPermanent
+	}
+	if cell.Value == 0 {
+		return nil
+	}
+	if card <= cell.Value && !(rules.CoverTop && card == b.TopCard()) {
+		return ErrNotHigher
+	}
+	if cell.OwnerID == playerID && !rules.OwnOverwrite {
+		return ErrOwnOverwrite
+	}
+	return nil
+}
+
+// IsEmpty reports whether no card has been placed yet
+func (b *Board) IsEmpty() bool {
+	if b.frontier.valid {
+		return b.frontier.set == [frontierWords]uint64{}
+	}
+	for _, row := range b.Cells {
+		for _, cell := range row {
+			if cell.Value != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
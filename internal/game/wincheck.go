@@ -1,24 +1,82 @@
 package game
 
+// WinLength is how many cards in a row win the game
+const WinLength = 4
+
 func IsWinningAfter(b Board, x, y int, owner string, card int) bool {
 	dirs := [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
 	for _, d := range dirs {
 		count := 1
 		i, j := x+d[0], y+d[1]
-		for in(i, j, b.Size) && b.Cells[j][i].OwnerID == owner {
+		for b.Owns(Coord{X: i, Y: j}, owner) {
 			count++
 			i += d[0]
 			j += d[1]
 		}
 		i, j = x-d[0], y-d[1]
-		for in(i, j, b.Size) && b.Cells[j][i].OwnerID == owner {
+		for b.Owns(Coord{X: i, Y: j}, owner) {
 			count++
 			i -= d[0]
 			j -= d[1]
 		}
-		if count >= 4 {
+		if count >= WinLength {
 			return true
 		}
 	}
 	return false
 }
+
+// WinCell is one cell of a winning line and the move number (1-based) that
+// placed the card currently on it
+type WinCell struct {
+	Coord
+	MoveNo int `json:"move_no"`
+}
+
+// WinningLine returns the cells of the longest line of owner's cards through
+// (x, y), ordered from one end to the other, or nil if it is shorter than
+// WinLength
+func WinningLine(b Board, x, y int, owner string) []Coord {
+	dirs := [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+	var best []Coord
+	for _, d := range dirs {
+		// Walk back to the start of the line, then collect forward
+		sx, sy := x, y
+		for b.Owns(Coord{X: sx - d[0], Y: sy - d[1]}, owner) {
+			sx -= d[0]
+			sy -= d[1]
+		}
+
+		var line []Coord
+		for i, j := sx, sy; b.Owns(Coord{X: i, Y: j}, owner); i, j = i+d[0], j+d[1] {
+			line = append(line, Coord{X: i, Y: j})
+		}
+		if len(line) > len(best) {
+			best = line
+		}
+	}
+
+	if len(best) < WinLength {
+		return nil
+	}
+	return best
+}
+
+// LineLength returns the length of the longest line of owner's cards through (x, y)
+func LineLength(b Board, x, y int, owner string) int {
+	dirs := [][2]int{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+	best := 0
+	for _, d := range dirs {
+		count := 1
+		for i, j := x+d[0], y+d[1]; b.Owns(Coord{X: i, Y: j}, owner); i, j = i+d[0], j+d[1] {
+			count++
+		}
+		for i, j := x-d[0], y-d[1]; b.Owns(Coord{X: i, Y: j}, owner); i, j = i-d[0], j-d[1] {
+			count++
+		}
+		if count > best {
+			best = count
+		}
+	}
+	return best
+}
@@ -0,0 +1,120 @@
+// Package i18n translates user-facing messages. Messages are written in
+// English throughout the server; the catalogs map them to the other
+// supported languages, so callers keep producing English and translate at
+// the edge, just before a message reaches a client.
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported languages
+const (
+	EN = "en" // English, the language messages are written in (default)
+	ID = "id" // Indonesian
+)
+
+// Default is the language used when a client asks for none we support
+const Default = EN
+
+// catalog translates the English messages of one language. Exact holds
+// fixed messages; Patterns match messages formatted with values, the
+// replacements referring to the captured values as $1, $2, ...
+type catalog struct {
+	Exact    map[string]string
+	Patterns []pattern
+}
+
+type pattern struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+var catalogs = map[string]*catalog{
+	EN: {},
+	ID: indonesian,
+}
+
+// Supported reports whether messages can be returned in lang
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Languages lists the supported language codes, sorted
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// T returns msg in lang. Messages without a translation, and languages we
+// do not support, return msg unchanged.
+func T(lang, msg string) string {
+	cat, ok := catalogs[lang]
+	if !ok || lang == EN {
+		return msg
+	}
+	if s, ok := cat.Exact[msg]; ok {
+		return s
+	}
+	for _, p := range cat.Patterns {
+		if m := p.re.FindStringSubmatchIndex(msg); m != nil {
+			return string(p.re.ExpandString(nil, p.repl, msg, m))
+		}
+	}
+	return msg
+}
+
+// Negotiate picks the supported language a client prefers. lang is an
+// explicit choice (a ?lang query parameter) and wins when supported;
+// otherwise the Accept-Language header is weighed by its q-values. It
+// returns "" when neither names a supported language, so the caller can
+// fall back, for example to the room's locale.
+func Negotiate(lang, acceptLanguage string) string {
+	if l := Normalize(lang); Supported(l) {
+		return l
+	}
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		if l := Normalize(tag); Supported(l) && q > bestQ {
+			best, bestQ = l, q
+		}
+	}
+	return best
+}
+
+// Normalize reduces a language tag such as "id-ID" or "EN_us" to its
+// primary language code
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// patterns compiles message patterns given as pairs of a regular
+// expression and its replacement
+func patterns(pairs ...string) []pattern {
+	ps := make([]pattern, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		ps = append(ps, pattern{re: regexp.MustCompile("^" + pairs[i] + "$"), repl: pairs[i+1]})
+	}
+	return ps
+}
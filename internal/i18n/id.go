@@ -0,0 +1,203 @@
+package i18n
+
+// indonesian is the Indonesian catalog
+var indonesian = &catalog{
+	Exact: map[string]string{
+		// Rooms and seats
+		"Failed to create room":                               "Gagal membuat room",
+		"Failed to set room password":                         "Gagal memasang kata sandi room",
+		"Room not found":                                      "Room tidak ditemukan",
+		"room not found":                                      "room tidak ditemukan",
+		"room is full":                                        "room sudah penuh",
+		"room has ended":                                      "room sudah berakhir",
+		"room has already ended":                              "room sudah berakhir",
+		"wrong room password":                                 "kata sandi room salah",
+		"a game needs at least 2 players":                     "permainan membutuhkan minimal 2 pemain",
+		"a room holds at most 4 players":                      "satu room menampung paling banyak 4 pemain",
+		"bot games cannot seat humans":                        "permainan bot tidak bisa diisi pemain manusia",
+		"no players to seat":                                  "tidak ada pemain untuk didudukkan",
+		"every seat needs a name":                             "setiap kursi membutuhkan nama",
+		"player name already exists in this room":             "nama pemain sudah dipakai di room ini",
+		"player not found":                                    "pemain tidak ditemukan",
+		"player not in room":                                  "pemain tidak ada di room ini",
+		"player is controlled by another user":                "pemain dikendalikan oleh pengguna lain",
+		"invalid seat secret":                                 "rahasia kursi tidak valid",
+		"player has already resigned":                         "pemain sudah menyerah",
+		"seat not found":                                      "kursi tidak ditemukan",
+		"seat is not held by a human":                         "kursi tidak ditempati pemain manusia",
+		"seat is still occupied":                              "kursi masih ditempati",
+		"seats cannot be taken over in ranked games":          "kursi tidak bisa diambil alih dalam permainan peringkat",
+		"only the room owner can take snapshots":              "hanya pemilik room yang bisa menyimpan snapshot",
+		"bots are always ready":                               "bot selalu siap",
+		"more bot settings than bots":                         "pengaturan bot lebih banyak daripada jumlah bot",
+		"bots cannot abort games":                             "bot tidak bisa membatalkan permainan",
+		"bots do not vote for rematches":                      "bot tidak ikut memilih main ulang",
+		"too late to abort, resign instead":                   "sudah terlambat untuk membatalkan, menyerahlah",
+		"game has already started":                            "permainan sudah dimulai",
+		"game has not started":                                "permainan belum dimulai",
+		"game is already over":                                "permainan sudah selesai",
+		"game is not in progress":                             "permainan sedang tidak berlangsung",
+		"game is not over":                                    "permainan belum selesai",
+		"game is not over yet":                                "permainan belum selesai",
+		"match is still in progress":                          "pertandingan masih berlangsung",
+		"best_of must be 1, 3 or 5":                           "best_of harus 1, 3 atau 5",
+		"deck can only change before the game starts":         "deck hanya bisa diubah sebelum permainan dimulai",
+		"engine can only change before the first move":        "engine hanya bisa diubah sebelum langkah pertama",
+		"cell lock can only change before the first move":     "kunci sel hanya bisa diubah sebelum langkah pertama",
+		"own overwrite can only change before the first move": "menimpa kartu sendiri hanya bisa diubah sebelum langkah pertama",
+		"capture tie can only change before the first move":   "penentu seri lewat tangkapan hanya bisa diubah sebelum langkah pertama",
+		"features can only change before the first move":      "fitur hanya bisa diubah sebelum langkah pertama",
+		"manual rooms cannot be ranked":                       "room manual tidak bisa menjadi permainan peringkat",
+		"hands can only be set in manual rooms":               "kartu tangan hanya bisa diatur di room manual",
+		"hands can only be set before the first move":         "kartu tangan hanya bisa diatur sebelum langkah pertama",
+
+		// Moves
+		"not your turn or player invalid":             "bukan giliran Anda atau pemain tidak valid",
+		"not bot's turn":                              "bukan giliran bot",
+		"it is not a bot's turn":                      "bukan giliran bot",
+		"card not in hand":                            "kartu tidak ada di tangan",
+		"card must be higher than the card it covers": "kartu harus lebih tinggi dari kartu yang ditimpa",
+		"cannot overwrite your own card":              "tidak bisa menimpa kartu sendiri",
+		"cell is not next to a card":                  "sel tidak bersebelahan dengan kartu",
+		"card must go next to the last card placed":   "kartu harus diletakkan di sebelah kartu terakhir",
+		"the card on this cell is permanent":          "kartu di sel ini permanen",
+		"the first card must go in the center":        "kartu pertama harus diletakkan di tengah",
+		"only cells holding a card can be locked":     "hanya sel berisi kartu yang bisa dikunci",
+		"no legal moves available":                    "tidak ada langkah yang sah",
+		"legal moves available, cannot skip":          "masih ada langkah yang sah, tidak bisa melewati giliran",
+		"deck is empty, cannot swap":                  "deck kosong, tidak bisa menukar kartu",
+		"unknown move type":                           "jenis langkah tidak dikenal",
+		"skip and resign moves do not carry a card":   "langkah lewati dan menyerah tidak membawa kartu",
+		"could not find best move":                    "tidak dapat menemukan langkah terbaik",
+		"no move to undo":                             "tidak ada langkah untuk dibatalkan",
+		"an undo request is already pending":          "permintaan batal langkah masih menunggu",
+		"no undo request pending":                     "tidak ada permintaan batal langkah",
+		"cannot respond to your own undo request":     "tidak bisa menanggapi permintaan batal langkah sendiri",
+		"hints are disabled in this room":             "petunjuk dinonaktifkan di room ini",
+		"hints are for human players":                 "petunjuk hanya untuk pemain manusia",
+		"provide cell, x and y, or row and col":       "isi cell, x dan y, atau row dan col",
+		"row and col must both be integers":           "row dan col harus bilangan bulat",
+		"from and to must be integers":                "from dan to harus bilangan bulat",
+
+		// Decks and rules
+		"deck is too small to deal a hand":                  "deck terlalu kecil untuk membagikan kartu",
+		"shared deck is too small to deal every hand":       "deck bersama terlalu kecil untuk membagikan kartu ke semua pemain",
+		"board cells do not match board size":               "sel papan tidak sesuai dengan ukuran papan",
+		"deck_rule must be continue, communal or endgame":   "deck_rule harus continue, communal atau endgame",
+		"first_player must be random, master or loser":      "first_player harus random, master atau loser",
+		"rotation must be clockwise or counterclockwise":    "rotation harus clockwise atau counterclockwise",
+		"abandon action must be bot or forfeit":             "aksi abandon harus bot atau forfeit",
+		"turn_seconds must be positive":                     "turn_seconds harus positif",
+		"time bank cap must be non-negative":                "batas bank waktu tidak boleh negatif",
+		"clock_seconds must be positive":                    "clock_seconds harus positif",
+		"clock_inc must be non-negative":                    "clock_inc tidak boleh negatif",
+		"clock_inc needs clock_seconds":                     "clock_inc membutuhkan clock_seconds",
+		"turn_seconds and clock_seconds cannot be combined": "turn_seconds dan clock_seconds tidak bisa digabung",
+		"weights are required":                              "bobot wajib diisi",
+		"weights must be non-negative":                      "bobot tidak boleh negatif",
+		"temperature must be non-negative":                  "temperature tidak boleh negatif",
+		"bot_think_max must not be below bot_think_ms":      "bot_think_max tidak boleh di bawah bot_think_ms",
+		"locale must be en or id":                           "locale harus en atau id",
+		"invalid board size":                                "ukuran papan tidak valid",
+
+		// Requests
+		"invalid payload":                       "data tidak valid",
+		"malformed message":                     "pesan tidak valid",
+		"code is required":                      "code wajib diisi",
+		"name is required":                      "nama wajib diisi",
+		"hand is required":                      "hand wajib diisi",
+		"hands are required":                    "hands wajib diisi",
+		"player id is required":                 "ID pemain wajib diisi",
+		"player_id is required":                 "player_id wajib diisi",
+		"player_name is required":               "player_name wajib diisi",
+		"player_name array is required":         "player_name berupa array wajib diisi",
+		"roomCode is required":                  "roomCode wajib diisi",
+		"room_code is required":                 "room_code wajib diisi",
+		"room_id is required":                   "room_id wajib diisi",
+		"room_code or board is required":        "room_code atau board wajib diisi",
+		"limit must not be negative":            "limit tidak boleh negatif",
+		"lines must be a positive integer":      "lines harus bilangan bulat positif",
+		"n must be a positive integer":          "n harus bilangan bulat positif",
+		"offset must be a non-negative integer": "offset harus bilangan bulat tidak negatif",
+		"since must be a non-negative integer":  "since harus bilangan bulat tidak negatif",
+		"page must be a positive integer":       "page harus bilangan bulat positif",
+		"rtt_ms must be between 0 and 60000":    "rtt_ms harus antara 0 dan 60000",
+		"log file not available":                "berkas log tidak tersedia",
+		"no such endpoint":                      "endpoint tidak ditemukan",
+		"internal server error":                 "kesalahan internal server",
+
+		// Chat and reactions
+		"message is empty":           "pesan kosong",
+		"message is too long":        "pesan terlalu panjang",
+		"sending messages too fast":  "mengirim pesan terlalu cepat",
+		"sending reactions too fast": "mengirim reaksi terlalu cepat",
+		"unknown reaction":           "reaksi tidak dikenal",
+
+		// Accounts
+		"invalid admin token":                    "token admin tidak valid",
+		"authentication required":                "autentikasi diperlukan",
+		"rate limit exceeded":                    "batas permintaan terlampaui",
+		"invalid token":                          "token tidak valid",
+		"token expired":                          "token kedaluwarsa",
+		"could not issue token":                  "tidak dapat menerbitkan token",
+		"invalid username or password":           "nama pengguna atau kata sandi salah",
+		"username already taken":                 "nama pengguna sudah dipakai",
+		"username is required":                   "nama pengguna wajib diisi",
+		"password must be at least 8 characters": "kata sandi minimal 8 karakter",
+		"ratings are disabled":                   "rating dinonaktifkan",
+
+		// Matchmaking, tournaments and experiments
+		"ticket not found":                                             "tiket tidak ditemukan",
+		"tournament not found":                                         "turnamen tidak ditemukan",
+		"tournament has already started":                               "turnamen sudah dimulai",
+		"a tournament needs at least 2 entrants":                       "turnamen membutuhkan minimal 2 peserta",
+		"name is already registered":                                   "nama sudah terdaftar",
+		"format must be swiss or round_robin":                          "format harus swiss atau round_robin",
+		"rounds cannot be negative":                                    "rounds tidak boleh negatif",
+		"a round robin plays one round per opponent; leave rounds out": "round robin memainkan satu ronde per lawan; kosongkan rounds",
+		"experiment not found":                                         "eksperimen tidak ditemukan",
+	},
+	Patterns: patterns(
+		`card must be between 1 and (\d+)`, "kartu harus antara 1 dan $1",
+		`hand cards must be between (\d+) and (\d+)`, "kartu di tangan harus antara $1 dan $2",
+		`a hand holds (\d+) cards`, "satu tangan berisi $1 kartu",
+		`card (\d+) is dealt more than the (\d+) time\(s\) the deck holds it`, "kartu $1 dibagikan lebih dari $2 kali, sebanyak isinya di deck",
+		`player (".*") is given two hands`, "pemain $1 diberi dua tangan",
+		`max_card must be between 1 and (\d+)`, "max_card harus antara 1 dan $1",
+		`board cells must hold a card 1-(\d+) with an owner, or be empty`, "sel papan harus berisi kartu 1-$1 beserta pemiliknya, atau kosong",
+		`deck copies must be between 1 and (\d+)`, "jumlah salinan deck harus antara 1 dan $1",
+		`shared deck copies must be between 1 and (\d+)`, "jumlah salinan deck bersama harus antara 1 dan $1",
+		`deck cards must satisfy 1 <= min_card < max_card <= (\d+)`, "kartu deck harus memenuhi 1 <= min_card < max_card <= $1",
+		`abandon turns must be between 0 and (\d+)`, "giliran abandon harus antara 0 dan $1",
+		`abandon minutes must be between 0 and (\d+)`, "menit abandon harus antara 0 dan $1",
+		`bot budget must be between 0 and (\d+) ms`, "anggaran waktu bot harus antara 0 dan $1 ms",
+		`bot think time must be between 0 and (\d+) ms`, "waktu berpikir bot harus antara 0 dan $1 ms",
+		`a bot game needs (\d+) to (\d+) bots`, "permainan bot membutuhkan $1 sampai $2 bot",
+		`a demo needs (\d+) to (\d+) bots`, "demo membutuhkan $1 sampai $2 bot",
+		`demo delay must be between (\S+) and (\S+)`, "jeda demo harus antara $1 dan $2",
+		`a room seats (\d+) to (\d+) players`, "satu room memuat $1 sampai $2 pemain",
+		`seat name (".*") is used twice`, "nama kursi $1 dipakai dua kali",
+		`room has (\d+) player\(s\); cannot add (\d+) bot\(s\) \(max (\d+) players\)`, "room berisi $1 pemain; tidak bisa menambah $2 bot (maksimal $3 pemain)",
+		`(\d+) personalities given for (\d+) bot\(s\)`, "$1 kepribadian diberikan untuk $2 bot",
+		`unknown bot personality (".*")`, "kepribadian bot $1 tidak dikenal",
+		`unknown engine (".*") \(available: (.*)\)`, "engine $1 tidak dikenal (tersedia: $2)",
+		`unknown feature (".*") \(available: (.*)\)`, "fitur $1 tidak dikenal (tersedia: $2)",
+		`waiting for (.+) to be ready`, "menunggu $1 siap",
+		`room (\S+) already exists`, "room $1 sudah ada",
+		`name is longer than (\d+) characters`, "nama lebih dari $1 karakter",
+		`tournament is full \((\d+) entrants\)`, "turnamen sudah penuh ($1 peserta)",
+		`unsupported protocol version (\d+) \(server speaks (\d+)\)`, "versi protokol $1 tidak didukung (server memakai $2)",
+		`unknown action (".*")`, "aksi $1 tidak dikenal",
+		`unhandled action (".*")`, "aksi $1 tidak ditangani",
+		`invalid (\S+) data: (.*)`, "data $1 tidak valid: $2",
+		`invalid game record: (.*)`, "rekaman permainan tidak valid: $1",
+		`limit must be between 1 and (\d+)`, "limit harus antara 1 dan $1",
+		`(\S+) must be true or false`, "$1 harus true atau false",
+		`unsupported game record version (\d+)`, "versi rekaman permainan $1 tidak didukung",
+		`cell is outside the (\d+)x(\d+) board: (".*") interpreted as (.+)`, "sel berada di luar papan ${1}x${2}: $3 dibaca sebagai $4",
+		`cell is outside the (\d+)x(\d+) board: (.+)`, "sel berada di luar papan ${1}x${2}: $3",
+		`invalid cell notation: (".*") interpreted as (.+)`, "notasi sel tidak valid: $1",
+		`cell must start with a column letter: (".*") interpreted as (.+)`, "sel harus diawali huruf kolom: $1",
+		`cell must end with a row number: (".*") interpreted as (.+)`, "sel harus diakhiri nomor baris: $1",
+	),
+}
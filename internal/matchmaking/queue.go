@@ -0,0 +1,199 @@
+package matchmaking
+
+import (
+	"errors"
+	"javanese-chess/internal/shared"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RoomCreator creates and starts a room for a group of matched players.
+// It returns the player IDs in the same order as names.
+type RoomCreator interface {
+	CreateQuickRoom(names []string, bots int) (*shared.Room, []string, error)
+	SeatSecret(r *shared.Room, playerID string) string
+}
+
+// Notifier delivers events to WebSocket clients subscribed to a channel
+type Notifier interface {
+	Broadcast(roomCode string, action string, data interface{})
+}
+
+// Ticket is a player waiting in the queue. Clients subscribe to the ticket ID
+// over WebSocket (/ws?room_code=<ticket id>) to receive the match_found event.
+type Ticket struct {
+	ID         string    `json:"ticket_id"`
+	PlayerName string    `json:"player_name"`
+	QueuedAt   time.Time `json:"queued_at"`
+}
+
+// Options tunes how long the queue waits before forming a game
+type Options struct {
+	MinPlayers     int
+	MaxPlayers     int
+	GatherWindow   time.Duration // Wait this long for more humans before starting with MinPlayers
+	BotFillTimeout time.Duration // Start a lone player against bots after this long
+	TickInterval   time.Duration
+}
+
+// Queue groups waiting players into rooms
+type Queue struct {
+	mu       sync.Mutex
+	waiting  []Ticket
+	creator  RoomCreator
+	notifier Notifier
+	opts     Options
+	stop     chan struct{}
+}
+
+func NewQueue(creator RoomCreator, notifier Notifier, opts Options) *Queue {
+	return &Queue{
+		creator:  creator,
+		notifier: notifier,
+		opts:     opts,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start runs the matching loop in the background until Stop is called
+func (q *Queue) Start() {
+	go func() {
+		ticker := time.NewTicker(q.opts.TickInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				q.match(time.Now())
+			case <-q.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (q *Queue) Stop() {
+	close(q.stop)
+}
+
+// Enqueue adds a player to the queue and returns their ticket
+func (q *Queue) Enqueue(playerName string) (Ticket, error) {
+	if playerName == "" {
+		return Ticket{}, errors.New("player_name is required")
+	}
+
+	t := Ticket{
+		ID:         "q-" + uuid.NewString(),
+		PlayerName: playerName,
+		QueuedAt:   time.Now(),
+	}
+
+	q.mu.Lock()
+	q.waiting = append(q.waiting, t)
+	q.mu.Unlock()
+
+	log.Printf("Matchmaking: %s queued with ticket %s", playerName, t.ID)
+	return t, nil
+}
+
+// Cancel removes a ticket from the queue. Returns false if it was not waiting.
+func (q *Queue) Cancel(ticketID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, t := range q.waiting {
+		if t.ID == ticketID {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Waiting returns the number of queued players
+func (q *Queue) Waiting() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.waiting)
+}
+
+// match forms as many groups as the queue allows at time now
+func (q *Queue) match(now time.Time) {
+	for {
+		group, bots := q.nextGroup(now)
+		if len(group) == 0 {
+			return
+		}
+		q.launch(group, bots)
+	}
+}
+
+// nextGroup pops the next group of tickets ready to play and the number of
+// bots needed to fill the room
+func (q *Queue) nextGroup(now time.Time) ([]Ticket, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.waiting) == 0 {
+		return nil, 0
+	}
+
+	oldestWait := now.Sub(q.waiting[0].QueuedAt)
+	size, bots := 0, 0
+	switch {
+	case len(q.waiting) >= q.opts.MaxPlayers:
+		size = q.opts.MaxPlayers
+	case len(q.waiting) >= q.opts.MinPlayers && oldestWait >= q.opts.GatherWindow:
+		size = len(q.waiting)
+	case oldestWait >= q.opts.BotFillTimeout:
+		size = len(q.waiting)
+		bots = q.opts.MinPlayers - size
+	default:
+		return nil, 0
+	}
+
+	group := make([]Ticket, size)
+	copy(group, q.waiting[:size])
+	q.waiting = q.waiting[size:]
+	return group, bots
+}
+
+// launch creates the room for a group and tells each ticket holder where to go
+func (q *Queue) launch(group []Ticket, bots int) {
+	names := make([]string, len(group))
+	for i, t := range group {
+		names[i] = t.PlayerName
+	}
+
+	rx, playerIDs, err := q.creator.CreateQuickRoom(names, bots)
+	if err != nil {
+		log.Printf("Matchmaking: failed to create room: %v", err)
+		for _, t := range group {
+			q.notifier.Broadcast(t.ID, "error", map[string]interface{}{
+				"message": "failed to create room",
+			})
+		}
+		return
+	}
+
+	log.Printf("Matchmaking: room %s created for %v with %d bot(s)", rx.Code, names, bots)
+	for i, t := range group {
+		// The room may rename duplicate names, so report the seat's actual name
+		name := names[i]
+		for _, p := range rx.Players {
+			if p.ID == playerIDs[i] {
+				name = p.Name
+			}
+		}
+		q.notifier.Broadcast(t.ID, "match_found", map[string]interface{}{
+			"room_code":   rx.Code,
+			"player_id":   playerIDs[i],
+			"seat_secret": q.creator.SeatSecret(rx, playerIDs[i]),
+			"player_name": name,
+			"turn_order":  rx.TurnOrder,
+			"players":     rx.PlayerView(),
+		})
+	}
+}
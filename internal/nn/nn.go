@@ -0,0 +1,118 @@
+// Package nn lets bots play with a trained policy/value network. Models are
+// run by a Runtime, which this package does not link: a binary that wants
+// neural bots imports a runtime package that calls Register from its init,
+// much like a database/sql driver. internal/nn/onnx runs ONNX models and is
+// linked by internal/app. Without a runtime, Open fails and bots keep the
+// heuristic.
+package nn
+
+import (
+	"errors"
+	"fmt"
+	"javanese-chess/internal/game"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Input is a position as the network sees it from the player to move:
+// the game.EncodeBoard planes and the hand as a count per card value, index
+// card-1, scaled by the hand size
+type Input struct {
+	Board [][][]float32
+	Hand  []float32
+}
+
+// Output is the network's verdict. Policy holds a logit per placement,
+// indexed [card-1][y][x]; Value is the expected outcome for the player to
+// move, from -1 (loss) to 1 (win).
+type Output struct {
+	Policy [][][]float32
+	Value  float32
+}
+
+// Model is a loaded network. Predict may be called from several goroutines.
+type Model interface {
+	Predict(in Input) (Output, error)
+	Close() error
+}
+
+// Runtime loads models of one format
+type Runtime interface {
+	Load(path string) (Model, error)
+}
+
+var (
+	mu       sync.RWMutex
+	runtimes = make(map[string]Runtime)
+)
+
+// Register makes a runtime available under name. It panics when the name is
+// taken, as registering twice is a programming error.
+func Register(name string, rt Runtime) {
+	mu.Lock()
+	defer mu.Unlock()
+	if rt == nil {
+		panic("nn: Register runtime is nil")
+	}
+	if _, dup := runtimes[name]; dup {
+		panic("nn: Register called twice for runtime " + name)
+	}
+	runtimes[name] = rt
+}
+
+// Runtimes lists the registered runtime names
+func Runtimes() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(runtimes))
+	for name := range runtimes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open loads the model at path with the named runtime
+func Open(runtime, path string) (Model, error) {
+	if path == "" {
+		return nil, errors.New("no model path configured (NN_MODEL)")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("model: %w", err)
+	}
+
+	mu.RLock()
+	rt, ok := runtimes[runtime]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("nn runtime %q is not linked into this binary", runtime)
+	}
+	return rt.Load(path)
+}
+
+// Encode builds the network input for playerID holding hand
+func Encode(b *game.Board, hand []int, playerID string) Input {
+	counts := make([]float32, b.TopCard())
+	for _, card := range hand {
+		if card >= 1 && card <= len(counts) {
+			counts[card-1]++
+		}
+	}
+	if len(hand) > 0 {
+		for i := range counts {
+			counts[i] /= float32(len(hand))
+		}
+	}
+	return Input{Board: game.EncodeBoard(b, playerID), Hand: counts}
+}
+
+// Logit returns the policy logit of a placement and whether the output
+// covers it
+func (o Output) Logit(mv game.Move) (float32, bool) {
+	c, y, x := mv.Card-1, mv.Y, mv.X
+	if c < 0 || c >= len(o.Policy) || y < 0 || y >= len(o.Policy[c]) || x < 0 || x >= len(o.Policy[c][y]) {
+		return 0, false
+	}
+	return o.Policy[c][y][x], true
+}
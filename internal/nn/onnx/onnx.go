@@ -0,0 +1,169 @@
+// Package onnx runs ONNX policy/value networks for neural bots in pure Go.
+// Importing it registers the "onnx" nn runtime. It runs the operators small
+// board networks are built from (Conv, Gemm, MatMul, elementwise arithmetic
+// and activations, Flatten, Reshape and Concat) on float32 tensors; a model
+// using any other operator fails to load.
+//
+// A model takes the board as its first input, shaped [1, planes, size,
+// size] as game.EncodeBoard lays it out, and optionally the hand as its
+// second, shaped [1, top card]. Its first output is the policy, one logit
+// per card value and cell in [card-1][y][x] order in any shape holding
+// exactly those values, e.g. [1, top card, size, size]. Its second output
+// is the value from -1 to 1; a model without one predicts 0.
+package onnx
+
+import (
+	"errors"
+	"fmt"
+	"javanese-chess/internal/nn"
+	"os"
+)
+
+func init() {
+	nn.Register("onnx", runtime{})
+}
+
+// runtime loads ONNX models
+type runtime struct{}
+
+func (runtime) Load(path string) (nn.Model, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("onnx model %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// model is a loaded graph. Predict only reads it, so it may run from several
+// goroutines.
+type model struct {
+	g *graph
+}
+
+// parse decodes a model and checks that it can run
+func parse(data []byte) (*model, error) {
+	g, err := decodeModel(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.inputs) < 1 || len(g.inputs) > 2 {
+		return nil, fmt.Errorf("want the board and optionally the hand as inputs, got %d inputs", len(g.inputs))
+	}
+	if len(g.outputs) < 1 {
+		return nil, errors.New("model has no outputs")
+	}
+
+	// Every value must be produced before it is used, as ONNX orders nodes
+	known := map[string]bool{"": true}
+	for name := range g.inits {
+		known[name] = true
+	}
+	for _, name := range g.inputs {
+		known[name] = true
+	}
+	for _, n := range g.nodes {
+		if _, ok := operators[n.op]; !ok {
+			return nil, fmt.Errorf("operator %s is not supported", n.op)
+		}
+		for _, in := range n.inputs {
+			if !known[in] {
+				return nil, fmt.Errorf("%s uses %s before it is computed", n.op, in)
+			}
+		}
+		for _, out := range n.outputs {
+			known[out] = true
+		}
+	}
+	for _, out := range g.outputs {
+		if !known[out] {
+			return nil, fmt.Errorf("output %s is never computed", out)
+		}
+	}
+	return &model{g: g}, nil
+}
+
+func (m *model) Predict(in nn.Input) (nn.Output, error) {
+	planes := len(in.Board)
+	if planes == 0 || len(in.Board[0]) == 0 {
+		return nn.Output{}, errors.New("empty board")
+	}
+	size := len(in.Board[0])
+
+	board := &tensor{shape: []int{1, planes, size, size}, data: make([]float32, 0, planes*size*size)}
+	for _, plane := range in.Board {
+		for _, row := range plane {
+			board.data = append(board.data, row...)
+		}
+	}
+	if len(board.data) != board.size() {
+		return nn.Output{}, errors.New("board planes are not square")
+	}
+
+	outs, err := m.run(board, &tensor{shape: []int{1, len(in.Hand)}, data: in.Hand})
+	if err != nil {
+		return nn.Output{}, err
+	}
+
+	cards := len(in.Hand)
+	policy := outs[0]
+	if len(policy.data) != cards*size*size {
+		return nn.Output{}, fmt.Errorf("policy has %d values, want %d cards x %d x %d", len(policy.data), cards, size, size)
+	}
+	out := nn.Output{Policy: make([][][]float32, cards)}
+	for c := range out.Policy {
+		out.Policy[c] = make([][]float32, size)
+		for y := range out.Policy[c] {
+			at := (c*size + y) * size
+			out.Policy[c][y] = policy.data[at : at+size]
+		}
+	}
+	if len(outs) > 1 && len(outs[1].data) > 0 {
+		out.Value = outs[1].data[0]
+	}
+	return out, nil
+}
+
+func (m *model) Close() error {
+	return nil
+}
+
+// run evaluates the graph on the board and hand and returns its outputs
+func (m *model) run(board, hand *tensor) ([]*tensor, error) {
+	values := make(map[string]*tensor, len(m.g.inits)+len(m.g.nodes)+2)
+	for name, t := range m.g.inits {
+		values[name] = t
+	}
+	values[m.g.inputs[0]] = board
+	if len(m.g.inputs) > 1 {
+		values[m.g.inputs[1]] = hand
+	}
+
+	for i := range m.g.nodes {
+		n := &m.g.nodes[i]
+		in := make([]*tensor, len(n.inputs))
+		for j, name := range n.inputs {
+			in[j] = values[name] // nil for an optional input left out
+		}
+		outs, err := operators[n.op](n, in)
+		if err != nil {
+			return nil, err
+		}
+		for j, name := range n.outputs {
+			if j < len(outs) {
+				values[name] = outs[j]
+			}
+		}
+	}
+
+	outs := make([]*tensor, len(m.g.outputs))
+	for i, name := range m.g.outputs {
+		if outs[i] = values[name]; outs[i] == nil {
+			return nil, fmt.Errorf("output %s was not computed", name)
+		}
+	}
+	return outs, nil
+}
@@ -0,0 +1,296 @@
+package onnx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/nn"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/tiny.onnx")
+
+// Protobuf encoding of the ONNX messages the tests build
+
+func bytesField(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func varintField(b []byte, num protowire.Number, v int64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+func packedInts(b []byte, num protowire.Number, v []int64) []byte {
+	var p []byte
+	for _, x := range v {
+		p = protowire.AppendVarint(p, uint64(x))
+	}
+	return bytesField(b, num, p)
+}
+
+func packedFloats(b []byte, num protowire.Number, v []float32) []byte {
+	var p []byte
+	for _, x := range v {
+		p = protowire.AppendFixed32(p, math.Float32bits(x))
+	}
+	return bytesField(b, num, p)
+}
+
+// floatTensor encodes a float TensorProto, in raw_data when raw is set and
+// in float_data otherwise
+func floatTensor(name string, shape []int64, data []float32, raw bool) []byte {
+	b := packedInts(nil, tensorDims, shape)
+	b = varintField(b, tensorDataType, dataFloat)
+	b = bytesField(b, tensorName, []byte(name))
+	if raw {
+		r := make([]byte, 4*len(data))
+		for i, x := range data {
+			binary.LittleEndian.PutUint32(r[4*i:], math.Float32bits(x))
+		}
+		return bytesField(b, tensorRawData, r)
+	}
+	return packedFloats(b, tensorFloatData, data)
+}
+
+func intTensor(name string, data []int64) []byte {
+	b := packedInts(nil, tensorDims, []int64{int64(len(data))})
+	b = varintField(b, tensorDataType, dataInt64)
+	b = bytesField(b, tensorName, []byte(name))
+	return packedInts(b, tensorInt64Data, data)
+}
+
+func intAttr(name string, v int64) []byte {
+	return varintField(bytesField(nil, attrName, []byte(name)), attrI, v)
+}
+
+func intsAttr(name string, v ...int64) []byte {
+	return packedInts(bytesField(nil, attrName, []byte(name)), attrInts, v)
+}
+
+func nodeProto(op string, in, out []string, attrs ...[]byte) []byte {
+	var b []byte
+	for _, name := range in {
+		b = bytesField(b, nodeInput, []byte(name))
+	}
+	for _, name := range out {
+		b = bytesField(b, nodeOutput, []byte(name))
+	}
+	b = bytesField(b, nodeOpType, []byte(op))
+	for _, a := range attrs {
+		b = bytesField(b, nodeAttribute, a)
+	}
+	return b
+}
+
+func modelProto(nodes, inits [][]byte, inputs, outputs []string) []byte {
+	var g []byte
+	for _, n := range nodes {
+		g = bytesField(g, graphNode, n)
+	}
+	for _, t := range inits {
+		g = bytesField(g, graphInitializer, t)
+	}
+	for _, name := range inputs {
+		g = bytesField(g, graphInput, bytesField(nil, valueInfoName, []byte(name)))
+	}
+	for _, name := range outputs {
+		g = bytesField(g, graphOutput, bytesField(nil, valueInfoName, []byte(name)))
+	}
+	m := varintField(nil, 1, 8) // ir_version
+	return bytesField(m, modelGraph, g)
+}
+
+// tinyWeights are the weights of the test model: a 1x1 convolution turning
+// the board planes into a logit per card, and a linear value of the hand
+func tinyWeights(cards int) (conv, bias, value []float32) {
+	for c := 0; c < cards; c++ {
+		conv = append(conv, 0.1*float32(c+1), -0.2, 0.05*float32(c), 0.5-0.05*float32(c))
+		bias = append(bias, 0.01*float32(c))
+		value = append(value, 0.1*float32(c+1)-0.5)
+	}
+	return conv, bias, value
+}
+
+// tinyModel encodes the test model for boards with game.TensorPlanes planes
+// and hands of cards values
+func tinyModel(cards int) []byte {
+	conv, bias, value := tinyWeights(cards)
+	return modelProto(
+		[][]byte{
+			nodeProto("Conv", []string{"board", "conv_w", "conv_b"}, []string{"logits"}, intsAttr("kernel_shape", 1, 1)),
+			nodeProto("Flatten", []string{"logits"}, []string{"policy"}),
+			nodeProto("Gemm", []string{"hand", "value_w", "value_b"}, []string{"value_pre"}),
+			nodeProto("Tanh", []string{"value_pre"}, []string{"value"}),
+		},
+		[][]byte{
+			floatTensor("conv_w", []int64{int64(cards), game.TensorPlanes, 1, 1}, conv, true),
+			floatTensor("conv_b", []int64{int64(cards)}, bias, false),
+			floatTensor("value_w", []int64{int64(cards), 1}, value, true),
+			floatTensor("value_b", []int64{1}, []float32{0.05}, false),
+		},
+		[]string{"board", "hand"},
+		[]string{"policy", "value"},
+	)
+}
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
+
+// The test model in testdata runs through nn.Open and matches the network
+// computed by hand
+func TestTinyModel(t *testing.T) {
+	b := game.NewBoard(9)
+	game.ApplyMove(&b, 4, 4, "bot", 5)
+	game.ApplyMove(&b, 5, 4, "opp", 7)
+	game.ApplyMove(&b, 5, 5, "bot", 9)
+	game.UpdateVState(&b)
+	hand := []int{2, 2, 8}
+	cards := b.TopCard()
+
+	path := filepath.Join("testdata", "tiny.onnx")
+	want := tinyModel(cards)
+	if *update {
+		if err := os.WriteFile(path, want, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, err := os.ReadFile(path); err != nil || !bytes.Equal(got, want) {
+		t.Fatalf("%s is missing or stale (%v); rerun with -update", path, err)
+	}
+
+	if !slices.Contains(nn.Runtimes(), "onnx") {
+		t.Fatalf("onnx runtime not registered, have %v", nn.Runtimes())
+	}
+	m, err := nn.Open("onnx", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	in := nn.Encode(&b, hand, "bot")
+	out, err := m.Predict(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conv, bias, value := tinyWeights(cards)
+	if len(out.Policy) != cards {
+		t.Fatalf("policy covers %d cards, want %d", len(out.Policy), cards)
+	}
+	for c := 0; c < cards; c++ {
+		for y := 0; y < b.Size; y++ {
+			for x := 0; x < b.Size; x++ {
+				want := bias[c]
+				for p := 0; p < game.TensorPlanes; p++ {
+					want += conv[c*game.TensorPlanes+p] * in.Board[p][y][x]
+				}
+				if got := out.Policy[c][y][x]; !near(got, want) {
+					t.Fatalf("policy[%d][%d][%d] = %v, want %v", c, y, x, got, want)
+				}
+			}
+		}
+	}
+
+	pre := float32(0.05)
+	for k, h := range in.Hand {
+		pre += h * value[k]
+	}
+	if want := float32(math.Tanh(float64(pre))); !near(out.Value, want) {
+		t.Errorf("value = %v, want %v", out.Value, want)
+	}
+}
+
+func run(t *testing.T, op string, attrs map[string]attr, in ...*tensor) *tensor {
+	t.Helper()
+	out, err := operators[op](&node{op: op, attrs: attrs}, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out[0]
+}
+
+func checkTensor(t *testing.T, got *tensor, shape []int, data []float32) {
+	t.Helper()
+	if !slices.Equal(got.shape, shape) {
+		t.Fatalf("shape = %v, want %v", got.shape, shape)
+	}
+	for i := range data {
+		if !near(got.data[i], data[i]) {
+			t.Fatalf("data = %v, want %v", got.data, data)
+		}
+	}
+}
+
+func TestConvPadding(t *testing.T) {
+	x := &tensor{shape: []int{1, 1, 3, 3}, data: []float32{1, 2, 3, 4, 5, 6, 7, 8, 9}}
+	w := &tensor{shape: []int{1, 1, 3, 3}, data: []float32{1, 1, 1, 1, 1, 1, 1, 1, 1}}
+	got := run(t, "Conv", map[string]attr{"pads": {ints: []int64{1, 1, 1, 1}}}, x, w)
+	checkTensor(t, got, []int{1, 1, 3, 3}, []float32{12, 21, 16, 27, 45, 33, 24, 39, 28})
+
+	got = run(t, "Conv", map[string]attr{"strides": {ints: []int64{2, 2}}, "pads": {ints: []int64{1, 1, 1, 1}}}, x, w,
+		&tensor{shape: []int{1}, data: []float32{-1}})
+	checkTensor(t, got, []int{1, 1, 2, 2}, []float32{11, 15, 23, 27})
+}
+
+func TestGemm(t *testing.T) {
+	a := &tensor{shape: []int{2, 3}, data: []float32{1, 2, 3, 4, 5, 6}}
+	bT := &tensor{shape: []int{2, 3}, data: []float32{1, 0, 1, 0, 1, 0}} // B transposed
+	c := &tensor{shape: []int{2}, data: []float32{10, 20}}
+	got := run(t, "Gemm", map[string]attr{"transB": {i: 1}, "alpha": {f: 2}, "beta": {f: 0.5}}, a, bT, c)
+	checkTensor(t, got, []int{2, 2}, []float32{13, 14, 25, 20})
+}
+
+func TestBroadcastAdd(t *testing.T) {
+	a := &tensor{shape: []int{2, 1, 3}, data: []float32{1, 2, 3, 4, 5, 6}}
+	b := &tensor{shape: []int{2, 1}, data: []float32{10, 20}}
+	got := run(t, "Add", nil, a, b)
+	checkTensor(t, got, []int{2, 2, 3}, []float32{11, 12, 13, 21, 22, 23, 14, 15, 16, 24, 25, 26})
+}
+
+func TestReshapeConcatMatMul(t *testing.T) {
+	x := &tensor{shape: []int{1, 2, 2}, data: []float32{1, 2, 3, 4}}
+	flat := run(t, "Reshape", nil, x, &tensor{shape: []int{2}, ints: []int64{0, -1}, data: []float32{0, -1}})
+	checkTensor(t, flat, []int{1, 4}, []float32{1, 2, 3, 4})
+
+	joined := run(t, "Concat", map[string]attr{"axis": {i: -1}}, flat, &tensor{shape: []int{1, 1}, data: []float32{5}})
+	checkTensor(t, joined, []int{1, 5}, []float32{1, 2, 3, 4, 5})
+
+	w := &tensor{shape: []int{5, 2}, data: []float32{1, 0, 1, 0, 1, 0, 1, 0, 1, 1}}
+	checkTensor(t, run(t, "MatMul", nil, joined, w), []int{1, 2}, []float32{15, 5})
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		model []byte
+		err   string
+	}{
+		{"not a model", []byte{0xff, 0xff}, ""},
+		{"unsupported operator", modelProto(
+			[][]byte{nodeProto("LSTM", []string{"board"}, []string{"policy"})}, nil,
+			[]string{"board"}, []string{"policy"}), "LSTM is not supported"},
+		{"value used before it is computed", modelProto(
+			[][]byte{nodeProto("Relu", []string{"later"}, []string{"policy"}), nodeProto("Relu", []string{"board"}, []string{"later"})}, nil,
+			[]string{"board"}, []string{"policy"}), "before it is computed"},
+		{"no inputs", modelProto(nil, [][]byte{intTensor("shape", []int64{1})}, nil, []string{"shape"}), "inputs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(tt.model)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("err = %v, want one mentioning %q", err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,421 @@
+package onnx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// tensor is a dense row-major array. Integer tensors, such as the shape of a
+// Reshape, also keep their exact values in ints.
+type tensor struct {
+	shape []int
+	data  []float32
+	ints  []int64
+}
+
+// size is the number of values the shape holds
+func (t *tensor) size() int {
+	n := 1
+	for _, d := range t.shape {
+		n *= d
+	}
+	return n
+}
+
+// operator computes a node's outputs from its inputs; a nil input is an
+// optional one left out
+type operator func(n *node, in []*tensor) ([]*tensor, error)
+
+// operators are the ONNX operators this runtime runs, enough for the
+// convolutional and fully connected policy/value networks bots use
+var operators = map[string]operator{
+	"Add":      elementwise(func(a, b float32) float32 { return a + b }),
+	"Sub":      elementwise(func(a, b float32) float32 { return a - b }),
+	"Mul":      elementwise(func(a, b float32) float32 { return a * b }),
+	"Relu":     unary(func(x float32) float32 { return max(x, 0) }),
+	"Tanh":     unary(func(x float32) float32 { return float32(math.Tanh(float64(x))) }),
+	"Sigmoid":  unary(func(x float32) float32 { return float32(1 / (1 + math.Exp(-float64(x)))) }),
+	"Identity": identity,
+	"Flatten":  flatten,
+	"Reshape":  reshape,
+	"Concat":   concat,
+	"MatMul":   matMul,
+	"Gemm":     gemm,
+	"Conv":     conv,
+}
+
+// need fails unless the first n inputs are present
+func need(op string, in []*tensor, n int) error {
+	if len(in) < n {
+		return fmt.Errorf("%s needs %d inputs, got %d", op, n, len(in))
+	}
+	for i := 0; i < n; i++ {
+		if in[i] == nil {
+			return fmt.Errorf("%s is missing input %d", op, i)
+		}
+	}
+	return nil
+}
+
+// axis resolves a possibly negative axis against a rank
+func axis(a int64, rank int) (int, error) {
+	if a < 0 {
+		a += int64(rank)
+	}
+	if a < 0 || a > int64(rank) {
+		return 0, fmt.Errorf("axis %d out of range for rank %d", a, rank)
+	}
+	return int(a), nil
+}
+
+func identity(n *node, in []*tensor) ([]*tensor, error) {
+	if err := need(n.op, in, 1); err != nil {
+		return nil, err
+	}
+	return []*tensor{in[0]}, nil
+}
+
+func unary(f func(float32) float32) operator {
+	return func(n *node, in []*tensor) ([]*tensor, error) {
+		if err := need(n.op, in, 1); err != nil {
+			return nil, err
+		}
+		out := &tensor{shape: in[0].shape, data: make([]float32, len(in[0].data))}
+		for i, v := range in[0].data {
+			out.data[i] = f(v)
+		}
+		return []*tensor{out}, nil
+	}
+}
+
+// elementwise applies f elementwise with numpy-style broadcasting
+func elementwise(f func(a, b float32) float32) operator {
+	return func(n *node, in []*tensor) ([]*tensor, error) {
+		if err := need(n.op, in, 2); err != nil {
+			return nil, err
+		}
+		a, b := in[0], in[1]
+
+		rank := max(len(a.shape), len(b.shape))
+		shape := make([]int, rank)
+		for i := range shape {
+			da, db := dimFromEnd(a.shape, rank-1-i), dimFromEnd(b.shape, rank-1-i)
+			switch {
+			case da == db || db == 1:
+				shape[i] = da
+			case da == 1:
+				shape[i] = db
+			default:
+				return nil, fmt.Errorf("%s cannot broadcast %v with %v", n.op, a.shape, b.shape)
+			}
+		}
+
+		out := &tensor{shape: shape}
+		out.data = make([]float32, out.size())
+		sa, sb := broadcastStrides(a.shape, shape), broadcastStrides(b.shape, shape)
+		idx := make([]int, rank)
+		for i := range out.data {
+			ia, ib, rem := 0, 0, i
+			for d := rank - 1; d >= 0; d-- {
+				idx[d] = rem % shape[d]
+				rem /= shape[d]
+				ia += idx[d] * sa[d]
+				ib += idx[d] * sb[d]
+			}
+			out.data[i] = f(a.data[ia], b.data[ib])
+		}
+		return []*tensor{out}, nil
+	}
+}
+
+// dimFromEnd returns the i-th dimension counted from the last, 1 past the rank
+func dimFromEnd(shape []int, i int) int {
+	if i >= len(shape) {
+		return 1
+	}
+	return shape[len(shape)-1-i]
+}
+
+// broadcastStrides returns the strides of shape aligned to the end of out,
+// 0 along dimensions it is broadcast over
+func broadcastStrides(shape, out []int) []int {
+	strides := make([]int, len(out))
+	step := 1
+	for i := len(out) - 1; i >= 0; i-- {
+		d := dimFromEnd(shape, len(out)-1-i)
+		if d != 1 {
+			strides[i] = step
+		}
+		step *= d
+	}
+	return strides
+}
+
+func flatten(n *node, in []*tensor) ([]*tensor, error) {
+	if err := need(n.op, in, 1); err != nil {
+		return nil, err
+	}
+	a := int64(1)
+	if at, ok := n.attrs["axis"]; ok {
+		a = at.i
+	}
+	ax, err := axis(a, len(in[0].shape))
+	if err != nil {
+		return nil, err
+	}
+	outer := 1
+	for _, d := range in[0].shape[:ax] {
+		outer *= d
+	}
+	return []*tensor{{shape: []int{outer, in[0].size() / max(outer, 1)}, data: in[0].data}}, nil
+}
+
+func reshape(n *node, in []*tensor) ([]*tensor, error) {
+	if err := need(n.op, in, 2); err != nil {
+		return nil, err
+	}
+	if in[1].ints == nil {
+		return nil, errors.New("Reshape needs an integer shape")
+	}
+
+	shape := make([]int, len(in[1].ints))
+	infer, known := -1, 1
+	for i, d := range in[1].ints {
+		switch {
+		case d == 0 && i < len(in[0].shape):
+			shape[i] = in[0].shape[i]
+		case d == -1 && infer < 0:
+			infer = i
+			continue
+		case d > 0:
+			shape[i] = int(d)
+		default:
+			return nil, fmt.Errorf("Reshape cannot use dimension %d", d)
+		}
+		known *= shape[i]
+	}
+	if infer >= 0 {
+		if known == 0 || in[0].size()%known != 0 {
+			return nil, fmt.Errorf("Reshape cannot fit %v into %v", in[0].shape, in[1].ints)
+		}
+		shape[infer] = in[0].size() / known
+	}
+	out := &tensor{shape: shape, data: in[0].data}
+	if out.size() != in[0].size() {
+		return nil, fmt.Errorf("Reshape cannot fit %v into %v", in[0].shape, shape)
+	}
+	return []*tensor{out}, nil
+}
+
+func concat(n *node, in []*tensor) ([]*tensor, error) {
+	if err := need(n.op, in, 1); err != nil {
+		return nil, err
+	}
+	first := in[0]
+	ax, err := axis(n.attrs["axis"].i, len(first.shape))
+	if err != nil || ax == len(first.shape) {
+		return nil, fmt.Errorf("Concat axis %d out of range for rank %d", n.attrs["axis"].i, len(first.shape))
+	}
+
+	shape := slices.Clone(first.shape)
+	shape[ax] = 0
+	for _, t := range in {
+		if t == nil || len(t.shape) != len(shape) {
+			return nil, errors.New("Concat inputs differ in rank")
+		}
+		for d := range shape {
+			if d != ax && t.shape[d] != first.shape[d] {
+				return nil, fmt.Errorf("Concat cannot join %v with %v", first.shape, t.shape)
+			}
+		}
+		shape[ax] += t.shape[ax]
+	}
+
+	// Each input contributes a block of its axis and inner dimensions per
+	// outer index
+	outer := 1
+	for _, d := range shape[:ax] {
+		outer *= d
+	}
+	out := &tensor{shape: shape, data: make([]float32, 0, (&tensor{shape: shape}).size())}
+	for o := 0; o < outer; o++ {
+		for _, t := range in {
+			block := t.size() / max(outer, 1)
+			out.data = append(out.data, t.data[o*block:(o+1)*block]...)
+		}
+	}
+	return []*tensor{out}, nil
+}
+
+// matMul multiplies a [..., M, K] by b [K, N]
+func matMul(n *node, in []*tensor) ([]*tensor, error) {
+	if err := need(n.op, in, 2); err != nil {
+		return nil, err
+	}
+	a, b := in[0], in[1]
+	if len(a.shape) < 2 || len(b.shape) != 2 || a.shape[len(a.shape)-1] != b.shape[0] {
+		return nil, fmt.Errorf("MatMul cannot multiply %v by %v", a.shape, b.shape)
+	}
+	k, cols := b.shape[0], b.shape[1]
+	rows := a.size() / k
+
+	shape := slices.Clone(a.shape)
+	shape[len(shape)-1] = cols
+	out := &tensor{shape: shape, data: make([]float32, rows*cols)}
+	for i := 0; i < rows; i++ {
+		for p := 0; p < k; p++ {
+			av := a.data[i*k+p]
+			if av == 0 {
+				continue
+			}
+			for j := 0; j < cols; j++ {
+				out.data[i*cols+j] += av * b.data[p*cols+j]
+			}
+		}
+	}
+	return []*tensor{out}, nil
+}
+
+// gemm computes alpha*A'*B' + beta*C for 2-D A and B, optionally transposed
+func gemm(n *node, in []*tensor) ([]*tensor, error) {
+	if err := need(n.op, in, 2); err != nil {
+		return nil, err
+	}
+	a, b := in[0], in[1]
+	if len(a.shape) != 2 || len(b.shape) != 2 {
+		return nil, fmt.Errorf("Gemm needs 2-D inputs, got %v and %v", a.shape, b.shape)
+	}
+	alpha, beta := float32(1), float32(1)
+	if at, ok := n.attrs["alpha"]; ok {
+		alpha = at.f
+	}
+	if at, ok := n.attrs["beta"]; ok {
+		beta = at.f
+	}
+	transA, transB := n.attrs["transA"].i != 0, n.attrs["transB"].i != 0
+
+	rows, k := a.shape[0], a.shape[1]
+	if transA {
+		rows, k = k, rows
+	}
+	kb, cols := b.shape[0], b.shape[1]
+	if transB {
+		kb, cols = cols, kb
+	}
+	if k != kb {
+		return nil, fmt.Errorf("Gemm cannot multiply %v by %v", a.shape, b.shape)
+	}
+
+	at := func(i, p int) float32 {
+		if transA {
+			return a.data[p*rows+i]
+		}
+		return a.data[i*k+p]
+	}
+	bt := func(p, j int) float32 {
+		if transB {
+			return b.data[j*k+p]
+		}
+		return b.data[p*cols+j]
+	}
+
+	out := &tensor{shape: []int{rows, cols}, data: make([]float32, rows*cols)}
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			var sum float32
+			for p := 0; p < k; p++ {
+				sum += at(i, p) * bt(p, j)
+			}
+			out.data[i*cols+j] = alpha * sum
+		}
+	}
+
+	if len(in) > 2 && in[2] != nil {
+		scaled, err := elementwise(func(x, c float32) float32 { return x + beta*c })(n, []*tensor{out, in[2]})
+		if err != nil {
+			return nil, err
+		}
+		out = scaled[0]
+	}
+	return []*tensor{out}, nil
+}
+
+// conv is a 2-D convolution of X [N, C, H, W] with W [M, C, kH, kW] plus an
+// optional bias [M]. Groups other than 1 are not supported.
+func conv(n *node, in []*tensor) ([]*tensor, error) {
+	if err := need(n.op, in, 2); err != nil {
+		return nil, err
+	}
+	x, w := in[0], in[1]
+	if len(x.shape) != 4 || len(w.shape) != 4 || x.shape[1] != w.shape[1] {
+		return nil, fmt.Errorf("Conv cannot convolve %v with %v", x.shape, w.shape)
+	}
+	if g, ok := n.attrs["group"]; ok && g.i != 1 {
+		return nil, fmt.Errorf("Conv group %d is not supported", g.i)
+	}
+	if p, ok := n.attrs["auto_pad"]; ok && p.s != "NOTSET" && p.s != "VALID" {
+		return nil, fmt.Errorf("Conv auto_pad %s is not supported", p.s)
+	}
+
+	pads := []int64{0, 0, 0, 0} // Top, left, bottom, right
+	if p, ok := n.attrs["pads"]; ok && len(p.ints) == 4 {
+		pads = p.ints
+	}
+	strides := []int64{1, 1}
+	if s, ok := n.attrs["strides"]; ok && len(s.ints) == 2 {
+		strides = s.ints
+	}
+	dil := []int64{1, 1}
+	if d, ok := n.attrs["dilations"]; ok && len(d.ints) == 2 {
+		dil = d.ints
+	}
+
+	batch, chans, h, wd := x.shape[0], x.shape[1], x.shape[2], x.shape[3]
+	filters, kh, kw := w.shape[0], w.shape[2], w.shape[3]
+	oh := (h+int(pads[0]+pads[2])-int(dil[0])*(kh-1)-1)/int(strides[0]) + 1
+	ow := (wd+int(pads[1]+pads[3])-int(dil[1])*(kw-1)-1)/int(strides[1]) + 1
+	if oh <= 0 || ow <= 0 {
+		return nil, fmt.Errorf("Conv kernel %v does not fit input %v", w.shape, x.shape)
+	}
+
+	var bias []float32
+	if len(in) > 2 && in[2] != nil {
+		if in[2].size() != filters {
+			return nil, fmt.Errorf("Conv bias %v does not match %d filters", in[2].shape, filters)
+		}
+		bias = in[2].data
+	}
+
+	out := &tensor{shape: []int{batch, filters, oh, ow}, data: make([]float32, batch*filters*oh*ow)}
+	for b := 0; b < batch; b++ {
+		for m := 0; m < filters; m++ {
+			for oy := 0; oy < oh; oy++ {
+				for ox := 0; ox < ow; ox++ {
+					var sum float32
+					if bias != nil {
+						sum = bias[m]
+					}
+					for c := 0; c < chans; c++ {
+						for ky := 0; ky < kh; ky++ {
+							iy := oy*int(strides[0]) - int(pads[0]) + ky*int(dil[0])
+							if iy < 0 || iy >= h {
+								continue
+							}
+							for kx := 0; kx < kw; kx++ {
+								ix := ox*int(strides[1]) - int(pads[1]) + kx*int(dil[1])
+								if ix < 0 || ix >= wd {
+									continue
+								}
+								sum += x.data[((b*chans+c)*h+iy)*wd+ix] * w.data[((m*chans+c)*kh+ky)*kw+kx]
+							}
+						}
+					}
+					out.data[((b*filters+m)*oh+oy)*ow+ox] = sum
+				}
+			}
+		}
+	}
+	return []*tensor{out}, nil
+}
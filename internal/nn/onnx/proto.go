@@ -0,0 +1,375 @@
+package onnx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the onnx.proto messages this package reads
+const (
+	modelGraph = 7
+
+	graphNode        = 1
+	graphInitializer = 5
+	graphInput       = 11
+	graphOutput      = 12
+
+	nodeInput     = 1
+	nodeOutput    = 2
+	nodeOpType    = 4
+	nodeAttribute = 5
+	nodeDomain    = 7
+
+	attrName   = 1
+	attrF      = 2
+	attrI      = 3
+	attrS      = 4
+	attrT      = 5
+	attrFloats = 7
+	attrInts   = 8
+
+	tensorDims      = 1
+	tensorDataType  = 2
+	tensorFloatData = 4
+	tensorInt32Data = 5
+	tensorInt64Data = 7
+	tensorName      = 8
+	tensorRawData   = 9
+
+	valueInfoName = 1
+)
+
+// TensorProto data types this package reads
+const (
+	dataFloat = 1
+	dataInt32 = 6
+	dataInt64 = 7
+)
+
+// graph is a decoded GraphProto
+type graph struct {
+	nodes   []node
+	inits   map[string]*tensor
+	inputs  []string // Graph inputs that are not initializers, in order
+	outputs []string
+}
+
+// node is a decoded NodeProto
+type node struct {
+	op      string
+	inputs  []string // "" marks an optional input left out
+	outputs []string
+	attrs   map[string]attr
+}
+
+// attr is a decoded AttributeProto; only the field its type uses is set
+type attr struct {
+	f      float32
+	i      int64
+	s      string
+	t      *tensor
+	floats []float32
+	ints   []int64
+}
+
+// field is one field of an encoded message: v for length-delimited fields,
+// x for the others
+type field struct {
+	num protowire.Number
+	typ protowire.Type
+	v   []byte
+	x   uint64
+}
+
+// walk calls fn for each field of an encoded message, in order
+func walk(b []byte, fn func(f field) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		f := field{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			f.x, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			var u uint32
+			u, n = protowire.ConsumeFixed32(b)
+			f.x = uint64(u)
+		case protowire.Fixed64Type:
+			f.x, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			f.v, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// floats appends the values of a repeated float field, packed or not
+func (f field) floats(out []float32) ([]float32, error) {
+	switch f.typ {
+	case protowire.Fixed32Type:
+		return append(out, math.Float32frombits(uint32(f.x))), nil
+	case protowire.BytesType:
+		if len(f.v)%4 != 0 {
+			return nil, errors.New("packed floats are not a multiple of 4 bytes")
+		}
+		for i := 0; i < len(f.v); i += 4 {
+			out = append(out, math.Float32frombits(binary.LittleEndian.Uint32(f.v[i:])))
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("field %d is not a float", f.num)
+}
+
+// ints appends the values of a repeated varint field, packed or not
+func (f field) ints(out []int64) ([]int64, error) {
+	switch f.typ {
+	case protowire.VarintType:
+		return append(out, int64(f.x)), nil
+	case protowire.BytesType:
+		for b := f.v; len(b) > 0; {
+			x, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			out = append(out, int64(x))
+			b = b[n:]
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("field %d is not an integer", f.num)
+}
+
+// decodeModel reads the graph of an encoded ModelProto
+func decodeModel(b []byte) (*graph, error) {
+	var g *graph
+	err := walk(b, func(f field) error {
+		if f.num != modelGraph || f.typ != protowire.BytesType {
+			return nil
+		}
+		var err error
+		g, err = decodeGraph(f.v)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, errors.New("model has no graph")
+	}
+	return g, nil
+}
+
+func decodeGraph(b []byte) (*graph, error) {
+	g := &graph{inits: map[string]*tensor{}}
+	var inputs []string
+	err := walk(b, func(f field) error {
+		if f.typ != protowire.BytesType {
+			return nil
+		}
+		switch f.num {
+		case graphNode:
+			n, err := decodeNode(f.v)
+			if err != nil {
+				return err
+			}
+			g.nodes = append(g.nodes, n)
+		case graphInitializer:
+			name, t, err := decodeTensor(f.v)
+			if err != nil {
+				return fmt.Errorf("initializer %s: %w", name, err)
+			}
+			g.inits[name] = t
+		case graphInput, graphOutput:
+			name, err := decodeValueInfo(f.v)
+			if err != nil {
+				return err
+			}
+			if f.num == graphInput {
+				inputs = append(inputs, name)
+			} else {
+				g.outputs = append(g.outputs, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Older models also list their initializers as inputs
+	for _, name := range inputs {
+		if _, ok := g.inits[name]; !ok {
+			g.inputs = append(g.inputs, name)
+		}
+	}
+	return g, nil
+}
+
+func decodeValueInfo(b []byte) (string, error) {
+	var name string
+	err := walk(b, func(f field) error {
+		if f.num == valueInfoName && f.typ == protowire.BytesType {
+			name = string(f.v)
+		}
+		return nil
+	})
+	return name, err
+}
+
+func decodeNode(b []byte) (node, error) {
+	n := node{attrs: map[string]attr{}}
+	var domain string
+	err := walk(b, func(f field) error {
+		if f.typ != protowire.BytesType {
+			return nil
+		}
+		switch f.num {
+		case nodeInput:
+			n.inputs = append(n.inputs, string(f.v))
+		case nodeOutput:
+			n.outputs = append(n.outputs, string(f.v))
+		case nodeOpType:
+			n.op = string(f.v)
+		case nodeDomain:
+			domain = string(f.v)
+		case nodeAttribute:
+			name, a, err := decodeAttr(f.v)
+			if err != nil {
+				return fmt.Errorf("attribute %s: %w", name, err)
+			}
+			n.attrs[name] = a
+		}
+		return nil
+	})
+	if err != nil {
+		return node{}, err
+	}
+	if domain != "" && domain != "ai.onnx" {
+		return node{}, fmt.Errorf("operator %s of domain %s is not supported", n.op, domain)
+	}
+	return n, nil
+}
+
+func decodeAttr(b []byte) (string, attr, error) {
+	var name string
+	var a attr
+	err := walk(b, func(f field) error {
+		var err error
+		switch f.num {
+		case attrName:
+			name = string(f.v)
+		case attrF:
+			a.f = math.Float32frombits(uint32(f.x))
+		case attrI:
+			a.i = int64(f.x)
+		case attrS:
+			a.s = string(f.v)
+		case attrT:
+			_, a.t, err = decodeTensor(f.v)
+		case attrFloats:
+			a.floats, err = f.floats(a.floats)
+		case attrInts:
+			a.ints, err = f.ints(a.ints)
+		}
+		return err
+	})
+	return name, a, err
+}
+
+// decodeTensor reads a TensorProto holding floats or integers. Integers are
+// kept in ints and converted to data, so either may be used.
+func decodeTensor(b []byte) (string, *tensor, error) {
+	var name string
+	var dims []int64
+	var dataType int64
+	var raw []byte
+	var floats []float32
+	var ints []int64
+	err := walk(b, func(f field) error {
+		var err error
+		switch f.num {
+		case tensorDims:
+			dims, err = f.ints(dims)
+		case tensorDataType:
+			dataType = int64(f.x)
+		case tensorFloatData:
+			floats, err = f.floats(floats)
+		case tensorInt32Data, tensorInt64Data:
+			ints, err = f.ints(ints)
+		case tensorName:
+			name = string(f.v)
+		case tensorRawData:
+			raw = f.v
+		}
+		return err
+	})
+	if err != nil {
+		return name, nil, err
+	}
+
+	shape := make([]int, len(dims))
+	for i, d := range dims {
+		if d < 0 {
+			return name, nil, fmt.Errorf("negative dimension %d", d)
+		}
+		shape[i] = int(d)
+	}
+	t := &tensor{shape: shape}
+	switch dataType {
+	case dataFloat:
+		if raw != nil {
+			if len(raw)%4 != 0 {
+				return name, nil, errors.New("raw float data is not a multiple of 4 bytes")
+			}
+			for i := 0; i < len(raw); i += 4 {
+				floats = append(floats, math.Float32frombits(binary.LittleEndian.Uint32(raw[i:])))
+			}
+		}
+		t.data = floats
+	case dataInt32, dataInt64:
+		if raw != nil {
+			size := 8
+			if dataType == dataInt32 {
+				size = 4
+			}
+			if len(raw)%size != 0 {
+				return name, nil, errors.New("raw integer data does not fill whole values")
+			}
+			for i := 0; i < len(raw); i += size {
+				if size == 4 {
+					ints = append(ints, int64(int32(binary.LittleEndian.Uint32(raw[i:]))))
+				} else {
+					ints = append(ints, int64(binary.LittleEndian.Uint64(raw[i:])))
+				}
+			}
+		}
+		t.ints = ints
+		t.data = make([]float32, len(ints))
+		for i, v := range ints {
+			t.data[i] = float32(v)
+		}
+	default:
+		return name, nil, fmt.Errorf("data type %d is not supported", dataType)
+	}
+	if len(t.data) != t.size() {
+		return name, nil, fmt.Errorf("%d values for shape %v", len(t.data), t.shape)
+	}
+	return name, t, nil
+}
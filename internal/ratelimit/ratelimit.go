@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"javanese-chess/internal/apierr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idleAfter is how long an untouched bucket is kept before it is dropped
+const idleAfter = 10 * time.Minute
+
+// Limiter is a set of token buckets, one per key (client IP, player ID...).
+// Each bucket refills at Rate tokens per second up to Burst tokens.
+// A nil Limiter allows everything.
+type Limiter struct {
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New returns a limiter, or nil (no limit) when rate is not positive
+func New(rate float64, burst int) *Limiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rate:      rate,
+		burst:     float64(burst),
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow takes a token from the key's bucket and reports whether one was available
+func (l *Limiter) Allow(key string) bool {
+	ok, _ := l.reserve(key, time.Now())
+	return ok
+}
+
+// reserve takes a token at time now. When the bucket is empty it returns the
+// wait until the next token is available.
+func (l *Limiter) reserve(key string, now time.Time) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops buckets that have been idle for a while so the map does not
+// grow with every client ever seen
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleAfter {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > idleAfter {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Middleware rate limits requests per client IP, answering 429 with a
+// Retry-After header once the client's bucket is empty
+func Middleware(l *Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, wait := l.reserve(c.ClientIP(), time.Now())
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			apierr.Abort(c, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package rating
+
+import "math"
+
+const (
+	// DefaultRating is assigned to players on their first rated game
+	DefaultRating = 1200.0
+	// KFactor controls how far a single game moves a rating
+	KFactor = 32.0
+)
+
+// Expected returns the expected score of a player rated a against one rated b
+func Expected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// Update applies a multi-player game result as pairwise ELO matches.
+// winnerID is empty for a draw. Each pair's change is divided by the number
+// of opponents so a four-player game moves ratings as much as a duel.
+func Update(ratings map[string]float64, winnerID string) map[string]float64 {
+	out := make(map[string]float64, len(ratings))
+	for id, r := range ratings {
+		out[id] = r
+	}
+	if len(ratings) < 2 {
+		return out
+	}
+
+	k := KFactor / float64(len(ratings)-1)
+	for a, ra := range ratings {
+		for b, rb := range ratings {
+			if a == b {
+				continue
+			}
+
+			score := 0.5
+			switch winnerID {
+			case a:
+				score = 1
+			case b:
+				score = 0
+			case "":
+				score = 0.5
+			default:
+				// Both lost to a third player: treat as a draw between them
+				score = 0.5
+			}
+			out[a] += k * (score - Expected(ra, rb))
+		}
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+package record
+
+import (
+	"fmt"
+	"javanese-chess/internal/game"
+)
+
+// StateDiff describes what changed between two move numbers of a game
+type StateDiff struct {
+	From         int               `json:"from"`
+	To           int               `json:"to"`
+	FromHash     game.PositionHash `json:"from_hash"` // Position hashes of both points, for deduplicating positions
+	ToHash       game.PositionHash `json:"to_hash"`
+	Cells        []CellChange      `json:"cells"`
+	Captures     []Capture         `json:"captures"`
+	HandSizes    map[string]int    `json:"hand_size_changes"` // Player ID -> change in hand size
+	MovesBetween int               `json:"moves_between"`
+}
+
+// CellChange is a board cell whose content differs between the two points
+type CellChange struct {
+	X      int       `json:"x"`
+	Y      int       `json:"y"`
+	Cell   string    `json:"cell"`
+	Before game.Cell `json:"before"`
+	After  game.Cell `json:"after"`
+}
+
+// Capture is a move that overwrote an opponent's card
+type Capture struct {
+	MoveNo     int    `json:"move_no"` // 1-based move number
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+	Cell       string `json:"cell"`
+	PlayerID   string `json:"player_id"`
+	Card       int    `json:"card"`
+	CapturedID string `json:"captured_id"`
+	Captured   int    `json:"captured_card"`
+}
+
+// Diff compares the game state after `from` moves with the state after `to`
+// moves. Move numbers count every recorded move (0 is the empty board) and
+// from may be greater than to to step backwards.
+func Diff(rec *GameRecord, from, to int) (*StateDiff, error) {
+	n := len(rec.Moves)
+	if from < 0 || from > n || to < 0 || to > n {
+		return nil, fmt.Errorf("move numbers must be between 0 and %d", n)
+	}
+
+	lo, hi, sign := from, to, 1
+	if from > to {
+		lo, hi, sign = to, from, -1
+	}
+
+	before, after := Replay(rec, from), Replay(rec, to)
+	diff := &StateDiff{
+		From:         from,
+		To:           to,
+		FromHash:     before.Hash,
+		ToHash:       after.Hash,
+		Cells:        []CellChange{},
+		Captures:     []Capture{},
+		HandSizes:    map[string]int{},
+		MovesBetween: hi - lo,
+	}
+
+	for y := 0; y < after.Size; y++ {
+		for x := 0; x < after.Size; x++ {
+			b, a := before.At(game.Coord{X: x, Y: y}), after.At(game.Coord{X: x, Y: y})
+			if b.Value == a.Value && b.OwnerID == a.OwnerID {
+				continue
+			}
+			diff.Cells = append(diff.Cells, CellChange{
+				X:      x,
+				Y:      y,
+				Cell:   game.Coord{X: x, Y: y}.Algebraic(),
+				Before: b,
+				After:  a,
+			})
+		}
+	}
+
+	for i, mv := range rec.Moves[lo:hi] {
+		if mv.Type.Normalize() != game.MovePlace {
+			continue
+		}
+
+		// Playing a card shrinks the hand unless a replacement was drawn
+		if mv.DrawnCard == 0 {
+			diff.HandSizes[mv.PlayerID] -= sign
+		}
+
+		if mv.PrevCell.OwnerID != "" && mv.PrevCell.OwnerID != mv.PlayerID {
+			diff.Captures = append(diff.Captures, Capture{
+				MoveNo:     lo + i + 1,
+				X:          mv.X,
+				Y:          mv.Y,
+				Cell:       game.Coord{X: mv.X, Y: mv.Y}.Algebraic(),
+				PlayerID:   mv.PlayerID,
+				Card:       mv.Card,
+				CapturedID: mv.PrevCell.OwnerID,
+				Captured:   mv.PrevCell.Value,
+			})
+		}
+	}
+
+	return diff, nil
+}
@@ -0,0 +1,331 @@
+package record
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+	"slices"
+	"time"
+)
+
+// Room event kinds. Every change to a room is appended to its event log: the
+// room is created, players join, a game starts, moves (placements, skips,
+// swaps and resignations) are played or taken back, turns time out and the
+// game ends. Create, start and checkpoint events carry the whole room, so
+// folding can begin at the latest of them. Checkpoints capture changes that
+// are not moves, such as a re-deal or a seat changing hands.
+const (
+	EventCreate     = "create"
+	EventJoin       = "join"
+	EventStart      = "start"
+	EventCheckpoint = "checkpoint"
+	EventMove       = "move"
+	EventTimeout    = "timeout"
+	EventUndo       = "undo"
+	EventResult     = "result"
+)
+
+// Event is one entry of a room's event log
+type Event struct {
+	Kind string    `json:"kind"`
+	At   time.Time `json:"at"`
+
+	// State is the room snapshot of create, start and checkpoint events
+	State json.RawMessage `json:"state,omitempty"`
+
+	// Join: the seated player, their deck and the turn order after seating
+	Player    *shared.Player `json:"player,omitempty"`
+	Deck      []int          `json:"deck,omitempty"`
+	TurnOrder []string       `json:"turn_order,omitempty"`
+
+	Move    *game.MoveRecord `json:"move,omitempty"` // Move, and the skip a timeout forced
+	Keep    int              `json:"keep,omitempty"` // Undo: moves left in the history
+	Outcome *Outcome         `json:"outcome,omitempty"`
+}
+
+// StateEvent captures the whole room in a create, start or checkpoint event
+func StateEvent(kind string, r *shared.Room) (Event, error) {
+	data, err := json.Marshal(TakeSnapshot(r))
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{Kind: kind, At: time.Now(), State: data}, nil
+}
+
+// JoinEvent records a player taking a seat in the lobby
+func JoinEvent(r *shared.Room, p shared.Player) Event {
+	p.Hand = slices.Clone(p.Hand)
+	return Event{
+		Kind:      EventJoin,
+		At:        time.Now(),
+		Player:    &p,
+		Deck:      slices.Clone(p.Deck),
+		TurnOrder: slices.Clone(r.TurnOrder),
+	}
+}
+
+// Outcome is how a game ended, as recorded in a result event
+type Outcome struct {
+	Status    string              `json:"status"`
+	WinnerID  *string             `json:"winner_id"`
+	Draw      bool                `json:"draw"`
+	WinLine   []game.WinCell      `json:"win_line,omitempty"`
+	AbortedBy *string             `json:"aborted_by,omitempty"`
+	Metrics   *shared.GameMetrics `json:"metrics,omitempty"`
+}
+
+// OutcomeOf captures the result fields of a room
+func OutcomeOf(r *shared.Room) *Outcome {
+	return &Outcome{
+		Status:    r.Status,
+		WinnerID:  r.WinnerID,
+		Draw:      r.Draw,
+		WinLine:   r.WinLine,
+		AbortedBy: r.AbortedBy,
+		Metrics:   r.Metrics,
+	}
+}
+
+// Validate checks that a stored room snapshot is internally consistent: the
+// board must match a replay of the move history and every seat must hold
+// real cards
+func Validate(r *shared.Room) error {
+	if r == nil {
+		return errors.New("snapshot is empty")
+	}
+	if r.Board.Size <= 0 || len(r.Board.Cells) != r.Board.Size {
+		return errors.New("board does not match its size")
+	}
+	for _, row := range r.Board.Cells {
+		if len(row) != r.Board.Size {
+			return errors.New("board does not match its size")
+		}
+	}
+	if len(r.Players) == 0 {
+		return errors.New("room has no players")
+	}
+	if r.TurnIdx < 0 || r.TurnIdx >= len(r.Players) {
+		return fmt.Errorf("turn index %d out of range", r.TurnIdx)
+	}
+	for _, p := range r.Players {
+		for _, card := range append(append([]int{}, p.Hand...), p.Deck...) {
+			if card < 1 || card > r.Board.TopCard() {
+				return fmt.Errorf("player %s holds invalid card %d", p.ID, card)
+			}
+		}
+	}
+
+	rec := GameRecord{BoardSize: r.Board.Size, Engine: r.Engine, Moves: r.History, FinalBoard: r.Board}
+	if !sameCells(Replay(&rec, len(r.History)), r.Board) {
+		return errors.New("board does not match the move history")
+	}
+	return nil
+}
+
+// Fold rebuilds a room from its event log. Folding starts at the last event
+// that carries the whole room and applies the events after it in order.
+func Fold(events []Event) (*shared.Room, error) {
+	start := -1
+	for i, ev := range events {
+		if ev.State != nil {
+			start = i
+		}
+	}
+	if start < 0 {
+		return nil, errors.New("log has no create or start event")
+	}
+
+	r, err := Restore(events[start].State)
+	if err != nil {
+		return nil, fmt.Errorf("%s event: %w", events[start].Kind, err)
+	}
+	return Rebuild(r, events[start+1:])
+}
+
+// Rebuild replays the events recorded after a checkpoint through the engine
+// and returns the room's current state. Every move is checked for legality
+// and against the hands and decks, so a stream that does not belong to the
+// checkpoint is rejected.
+func Rebuild(start *shared.Room, events []Event) (*shared.Room, error) {
+	if err := Validate(start); err != nil {
+		return nil, fmt.Errorf("checkpoint: %w", err)
+	}
+
+	// Undos only remove moves, so work out the surviving move list first
+	base := len(start.History)
+	moves := append([]game.MoveRecord{}, start.History...)
+	var outcome *Outcome
+	for i, ev := range events {
+		switch ev.Kind {
+		case EventJoin:
+			if err := seat(start, ev); err != nil {
+				return nil, fmt.Errorf("event %d: %w", i, err)
+			}
+		case EventMove, EventTimeout:
+			if ev.Move == nil {
+				return nil, fmt.Errorf("event %d: move is missing", i)
+			}
+			moves = append(moves, *ev.Move)
+		case EventUndo:
+			if ev.Keep < base || ev.Keep > len(moves) {
+				return nil, fmt.Errorf("event %d: undo to %d moves is out of range", i, ev.Keep)
+			}
+			moves = moves[:ev.Keep]
+			outcome = nil
+		case EventResult:
+			outcome = ev.Outcome
+		default:
+			return nil, fmt.Errorf("event %d: unexpected kind %q", i, ev.Kind)
+		}
+	}
+	r := start
+	for i, mv := range moves[base:] {
+		if err := applyRecord(r, mv); err != nil {
+			return nil, fmt.Errorf("move %d: %w", base+i+1, err)
+		}
+		r.History = append(r.History, mv)
+	}
+	if len(moves) > base {
+		r.TurnIdx = nextTurn(r, moves[len(moves)-1])
+	}
+
+	if outcome != nil {
+		r.Status = outcome.Status
+		r.WinnerID = outcome.WinnerID
+		r.Draw = outcome.Draw
+		r.WinLine = outcome.WinLine
+		r.AbortedBy = outcome.AbortedBy
+		r.Metrics = outcome.Metrics
+	}
+	r.PendingUndo = nil
+	r.TurnStartedAt = time.Now()
+	return r, nil
+}
+
+// seat adds a joining player to a lobby and puts the players in the turn
+// order recorded with the join, if any
+func seat(r *shared.Room, ev Event) error {
+	if r.Status != "lobby" {
+		return errors.New("join after the game started")
+	}
+	if ev.Player == nil {
+		return errors.New("player is missing")
+	}
+
+	p := *ev.Player
+	p.Deck = ev.Deck
+	players := append(r.Players, p)
+	if len(ev.TurnOrder) == 0 {
+		r.Players = players
+		return nil
+	}
+	if len(ev.TurnOrder) != len(players) {
+		return fmt.Errorf("turn order lists %d of %d players", len(ev.TurnOrder), len(players))
+	}
+
+	seated := make([]shared.Player, 0, len(players))
+	for _, id := range ev.TurnOrder {
+		for _, pl := range players {
+			if pl.ID == id {
+				seated = append(seated, pl)
+			}
+		}
+	}
+	if len(seated) != len(players) {
+		return errors.New("turn order does not match the players")
+	}
+	r.Players = seated
+	r.TurnOrder = ev.TurnOrder
+	return nil
+}
+
+// applyRecord plays a recorded move forward: the inverse of the undo path
+func applyRecord(r *shared.Room, rec game.MoveRecord) error {
+	var p *shared.Player
+	for i := range r.Players {
+		if r.Players[i].ID == rec.PlayerID {
+			p = &r.Players[i]
+		}
+	}
+	if p == nil {
+		return fmt.Errorf("unknown player %s", rec.PlayerID)
+	}
+	r.TurnIdx = rec.TurnIdx
+
+	switch rec.Type.Normalize() {
+	case game.MoveSkip:
+		return nil
+	case game.MoveResign:
+		p.Resigned = true
+		return nil
+	}
+
+	if rec.HandIdx < 0 || rec.HandIdx >= len(p.Hand) || p.Hand[rec.HandIdx] != rec.Card {
+		return fmt.Errorf("card %d is not in %s's hand", rec.Card, p.ID)
+	}
+
+	deck := &p.Deck
+	if rec.FromPile {
+		deck = &r.CommunalPile
+	}
+
+	if rec.Type.Normalize() == game.MovePlace {
+		mv := game.Move{X: rec.X, Y: rec.Y, Card: rec.Card, PlayerID: rec.PlayerID}
+		if err := game.ValidateMove(&r.Board, mv, r.Board.Placement()); err != nil {
+			return fmt.Errorf("illegal placement of %d at (%d,%d): %w", rec.Card, rec.X, rec.Y, err)
+		}
+		game.ApplyMove(&r.Board, rec.X, rec.Y, rec.PlayerID, rec.Card)
+		game.UpdateVState(&r.Board)
+		p.TallyCapture(rec, 1)
+		p.TallyStats(rec, 1)
+		if rec.Hash != 0 && rec.Hash != r.Board.Hash {
+			return fmt.Errorf("position hash %s does not match the recorded %s", r.Board.Hash, rec.Hash)
+		}
+	}
+
+	p.Hand = append(append([]int{}, p.Hand[:rec.HandIdx]...), p.Hand[rec.HandIdx+1:]...)
+	if rec.Type == game.MoveSwap {
+		*deck = append(*deck, rec.Card)
+	}
+	if rec.DrawnCard != 0 {
+		if len(*deck) == 0 || (*deck)[0] != rec.DrawnCard {
+			return fmt.Errorf("drawn card %d is not on top of the deck", rec.DrawnCard)
+		}
+		*deck = (*deck)[1:]
+		p.Hand = append(p.Hand, rec.DrawnCard)
+	}
+	return nil
+}
+
+// nextTurn returns whose turn it is after rec. The turn does not move on
+// after a winning placement, a resignation that ends the game, or a
+// resignation out of turn.
+func nextTurn(r *shared.Room, rec game.MoveRecord) int {
+	n := len(r.Players)
+	switch rec.Type.Normalize() {
+	case game.MovePlace:
+		if game.IsWinningAfter(r.Board, rec.X, rec.Y, rec.PlayerID, rec.Card) {
+			return rec.TurnIdx
+		}
+	case game.MoveResign:
+		active := 0
+		for _, p := range r.Players {
+			if !p.Resigned {
+				active++
+			}
+		}
+		if active <= 1 || r.Players[rec.TurnIdx].ID != rec.PlayerID {
+			return rec.TurnIdx
+		}
+	}
+
+	for i := 1; i <= n; i++ {
+		idx := (rec.TurnIdx + i) % n
+		if !r.Players[idx].Resigned {
+			return idx
+		}
+	}
+	return rec.TurnIdx
+}
@@ -0,0 +1,200 @@
+package record
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/engine"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+	"time"
+)
+
+// FormatVersion is bumped whenever the game record layout changes incompatibly
+const FormatVersion = 1
+
+// GameRecord is a portable, self-contained description of a played game
+type GameRecord struct {
+	Version    int                     `json:"version"`
+	RoomCode   string                  `json:"room_code"`
+	ExportedAt time.Time               `json:"exported_at"`
+	CreatedAt  time.Time               `json:"created_at"`
+	Seed       int64                   `json:"seed,omitempty"` // Only once the game is over
+	BoardSize  int                     `json:"board_size"`
+	Engine     string                  `json:"engine"`
+	Weights    config.HeuristicWeights `json:"weights"`
+	Players    []PlayerRecord          `json:"players"`
+	TurnOrder  []string                `json:"turn_order"`
+	Moves      []game.MoveRecord       `json:"moves"`
+	FinalBoard game.Board              `json:"final_board"`
+	Position   string                  `json:"position"` // FinalBoard in the engine's notation, owners lettered by turn order
+	Hash       game.PositionHash       `json:"position_hash"`
+	Result     Result                  `json:"result"`
+	Metrics    *shared.GameMetrics     `json:"metrics,omitempty"` // Set once the game has ended
+}
+
+// PlayerRecord is the public information about a seat in the game
+type PlayerRecord struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	IsBot   bool   `json:"is_bot"`
+	Color   string `json:"color"`
+	Persona string `json:"persona,omitempty"`
+}
+
+// Result describes how the game ended
+type Result struct {
+	Status    string         `json:"status"`
+	WinnerID  *string        `json:"winner_id"`
+	AbortedBy *string        `json:"aborted_by,omitempty"`
+	Draw      bool           `json:"draw"`
+	WinLine   []game.WinCell `json:"win_line,omitempty"`
+}
+
+// Export builds a game record from the room's current state and move history
+func Export(r *shared.Room) GameRecord {
+	weights := config.Get().DefaultWeights
+	if r.RoomConfig != nil {
+		weights = r.RoomConfig.GetWeights()
+	}
+
+	players := make([]PlayerRecord, 0, len(r.Players))
+	for _, p := range r.Players {
+		players = append(players, PlayerRecord{
+			ID:      p.ID,
+			Name:    p.Name,
+			IsBot:   p.IsBot,
+			Color:   p.Color,
+			Persona: p.Persona,
+		})
+	}
+
+	moves := make([]game.MoveRecord, len(r.History))
+	copy(moves, r.History)
+
+	eng, err := engine.Get(r.Engine)
+	if err != nil {
+		eng, _ = engine.Get(engine.Default)
+	}
+
+	// The seed predicts the deal, so it stays private while the game runs
+	var seed int64
+	if r.Over() {
+		seed = r.Seed
+	}
+
+	return GameRecord{
+		Version:    FormatVersion,
+		RoomCode:   r.Code,
+		ExportedAt: time.Now(),
+		CreatedAt:  r.CreatedAt,
+		Seed:       seed,
+		BoardSize:  r.Board.Size,
+		Engine:     eng.Name(),
+		Weights:    weights,
+		Players:    players,
+		TurnOrder:  r.TurnOrder,
+		Moves:      moves,
+		FinalBoard: r.Board,
+		Position:   eng.Export(&r.Board, r.TurnOrder),
+		Hash:       r.Board.Hash,
+		Result: Result{
+			Status:    r.Status,
+			WinnerID:  r.WinnerID,
+			AbortedBy: r.AbortedBy,
+			Draw:      r.Draw,
+			WinLine:   r.WinLine,
+		},
+		Metrics: r.Metrics,
+	}
+}
+
+// Import parses a game record, checks its version and verifies that replaying
+// the move list reproduces the recorded final board.
+func Import(data []byte) (*GameRecord, error) {
+	rec, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	board := Replay(rec, len(rec.Moves))
+	if len(rec.FinalBoard.Cells) > 0 && !sameCells(board, rec.FinalBoard) {
+		return nil, errors.New("move list does not reproduce the final board")
+	}
+	if rec.Hash != 0 && board.Hash != rec.Hash {
+		return nil, errors.New("move list does not reproduce the position hash")
+	}
+
+	return rec, nil
+}
+
+// Decode parses a game record and checks its version, board size and engine
+// without replaying it; see Import and Verify
+func Decode(data []byte) (*GameRecord, error) {
+	var rec GameRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("invalid game record: %w", err)
+	}
+	if rec.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported game record version %d", rec.Version)
+	}
+	if rec.BoardSize <= 0 {
+		return nil, errors.New("invalid board size")
+	}
+	if _, err := engine.Get(rec.Engine); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// Replay rebuilds the board after the first n moves of the record. Records
+// from before engines were selectable replay on the default engine.
+func Replay(rec *GameRecord, n int) game.Board {
+	eng, err := engine.Get(rec.Engine)
+	if err != nil {
+		eng, _ = engine.Get(engine.Default)
+	}
+	board := eng.NewGame(rec.BoardSize)
+	board.KeepRules(&rec.FinalBoard)
+	board.SetSeats(recordSeats(rec))
+
+	if n > len(rec.Moves) {
+		n = len(rec.Moves)
+	}
+	for _, mv := range rec.Moves[:n] {
+		if mv.Type.Normalize() != game.MovePlace {
+			continue
+		}
+		eng.Apply(&board, game.Move{X: mv.X, Y: mv.Y, Card: mv.Card, PlayerID: mv.PlayerID})
+	}
+	return board
+}
+
+// recordSeats is the seat order the record's positions were hashed by.
+// Records without seats fall back to the player list.
+func recordSeats(rec *GameRecord) []string {
+	if len(rec.FinalBoard.Seats) > 0 {
+		return rec.FinalBoard.Seats
+	}
+	seats := make([]string, 0, len(rec.Players))
+	for _, p := range rec.Players {
+		seats = append(seats, p.ID)
+	}
+	return seats
+}
+
+func sameCells(a, b game.Board) bool {
+	if a.Size != b.Size {
+		return false
+	}
+	for y := 0; y < a.Size; y++ {
+		for x := 0; x < a.Size; x++ {
+			c := game.Coord{X: x, Y: y}
+			if a.At(c).Value != b.At(c).Value || a.At(c).OwnerID != b.At(c).OwnerID {
+				return false
+			}
+		}
+	}
+	return true
+}
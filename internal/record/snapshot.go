@@ -0,0 +1,94 @@
+package record
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+	"time"
+)
+
+// SnapshotVersion is bumped whenever the snapshot layout changes incompatibly
+const SnapshotVersion = 1
+
+// Snapshot is the complete state of a room, including the parts hidden from
+// clients (decks, draw pile, move history, chat and the random state), so a
+// game can be stored outside the server and resumed exactly where it stopped
+type Snapshot struct {
+	Version      int                  `json:"version"`
+	TakenAt      time.Time            `json:"taken_at"`
+	Room         *shared.Room         `json:"room"`
+	Decks        map[string][]int     `json:"decks"`
+	History      []game.MoveRecord    `json:"history"`
+	Pile         []int                `json:"pile,omitempty"`
+	PasswordHash []byte               `json:"password_hash,omitempty"`
+	Chat         []shared.ChatMessage `json:"chat,omitempty"`
+	Seed         int64                `json:"seed"`
+	RandDraws    uint64               `json:"rand_draws"` // Values drawn from the room's seeded source
+}
+
+// TakeSnapshot captures the room's current state
+func TakeSnapshot(r *shared.Room) Snapshot {
+	decks := make(map[string][]int, len(r.Players))
+	for _, p := range r.Players {
+		decks[p.ID] = p.Deck
+	}
+
+	return Snapshot{
+		Version:      SnapshotVersion,
+		TakenAt:      time.Now(),
+		Room:         r,
+		Decks:        decks,
+		History:      r.History,
+		Pile:         r.CommunalPile,
+		PasswordHash: r.PasswordHash,
+		Chat:         r.Chat,
+		Seed:         r.Seed,
+		RandDraws:    r.RandDraws(),
+	}
+}
+
+// Restore parses a snapshot and rebuilds the room from it. The board must
+// match a replay of the move history, so hand-edited snapshots that no longer
+// add up are rejected.
+func Restore(data []byte) (*shared.Room, error) {
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	if snap.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (expected %d)", snap.Version, SnapshotVersion)
+	}
+	r := snap.Room
+	if r == nil {
+		return nil, errors.New("snapshot has no room")
+	}
+
+	// Both are paid for before the snapshot is validated: the board by
+	// replaying onto a new one, the draws by fast-forwarding the source
+	if r.Board.Size < 1 || r.Board.Size > config.DefaultBoardSize {
+		return nil, fmt.Errorf("board size %d out of range (1 to %d)", r.Board.Size, config.DefaultBoardSize)
+	}
+	if snap.RandDraws > shared.MaxDraws {
+		return nil, fmt.Errorf("rand_draws %d out of range (at most %d)", snap.RandDraws, shared.MaxDraws)
+	}
+
+	for i := range r.Players {
+		r.Players[i].Deck = snap.Decks[r.Players[i].ID]
+	}
+	r.History = snap.History
+	r.CommunalPile = snap.Pile
+	r.PasswordHash = snap.PasswordHash
+	r.Chat = snap.Chat
+	if snap.Seed != 0 {
+		r.SeedRand(snap.Seed, snap.RandDraws) // Rooms that never drew are seeded when they first do
+	}
+	r.Board.Rehash()
+
+	if err := Validate(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,111 @@
+package record
+
+import (
+	"fmt"
+	"javanese-chess/internal/engine"
+	"javanese-chess/internal/game"
+)
+
+// Verification is the outcome of replaying a record through the current engine
+type Verification struct {
+	Engine     string      `json:"engine"`
+	Moves      int         `json:"moves"`       // Moves in the record
+	Replayed   int         `json:"replayed"`    // Moves replayed before the first divergence, or all of them
+	HashChecks int         `json:"hash_checks"` // Recorded position hashes compared
+	OK         bool        `json:"ok"`
+	Divergence *Divergence `json:"divergence,omitempty"`
+}
+
+// Divergence is the first point where the current engine disagrees with the
+// record. MoveNo is 1-based; 0 means the final board or hash of the record.
+type Divergence struct {
+	MoveNo int               `json:"move_no"`
+	Move   *game.MoveRecord  `json:"move,omitempty"`
+	Cell   string            `json:"cell,omitempty"`
+	Reason string            `json:"reason"`
+	Want   game.PositionHash `json:"want_hash,omitempty"`
+	Got    game.PositionHash `json:"got_hash,omitempty"`
+}
+
+func (d *Divergence) Error() string {
+	if d.MoveNo == 0 {
+		return d.Reason
+	}
+	return fmt.Sprintf("move %d (%s at %s): %s", d.MoveNo, d.Move.PlayerID, d.Cell, d.Reason)
+}
+
+// Verify replays every move of the record through the engine as it plays
+// today and stops at the first move that is illegal now, produces a position
+// hash other than the recorded one, or ends the game early. Finally the
+// replayed board must match the recorded final board and hash. Rule changes
+// that would silently alter historical games show up as a divergence.
+func Verify(rec *GameRecord) Verification {
+	eng, err := engine.Get(rec.Engine)
+	if err != nil {
+		eng, _ = engine.Get(engine.Default)
+	}
+	v := Verification{Engine: eng.Name(), Moves: len(rec.Moves)}
+
+	board := eng.NewGame(rec.BoardSize)
+	board.KeepRules(&rec.FinalBoard)
+	board.SetSeats(recordSeats(rec))
+
+	last := lastPlacement(rec)
+	for i := range rec.Moves {
+		mv := rec.Moves[i]
+		if mv.Type.Normalize() != game.MovePlace {
+			v.Replayed++
+			continue
+		}
+
+		diverge := func(reason string) Verification {
+			v.Divergence = &Divergence{
+				MoveNo: i + 1,
+				Move:   &mv,
+				Cell:   game.Coord{X: mv.X, Y: mv.Y}.Algebraic(),
+				Reason: reason,
+			}
+			return v
+		}
+
+		m := game.Move{X: mv.X, Y: mv.Y, Card: mv.Card, PlayerID: mv.PlayerID}
+		if err := eng.Validate(&board, m); err != nil {
+			return diverge(fmt.Sprintf("placing %d is illegal: %v", mv.Card, err))
+		}
+		eng.Apply(&board, m)
+
+		if mv.Hash != 0 {
+			v.HashChecks++
+			if board.Hash != mv.Hash {
+				v = diverge(fmt.Sprintf("position hash %s does not match the recorded %s", board.Hash, mv.Hash))
+				v.Divergence.Want, v.Divergence.Got = mv.Hash, board.Hash
+				return v
+			}
+		}
+		if i != last && eng.Winner(&board, mv.X, mv.Y, mv.PlayerID) != nil {
+			return diverge("the placement wins, ending the game before the recorded moves that follow")
+		}
+		v.Replayed++
+	}
+
+	if len(rec.FinalBoard.Cells) > 0 && !sameCells(board, rec.FinalBoard) {
+		v.Divergence = &Divergence{Reason: "move list does not reproduce the final board"}
+		return v
+	}
+	if rec.Hash != 0 && board.Hash != rec.Hash {
+		v.Divergence = &Divergence{Reason: "move list does not reproduce the position hash", Want: rec.Hash, Got: board.Hash}
+		return v
+	}
+	v.OK = true
+	return v
+}
+
+// lastPlacement is the index of the record's last placement, or -1
+func lastPlacement(rec *GameRecord) int {
+	for i := len(rec.Moves) - 1; i >= 0; i-- {
+		if rec.Moves[i].Type.Normalize() == game.MovePlace {
+			return i
+		}
+	}
+	return -1
+}
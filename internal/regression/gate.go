@@ -0,0 +1,124 @@
+package regression
+
+import (
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/engine"
+	"javanese-chess/internal/game"
+	"math"
+)
+
+// Placement is a card put on the board while setting up a position
+type Placement struct {
+	X, Y  int
+	Owner string
+	Card  int
+}
+
+// Position is a critical position with the move the bot is approved to play
+// in it. Score is the bot's evaluation of that move when it was approved.
+type Position struct {
+	Name      string
+	Source    string // Where the position comes from: a paper example or a bug report
+	Size      int
+	LockAfter int // Cell-lock variant; 0 = off
+	Setup     []Placement
+
+	Player string
+	Hand   []int
+
+	Approved game.Move
+	Score    int
+}
+
+// Result is what the bot played in a position
+type Result struct {
+	Position string
+	Chosen   game.Move
+	Score    int
+	// ApprovedScore is the current evaluation of the approved move
+	ApprovedScore int
+}
+
+// Failure describes a position where the bot's behavior regressed
+type Failure struct {
+	Position string
+	Source   string
+	Reason   string
+}
+
+func (f Failure) String() string {
+	return fmt.Sprintf("%s (%s): %s", f.Position, f.Source, f.Reason)
+}
+
+// Check plays every position with the weights and fails those where the bot
+// picks a different move than approved, or where its evaluation of the
+// approved move fell by more than tolerance (a fraction of the size of the
+// approved score, so negative scores get the same slack). A different move
+// with the same score as the approved one is a tie, not a regression.
+func Check(weights *config.HeuristicWeights, tolerance float64) ([]Result, []Failure) {
+	var results []Result
+	var failures []Failure
+	for _, p := range Positions {
+		res, reason := CheckPosition(p, weights, tolerance)
+		results = append(results, res)
+		if reason != "" {
+			failures = append(failures, Failure{Position: p.Name, Source: p.Source, Reason: reason})
+		}
+	}
+	return results, failures
+}
+
+// CheckPosition plays a single position and returns the reason it failed, or
+// an empty string when the bot still plays as approved
+func CheckPosition(p Position, weights *config.HeuristicWeights, tolerance float64) (Result, string) {
+	res := Result{Position: p.Name}
+	b := setup(p)
+
+	ranked := game.RankMoves(&b, p.Hand, p.Player, weights)
+	if len(ranked) == 0 {
+		return res, "no legal moves"
+	}
+	best := ranked[0]
+	res.Chosen = best.Move
+	res.Score = best.Breakdown.Total
+
+	approved := -1
+	for i, rm := range ranked {
+		if sameMove(rm.Move, p.Approved) {
+			approved = i
+			res.ApprovedScore = rm.Breakdown.Total
+		}
+	}
+
+	switch {
+	case approved < 0:
+		return res, fmt.Sprintf("approved move %s is not legal", describe(p.Approved))
+	case res.ApprovedScore < res.Score:
+		return res, fmt.Sprintf("plays %s (score %d) instead of approved %s (score %d)",
+			describe(best.Move), res.Score, describe(p.Approved), res.ApprovedScore)
+	case float64(res.ApprovedScore) < float64(p.Score)-math.Abs(float64(p.Score))*tolerance:
+		return res, fmt.Sprintf("evaluation of approved %s fell from %d to %d",
+			describe(p.Approved), p.Score, res.ApprovedScore)
+	}
+	return res, ""
+}
+
+// setup builds the position's board with the default engine
+func setup(p Position) game.Board {
+	eng, _ := engine.Get(engine.Default)
+	b := eng.NewGame(p.Size)
+	b.LockAfter = p.LockAfter
+	for _, pl := range p.Setup {
+		eng.Apply(&b, game.Move{X: pl.X, Y: pl.Y, Card: pl.Card, PlayerID: pl.Owner})
+	}
+	return b
+}
+
+func sameMove(a, b game.Move) bool {
+	return a.X == b.X && a.Y == b.Y && a.Card == b.Card
+}
+
+func describe(mv game.Move) string {
+	return fmt.Sprintf("%d at %s", mv.Card, game.Coord{X: mv.X, Y: mv.Y}.Algebraic())
+}
@@ -0,0 +1,162 @@
+package regression
+
+import "javanese-chess/internal/game"
+
+func mv(x, y, card int) game.Move {
+	return game.Move{X: x, Y: y, Card: card}
+}
+
+func row(owner string, y int, xs []int, card int) []Placement {
+	out := make([]Placement, 0, len(xs))
+	for _, x := range xs {
+		out = append(out, Placement{X: x, Y: y, Owner: owner, Card: card})
+	}
+	return out
+}
+
+// forkSetup gives the bot two pairs, each blocked on one end, that a
+// placement at (5,4) turns into two threes at once
+func forkSetup() []Placement {
+	return []Placement{
+		{X: 3, Y: 4, Owner: "bot", Card: 4}, {X: 4, Y: 4, Owner: "bot", Card: 5}, {X: 2, Y: 4, Owner: "opp", Card: 9},
+		{X: 5, Y: 2, Owner: "bot", Card: 3}, {X: 5, Y: 3, Owner: "bot", Card: 6}, {X: 5, Y: 1, Owner: "opp", Card: 9},
+		{X: 3, Y: 5, Owner: "opp", Card: 9}, {X: 6, Y: 1, Owner: "opp", Card: 9},
+	}
+}
+
+// threatCellSetup gives the opponent a three whose only open end is a cell
+// already played twice, with the other end closed by a permanent card, so
+// the bot must overwrite that cell to block
+func threatCellSetup() []Placement {
+	return append(row("opp", 4, []int{2, 3, 4}, 4),
+		Placement{X: 5, Y: 4, Owner: "opp", Card: 2}, Placement{X: 5, Y: 4, Owner: "opp2", Card: 3},
+		Placement{X: 1, Y: 4, Owner: "opp2", Card: 9})
+}
+
+// Positions are the curated critical positions. The bot is always "bot";
+// its opponents are "opp" and "opp2". When a heuristic change is meant to
+// alter one of these choices, update the approval together with the change.
+var Positions = []Position{
+	{
+		Name:     "opening card",
+		Source:   "paper 2.4: play the smallest card",
+		Size:     9,
+		Player:   "bot",
+		Hand:     []int{3, 7, 9},
+		Approved: mv(4, 4, 3),
+		Score:    110,
+	},
+	{
+		Name:     "complete four in a row",
+		Source:   "paper 2.4: f_win",
+		Size:     9,
+		Setup:    append(row("bot", 4, []int{2, 3, 4}, 5), row("opp", 5, []int{3, 4}, 6)...),
+		Player:   "bot",
+		Hand:     []int{1, 4, 8},
+		Approved: mv(1, 4, 1),
+		Score:    10030,
+	},
+	{
+		Name:     "win rather than block",
+		Source:   "paper 2.4: f_win outranks f_threat",
+		Size:     9,
+		Setup:    append(row("opp", 3, []int{2, 3, 4}, 7), row("bot", 4, []int{2, 3, 4}, 5)...),
+		Player:   "bot",
+		Hand:     []int{2, 6, 9},
+		Approved: mv(1, 4, 2),
+		Score:    10030,
+	},
+	{
+		Name:     "block an open three",
+		Source:   "paper 2.4: f_threat",
+		Size:     9,
+		Setup:    append(row("opp", 4, []int{2, 3, 4}, 6), Placement{X: 4, Y: 5, Owner: "bot", Card: 2}),
+		Player:   "bot",
+		Hand:     []int{1, 5, 8},
+		Approved: mv(3, 4, 8),
+		Score:    460,
+	},
+	{
+		Name:     "capture the end of an open three",
+		Source:   "paper 2.4: f_replace with low-card defence",
+		Size:     9,
+		Setup:    append(row("opp", 4, []int{3, 4, 5}, 3), Placement{X: 2, Y: 4, Owner: "bot", Card: 9}),
+		Player:   "bot",
+		Hand:     []int{4, 6, 8},
+		Approved: mv(3, 4, 4),
+		Score:    455,
+	},
+	{
+		Name:     "overwrite a threat cell with a high card",
+		Source:   "paper 2.4: blocking prefers high cards",
+		Size:     9,
+		Setup:    threatCellSetup(),
+		Player:   "bot",
+		Hand:     []int{5, 9},
+		Approved: mv(5, 4, 9),
+		Score:    630,
+	},
+	{
+		Name:     "extend own line",
+		Source:   "paper 2.4: f_formation",
+		Size:     9,
+		Setup:    []Placement{{X: 4, Y: 4, Owner: "bot", Card: 4}, {X: 5, Y: 5, Owner: "opp", Card: 3}},
+		Player:   "bot",
+		Hand:     []int{2, 5, 7},
+		Approved: mv(5, 5, 5),
+		Score:    325,
+	},
+	{
+		Name:      "locking capture uses a low card",
+		Source:    "bug report: cell-lock captures spent 9s on cells that lock anyway",
+		Size:      9,
+		LockAfter: 2,
+		Setup:     threatCellSetup(),
+		Player:    "bot",
+		Hand:      []int{5, 9},
+		Approved:  mv(5, 4, 5),
+		Score:     590,
+	},
+	{
+		Name:     "fork two threes",
+		Source:   "f_fork: two cells to complete four, one reply cannot block both",
+		Size:     9,
+		Setup:    forkSetup(),
+		Player:   "bot",
+		Hand:     []int{2, 6, 8},
+		Approved: mv(5, 4, 2),
+		Score:    360,
+	},
+	{
+		Name:   "block a three rather than fork",
+		Source: "f_fork: a fork is too slow against a three that wins next turn",
+		Size:   9,
+		Setup: append(forkSetup(),
+			append(row("bot", 8, []int{2}, 9), row("opp", 8, []int{3, 4, 5}, 9)...)...),
+		Player:   "bot",
+		Hand:     []int{2, 6, 9},
+		Approved: mv(6, 8, 9),
+		Score:    350,
+	},
+	{
+		Name:   "block the two with room to open",
+		Source: "open threes: a two blocked on one end can only make a half-open three",
+		Size:   9,
+		Setup: append(append(row("opp2", 2, []int{2}, 9), row("opp", 2, []int{3, 4}, 9)...),
+			row("opp", 6, []int{3, 4}, 9)...),
+		Player:   "bot",
+		Hand:     []int{3, 7},
+		Approved: mv(5, 6, 3),
+		Score:    200,
+	},
+	{
+		Name:     "fill a gap with a card that cannot be covered",
+		Source:   "f_refute: a low card in the gap is covered and the line completes",
+		Size:     9,
+		Setup:    append(row("opp", 4, []int{2, 3, 5}, 9), Placement{X: 3, Y: 5, Owner: "bot", Card: 2}),
+		Player:   "bot",
+		Hand:     []int{1, 5, 9},
+		Approved: mv(4, 4, 9),
+		Score:    460,
+	},
+}
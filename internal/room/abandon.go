@@ -0,0 +1,96 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Why a player was given up on
+const (
+	abandonDisconnected = "disconnected" // Offline longer than the room allows
+	abandonMissedTurns  = "missed_turns" // Too many turns timed out while offline
+)
+
+// SetAbandonRule chooses when the room gives up on a disconnected player and
+// whether a bot takes over their seat or they forfeit
+func (m *Manager) SetAbandonRule(room *shared.Room, rule shared.AbandonRule) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	switch rule.Action {
+	case "", shared.AbandonBot, shared.AbandonForfeit:
+	default:
+		return errors.New("abandon action must be bot or forfeit")
+	}
+	if rule.Turns < 0 || rule.Turns > config.MaxAbandonTurns {
+		return fmt.Errorf("abandon turns must be between 0 and %d", config.MaxAbandonTurns)
+	}
+	if rule.Minutes < 0 || rule.Minutes > config.MaxAbandonMinutes {
+		return fmt.Errorf("abandon minutes must be between 0 and %d", config.MaxAbandonMinutes)
+	}
+
+	r.Abandon = rule
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// AbandonPlayer gives up on a human who stayed disconnected longer than the
+// room allows, so the game no longer waits for them
+func (m *Manager) AbandonPlayer(room *shared.Room, playerID string) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	return m.abandonPlayer(r, playerID, abandonDisconnected)
+}
+
+// abandonPlayer broadcasts player_abandoned, then applies the room's rule:
+// a bot plays on in the seat, or the player forfeits
+func (m *Manager) abandonPlayer(r *shared.Room, playerID, reason string) error {
+	if r.Status != "playing" || r.Over() {
+		return errors.New("game is not in progress")
+	}
+
+	seat := findPlayer(r, playerID)
+	if seat == nil {
+		return errors.New("seat not found")
+	}
+	if seat.IsBot || seat.Resigned {
+		return errors.New("seat is not held by a human")
+	}
+
+	action := r.Abandon.Action
+	if action == "" {
+		action = shared.AbandonBot
+	}
+	m.logEvent(r, roomlog.KindSeat, playerID, "Player %s abandoned room %s (%s), action: %s", playerID, r.Code, reason, action)
+	m.hub.Broadcast(r.Code, "player_abandoned", gin.H{
+		"player_id": playerID,
+		"reason":    reason,
+		"action":    action,
+	})
+
+	if action == shared.AbandonForfeit {
+		return m.resign(r, playerID)
+	}
+	m.abandonSeat(r, seat)
+	return nil
+}
+
+// abandonIfAway abandons a disconnected player once the room's limit of
+// turns timed out in a row is reached
+func (m *Manager) abandonIfAway(r *shared.Room, playerID string) {
+	p := findPlayer(r, playerID)
+	if p == nil || p.IsBot || r.Over() {
+		return
+	}
+	if r.Abandon.Turns == 0 || p.MissedTurns < r.Abandon.Turns || m.hub.Connected(r.Code, playerID) {
+		return
+	}
+	if err := m.abandonPlayer(r, playerID, abandonMissedTurns); err != nil {
+		m.logEvent(r, roomlog.KindError, playerID, "Player %s in room %s not abandoned: %v", playerID, r.Code, err)
+	}
+}
@@ -0,0 +1,60 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Abort calls off a game that has barely started. It is only allowed during
+// the first config.AbortMaxPlies moves; the game ends without a result, so
+// ratings, stats and match scores are left untouched and the room can be
+// restarted with a rematch.
+func (m *Manager) Abort(room *shared.Room, playerID string) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.Over() || isClosed(r) {
+		return errors.New("game is already over")
+	}
+	if r.Status != "playing" {
+		return errors.New("game has not started")
+	}
+
+	p := findPlayer(r, playerID)
+	if p == nil {
+		return errors.New("player not in room")
+	}
+	if p.IsBot {
+		return errors.New("bots cannot abort games")
+	}
+	if len(r.History) >= config.AbortMaxPlies {
+		return errors.New("too late to abort, resign instead")
+	}
+
+	r.Status = "aborted"
+	r.AbortedBy = &playerID
+	r.PendingUndo = nil
+	m.startTurnClock(r)
+	m.logResult(r)
+	m.training.Discard(r.Code)
+	m.store.SaveRoom(r)
+
+	m.logEvent(r, roomlog.KindGame, playerID, "Player %s aborted the game in room %s after %d moves", playerID, r.Code, len(r.History))
+	m.hub.Broadcast(r.Code, "game_aborted", gin.H{
+		"player_id": playerID,
+		"plies":     len(r.History),
+		"board":     r.Board,
+	})
+	m.runGameOverHooks(r)
+	return nil
+}
+
+// isClosed reports whether the room no longer accepts moves: an operator
+// ended it, a player aborted the game or its owner closed it
+func isClosed(r *shared.Room) bool {
+	return r.Status == "ended" || r.Status == "aborted" || r.Status == "closed"
+}
@@ -0,0 +1,294 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+	"javanese-chess/internal/tracing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlayMove validates a typed move and dispatches it to the matching action
+func (m *Manager) PlayMove(ctx context.Context, room *shared.Room, mv game.Move) (err error) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	ctx, span := roomSpan(ctx, "room.play_move", r, mv.PlayerID, tracing.String("move.type", string(mv.Type.Normalize())))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	if err := mv.Validate(&r.Board); err != nil {
+		return err
+	}
+	if isClosed(r) {
+		return errors.New("room has ended")
+	}
+
+	switch mv.Type.Normalize() {
+	case game.MovePlace:
+		return m.applyMove(ctx, r, mv.PlayerID, mv.X, mv.Y, mv.Card, nil)
+	case game.MoveSkip:
+		return m.skip(r, mv.PlayerID)
+	case game.MoveResign:
+		return m.resign(r, mv.PlayerID)
+	case game.MoveSwap:
+		return m.swap(r, mv.PlayerID, mv.Card)
+	}
+	return errors.New("unknown move type")
+}
+
+// Skip passes the current player's turn. It is only allowed when the player
+// has no legal placement with the cards in hand.
+func (m *Manager) Skip(room *shared.Room, playerID string) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	return m.skip(r, playerID)
+}
+
+func (m *Manager) skip(r *shared.Room, playerID string) error {
+	if r.Over() || isClosed(r) {
+		return errors.New("game is already over")
+	}
+	if r.Status != "playing" {
+		return errors.New("game has not started")
+	}
+
+	cp := m.currentPlayer(r)
+	if cp == nil || cp.ID != playerID {
+		return errors.New("not your turn or player invalid")
+	}
+	if len(engineFor(r).LegalMoves(&r.Board, cp.Hand, playerID)) > 0 {
+		return errors.New("legal moves available, cannot skip")
+	}
+
+	cp.MissedTurns = 0
+	m.recordSkip(r, playerID, "requested")
+	m.skipStuckPlayers(r)
+	return nil
+}
+
+// recordSkip passes the current player's turn and broadcasts turn_skipped
+func (m *Manager) recordSkip(r *shared.Room, playerID string, reason string) {
+	r.History = append(r.History, game.MoveRecord{
+		Type:     game.MoveSkip,
+		PlayerID: playerID,
+		TurnIdx:  r.TurnIdx,
+		At:       time.Now(),
+	})
+	r.PendingUndo = nil
+	if reason == "timeout" {
+		m.logMove(r, record.EventTimeout)
+	} else {
+		m.logMove(r, record.EventMove)
+	}
+	advanceTurn(r)
+	m.startTurnClock(r)
+	m.store.SaveRoom(r)
+
+	m.logEvent(r, roomlog.KindMove, playerID, "Player %s skipped their turn in room %s (%s)", playerID, r.Code, reason)
+	m.hub.Broadcast(r.Code, "turn_skipped", gin.H{
+		"player_id": playerID,
+		"reason":    reason,
+		"next_turn": r.Players[r.TurnIdx].ID,
+		"clock":     clockView(r),
+	})
+}
+
+// skipStuckPlayers advances past players without a legal move until someone
+// can play. When no active player can move the game ends by tie-break.
+func (m *Manager) skipStuckPlayers(r *shared.Room) {
+	for i := 0; i < len(r.Players); i++ {
+		if r.Over() {
+			return
+		}
+
+		cp := m.currentPlayer(r)
+		if cp == nil || len(engineFor(r).LegalMoves(&r.Board, cp.Hand, cp.ID)) > 0 {
+			return
+		}
+		if m.CheckEndgame(r) {
+			return
+		}
+		m.recordSkip(r, cp.ID, "no_legal_moves")
+	}
+}
+
+// Resign removes a player from the turn rotation. Their cards stay on the
+// board. When a single active player remains they win the game; otherwise
+// play goes on and the player ranks below everyone still playing (see
+// standings).
+func (m *Manager) Resign(room *shared.Room, playerID string) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	return m.resign(r, playerID)
+}
+
+func (m *Manager) resign(r *shared.Room, playerID string) error {
+	if r.Over() || isClosed(r) {
+		return errors.New("game is already over")
+	}
+	if r.Status != "playing" {
+		return errors.New("game has not started")
+	}
+
+	p := findPlayer(r, playerID)
+	if p == nil {
+		return errors.New("player not in room")
+	}
+	if p.Resigned {
+		return errors.New("player has already resigned")
+	}
+
+	r.History = append(r.History, game.MoveRecord{
+		Type:     game.MoveResign,
+		PlayerID: playerID,
+		TurnIdx:  r.TurnIdx,
+		At:       time.Now(),
+	})
+	r.PendingUndo = nil
+	m.logMove(r, record.EventMove)
+	p.Resigned = true
+
+	active := activePlayers(r)
+	if len(active) == 1 {
+		winnerID := active[0].ID
+		m.hub.Broadcast(r.Code, "player_resigned", gin.H{
+			"player_id": playerID,
+		})
+		m.finishGame(r, &winnerID)
+		return nil
+	}
+
+	if r.Players[r.TurnIdx].ID == playerID {
+		advanceTurn(r)
+		m.startTurnClock(r)
+	}
+	m.store.SaveRoom(r)
+
+	m.logEvent(r, roomlog.KindMove, playerID, "Player %s resigned in room %s", playerID, r.Code)
+	m.hub.Broadcast(r.Code, "player_resigned", gin.H{
+		"player_id": playerID,
+		"next_turn": r.Players[r.TurnIdx].ID,
+		"clock":     clockView(r),
+	})
+	m.skipStuckPlayers(r)
+	return nil
+}
+
+// Swap puts a card from the current player's hand under their deck and draws
+// the top card, using up the turn.
+func (m *Manager) Swap(room *shared.Room, playerID string, card int) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	return m.swap(r, playerID, card)
+}
+
+func (m *Manager) swap(r *shared.Room, playerID string, card int) error {
+	if r.Over() || isClosed(r) {
+		return errors.New("game is already over")
+	}
+	if r.Status != "playing" {
+		return errors.New("game has not started")
+	}
+
+	cp := m.currentPlayer(r)
+	if cp == nil || cp.ID != playerID {
+		return errors.New("not your turn or player invalid")
+	}
+
+	// Shared deck rooms swap with the shared draw pile
+	deck, fromPile := &cp.Deck, r.SharedDeck > 0
+	if fromPile {
+		deck = &r.CommunalPile
+	}
+	if len(*deck) == 0 {
+		return errors.New("deck is empty, cannot swap")
+	}
+
+	handIdx := -1
+	for i, c := range cp.Hand {
+		if c == card {
+			handIdx = i
+			break
+		}
+	}
+	if handIdx < 0 {
+		return errors.New("card not in hand")
+	}
+
+	m.recordPosition(r, cp, game.Move{Card: card, PlayerID: playerID, Type: game.MoveSwap})
+
+	cp.Hand = append(cp.Hand[:handIdx], cp.Hand[handIdx+1:]...)
+	*deck = append(*deck, card)
+	drawnCard := (*deck)[0]
+	*deck = (*deck)[1:]
+	cp.Hand = append(cp.Hand, drawnCard)
+	cp.MissedTurns = 0
+
+	r.History = append(r.History, game.MoveRecord{
+		Type:      game.MoveSwap,
+		Card:      card,
+		PlayerID:  playerID,
+		HandIdx:   handIdx,
+		DrawnCard: drawnCard,
+		FromPile:  fromPile,
+		TurnIdx:   r.TurnIdx,
+		At:        time.Now(),
+	})
+	r.PendingUndo = nil
+	m.logMove(r, record.EventMove)
+	m.noteEvent(r, roomlog.KindMove, playerID, "Player %s swapped a card in room %s", playerID, r.Code)
+	advanceTurn(r)
+	m.startTurnClock(r)
+	m.store.SaveRoom(r)
+
+	m.hub.Broadcast(r.Code, "card_swapped", gin.H{
+		"player_id": playerID,
+		"next_turn": r.Players[r.TurnIdx].ID,
+		"clock":     clockView(r),
+	})
+	m.SyncHands(r)
+	m.auditCards(r)
+	m.skipStuckPlayers(r)
+	return nil
+}
+
+// advanceTurn moves TurnIdx to the next player who has not resigned, banking
+// the outgoing player's unused turn time
+func advanceTurn(r *shared.Room) {
+	settleTurnTime(r, time.Now())
+
+	n := len(r.Players)
+	for i := 1; i <= n; i++ {
+		idx := (r.TurnIdx + i) % n
+		if !r.Players[idx].Resigned {
+			r.TurnIdx = idx
+			return
+		}
+	}
+}
+
+func findPlayer(r *shared.Room, playerID string) *shared.Player {
+	for i := range r.Players {
+		if r.Players[i].ID == playerID {
+			return &r.Players[i]
+		}
+	}
+	return nil
+}
+
+func activePlayers(r *shared.Room) []shared.Player {
+	var out []shared.Player
+	for _, p := range r.Players {
+		if !p.Resigned {
+			out = append(out, p)
+		}
+	}
+	return out
+}
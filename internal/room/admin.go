@@ -0,0 +1,66 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListRooms returns every room known to the store
+func (m *Manager) ListRooms() []*shared.Room {
+	return m.store.ListRooms()
+}
+
+// ForceEnd stops a room on behalf of an operator. The game ends without a
+// result: no winner is declared and ratings are left untouched.
+func (m *Manager) ForceEnd(room *shared.Room, reason string) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	defer m.dropLock(r.Code)
+
+	if r.Status == "ended" {
+		return errors.New("room has already ended")
+	}
+
+	r.Status = "ended"
+	r.PendingUndo = nil
+	if r.TurnTimer != nil {
+		r.TurnTimer.Stop()
+		r.TurnTimer = nil
+	}
+	m.logResult(r)
+	m.training.Discard(r.Code)
+	m.store.SaveRoom(r)
+
+	m.logEvent(r, roomlog.KindRoom, "", "Room %s force-ended by operator: %s", r.Code, reason)
+	m.hub.Broadcast(r.Code, "room_ended", gin.H{
+		"reason": reason,
+		"board":  r.Board,
+	})
+	return nil
+}
+
+// SetDefaultWeights replaces the server-wide heuristic weights used by bots
+// and by rooms created from now on
+func (m *Manager) SetDefaultWeights(w config.HeuristicWeights) {
+	m.cfg.DefaultWeights = w
+	config.Get().DefaultWeights = w
+	log.Printf("Default heuristic weights updated by operator")
+}
+
+// ReloadConfig reads the config file and the environment again. The new
+// defaults apply to rooms created from now on; running rooms keep theirs.
+func (m *Manager) ReloadConfig() (config.Defaults, error) {
+	d, err := config.Reload()
+	if err != nil {
+		return d, err
+	}
+	m.cfg.DefaultWeights = d.Weights
+	m.cfg.BotTemperature = d.BotTemperature
+	m.cfg.BotTemperatureMoves = d.BotTemperatureMoves
+	return d, nil
+}
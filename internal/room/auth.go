@@ -0,0 +1,61 @@
+package room
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/shared"
+)
+
+// BindUser links a seat to an authenticated account so only that user can act for it
+func (m *Manager) BindUser(room *shared.Room, playerID, userID string) {
+	if userID == "" {
+		return
+	}
+
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	if p := findPlayer(r, playerID); p != nil && p.UserID != userID {
+		p.UserID = userID
+		m.logState(r, record.EventCheckpoint)
+		m.store.SaveRoom(r)
+	}
+}
+
+// SeatSecret is the credential a client shows to identify as a seat that has
+// no account. It is derived from the server secret, so nothing is stored, and
+// changes when the seat is taken over.
+func (m *Manager) SeatSecret(r *shared.Room, playerID string) string {
+	takeovers := 0
+	for _, c := range r.SeatChanges {
+		if c.PlayerID == playerID {
+			takeovers++
+		}
+	}
+	mac := hmac.New(sha256.New, []byte(m.cfg.JWTSecret))
+	fmt.Fprintf(mac, "%s/%s/%d", r.Code, playerID, takeovers)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// AuthorizeSeat checks that a client may act for playerID and receive its
+// private hand. Account seats need their user; other seats need the seat's
+// secret.
+func (m *Manager) AuthorizeSeat(r *shared.Room, playerID, userID, secret string) error {
+	p := findPlayer(r, playerID)
+	if p == nil {
+		return errors.New("player not in room")
+	}
+	if p.UserID != "" {
+		if p.UserID != userID {
+			return errors.New("player is controlled by another user")
+		}
+		return nil
+	}
+	if !hmac.Equal([]byte(secret), []byte(m.SeatSecret(r, playerID))) {
+		return errors.New("invalid seat secret")
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package room
+
+import (
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+	"time"
+)
+
+// SetBotBudget limits how long the room's bots may spend scoring their
+// candidate moves. Zero restores config.DefaultBotBudget.
+func (m *Manager) SetBotBudget(room *shared.Room, budget time.Duration) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if budget < 0 || budget > config.MaxBotBudget {
+		return fmt.Errorf("bot budget must be between 0 and %d ms", config.MaxBotBudget.Milliseconds())
+	}
+	if r.RoomConfig == nil {
+		r.RoomConfig = config.NewRoomConfig(r.Code)
+	}
+	r.RoomConfig.SetBotBudget(budget)
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// botBudget is how long the room's bots may spend scoring their moves
+func botBudget(r *shared.Room) time.Duration {
+	if r.RoomConfig == nil {
+		return config.DefaultBotBudget
+	}
+	return r.RoomConfig.GetBotBudget()
+}
@@ -0,0 +1,79 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+
+	"github.com/google/uuid"
+)
+
+// CreateBotGame starts a game between bots only, for offline evaluation such
+// as tournaments. Each entry of weights seats one bot, named "Seat 1" and so
+// on in order; nil plays with the default weights. Bots play at full strength
+// and the same seed always deals the same seats the same cards.
+func (m *Manager) CreateBotGame(code string, seed int64, weights []*config.HeuristicWeights) (*shared.Room, error) {
+	if len(weights) < config.MinPlayers || len(weights) > config.MaxPlayers {
+		return nil, fmt.Errorf("a bot game needs %d to %d bots", config.MinPlayers, config.MaxPlayers)
+	}
+
+	r, err := m.createBotRoom(code, len(weights))
+	if err != nil {
+		return nil, err
+	}
+
+	// Persona difficulty would make bots play random moves on purpose
+	for i := range r.Players {
+		r.Players[i].Name = fmt.Sprintf("Seat %d", i+1)
+		r.Players[i].Persona = ""
+		r.Players[i].Weights = weights[i]
+	}
+	m.store.SaveRoom(r)
+
+	m.Reseed(r, seed)
+	m.StartGame(r)
+	return r, nil
+}
+
+// createBotRoom creates a lobby room seating n bots with their personas
+func (m *Manager) createBotRoom(code string, n int) (*shared.Room, error) {
+	// The lobby host becomes the first bot
+	persona := personaFor(0)
+	r, err := m.CreateLobbyRoom(code, persona.Name)
+	if err != nil {
+		return nil, err
+	}
+	r.Players[0].ID = "bot-" + uuid.NewString()
+	r.Players[0].IsBot = true
+	r.Players[0].Persona = persona.Name
+	r.MasterID = r.Players[0].ID
+	m.store.SaveRoom(r)
+	if err := m.AddBots(r, n-1); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// PlayBotGame plays bot turns until the game ends. It fails when a human is
+// seated or the game is still going after maxMoves turns.
+func (m *Manager) PlayBotGame(r *shared.Room, maxMoves int) error {
+	for n := 0; n < maxMoves; n++ {
+		if r.Over() || isClosed(r) {
+			return nil
+		}
+
+		cp := m.currentPlayer(r)
+		if cp == nil || !cp.IsBot {
+			return errors.New("bot games cannot seat humans")
+		}
+		if _, err := m.BotMove(context.Background(), r, cp.ID); err != nil {
+			return fmt.Errorf("move %d: %w", len(r.History)+1, err)
+		}
+	}
+	if r.Over() || isClosed(r) {
+		return nil
+	}
+	return fmt.Errorf("game did not finish within %d moves", maxMoves)
+}
@@ -0,0 +1,19 @@
+package room
+
+import (
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+)
+
+// SetTemperature makes the room's bots sample among near-best moves for the
+// first moves of the game, so games against them diverge
+func (m *Manager) SetTemperature(room *shared.Room, temperature float64, moves int) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.RoomConfig == nil {
+		r.RoomConfig = config.NewRoomConfig(r.Code)
+	}
+	r.RoomConfig.SetTemperature(temperature, moves)
+	m.store.SaveRoom(r)
+}
@@ -0,0 +1,30 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+	"time"
+)
+
+// SetBotThinkTime paces the room's bots: each move waits a thinking time drawn
+// between min and max, announced to clients with a bot_thinking event. Zero
+// for both restores the server default.
+func (m *Manager) SetBotThinkTime(room *shared.Room, min, max time.Duration) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if min < 0 || max > config.MaxBotDelay {
+		return fmt.Errorf("bot think time must be between 0 and %d ms", config.MaxBotDelay.Milliseconds())
+	}
+	if max < min {
+		return errors.New("bot_think_max must not be below bot_think_ms")
+	}
+	if r.RoomConfig == nil {
+		r.RoomConfig = config.NewRoomConfig(r.Code)
+	}
+	r.RoomConfig.SetBotDelayRange(min, max)
+	m.store.SaveRoom(r)
+	return nil
+}
@@ -0,0 +1,18 @@
+package room
+
+import (
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+)
+
+// SetWeights replaces the heuristic weights the room's bots evaluate moves with
+func (m *Manager) SetWeights(room *shared.Room, weights config.HeuristicWeights) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.RoomConfig == nil {
+		r.RoomConfig = config.NewRoomConfig(r.Code)
+	}
+	r.RoomConfig.SetWeights(weights)
+	m.store.SaveRoom(r)
+}
@@ -0,0 +1,21 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/shared"
+)
+
+// SetCaptureTie makes captures the next tie-breaker once line and total sums
+// are level: more captures rank higher, then a higher captured value, and
+// only leaders level on those draw. It can only change before the first move.
+func (m *Manager) SetCaptureTie(room *shared.Room, enabled bool) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.Status == "playing" && len(r.History) > 0 {
+		return errors.New("capture tie can only change before the first move")
+	}
+	r.CaptureTie = enabled
+	m.store.SaveRoom(r)
+	return nil
+}
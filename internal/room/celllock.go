@@ -0,0 +1,26 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+)
+
+// SetCellLock turns the cell-lock variant on or off before a game starts.
+// With it on, a cell captured config.CellLockCaptures times becomes permanent
+// like the highest card, which cuts short endless capture exchanges.
+func (m *Manager) SetCellLock(room *shared.Room, enabled bool) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.Status == "playing" && len(r.History) > 0 {
+		return errors.New("cell lock can only change before the first move")
+	}
+	r.Board.LockAfter = 0
+	if enabled {
+		r.Board.LockAfter = config.CellLockCaptures
+	}
+	r.Board.Rehash()
+	m.store.SaveRoom(r)
+	return nil
+}
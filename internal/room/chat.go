@@ -0,0 +1,98 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ChatModerator inspects a chat message before it is posted. It may rewrite
+// msg.Text (e.g. to mask words) or return an error to reject the message.
+type ChatModerator func(r *shared.Room, msg *shared.ChatMessage) error
+
+// AddChatModerator registers a moderation hook; hooks run in the order added
+func (m *Manager) AddChatModerator(fn ChatModerator) {
+	m.moderators = append(m.moderators, fn)
+}
+
+// PostChat validates a chat message from a seated player, runs the moderation
+// hooks, appends it to the room's chat log and broadcasts chat_message
+func (m *Manager) PostChat(room *shared.Room, playerID, text string) (shared.ChatMessage, error) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	p := findPlayer(r, playerID)
+	if p == nil {
+		return shared.ChatMessage{}, errors.New("player not in room")
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return shared.ChatMessage{}, errors.New("message is empty")
+	}
+	if utf8.RuneCountInString(text) > config.ChatMaxLength {
+		return shared.ChatMessage{}, errors.New("message is too long")
+	}
+
+	now := time.Now()
+	if !allowChat(r, playerID, now) {
+		return shared.ChatMessage{}, errors.New("sending messages too fast")
+	}
+
+	msg := shared.ChatMessage{
+		PlayerID: playerID,
+		Name:     p.Name,
+		Text:     text,
+		At:       now,
+	}
+	for _, moderate := range m.moderators {
+		if err := moderate(r, &msg); err != nil {
+			return shared.ChatMessage{}, err
+		}
+	}
+
+	r.Chat = append(r.Chat, msg)
+	if len(r.Chat) > config.ChatLogSize {
+		r.Chat = r.Chat[len(r.Chat)-config.ChatLogSize:]
+	}
+	m.store.SaveRoom(r)
+
+	m.hub.Broadcast(r.Code, "chat_message", msg)
+	return msg, nil
+}
+
+// ChatLog returns a copy of the room's chat log, oldest first
+func (m *Manager) ChatLog(r *shared.Room) []shared.ChatMessage {
+	out := make([]shared.ChatMessage, len(r.Chat))
+	copy(out, r.Chat)
+	return out
+}
+
+// allowChat applies the per-player sliding window rate limit and records the
+// message time when it is allowed
+func allowChat(r *shared.Room, playerID string, now time.Time) bool {
+	if r.ChatSent == nil {
+		r.ChatSent = make(map[string][]time.Time)
+	}
+	return allowRate(r.ChatSent, playerID, now, config.ChatRateLimit, config.ChatRateWindow)
+}
+
+// allowRate allows at most limit sends per player within window, given the
+// times of their recent sends, and records now when the send is allowed
+func allowRate(sent map[string][]time.Time, playerID string, now time.Time, limit int, window time.Duration) bool {
+	recent := sent[playerID][:0]
+	for _, t := range sent[playerID] {
+		if now.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= limit {
+		sent[playerID] = recent
+		return false
+	}
+	sent[playerID] = append(recent, now)
+	return true
+}
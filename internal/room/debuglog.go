@@ -0,0 +1,28 @@
+package room
+
+import (
+	"fmt"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+	"log"
+)
+
+// logEvent writes a room event to the process log and keeps it in the
+// room's event log for the debug endpoint
+func (m *Manager) logEvent(r *shared.Room, kind, playerID, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	log.Print(msg)
+	m.events.Add(r.Code, kind, playerID, msg)
+}
+
+// noteEvent keeps a room event in the room's event log only; routine
+// events such as moves would flood the process log
+func (m *Manager) noteEvent(r *shared.Room, kind, playerID, format string, args ...interface{}) {
+	m.events.Add(r.Code, kind, playerID, fmt.Sprintf(format, args...))
+}
+
+// DebugEvents returns the room's recent events numbered after since, oldest
+// first, and whether some of them were already dropped
+func (m *Manager) DebugEvents(code string, since uint64) ([]roomlog.Entry, bool) {
+	return m.events.Since(code, since)
+}
@@ -0,0 +1,71 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+)
+
+// deckSpec returns the deck composition of a room. Shared deck rooms saved
+// before decks were configurable take their copies from r.SharedDeck.
+func deckSpec(r *shared.Room) config.DeckSpec {
+	spec := config.DefaultDeckSpec()
+	if r.RoomConfig != nil {
+		spec = r.RoomConfig.GetDeck()
+	}
+	if r.SharedDeck > 0 {
+		spec.Shared = true
+		spec.Copies = r.SharedDeck
+	}
+	return spec
+}
+
+// SetDeckSpec changes the cards a room plays with before the game starts:
+// how many copies of which values, and whether players share one deck.
+// The cards are dealt by StartGame.
+func (m *Manager) SetDeckSpec(room *shared.Room, spec config.DeckSpec) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	return m.applyDeckSpec(r, spec.WithDefaults())
+}
+
+// applyDeckSpec validates spec and makes it the room's deck
+func (m *Manager) applyDeckSpec(r *shared.Room, spec config.DeckSpec) error {
+	if r.Status != "lobby" {
+		return errors.New("deck can only change before the game starts")
+	}
+	if err := validateDeckSpec(spec, len(r.Players)); err != nil {
+		return err
+	}
+
+	if r.RoomConfig == nil {
+		r.RoomConfig = config.NewRoomConfig(r.Code)
+	}
+	r.RoomConfig.SetDeck(spec)
+	r.SharedDeck = 0
+	if spec.Shared {
+		r.SharedDeck = spec.Copies
+	}
+	r.Board.MaxCard = spec.MaxCard
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// validateDeckSpec checks that a deck composition is within limits and can
+// deal every seated player a hand
+func validateDeckSpec(spec config.DeckSpec, players int) error {
+	if spec.Copies < 1 || spec.Copies > config.MaxDeckCopies {
+		return fmt.Errorf("deck copies must be between 1 and %d", config.MaxDeckCopies)
+	}
+	if spec.MinCard < 1 || spec.MaxCard > config.MaxCardValue || spec.MinCard >= spec.MaxCard {
+		return fmt.Errorf("deck cards must satisfy 1 <= min_card < max_card <= %d", config.MaxCardValue)
+	}
+	if spec.Shared && spec.Size() < handSize*max(players, 1) {
+		return errors.New("shared deck is too small to deal every hand")
+	}
+	if !spec.Shared && spec.Size() < handSize {
+		return errors.New("deck is too small to deal a hand")
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+)
+
+// SetDeckRule chooses what happens when a player's deck runs out. An empty
+// rule keeps the default of playing on with the remaining hand.
+func (m *Manager) SetDeckRule(room *shared.Room, rule string) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	switch rule {
+	case "", shared.DeckRuleContinue, shared.DeckRuleCommunal, shared.DeckRuleEndgame:
+		r.DeckRule = rule
+		m.store.SaveRoom(r)
+		return nil
+	}
+	return errors.New("deck_rule must be continue, communal or endgame")
+}
+
+// dealHands gives every player a fresh personal deck from the room's deck
+// composition and deals their opening hand from it
+func dealHands(r *shared.Room) {
+	spec := deckSpec(r)
+	for i := range r.Players {
+		deck := GenerateDeck(spec, roomRand(r))
+		r.Players[i].Hand = deck[:handSize]
+		r.Players[i].Deck = deck[handSize:]
+	}
+}
+
+// prepareDecks sets up the cards for a new game. Shared deck rooms deal every
+// hand from one deck that becomes the draw pile; communal rule rooms get a
+// fresh pile next to the personal decks.
+func prepareDecks(r *shared.Room) {
+	r.CommunalPile = nil
+	switch {
+	case r.SharedDeck > 0:
+		deck := GenerateDeck(deckSpec(r), roomRand(r))
+		for i := range r.Players {
+			r.Players[i].Hand = append([]int(nil), deck[:3]...)
+			r.Players[i].Deck = nil
+			deck = deck[3:]
+		}
+		r.CommunalPile = deck
+	case r.DeckRule == shared.DeckRuleCommunal:
+		r.CommunalPile = GenerateDeck(deckSpec(r), roomRand(r))
+	}
+}
+
+// usesPile reports whether players draw from the room's pile
+func usesPile(r *shared.Room) bool {
+	return r.SharedDeck > 0 || r.DeckRule == shared.DeckRuleCommunal
+}
+
+// cardsLeft is how many cards the player can still draw: the shared deck in
+// shared deck rooms, otherwise their own deck
+func cardsLeft(r *shared.Room, p *shared.Player) int {
+	if r.SharedDeck > 0 {
+		return len(r.CommunalPile)
+	}
+	return len(p.Deck)
+}
+
+// drawCard moves the next card into the player's hand from their deck, or
+// from the pile once the deck is empty. Returns 0 when nothing could be drawn.
+func drawCard(r *shared.Room, p *shared.Player) (card int, fromPile bool) {
+	switch {
+	case len(p.Deck) > 0:
+		card = p.Deck[0]
+		p.Deck = p.Deck[1:]
+	case usesPile(r) && len(r.CommunalPile) > 0:
+		card = r.CommunalPile[0]
+		r.CommunalPile = r.CommunalPile[1:]
+		fromPile = true
+	default:
+		return 0, false
+	}
+
+	p.Hand = append(p.Hand, card)
+	return card, fromPile
+}
+
+// finalScoringMove picks the candidate that leaves the bot furthest ahead
+// on the tie-break scores, for a move after which the game is scored.
+// An immediate win always comes first.
+func finalScoringMove(r *shared.Room, botID string, cands []game.Move) *game.Move {
+	var best *game.Move
+	bestMargin := 0
+	eng := engineFor(r)
+
+	for i := range cands {
+		mv := cands[i]
+		b := r.Board.Clone()
+		eng.Apply(&b, game.Move{X: mv.X, Y: mv.Y, Card: mv.Card, PlayerID: botID})
+		if eng.Winner(&b, mv.X, mv.Y, botID) != nil {
+			return &cands[i]
+		}
+
+		line, total := game.TieBreakerLineSum(b, botID), game.TotalOwnedSum(b, botID)
+		oppLine, oppTotal := 0, 0
+		for _, p := range r.Players {
+			if p.ID == botID || p.Resigned {
+				continue
+			}
+			if l := game.TieBreakerLineSum(b, p.ID); l > oppLine {
+				oppLine = l
+			}
+			if t := game.TotalOwnedSum(b, p.ID); t > oppTotal {
+				oppTotal = t
+			}
+		}
+
+		// Line sum decides first; total owned sum only breaks ties
+		margin := (line-oppLine)*1000 + (total - oppTotal)
+		if best == nil || margin > bestMargin {
+			best = &cands[i]
+			bestMargin = margin
+		}
+	}
+	return best
+}
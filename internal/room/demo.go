@@ -0,0 +1,37 @@
+package room
+
+import (
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+	"time"
+)
+
+// CreateDemoRoom creates and starts a game between bots for spectators, such
+// as the live game on the landing page. The bots pause for delay before each
+// move so the game can be followed; the caller sets them playing with
+// Hub.ResumeBots.
+func (m *Manager) CreateDemoRoom(bots int, delay time.Duration) (*shared.Room, error) {
+	if bots < config.MinPlayers || bots > config.MaxPlayers {
+		return nil, fmt.Errorf("a demo needs %d to %d bots", config.MinPlayers, config.MaxPlayers)
+	}
+	if delay < config.MinDemoDelay || delay > config.MaxDemoDelay {
+		return nil, fmt.Errorf("demo delay must be between %v and %v", config.MinDemoDelay, config.MaxDemoDelay)
+	}
+
+	code := randCode(6)
+	for _, exists := m.store.GetRoom(code); exists; _, exists = m.store.GetRoom(code) {
+		code = randCode(6)
+	}
+
+	r, err := m.createBotRoom(code, bots)
+	if err != nil {
+		return nil, err
+	}
+	r.Demo = true
+	r.RoomConfig.SetBotDelay(delay)
+	m.store.SaveRoom(r)
+
+	m.StartGame(r)
+	return r, nil
+}
@@ -0,0 +1,65 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/engine"
+	"javanese-chess/internal/shared"
+	"log"
+)
+
+// SetEngine chooses the rules engine a room plays by. It can only change
+// before the first move, since positions are not portable between engines.
+func (m *Manager) SetEngine(room *shared.Room, name string) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.Status == "playing" && len(r.History) > 0 {
+		return errors.New("engine can only change before the first move")
+	}
+	e, err := engine.Get(name)
+	if err != nil {
+		return err
+	}
+
+	prev := r.Board
+	r.Engine = e.Name()
+	r.Board = e.NewGame(r.Board.Size)
+	r.Board.KeepRules(&prev)
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// engineFor returns the rules engine of a room. Rooms restored with an
+// engine that is no longer registered fall back to the default.
+func engineFor(r *shared.Room) engine.Engine {
+	e, err := engine.Get(r.Engine)
+	if err != nil {
+		log.Printf("Room %s: %v, using %s", r.Code, err, engine.Default)
+		return defaultEngine()
+	}
+	return e
+}
+
+// seatBoard hashes the board by the room's seating so positions compare
+// across games regardless of player IDs
+func seatBoard(r *shared.Room) {
+	seats := make([]string, len(r.Players))
+	for i, p := range r.Players {
+		seats[i] = p.ID
+	}
+	r.Board.SetSeats(seats)
+}
+
+// newGame replaces the room's game with the next one, on an empty board
+// that keeps the room's size and rules
+func newGame(r *shared.Room) {
+	board := engineFor(r).NewGame(r.Board.Size)
+	board.KeepRules(&r.Board)
+	r.Game = shared.NewGame(r.No+1, board)
+}
+
+// defaultEngine sets up new rooms until they choose an engine
+func defaultEngine() engine.Engine {
+	e, _ := engine.Get(engine.Default)
+	return e
+}
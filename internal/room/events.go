@@ -0,0 +1,69 @@
+package room
+
+import (
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/shared"
+	"log"
+	"time"
+)
+
+// Every change to a room is appended to its event log (see record.Event), so
+// the room can be rebuilt, replayed and audited by folding the log. Changes
+// that are not moves, such as a seat changing hands, are logged as
+// checkpoints of the whole room.
+
+// logState appends an event that carries the whole room
+func (m *Manager) logState(r *shared.Room, kind string) {
+	ev, err := record.StateEvent(kind, r)
+	if err != nil {
+		log.Printf("Warning: could not log %s of room %s: %v", kind, r.Code, err)
+		return
+	}
+	m.store.AppendEvents(r, ev)
+}
+
+// logJoins appends a join for each newly seated player. The turn order is
+// recorded with the last join, once everybody has been seated.
+func (m *Manager) logJoins(r *shared.Room, playerIDs ...string) {
+	events := make([]record.Event, 0, len(playerIDs))
+	for i, id := range playerIDs {
+		ev := record.JoinEvent(r, *findPlayer(r, id))
+		if i < len(playerIDs)-1 {
+			ev.TurnOrder = nil
+		}
+		events = append(events, ev)
+	}
+	m.store.AppendEvents(r, events...)
+}
+
+// logMove appends the room's latest move. Kind is a move, or the timeout
+// that forced a skip.
+func (m *Manager) logMove(r *shared.Room, kind string) {
+	rec := r.History[len(r.History)-1]
+	m.store.AppendEvents(r, record.Event{Kind: kind, At: rec.At, Move: &rec})
+}
+
+// logUndo appends the take back of every move after the first keep
+func (m *Manager) logUndo(r *shared.Room, keep int) {
+	m.store.AppendEvents(r, record.Event{Kind: record.EventUndo, At: time.Now(), Keep: keep})
+}
+
+// logResult appends how the current game ended
+func (m *Manager) logResult(r *shared.Room) {
+	m.store.AppendEvents(r, record.Event{Kind: record.EventResult, At: time.Now(), Outcome: record.OutcomeOf(r)})
+}
+
+// Events returns the room's event log, oldest event first
+func (m *Manager) Events(code string) []record.Event {
+	return m.store.Events(code)
+}
+
+// Rebuild folds the room's event log into a new copy of the room
+func (m *Manager) Rebuild(code string) (*shared.Room, error) {
+	r, err := record.Fold(m.store.Events(code))
+	if err != nil {
+		return nil, err
+	}
+	r.Cfg = m.cfg
+	return r, nil
+}
@@ -0,0 +1,36 @@
+package room
+
+import (
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+)
+
+// Experiments assigns new rooms to the arms of a running heuristic weight
+// experiment and knows each arm's weights
+type Experiments interface {
+	// Assign returns the arm for a new room; nil when no experiment runs
+	Assign() *shared.ArmAssignment
+	ArmWeights(a shared.ArmAssignment) (config.HeuristicWeights, bool)
+}
+
+// SetExperiments makes new rooms join the running weight experiment
+func (m *Manager) SetExperiments(x Experiments) {
+	m.experiments = x
+}
+
+// assignArm places a new room in an experiment arm, if one is running
+func (m *Manager) assignArm(r *shared.Room) {
+	if m.experiments == nil {
+		return
+	}
+	r.Experiment = m.experiments.Assign()
+}
+
+// armWeights returns the weights of the room's experiment arm. Rooms outside
+// any experiment, or whose experiment is gone, report false.
+func (m *Manager) armWeights(r *shared.Room) (config.HeuristicWeights, bool) {
+	if m.experiments == nil || r.Experiment == nil {
+		return config.HeuristicWeights{}, false
+	}
+	return m.experiments.ArmWeights(*r.Experiment)
+}
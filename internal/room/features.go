@@ -0,0 +1,71 @@
+package room
+
+import (
+	"errors"
+
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+)
+
+// SetFeatures sets the room's feature flags for experimental rules. Unknown
+// features are rejected. Flags can only change before the first move and
+// take effect when the game starts.
+func (m *Manager) SetFeatures(room *shared.Room, flags map[string]bool) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.Status == "playing" && len(r.History) > 0 {
+		return errors.New("features can only change before the first move")
+	}
+	if err := config.ValidateFeatures(flags); err != nil {
+		return err
+	}
+	if r.RoomConfig == nil {
+		r.RoomConfig = config.NewRoomConfig(r.Code)
+	}
+	r.RoomConfig.SetFeatures(flags)
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// CheckFeatures refuses to start a game with flags for features this server
+// does not know, such as a room restored from another build
+func (m *Manager) CheckFeatures(room *shared.Room) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.RoomConfig == nil {
+		return nil
+	}
+	return config.ValidateFeatures(r.RoomConfig.GetFeatures())
+}
+
+// applyFeatures sets the rules the board and the bots play by from the
+// room's feature flags. Features without a flag keep the room's settings.
+func applyFeatures(r *shared.Room) {
+	if r.RoomConfig == nil {
+		return
+	}
+	for name, on := range r.RoomConfig.GetFeatures() {
+		switch name {
+		case config.FeaturePermanentNine:
+			r.Board.CoverTop = !on
+		case config.FeatureOwnOverwrite:
+			r.Board.OwnOverwrite = on
+		case config.FeatureLastMoveAdjacency:
+			r.Board.NextToLast = on
+		case config.FeatureHoldAction:
+			r.Hold = on
+		}
+	}
+}
+
+// featuresOf reports every feature as the room plays it
+func featuresOf(r *shared.Room) map[string]bool {
+	return map[string]bool{
+		config.FeaturePermanentNine:     !r.Board.CoverTop,
+		config.FeatureOwnOverwrite:      r.Board.OwnOverwrite,
+		config.FeatureLastMoveAdjacency: r.Board.NextToLast,
+		config.FeatureHoldAction:        r.Hold,
+	}
+}
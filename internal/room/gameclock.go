@@ -0,0 +1,97 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetGameClock gives every player initialSeconds for the whole game, with
+// incrementSeconds added after each of their moves. The clock of the player
+// to move runs down during their turn and they lose when it reaches zero.
+// The game clock replaces a time bank.
+func (m *Manager) SetGameClock(room *shared.Room, initialSeconds, incrementSeconds int) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if initialSeconds <= 0 {
+		return errors.New("clock_seconds must be positive")
+	}
+	if incrementSeconds < 0 {
+		return errors.New("clock_inc must be non-negative")
+	}
+
+	r.GameClock = &shared.GameClockRule{InitialSeconds: initialSeconds, IncrementSeconds: incrementSeconds}
+	r.TimeBank = nil
+	for i := range r.Players {
+		r.Players[i].TimeBankMs = 0
+	}
+	resetGameClock(r)
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// resetGameClock sets every player's clock to the full game time
+func resetGameClock(r *shared.Room) {
+	if r.GameClock == nil {
+		return
+	}
+	for i := range r.Players {
+		r.Players[i].ClockMs = int64(r.GameClock.InitialSeconds) * 1000
+	}
+}
+
+// settleGameClock charges the turn to the current player's clock and adds
+// the increment if they moved in time
+func settleGameClock(r *shared.Room, now time.Time) {
+	p := &r.Players[r.TurnIdx]
+	p.ClockMs -= now.Sub(r.TurnStartedAt).Milliseconds()
+	if p.ClockMs <= 0 {
+		p.ClockMs = 0
+		return
+	}
+	p.ClockMs += int64(r.GameClock.IncrementSeconds) * 1000
+}
+
+// flagFall ends the game for a player whose clock ran out: they resign, and
+// the last player left wins. Stale timers (the turn already moved on) are
+// ignored.
+func (m *Manager) flagFall(code, playerID string, startedAt time.Time) {
+	r, unlock := m.lockCode(code)
+	defer unlock()
+	if r == nil || r.Over() || !r.TurnStartedAt.Equal(startedAt) || r.Players[r.TurnIdx].ID != playerID {
+		return
+	}
+
+	m.logEvent(r, roomlog.KindClock, playerID, "Player %s lost on time in room %s", playerID, r.Code)
+	r.Players[r.TurnIdx].ClockMs = 0
+	m.hub.Broadcast(r.Code, "flag_fall", gin.H{
+		"player_id": playerID,
+	})
+	if err := m.resign(r, playerID); err != nil {
+		m.logEvent(r, roomlog.KindError, playerID, "Flag fall in room %s: %v", r.Code, err)
+		return
+	}
+	if r.WinnerID == nil {
+		m.hub.ResumeBots(r.Code)
+	}
+}
+
+// gameClockView describes the game clock for broadcasts. Clocks are as of
+// the start of the turn; the player to move runs out at the deadline.
+func gameClockView(r *shared.Room) gin.H {
+	clocks := make(map[string]int64, len(r.Players))
+	for _, p := range r.Players {
+		clocks[p.ID] = p.ClockMs
+	}
+	return gin.H{
+		"initial_seconds":   r.GameClock.InitialSeconds,
+		"increment_seconds": r.GameClock.IncrementSeconds,
+		"turn_started_at":   r.TurnStartedAt,
+		"deadline":          turnDeadline(r),
+		"clock_ms":          clocks,
+	}
+}
@@ -0,0 +1,134 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetManual marks a room as a manual testing room, where clients may set
+// the dealt hands. Manual rooms cannot be ranked and do not count towards
+// ratings.
+func (m *Manager) SetManual(room *shared.Room, manual bool) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if manual && r.Ranked {
+		return errors.New("manual rooms cannot be ranked")
+	}
+	r.Manual = manual
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// SetHands replaces dealt hands in a manual room before the first move.
+// hands maps player IDs to their new hand; players left out keep theirs.
+// Every hand holds handSize cards of the room's deck and no value may be
+// dealt more often than the deck holds: per player with personal decks,
+// across all hands with a shared deck. The cards left over are shuffled
+// into the player's deck, or the shared pile.
+func (m *Manager) SetHands(room *shared.Room, hands map[string][]int) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if !r.Manual {
+		return errors.New("hands can only be set in manual rooms")
+	}
+	if r.Status != "playing" {
+		return errors.New("game has not started")
+	}
+	if len(r.History) > 0 {
+		return errors.New("hands can only be set before the first move")
+	}
+	if len(hands) == 0 {
+		return errors.New("hands are required")
+	}
+
+	spec := deckSpec(r)
+	for id, hand := range hands {
+		if findPlayer(r, id) == nil {
+			return errors.New("player not in room")
+		}
+		if len(hand) != handSize {
+			return fmt.Errorf("a hand holds %d cards", handSize)
+		}
+		for _, card := range hand {
+			if card < spec.MinCard || card > spec.MaxCard {
+				return fmt.Errorf("hand cards must be between %d and %d", spec.MinCard, spec.MaxCard)
+			}
+		}
+	}
+
+	if r.SharedDeck > 0 {
+		dealt := make([][]int, 0, len(r.Players))
+		for _, p := range r.Players {
+			hand, ok := hands[p.ID]
+			if !ok {
+				hand = p.Hand
+			}
+			dealt = append(dealt, hand)
+		}
+		pile, err := undealt(r, spec, dealt...)
+		if err != nil {
+			return err
+		}
+		for i := range r.Players {
+			r.Players[i].Hand = append([]int(nil), dealt[i]...)
+		}
+		r.CommunalPile = pile
+	} else {
+		decks := make(map[string][]int, len(hands))
+		for id, hand := range hands {
+			deck, err := undealt(r, spec, hand)
+			if err != nil {
+				return err
+			}
+			decks[id] = deck
+		}
+		for i := range r.Players {
+			p := &r.Players[i]
+			if hand, ok := hands[p.ID]; ok {
+				p.Hand, p.Deck = append([]int(nil), hand...), decks[p.ID]
+			}
+		}
+	}
+
+	m.logState(r, record.EventCheckpoint)
+	m.store.SaveRoom(r)
+	m.hub.Broadcast(r.Code, "hands_set", gin.H{
+		"room_code": r.Code,
+		"players":   r.PlayerView(),
+	})
+	m.SyncHands(r)
+	return nil
+}
+
+// undealt returns the cards of one deck of spec left after the hands are
+// taken out, shuffled with the room's random source. It fails when a hand
+// asks for more copies of a value than the deck holds.
+func undealt(r *shared.Room, spec config.DeckSpec, hands ...[]int) ([]int, error) {
+	taken := map[int]int{}
+	for _, hand := range hands {
+		for _, card := range hand {
+			if taken[card]++; taken[card] > spec.Copies {
+				return nil, fmt.Errorf("card %d is dealt more than the %d time(s) the deck holds it", card, spec.Copies)
+			}
+		}
+	}
+
+	rest := make([]int, 0, spec.Size())
+	for v := spec.MinCard; v <= spec.MaxCard; v++ {
+		for n := taken[v]; n < spec.Copies; n++ {
+			rest = append(rest, v)
+		}
+	}
+	rng := roomRand(r)
+	rng.Shuffle(len(rest), func(i, j int) {
+		rest[i], rest[j] = rest[j], rest[i]
+	})
+	return rest, nil
+}
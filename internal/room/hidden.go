@@ -0,0 +1,25 @@
+package room
+
+import (
+	"javanese-chess/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyncHands privately sends every human player their current hand when the
+// room's broadcasts do not show it in full. It is a no-op for open rooms.
+func (m *Manager) SyncHands(r *shared.Room) {
+	if !r.Policy.PrivateHands() {
+		return
+	}
+
+	for _, p := range r.Players {
+		if p.IsBot {
+			continue
+		}
+		m.hub.SendToPlayer(r.Code, p.ID, "hand_update", gin.H{
+			"hand":       p.Hand,
+			"deck_count": len(p.Deck),
+		})
+	}
+}
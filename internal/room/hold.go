@@ -0,0 +1,37 @@
+package room
+
+import (
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+)
+
+// SetHold lets the room's bots hold a card, putting it under their deck and
+// drawing the top card, when no placement is worth making. Humans can always
+// hold with a swap.
+func (m *Manager) SetHold(room *shared.Room, enabled bool) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	r.Hold = enabled
+	m.store.SaveRoom(r)
+}
+
+// botHold returns the card a bot should hold instead of placing, if any. Bots
+// only hold in hold rooms, with cards left to draw, after scoring every
+// placement, and never twice in a row, so the game keeps moving.
+func botHold(r *shared.Room, cp *shared.Player, scored []game.ScoredMove, complete bool, holdBelow int) (int, bool) {
+	if !r.Hold || !complete || cardsLeft(r, cp) == 0 || heldLastTurn(r, cp.ID) {
+		return 0, false
+	}
+	return game.ChooseHold(cp.Hand, scored, holdBelow)
+}
+
+// heldLastTurn reports whether the player's last move this game was a hold
+func heldLastTurn(r *shared.Room, playerID string) bool {
+	for i := len(r.History) - 1; i >= 0; i-- {
+		if r.History[i].PlayerID == playerID {
+			return r.History[i].Type == game.MoveSwap
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package room
+
+import "javanese-chess/internal/shared"
+
+// GameOverHook is told about every game that ends, whether it was won, drawn
+// or aborted. Hooks run under the room's lock with the final state of the
+// room, so they must not call back into the manager for the same room; copy
+// what is needed and do further work in a goroutine.
+type GameOverHook func(r *shared.Room)
+
+// OnGameOver registers a hook; hooks run in the order added. Register hooks
+// before the server starts serving.
+func (m *Manager) OnGameOver(fn GameOverHook) {
+	m.gameOverHooks = append(m.gameOverHooks, fn)
+}
+
+// runGameOverHooks hands the finished room to every hook
+func (m *Manager) runGameOverHooks(r *shared.Room) {
+	for _, hook := range m.gameOverHooks {
+		hook(r)
+	}
+}
@@ -0,0 +1,22 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/i18n"
+	"javanese-chess/internal/shared"
+)
+
+// SetLocale sets the language the room's errors are reported in to clients
+// that do not ask for one. An empty locale keeps the server default.
+func (m *Manager) SetLocale(room *shared.Room, locale string) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	locale = i18n.Normalize(locale)
+	if locale != "" && !i18n.Supported(locale) {
+		return errors.New("locale must be en or id")
+	}
+	r.Locale = locale
+	m.store.SaveRoom(r)
+	return nil
+}
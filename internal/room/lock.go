@@ -0,0 +1,55 @@
+package room
+
+import (
+	"javanese-chess/internal/shared"
+	"sync"
+)
+
+// Changes to a room are serialized by a lock per room code. Every exported
+// method that changes a room takes the lock by code first and only then loads
+// the room, so the read, validate, change and save sequence always starts
+// from the latest saved state: two moves submitted at the same time are
+// applied one after the other instead of overwriting each other. Callers
+// change rooms only through these methods, never by editing their copy.
+// Unexported helpers expect the caller to hold the lock.
+
+// lockRoom takes the lock of the room's code and loads the room. The
+// returned func releases the lock and hands the changed room back to the
+// caller's copy, which may have been loaded before the lock was taken. A
+// room that was never saved is changed in place.
+func (m *Manager) lockRoom(room *shared.Room) (*shared.Room, func()) {
+	r, unlock := m.lockCode(room.Code)
+	if r == nil {
+		return room, unlock
+	}
+	return r, func() {
+		*room = *r
+		unlock()
+	}
+}
+
+// lockCode takes the lock of the room with the given code and fetches the
+// room. The room is nil when it does not exist; the lock is held either way.
+func (m *Manager) lockCode(code string) (*shared.Room, func()) {
+	for {
+		v, _ := m.locks.LoadOrStore(code, &sync.Mutex{})
+		mu := v.(*sync.Mutex)
+		mu.Lock()
+
+		// The lock of a closed room is dropped while held; whoever was
+		// waiting for it takes the room's current lock instead
+		if cur, ok := m.locks.Load(code); ok && cur == v {
+			r, _ := m.store.GetRoom(code)
+			return r, mu.Unlock
+		}
+		mu.Unlock()
+	}
+}
+
+// dropLock forgets the lock of a room that has closed for good, so the
+// manager does not keep a lock for every room it ever served. The caller
+// holds the lock and defers dropLock after its unlock, so the lock is
+// forgotten just before it is released.
+func (m *Manager) dropLock(code string) {
+	m.locks.Delete(code)
+}
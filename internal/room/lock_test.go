@@ -0,0 +1,199 @@
+package room
+
+import (
+	"context"
+	"io"
+	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/shared"
+	"javanese-chess/internal/store"
+	"log"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// newTestManager returns a manager on a fresh memory store, wired to a hub
+// without connections, whose bots move without thinking time
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	log.SetOutput(io.Discard)
+	t.Cleanup(func() { log.SetOutput(io.Discard) })
+
+	cfg := *config.Get()
+	cfg.JWTSecret = "test"
+	m := NewManager(store.NewMemoryStore(), cfg, nil)
+	m.SetHub(ws.NewHub(m))
+	m.SetBotDelay(0)
+	return m
+}
+
+// startBotGame starts a game between one human and one bot and returns the
+// room with the human's and the bot's player IDs
+func startBotGame(t *testing.T, m *Manager, code string) (*shared.Room, string, string) {
+	t.Helper()
+	r, err := m.CreateLobbyRoom(code, "host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddBots(r, 1); err != nil {
+		t.Fatal(err)
+	}
+	m.StartGame(r)
+
+	var human, bot string
+	for _, p := range r.Players {
+		if p.IsBot {
+			bot = p.ID
+		} else {
+			human = p.ID
+		}
+	}
+	return r, human, bot
+}
+
+// checkConsistent fails unless the stored room adds up: the board matches a
+// replay of the history, every move was played on its player's turn, and no
+// card was lost or dealt twice
+func checkConsistent(t *testing.T, m *Manager, code string, dealt map[string]int) {
+	t.Helper()
+	r, ok := m.Get(code)
+	if !ok {
+		t.Fatalf("room %s is gone", code)
+	}
+	if err := record.Validate(r); err != nil {
+		t.Fatalf("room does not add up: %v", err)
+	}
+
+	placed := map[string]int{}
+	for i, rec := range r.History {
+		if r.Players[rec.TurnIdx].ID != rec.PlayerID {
+			t.Errorf("move %d by %s was played on the turn of %s", i+1, rec.PlayerID, r.Players[rec.TurnIdx].ID)
+		}
+		if rec.Type.Normalize() == game.MovePlace {
+			placed[rec.PlayerID]++
+		}
+	}
+	for _, p := range r.Players {
+		if got := len(p.Hand) + len(p.Deck) + placed[p.ID]; got != dealt[p.ID] {
+			t.Errorf("player %s accounts for %d cards, was dealt %d", p.ID, got, dealt[p.ID])
+		}
+	}
+}
+
+// dealtCards counts the cards each player holds right after the deal
+func dealtCards(r *shared.Room) map[string]int {
+	dealt := map[string]int{}
+	for _, p := range r.Players {
+		dealt[p.ID] = len(p.Hand) + len(p.Deck)
+	}
+	return dealt
+}
+
+// Moves submitted at the same time from copies of the same room are applied
+// one after the other: only the first finds it is its turn
+func TestConcurrentMovesApplyOnce(t *testing.T) {
+	m := newTestManager(t)
+	r, human, bot := startBotGame(t, m, "LOCKONE")
+	dealt := dealtCards(r)
+
+	// Make it the human's turn
+	if r.Players[r.TurnIdx].ID != human {
+		if _, err := m.BotMove(context.Background(), r, bot); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var hand []int
+	for _, p := range r.Players {
+		if p.ID == human {
+			hand = p.Hand
+		}
+	}
+	moves := game.GenerateLegalMoves(&r.Board, hand, human)
+	if len(moves) == 0 {
+		t.Fatal("human has no legal move")
+	}
+	mv := moves[0]
+	before := len(r.History)
+
+	const submitters = 16
+	var wg sync.WaitGroup
+	errs := make(chan error, submitters)
+	for i := 0; i < submitters; i++ {
+		stale, _ := m.Get(r.Code)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- m.ApplyMove(context.Background(), stale, human, mv.X, mv.Y, mv.Card)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	applied := 0
+	for err := range errs {
+		if err == nil {
+			applied++
+		}
+	}
+	if applied != 1 {
+		t.Fatalf("%d of %d identical moves were applied, want 1", applied, submitters)
+	}
+
+	after, _ := m.Get(r.Code)
+	if len(after.History) != before+1 {
+		t.Fatalf("history grew by %d moves, want 1", len(after.History)-before)
+	}
+	checkConsistent(t, m, r.Code, dealt)
+}
+
+// Human moves, bot moves and undo requests racing on one room leave it
+// consistent. Run with -race to also catch unsynchronized access.
+func TestConcurrentMovesBotMovesAndUndos(t *testing.T) {
+	m := newTestManager(t)
+	r, human, bot := startBotGame(t, m, "LOCKMIX")
+	dealt := dealtCards(r)
+
+	const workers, rounds = 4, 25
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		rng := rand.New(rand.NewSource(int64(w)))
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				room, _ := m.Get(r.Code)
+				var hand []int
+				for _, p := range room.Players {
+					if p.ID == human {
+						hand = p.Hand
+					}
+				}
+				if moves := game.GenerateLegalMoves(&room.Board, hand, human); len(moves) > 0 {
+					mv := moves[rng.Intn(len(moves))]
+					m.ApplyMove(context.Background(), room, human, mv.X, mv.Y, mv.Card)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				room, _ := m.Get(r.Code)
+				m.BotMove(context.Background(), room, bot)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				room, _ := m.Get(r.Code)
+				m.RequestUndo(room, human)
+			}
+		}()
+	}
+	wg.Wait()
+
+	checkConsistent(t, m, r.Code, dealt)
+}
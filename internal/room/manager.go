@@ -1,31 +1,88 @@
 package room
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"javanese-chess/internal/api/ws"
 	"javanese-chess/internal/config"
 	"javanese-chess/internal/game"
+	"javanese-chess/internal/nn"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/roomlog"
 	"javanese-chess/internal/shared"
+	"javanese-chess/internal/tracing"
+	"javanese-chess/internal/training"
 	"log"
 	"math/rand"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// ErrRoomExists is returned when a room is created with a code in use
+var ErrRoomExists = errors.New("room already exists")
+
 type Manager struct {
-	store Store
-	cfg   config.Config
-	hub   *ws.Hub
+	store    Store
+	cfg      config.Config
+	hub      *ws.Hub
+	ratings  RatingStore
+	botDelay time.Duration      // Simulated thinking time before each bot move
+	evalPool *game.EvalPool     // Workers scoring bot moves, shared by all rooms
+	locks    sync.Map           // Room code -> *sync.Mutex, see lockRoom
+	training *training.Recorder // Training data export; nil when off
+
+	policy      nn.Model // Policy network of neural bots; nil plays the heuristic
+	policyBlend float64
+
+	experiments Experiments // Weight experiments new rooms join; nil when none
+
+	moderators    []ChatModerator
+	gameOverHooks []GameOverHook
+
+	events *roomlog.Log // Recent events of each room, see debuglog.go
 }
 
 func NewManager(s Store, cfg config.Config, hub *ws.Hub) *Manager {
-	return &Manager{store: s, cfg: cfg, hub: hub}
+	return &Manager{
+		store:    s,
+		cfg:      cfg,
+		hub:      hub,
+		botDelay: time.Second,
+		evalPool: game.NewEvalPool(cfg.BotWorkers),
+		events:   roomlog.New(config.RoomLogSize, config.RoomLogRooms),
+	}
+}
+
+// SetBotDelay changes the simulated thinking time before bot moves. Offline
+// tools set it to zero.
+func (m *Manager) SetBotDelay(d time.Duration) {
+	m.botDelay = d
+}
+
+// botDelayFor is the thinking time of a bot move in the room: drawn between
+// the room's shortest and longest delay when it sets them, otherwise the
+// manager's. The draw does not use the room's random source, so seeded
+// deals stay reproducible.
+func (m *Manager) botDelayFor(r *shared.Room) time.Duration {
+	if r.RoomConfig != nil {
+		lo, hi := r.RoomConfig.GetBotDelayRange()
+		if hi > lo {
+			return lo + time.Duration(rand.Int63n(int64(hi-lo)+1))
+		}
+		if lo > 0 {
+			return lo
+		}
+	}
+	return m.botDelay
 }
 
 func (m *Manager) SetHub(hub *ws.Hub) {
-	log.Printf("Setting Hub in Manager: %+v", hub)
+	log.Printf("Setting Hub in Manager")
 	m.hub = hub
 }
 
@@ -33,8 +90,7 @@ func (m *Manager) CreateRoom(creatorName string) *shared.Room {
 	code := randCode(6)
 	r := &shared.Room{
 		Code:       code,
-		Board:      game.NewBoard(m.cfg.BoardSize),
-		TurnIdx:    0,
+		Game:       shared.Game{Board: defaultEngine().NewGame(m.cfg.BoardSize)},
 		CreatedAt:  time.Now(),
 		Cfg:        m.cfg,
 		RoomConfig: config.NewRoomConfig(code),
@@ -44,61 +100,70 @@ func (m *Manager) CreateRoom(creatorName string) *shared.Room {
 				ID:    uuid.NewString(),
 				Name:  creatorName,
 				IsBot: false,
-				Hand:  []int{1, 2, 3},
 			},
 		},
 	}
 
-	// Set only center cell [4,4] to VState = CellBlocked (1) for first move
-	centerX, centerY := r.Board.Size/2, r.Board.Size/2
-	r.Board.Cells[centerY][centerX].VState = game.CellBlocked
-
-	// Define available colors
-	colors := []string{"red", "green", "blue", "purple"}
-
 	// Assign a color to the human player
-	r.Players[0].Color = colors[0]
+	r.Players[0].Color = config.DefaultPlayerColors[0]
+	r.MasterID = r.Players[0].ID
+	m.assignArm(r)
+
+	// Old flow rooms skip StartGame, so deal right away
+	dealHands(r)
+	syncTurnOrder(r)
 
+	m.logState(r, record.EventCreate)
 	m.store.SaveRoom(r)
+	m.noteEvent(r, roomlog.KindRoom, r.MasterID, "Room %s created by %s", r.Code, creatorName)
 	return r
 }
 
-// CreateLobbyRoom creates a room in lobby state (waiting for players)
-func (m *Manager) CreateLobbyRoom(roomCode string, roomMasterName string) *shared.Room {
-	// Generate deck and hand for room master
-	deck := GenerateDeck()
-	hand := deck[:3]
-	deck = deck[3:]
+// CreateLobbyRoom creates a room in lobby state (waiting for players). It
+// fails with ErrRoomExists when the code is taken; the check and the save
+// happen under the code's lock, so a live room is never replaced.
+func (m *Manager) CreateLobbyRoom(roomCode string, roomMasterName string) (*shared.Room, error) {
+	existing, unlock := m.lockCode(roomCode)
+	defer unlock()
+	if existing != nil {
+		return nil, ErrRoomExists
+	}
+
+	// Seed the room's random source; /api/play may replace it with a fixed seed
+	seed := time.Now().UnixNano()
+	src := shared.NewCountingSource(seed, 0)
+	rng := rand.New(src)
 
 	// Define available colors
 	colors := config.DefaultPlayerColors
 
 	r := &shared.Room{
 		Code:       roomCode,
-		Board:      game.NewBoard(m.cfg.BoardSize),
-		TurnIdx:    0,
+		Game:       shared.Game{Board: defaultEngine().NewGame(m.cfg.BoardSize)},
 		CreatedAt:  time.Now(),
 		Cfg:        m.cfg,
 		RoomConfig: config.NewRoomConfig(roomCode),
 		Status:     "lobby",
+		Seed:       seed,
+		Rand:       rng,
+		RandSource: src,
 		Players: []shared.Player{
 			{
 				ID:    uuid.NewString(),
 				Name:  roomMasterName,
 				IsBot: false,
-				Hand:  hand,
-				Deck:  deck,
 				Color: colors[0], // First player gets first color
 			},
 		},
 	}
+	r.MasterID = r.Players[0].ID
+	syncTurnOrder(r)
+	m.assignArm(r)
 
-	// Set only center cell [4,4] to VState = CellBlocked (1) for first move
-	centerX, centerY := r.Board.Size/2, r.Board.Size/2
-	r.Board.Cells[centerY][centerX].VState = game.CellBlocked
-
+	m.logState(r, record.EventCreate)
 	m.store.SaveRoom(r)
-	return r
+	m.noteEvent(r, roomlog.KindRoom, r.MasterID, "Lobby %s opened by %s", r.Code, roomMasterName)
+	return r, nil
 }
 
 func NewRoomWithID(roomID, creatorName string) *shared.Room {
@@ -110,10 +175,13 @@ func NewRoomWithID(roomID, creatorName string) *shared.Room {
 	defaultCfg := config.Get()
 
 	// Create a new board with the default configuration
-	board := game.NewBoard(defaultCfg.BoardSize)
+	board := defaultEngine().NewGame(defaultCfg.BoardSize)
 
 	// Generate and shuffle the deck for the first player
-	deck := GenerateDeck()
+	seed := time.Now().UnixNano()
+	src := shared.NewCountingSource(seed, 0)
+	rng := rand.New(src)
+	deck := GenerateDeck(config.DefaultDeckSpec(), rng)
 
 	// Draw the initial 3 cards
 	initialHand := deck[:3]
@@ -121,11 +189,13 @@ func NewRoomWithID(roomID, creatorName string) *shared.Room {
 
 	r := &shared.Room{
 		Code:       roomID, // Use the provided RoomID as the Code
-		Board:      board,
-		TurnIdx:    0,
+		Game:       shared.Game{Board: board},
 		CreatedAt:  time.Now(),
 		Cfg:        *defaultCfg,
 		RoomConfig: config.NewRoomConfig(roomID),
+		Seed:       seed,
+		Rand:       rng,
+		RandSource: src,
 		Players: []shared.Player{
 			{
 				ID:    uuid.NewString(),
@@ -137,173 +207,191 @@ func NewRoomWithID(roomID, creatorName string) *shared.Room {
 		},
 	}
 
-	// Set only center cell [4,4] to VState = CellBlocked (1) for first move
-	centerX, centerY := r.Board.Size/2, r.Board.Size/2
-	r.Board.Cells[centerY][centerX].VState = game.CellBlocked
-
 	r.Status = "playing" // Old flow: immediately playing
 
 	return r
 }
 
-// GenerateDeck creates a shuffled deck of 18 cards (two sets of 1-9)
-func GenerateDeck() []int {
-	deck := make([]int, 18)
-	for i := 0; i < 9; i++ {
-		deck[i] = i + 1
-		deck[i+9] = i + 1
+// GenerateDeck creates a shuffled deck holding spec.Copies of every card
+// from spec.MinCard to spec.MaxCard (two sets of 1-9 by default).
+// A nil rng falls back to a clock-seeded source.
+func GenerateDeck(spec config.DeckSpec, r *rand.Rand) []int {
+	deck := make([]int, 0, spec.Size())
+	for c := 0; c < spec.Copies; c++ {
+		for v := spec.MinCard; v <= spec.MaxCard; v++ {
+			deck = append(deck, v)
+		}
+	}
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
 	}
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	r.Shuffle(len(deck), func(i, j int) {
 		deck[i], deck[j] = deck[j], deck[i]
 	})
 	return deck
 }
 
+// freeColor returns the first player color nobody in the room uses yet
+func freeColor(r *shared.Room) string {
+	colors := config.DefaultPlayerColors
+	used := make(map[string]bool, len(r.Players))
+	for _, p := range r.Players {
+		used[p.Color] = true
+	}
+	for _, color := range colors {
+		if !used[color] {
+			return color
+		}
+	}
+	return colors[len(r.Players)%len(colors)]
+}
+
 func (m *Manager) CreateRoomWithID(roomID, playerName string) *shared.Room {
 	room := NewRoomWithID(roomID, playerName)
+	m.logState(room, record.EventCreate)
 	m.store.SaveRoom(room)
 	return room
 }
 
-func (m *Manager) JoinRoom(roomCode string, playerName string) (*shared.Room, error) {
+// JoinRoom seats a human in a lobby and returns the room and the new seat.
+// The seat, its name and its color are settled under the room's lock, so
+// players joining at the same time never share a seat or a color. A name
+// already used in the room gets a numeric suffix, e.g. "Ayu (2)". The room
+// is told about the new roster.
+func (m *Manager) JoinRoom(roomCode string, playerName string) (*shared.Room, shared.Player, error) {
 	// Get the room
-	r, ok := m.store.GetRoom(roomCode)
-	if !ok {
-		return nil, errors.New("room not found")
+	r, unlock := m.lockCode(roomCode)
+	defer unlock()
+	if r == nil {
+		return nil, shared.Player{}, errors.New("room not found")
 	}
 
-	// Check if game has already started using status field
-	if r.Status == "playing" {
-		return nil, errors.New("game has already started")
+	// Players can only join while the room is in the lobby
+	if r.Status != "lobby" {
+		return nil, shared.Player{}, errors.New("game has already started")
 	}
 
 	// Check max players (4 players max)
-	if len(r.Players) >= 4 {
-		return nil, errors.New("room is full")
+	if len(r.Players) >= config.MaxPlayers {
+		return nil, shared.Player{}, errors.New("room is full")
 	}
 
-	// Check if player name already exists
-	for _, p := range r.Players {
-		if p.Name == playerName {
-			return nil, errors.New("player name already exists in this room")
-		}
+	playerName = strings.TrimSpace(playerName)
+	if playerName == "" {
+		return nil, shared.Player{}, errors.New("player_name is required")
 	}
 
-	// Generate deck and hand for new player
-	deck := GenerateDeck()
-	hand := deck[:3]
-	deck = deck[3:]
-
-	// Assign color (use available colors)
-	colors := config.DefaultPlayerColors
-	usedColors := make(map[string]bool)
-	for _, p := range r.Players {
-		usedColors[p.Color] = true
-	}
-
-	playerColor := colors[0] // Default
-	for _, color := range colors {
-		if !usedColors[color] {
-			playerColor = color
-			break
-		}
-	}
-
-	// Add new player
+	// Add new player; cards are dealt when the game starts
 	newPlayer := shared.Player{
 		ID:    uuid.NewString(),
-		Name:  playerName,
+		Name:  seatName(r, playerName),
 		IsBot: false,
-		Hand:  hand,
-		Deck:  deck,
-		Color: playerColor,
+		Color: freeColor(r),
 	}
 
 	r.Players = append(r.Players, newPlayer)
+	r.TurnOrder = append(r.TurnOrder, newPlayer.ID)
 
-	// Reshuffle turn order to include new player fairly
-	// This ensures new joiners aren't always at the back
-	randGen := rand.New(rand.NewSource(time.Now().UnixNano()))
-	randGen.Shuffle(len(r.Players), func(i, j int) {
-		r.Players[i], r.Players[j] = r.Players[j], r.Players[i]
+	// Save updated room
+	m.logJoins(r, newPlayer.ID)
+	m.store.SaveRoom(r)
+	m.noteEvent(r, roomlog.KindSeat, newPlayer.ID, "%s joined room %s", newPlayer.Name, r.Code)
+
+	m.hub.Broadcast(r.Code, "new_player_joined", gin.H{
+		"player_id":   newPlayer.ID,
+		"player_name": newPlayer.Name,
+		"color":       newPlayer.Color,
+		"players":     r.PlayerView(),
 	})
+	m.hub.BroadcastLobby(r.Code)
 
-	// Update turn order based on reshuffled players
-	r.TurnOrder = make([]string, len(r.Players))
-	for i, player := range r.Players {
-		r.TurnOrder[i] = player.ID
+	return r, newPlayer, nil
+}
+
+// seatName returns name, suffixed with the first free number when another
+// player in the room already goes by it (ignoring case)
+func seatName(r *shared.Room, name string) string {
+	taken := func(candidate string) bool {
+		for _, p := range r.Players {
+			if strings.EqualFold(p.Name, candidate) {
+				return true
+			}
+		}
+		return false
 	}
+	seated := name
+	for n := 2; taken(seated); n++ {
+		seated = fmt.Sprintf("%s (%d)", name, n)
+	}
+	return seated
+}
 
-	// Save updated room
-	m.store.SaveRoom(r)
+// AddBots seats n bots. Personalities are assigned to the new bots in order;
+// bots without one play with the default weights.
+func (m *Manager) AddBots(room *shared.Room, n int, personalities ...string) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
 
-	return r, nil
-}
+	// Humans and bots share the same four seats
+	if len(r.Players)+n > config.MaxPlayers {
+		return fmt.Errorf("room has %d player(s); cannot add %d bot(s) (max %d players)", len(r.Players), n, config.MaxPlayers)
+	}
+	if len(personalities) > n {
+		return fmt.Errorf("%d personalities given for %d bot(s)", len(personalities), n)
+	}
+	for _, name := range personalities {
+		if _, ok := config.GetBotPersonality(name); name != "" && !ok {
+			return fmt.Errorf("unknown bot personality %q", name)
+		}
+	}
 
-func (m *Manager) AddBots(r *shared.Room, n int) {
-	// Use the DefaultPlayerColors from the config package
-	colors := config.DefaultPlayerColors
+	var joined []string
 
 	// Ensure the human player is included in the shuffle
 	if len(r.Players) == 0 {
-		// Generate a unique deck for the human player
-		deck := GenerateDeck()
-		hand := deck[:3]
-		deck = deck[3:]
-
 		r.Players = append(r.Players, shared.Player{
 			ID:    uuid.NewString(),
 			Name:  "Human Player",
 			IsBot: false,
-			Hand:  hand,
-			Deck:  deck,
-			Color: colors[0], // Assign the first color
-		})
-	}
-
-	for i := 0; i < n; i++ {
-		// Generate a unique deck for the bot
-		deck := GenerateDeck()
-		// Assign the first 3 cards to the bot's hand
-		hand := deck[:3]
-		deck = deck[3:]
-
-		r.Players = append(r.Players, shared.Player{
-			ID:    "bot-" + uuid.NewString(),
-			Name:  "Bot",
-			IsBot: true,
-			Hand:  hand,
-			Deck:  deck,
-			Color: colors[(len(r.Players))%len(colors)], // Assign colors in a round-robin fashion
+			Color: freeColor(r),
 		})
+		joined = append(joined, r.Players[0].ID)
 	}
 
-	// Ensure unique colors for up to 4 players
-	usedColors := make(map[string]bool)
-	for i := range r.Players {
-		for _, color := range colors {
-			if !usedColors[color] {
-				r.Players[i].Color = color
-				usedColors[color] = true
-				break
-			}
+	// Count existing bots so personas keep rotating across calls
+	botCount := 0
+	for _, p := range r.Players {
+		if p.IsBot {
+			botCount++
 		}
 	}
 
-	// Shuffle the players
-	randGen := rand.New(rand.NewSource(time.Now().UnixNano()))
-	randGen.Shuffle(len(r.Players), func(i, j int) {
-		r.Players[i], r.Players[j] = r.Players[j], r.Players[i]
-	})
+	for i := 0; i < n; i++ {
+		persona := personaFor(botCount + i)
+		personality := ""
+		if i < len(personalities) {
+			personality = personalities[i]
+		}
 
-	// Update turn order based on shuffled players
-	r.TurnOrder = make([]string, len(r.Players))
-	for i, player := range r.Players {
-		r.TurnOrder[i] = player.ID
+		bot := shared.Player{
+			ID:          "bot-" + uuid.NewString(),
+			Name:        seatName(r, persona.Name),
+			IsBot:       true,
+			Persona:     persona.Name,
+			Personality: personality,
+			Color:       freeColor(r),
+		}
+		r.Players = append(r.Players, bot)
+		joined = append(joined, bot.ID)
 	}
 
+	// Turn order is drawn when the game starts, by the room's turn rule
+	syncTurnOrder(r)
+
+	m.logJoins(r, joined...)
 	m.store.SaveRoom(r)
+	m.noteEvent(r, roomlog.KindSeat, "", "%d bot(s) seated in room %s", n, r.Code)
+	return nil
 }
 
 func (m *Manager) Get(code string) (*shared.Room, bool) {
@@ -317,17 +405,39 @@ func (m *Manager) currentPlayer(r *shared.Room) *shared.Player {
 	return &r.Players[r.TurnIdx%len(r.Players)]
 }
 
-func (m *Manager) ApplyMove(r *shared.Room, playerID string, x, y, card int) error {
+func (m *Manager) ApplyMove(ctx context.Context, room *shared.Room, playerID string, x, y, card int) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	return m.applyMove(ctx, r, playerID, x, y, card, nil)
+}
+
+// applyMove places a card for the current player. Bots pass how they chose
+// the move, which is kept in the history; humans pass nil.
+func (m *Manager) applyMove(ctx context.Context, r *shared.Room, playerID string, x, y, card int, bot *game.BotDecision) (err error) {
+	_, span := roomSpan(ctx, "room.apply_move", r, playerID, placeAttrs(x, y, card)...)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	// Check if game is already over
-	if r.WinnerID != nil {
+	if r.Over() {
 		return errors.New("game is already over")
 	}
+	if isClosed(r) {
+		return errors.New("room has ended")
+	}
 
 	cp := m.currentPlayer(r)
 	if cp == nil || cp.ID != playerID {
 		return errors.New("not your turn or player invalid")
 	}
 
+	// Reject off-board positions, echoing how the cell was interpreted
+	if err := r.Board.CheckCoord(game.Coord{X: x, Y: y}, ""); err != nil {
+		return err
+	}
+
 	// Check if card is in player's hand
 	cardInHand := false
 	for _, c := range cp.Hand {
@@ -337,99 +447,126 @@ func (m *Manager) ApplyMove(r *shared.Room, playerID string, x, y, card int) err
 		}
 	}
 	if !cardInHand {
-		log.Printf("ERROR: Card %d not in player's hand: %v", card, cp.Hand)
+		m.logEvent(r, roomlog.KindError, playerID, "ERROR: Card %d not in player's hand: %v", card, cp.Hand)
 		return errors.New("card not in hand")
 	}
 
 	// Ensure the move is legal
-	legalMoves := game.GenerateLegalMoves(&r.Board, cp.Hand, playerID)
-
-	// Debug: Check board state
-	totalCards := 0
-	for y := 0; y < r.Board.Size; y++ {
-		for x := 0; x < r.Board.Size; x++ {
-			if r.Board.Cells[y][x].Value != 0 {
-				totalCards++
-			}
-		}
-	}
-
-	log.Printf("=== MOVE VALIDATION DEBUG ===")
-	log.Printf("Player %s attempting move at (%d,%d) with card %d", playerID, x, y, card)
-	log.Printf("Board size: %d, Total cards on board: %d", r.Board.Size, totalCards)
-	log.Printf("Player hand: %v", cp.Hand)
-	log.Printf("Generated %d legal moves: %+v", len(legalMoves), legalMoves)
-	log.Printf("=============================")
-
-	legal := false
-	for _, mv := range legalMoves {
-		if mv.X == x && mv.Y == y && mv.Card == card {
-			legal = true
-			break
-		}
-	}
-	if !legal {
-		log.Printf("ERROR: Move (%d,%d) card %d is NOT in legal moves list!", x, y, card)
-		return errors.New("illegal move")
+	eng := engineFor(r)
+	if err := eng.Validate(&r.Board, game.Move{X: x, Y: y, Card: card, PlayerID: playerID}); err != nil {
+		m.logEvent(r, roomlog.KindError, playerID, "ERROR: Move (%d,%d) card %d by %s rejected: %v", x, y, card, playerID, err)
+		return fmt.Errorf("illegal move: card %d at %s: %w", card, game.Coord{X: x, Y: y}, err)
+	}
+
+	m.recordPosition(r, cp, game.Move{X: x, Y: y, Card: card, PlayerID: playerID, Type: game.MovePlace})
+
+	// Keep a reversible record of the move for undo
+	now := time.Now()
+	rec := game.MoveRecord{
+		Type:     game.MovePlace,
+		X:        x,
+		Y:        y,
+		Card:     card,
+		PlayerID: playerID,
+		PrevCell: r.Board.At(game.Coord{X: x, Y: y}),
+		TurnIdx:  r.TurnIdx,
+		At:       now,
+		ThinkMs:  now.Sub(turnStart(r)).Milliseconds(),
+		Block:    game.BlocksThreat(&r.Board, x, y, playerID),
+		Bot:      bot,
 	}
 
 	// Apply the move to the board
-	game.ApplyMove(&r.Board, x, y, playerID, card)
+	eng.Apply(&r.Board, game.Move{X: x, Y: y, Card: card, PlayerID: playerID})
+	rec.Hash = r.Board.Hash
+	cp.TallyCapture(rec, 1)
+	cp.TallyStats(rec, 1)
 
 	// Remove the card from the player's hand
 	for i, v := range cp.Hand {
 		if v == card {
+			rec.HandIdx = i
 			cp.Hand = append(cp.Hand[:i], cp.Hand[i+1:]...)
 			break
 		}
 	}
-	game.UpdateVState(&r.Board)
 
 	// Draw a new card from the deck
-	var drawnCard int
-	if len(cp.Deck) > 0 {
-		drawnCard = cp.Deck[0]
-		cp.Hand = append(cp.Hand, drawnCard)
-		cp.Deck = cp.Deck[1:]
-	}
+	drawnCard, fromPile := drawCard(r, cp)
+	rec.DrawnCard = drawnCard
+	rec.FromPile = fromPile
+	cp.MissedTurns = 0
+	r.History = append(r.History, rec)
+	r.PendingUndo = nil
+	m.logMove(r, record.EventMove)
+	m.noteEvent(r, roomlog.KindMove, playerID, "Player %s played %d at %s in room %s", playerID, card, game.Coord{X: x, Y: y}, r.Code)
 
 	// Check for a winning move
-	if game.IsWinningAfter(r.Board, x, y, playerID, card) {
-		r.WinnerID = &playerID
-
-		// Save the room with winner set BEFORE broadcasting
-		m.store.SaveRoom(r)
-
-		// Broadcast game over
-		m.hub.Broadcast(r.Code, "game_over", gin.H{
-			"winner": playerID,
-			"board":  r.Board,
-		})
+	if line := eng.Winner(&r.Board, x, y, playerID); line != nil {
+		r.WinLine = winLine(r, line)
+		m.finishGame(r, &playerID)
 		return nil
 	}
 
 	// Update the turn index to the next player
-	r.TurnIdx = (r.TurnIdx + 1) % len(r.Players)
+	advanceTurn(r)
+	m.startTurnClock(r)
 
 	// Broadcast the updated game state
-	m.hub.Broadcast(r.Code, "move", gin.H{
+	payload := gin.H{
 		"playerID":  playerID,
 		"x":         x,
 		"y":         y,
 		"card":      card,
 		"board":     r.Board,
 		"nextTurn":  r.Players[r.TurnIdx].ID,
+		"hash":      r.Board.Hash,
 		"drawnCard": drawnCard,
-	})
+	}
+	if clock := clockView(r); clock != nil {
+		payload["clock"] = clock
+	}
+	if usesPile(r) {
+		payload["pileCount"] = len(r.CommunalPile)
+	}
+	m.hub.Broadcast(r.Code, "move", payload)
 
 	// Save the updated room state
 	m.store.SaveRoom(r)
+	m.SyncHands(r)
+	m.auditCards(r)
+
+	// Under the endgame deck rule an empty deck ends the game by scoring
+	if r.DeckRule == shared.DeckRuleEndgame && cardsLeft(r, cp) == 0 {
+		m.scoreGame(r)
+		return nil
+	}
+
+	// Pass over players who cannot move, ending the game if nobody can
+	m.skipStuckPlayers(r)
 	return nil
 }
 
-func (m *Manager) BotMove(r *shared.Room, botID string) (shared.Move, error) {
-	// Add 1 second delay to simulate thinking time
-	time.Sleep(1 * time.Second)
+func (m *Manager) BotMove(ctx context.Context, room *shared.Room, botID string) (mv shared.Move, err error) {
+	ctx, span := roomSpan(ctx, "room.bot_move", room, botID)
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
+	// Pause to simulate thinking time, telling clients the bot is thinking.
+	// Only this bot's goroutine waits; the room is not locked meanwhile and
+	// may change, so the turn is checked again once the lock is retaken.
+	delay, ok := m.announceThinking(room.Code, botID)
+	if !ok {
+		return shared.Move{}, errors.New("not bot's turn")
+	}
+	_, think := tracing.Start(ctx, "bot.think")
+	time.Sleep(delay)
+	think.End()
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	started := time.Now()
 
 	cp := m.currentPlayer(r)
 	if cp == nil || cp.ID != botID {
@@ -437,109 +574,178 @@ func (m *Manager) BotMove(r *shared.Room, botID string) (shared.Move, error) {
 	}
 
 	// Generate all legal moves for the bot (FIX: Add & before r.Board)
-	cands := game.GenerateLegalMoves(&r.Board, cp.Hand, botID)
+	cands := engineFor(r).LegalMoves(&r.Board, cp.Hand, botID)
 	if len(cands) == 0 {
 		return shared.Move{}, errors.New("no legal moves available")
 	}
 
-	// Find the best move using the new heuristic evaluation
-	var bestMove *game.Move
-	bestScore := -1
+	// Known openings are played from the book without evaluation
+	if mv := bookMove(r, cp); mv != nil {
+		decision := &game.BotDecision{Candidates: len(cands), Book: true}
+		decision.LatencyMs = float64(time.Since(started).Microseconds()) / 1000
+		span.SetAttributes(placeAttrs(mv.X, mv.Y, mv.Card)...)
+		if err := m.applyMove(ctx, r, botID, mv.X, mv.Y, mv.Card, decision); err != nil {
+			return shared.Move{}, err
+		}
+		return shared.Move{X: mv.X, Y: mv.Y, Card: mv.Card, PlayerID: botID}, nil
+	}
+
+	// Score every candidate with the heuristic evaluation, in parallel and
+	// within the room's time budget, reusing scores of positions evaluated
+	// before under the same weights. The room is locked, so the board holds
+	// still while the workers read it.
+	_, search := tracing.Start(ctx, "bot.search", tracing.Int("bot.candidates", len(cands)))
+	defer search.End()
+	cfg := m.botConfig(r, cp)
+	scored, complete := m.evalPool.ScoreBoard(&r.Board, botID, game.WeightsKey(&cfg.DefaultWeights), cands, started.Add(botBudget(r)), func(candidate game.Move) int {
+		return game.EvaluateMove(&r.Board, candidate.X, candidate.Y, candidate.Card, botID, cfg)
+	})
+	// The penalty depends on the cards seen so far, which the position does not capture
+	for i := range scored {
+		scored[i].Score -= exposurePenalty(r, cp, scored[i].Move.Card)
+	}
+	if !complete {
+		m.logEvent(r, roomlog.KindBot, botID, "Bot %s in room %s ran out of time after scoring %d of %d moves", botID, r.Code, len(scored), len(cands))
+	}
 
-	for _, candidate := range cands {
-		// Use the new EvaluateMove function
-		score := game.EvaluateMove(&r.Board, candidate.X, candidate.Y, candidate.Card, botID, &m.cfg)
+	// Early in the game sample among near-best moves so bot games diverge
+	chosen, ok := game.SampleMove(scored, openingTemperature(r), roomRand(r))
+	if !ok {
+		return shared.Move{}, errors.New("could not find best move")
+	}
 
-		if score > bestScore {
-			bestScore = score
-			bestMove = &candidate
+	// Neural bots let the policy network choose among the scored moves
+	if pick, ok := m.networkMove(r, cp, scored, cfg.DefaultWeights.WWin); ok {
+		chosen = pick
+	}
+	bestMove := &chosen.Move
+
+	// Hold rooms let the bot hold its least useful card instead of a weak placement
+	if card, ok := botHold(r, cp, scored, complete, cfg.DefaultWeights.HoldBelow); ok {
+		span.SetAttributes(tracing.String("move.type", string(game.MoveSwap)), tracing.Int("move.card", card))
+		if err := m.swap(r, botID, card); err != nil {
+			return shared.Move{}, err
 		}
+		return shared.Move{Card: card, PlayerID: botID, Type: game.MoveSwap}, nil
 	}
 
-	if bestMove == nil {
-		return shared.Move{}, errors.New("could not find best move")
+	// Personas adapt their strength to the humans they are playing
+	bestMove = pickByDifficulty(roomRand(r), bestMove, cands, m.personaDifficulty(r, cp))
+
+	// When this move empties the deck under the endgame rule, play for the final score
+	if r.DeckRule == shared.DeckRuleEndgame && cardsLeft(r, cp) <= 1 {
+		bestMove = finalScoringMove(r, botID, cands)
+	}
+
+	// Report the score of the move actually played and the best alternative
+	bestScore := 0
+	decision := &game.BotDecision{Candidates: len(scored), OutOfTime: !complete}
+	for i, s := range scored {
+		if s.Move == *bestMove {
+			bestScore = s.Score
+			continue
+		}
+		if decision.RunnerUp == nil || s.Score > decision.RunnerUpScore {
+			decision.RunnerUp = &scored[i].Move
+			decision.RunnerUpScore = s.Score
+		}
 	}
+	decision.Score = bestScore
+	decision.LatencyMs = float64(time.Since(started).Microseconds()) / 1000
 
 	// Apply the best move
-	if err := m.ApplyMove(r, botID, bestMove.X, bestMove.Y, bestMove.Card); err != nil {
+	span.SetAttributes(placeAttrs(bestMove.X, bestMove.Y, bestMove.Card)...)
+	span.SetAttributes(tracing.Int("bot.score", bestScore))
+	if err := m.applyMove(ctx, r, botID, bestMove.X, bestMove.Y, bestMove.Card, decision); err != nil {
 		return shared.Move{}, err
 	}
 
-	game.UpdateVState(&r.Board)
-
 	return shared.Move{
 		X:        bestMove.X,
 		Y:        bestMove.Y,
 		Card:     bestMove.Card,
 		PlayerID: botID,
+		Score:    bestScore,
 	}, nil
 }
 
-func (m *Manager) CheckEndgame(r *shared.Room) {
-	// Check if there is already a winner
-	if r.WinnerID != nil {
-		return
+// announceThinking tells clients the bot is about to think, if it is the
+// bot's turn, and returns how long it thinks. The turn is read under the
+// room's lock so the announcement matches the room clients last saw.
+func (m *Manager) announceThinking(code, botID string) (time.Duration, bool) {
+	r, unlock := m.lockCode(code)
+	defer unlock()
+	if r == nil {
+		return 0, false
+	}
+	cp := m.currentPlayer(r)
+	if cp == nil || cp.ID != botID {
+		return 0, false
 	}
+	delay := m.botDelayFor(r)
+	m.hub.Broadcast(r.Code, "bot_thinking", gin.H{
+		"player_id": botID,
+		"think_ms":  delay.Milliseconds(),
+	})
+	return delay, true
+}
 
-	// Check if no moves are left for all players (FIX: Add & before r.Board)
-	noMovesLeft := true
+// CheckEndgame ends the game when no player can place a card any more. The
+// winner is decided by the non-instant rules: best line sum, then total owned
+// sum; leaders level on both draw.
+func (m *Manager) CheckEndgame(r *shared.Room) bool {
+	// Check if the game is already decided
+	if r.Over() {
+		return true
+	}
+
+	// Check if no moves are left for all active players
+	eng := engineFor(r)
 	for _, player := range r.Players {
-		if len(game.GenerateLegalMoves(&r.Board, player.Hand, player.ID)) > 0 {
-			noMovesLeft = false
-			break
+		if player.Resigned {
+			continue
+		}
+		if len(eng.LegalMoves(&r.Board, player.Hand, player.ID)) > 0 {
+			return false
 		}
 	}
 
-	if noMovesLeft {
-		// Determine the winner based on adjacent card values
-		m.determineWinnerByAdjacentValues(r)
-	}
+	return m.scoreGame(r)
 }
 
-func (m *Manager) determineWinnerByAdjacentValues(r *shared.Room) {
-	playerScores := make(map[string]int)
-
-	// Calculate scores for each player based on adjacent card values
-	for x := 0; x < r.Board.Size; x++ {
-		for y := 0; y < r.Board.Size; y++ {
-			cell := r.Board.Cells[x][y]
-			if cell.OwnerID != "" {
-				playerScores[cell.OwnerID] += m.calculateAdjacentCardValue(r.Board, x, y)
-			}
-		}
+// scoreGame ends the game on the non-instant rules. Returns false when there
+// is nobody left to rank.
+func (m *Manager) scoreGame(r *shared.Room) bool {
+	ranking := m.Rank(r)
+	if len(ranking) == 0 {
+		return false
 	}
 
-	// Find the player with the highest score
-	var winnerID string
-	highestScore := -1
-	for playerID, score := range playerScores {
-		if score > highestScore {
-			highestScore = score
-			winnerID = playerID
-		}
+	if len(ranking) > 1 && !ranking[0].ahead(ranking[1], r.CaptureTie) {
+		// Nothing separates the leaders
+		m.finishGame(r, nil)
+		return true
 	}
 
-	// Set the winner
-	if winnerID != "" {
-		r.WinnerID = &winnerID
-	}
+	winnerID := ranking[0].PlayerID
+	m.finishGame(r, &winnerID)
+	return true
 }
 
-func (m *Manager) calculateAdjacentCardValue(board game.Board, x, y int) int {
-	totalValue := 0
-	directions := []struct{ dx, dy int }{
-		{-1, 0}, {1, 0}, {0, -1}, {0, 1}, // Horizontal and vertical
-		{-1, -1}, {1, 1}, {-1, 1}, {1, -1}, // Diagonal
+// drawnLeaders returns the players sharing first place in a drawn game
+func drawnLeaders(r *shared.Room) []string {
+	if !r.Draw {
+		return nil
 	}
-
-	for _, dir := range directions {
-		nx, ny := x+dir.dx, y+dir.dy
-		if nx >= 0 && ny >= 0 && nx < board.Size && ny < board.Size {
-			totalValue += board.Cells[nx][ny].Value
+	ranking := rank(r)
+	var out []string
+	for _, row := range ranking {
+		if ranking[0].ahead(row, r.CaptureTie) {
+			break
 		}
+		out = append(out, row.PlayerID)
 	}
-
-	return totalValue
+	return out
 }
 
 const letters = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
@@ -554,23 +760,50 @@ func randCode(n int) string {
 }
 
 type RankRow struct {
-	PlayerID string `json:"playerId"`
-	LineSum  int    `json:"tieBreakerLineSum"`
-	TotalSum int    `json:"totalCellsSum"`
+	PlayerID      string `json:"playerId"`
+	LineSum       int    `json:"tieBreakerLineSum"`
+	TotalSum      int    `json:"totalCellsSum"`
+	Captures      int    `json:"captures"`
+	CapturedValue int    `json:"capturedValue"`
+}
+
+// ahead reports whether a ranks above b. Line sums decide, then total sums;
+// capture tie rooms then look at captures and captured value.
+func (a RankRow) ahead(b RankRow, captureTie bool) bool {
+	if a.LineSum != b.LineSum {
+		return a.LineSum > b.LineSum
+	}
+	if a.TotalSum != b.TotalSum || !captureTie {
+		return a.TotalSum > b.TotalSum
+	}
+	if a.Captures != b.Captures {
+		return a.Captures > b.Captures
+	}
+	return a.CapturedValue > b.CapturedValue
 }
 
 func (m *Manager) Rank(r *shared.Room) []RankRow {
+	return rank(r)
+}
+
+// rank orders the players still in the game by their tie-break scores
+func rank(r *shared.Room) []RankRow {
 	out := make([]RankRow, 0, len(r.Players))
 	for _, p := range r.Players {
+		if p.Resigned {
+			continue
+		}
 		out = append(out, RankRow{
-			PlayerID: p.ID,
-			LineSum:  game.TieBreakerLineSum(r.Board, p.ID),
-			TotalSum: game.TotalOwnedSum(r.Board, p.ID),
+			PlayerID:      p.ID,
+			LineSum:       game.TieBreakerLineSum(r.Board, p.ID),
+			TotalSum:      game.TotalOwnedSum(r.Board, p.ID),
+			Captures:      p.Captures,
+			CapturedValue: p.CapturedValue,
 		})
 	}
 	for i := 0; i < len(out); i++ {
 		for j := i + 1; j < len(out); j++ {
-			if out[j].LineSum > out[i].LineSum || (out[j].LineSum == out[i].LineSum && out[j].TotalSum > out[i].TotalSum) {
+			if out[j].ahead(out[i], r.CaptureTie) {
 				out[i], out[j] = out[j], out[i]
 			}
 		}
@@ -578,8 +811,87 @@ func (m *Manager) Rank(r *shared.Room) []RankRow {
 	return out
 }
 
-// StartGame transitions a room from lobby to playing state
-func (m *Manager) StartGame(r *shared.Room) {
+// finishGame records the winner (nil for a draw), notifies clients and hands
+// over to personas and the match series
+func (m *Manager) finishGame(r *shared.Room, winnerID *string) {
+	r.WinnerID = winnerID
+	r.Draw = winnerID == nil
+	r.Metrics = computeMetrics(r, time.Now())
+	scoreMatchGame(r)
+	m.startTurnClock(r) // Stops the turn timer now the game is over
+
+	// Save the room with winner set BEFORE broadcasting
+	m.logResult(r)
+	m.recordOutcome(r)
+	m.store.SaveRoom(r)
+	if winnerID != nil {
+		m.noteEvent(r, roomlog.KindGame, *winnerID, "Game over in room %s after %d moves, %s won", r.Code, len(r.History), *winnerID)
+	} else {
+		m.noteEvent(r, roomlog.KindGame, "", "Game over in room %s after %d moves, drawn", r.Code, len(r.History))
+	}
+
+	// Broadcast game over
+	m.hub.Broadcast(r.Code, "game_over", gin.H{
+		"winner":   winnerID,
+		"board":    r.Board,
+		"match":    r.Match,
+		"win_line": r.WinLine,
+		"metrics":  r.Metrics,
+		"result":   resultOf(r),
+		"stats":    statsOf(r),
+	})
+
+	// Let bot personas remember how this opponent did
+	m.recordPersonaResults(r)
+
+	// Update ELO ratings of account holders and bot personas
+	m.recordRatings(r)
+
+	// Move on to the next game when the room is playing a series
+	m.advanceMatch(r)
+
+	m.runGameOverHooks(r)
+}
+
+// StartGame transitions a room from lobby to playing state. It deals every
+// player a fresh deck and hand, draws the turn order and announces the game,
+// all under the room's lock so no client sees a half-dealt room.
+func (m *Manager) StartGame(room *shared.Room) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
 	r.Status = "playing"
+	newGame(r)
+	for i := range r.Players {
+		r.Players[i].Ready = false // Readiness only counts in the lobby
+		r.Players[i].ResetTallies()
+	}
+	m.training.Discard(r.Code) // A game cut short leaves its moves behind
+	dealHands(r)
+	drawTurnOrder(r)
+	seatBoard(r)
+	applyFeatures(r)
+	prepareDecks(r)
+	resetGameClock(r)
+	m.startTurnClock(r)
+	m.logState(r, record.EventStart)
 	m.store.SaveRoom(r)
+	m.noteEvent(r, roomlog.KindGame, "", "Game started in room %s with %d player(s), %s opens", r.Code, len(r.Players), r.Players[r.TurnIdx].ID)
+	m.broadcastGameStarted(r)
+	m.SyncHands(r)
+}
+
+// broadcastGameStarted sends the opening state of a game to the room
+func (m *Manager) broadcastGameStarted(r *shared.Room) {
+	m.hub.Broadcast(r.Code, "game_started", gin.H{
+		"room_code":  r.Code,
+		"turn_order": r.TurnOrder,
+		"players":    r.Players,
+		"board":      r.Board,
+		"status":     r.Status,
+		"match":      r.Match,
+		"time_bank":  r.TimeBank,
+		"game_clock": r.GameClock,
+		"next_turn":  r.Players[r.TurnIdx].ID,
+	})
 }
@@ -0,0 +1,134 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// Creating a room with a code in use fails and leaves the live room as it was
+func TestCreateLobbyRoomKeepsExistingRoom(t *testing.T) {
+	m := newTestManager(t)
+	r, err := m.CreateLobbyRoom("TAKEN1", "ann")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.CreateLobbyRoom("TAKEN1", "mallory"); !errors.Is(err, ErrRoomExists) {
+		t.Fatalf("second create: err = %v, want ErrRoomExists", err)
+	}
+	got, _ := m.Get("TAKEN1")
+	if got.MasterID != r.MasterID || len(got.Players) != 1 || got.Players[0].Name != "ann" {
+		t.Fatalf("room was replaced: master %s, players %+v", got.MasterID, got.Players)
+	}
+}
+
+// Of several rooms created at the same time with one code, exactly one is
+// created
+func TestConcurrentCreateLobbyRoom(t *testing.T) {
+	m := newTestManager(t)
+
+	const creators = 16
+	var wg sync.WaitGroup
+	errs := make(chan error, creators)
+	for i := 0; i < creators; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := m.CreateLobbyRoom("RACE01", "host")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, ErrRoomExists):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("%d of %d creates succeeded, want 1", created, creators)
+	}
+}
+
+// A game nobody can move on, with the leaders level on every tie-break, is
+// drawn and takes no further moves
+func TestLevelEndgameIsDrawn(t *testing.T) {
+	m := newTestManager(t)
+	room, human, bot := startBotGame(t, m, "DRAW01")
+
+	r, unlock := m.lockRoom(room)
+	r.Board = game.NewBoard(r.Board.Size)
+	game.ApplyMove(&r.Board, 3, 4, human, 5)
+	game.ApplyMove(&r.Board, 5, 4, bot, 5)
+	for i := range r.Players {
+		r.Players[i].Hand, r.Players[i].Deck = nil, nil
+	}
+	over := m.CheckEndgame(r)
+	unlock()
+
+	if !over || !r.Draw || r.WinnerID != nil {
+		t.Fatalf("over = %v, draw = %v, winner = %v; want a draw", over, r.Draw, r.WinnerID)
+	}
+	if err := m.ApplyMove(context.Background(), r, r.Players[r.TurnIdx].ID, 4, 4, 1); err == nil {
+		t.Error("move accepted after the draw")
+	}
+
+	res, err := m.Result(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range res.Players {
+		if p.Placement != 1 {
+			t.Errorf("%s placed %d in a draw, want 1", p.PlayerID, p.Placement)
+		}
+	}
+}
+
+// Skips and swaps are refused before the game starts and after the room ends
+func TestSkipAndSwapNeedGameInPlay(t *testing.T) {
+	m := newTestManager(t)
+	r, err := m.CreateLobbyRoom("IDLE01", "host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := r.Players[0].ID
+	if err := m.Skip(r, host); err == nil {
+		t.Error("skip accepted in the lobby")
+	}
+	if err := m.Swap(r, host, 1); err == nil {
+		t.Error("swap accepted in the lobby")
+	}
+	if len(r.History) != 0 {
+		t.Fatalf("lobby history has %d moves", len(r.History))
+	}
+
+	room, human, _ := startBotGame(t, m, "IDLE02")
+	if err := m.ForceEnd(room, "test"); err != nil {
+		t.Fatal(err)
+	}
+	r, unlock := m.lockRoom(room)
+	r.TurnIdx = slices.IndexFunc(r.Players, func(p shared.Player) bool { return p.ID == human })
+	hand := r.Players[r.TurnIdx].Hand
+	r.Players[r.TurnIdx].Hand = nil // Nothing to place, so only the guard stops a skip
+	unlock()
+	if err := m.Skip(r, human); err == nil {
+		t.Error("skip accepted in an ended room")
+	}
+
+	r, unlock = m.lockRoom(room)
+	r.Players[r.TurnIdx].Hand = hand
+	unlock()
+	if err := m.Swap(r, human, hand[0]); err == nil {
+		t.Error("swap accepted in an ended room")
+	}
+}
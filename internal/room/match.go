@@ -0,0 +1,163 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Supported series lengths for a match
+var allowedBestOf = map[int]bool{1: true, 3: true, 5: true}
+
+// StartMatch turns the room into a best-of-N series. The first game is the
+// one currently set up in the room.
+func (m *Manager) StartMatch(room *shared.Room, bestOf int) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	return m.startMatch(r, bestOf)
+}
+
+func (m *Manager) startMatch(r *shared.Room, bestOf int) error {
+	if !allowedBestOf[bestOf] {
+		return errors.New("best_of must be 1, 3 or 5")
+	}
+
+	scores := make(map[string]int, len(r.Players))
+	for _, p := range r.Players {
+		scores[p.ID] = 0
+	}
+
+	r.Match = &shared.Match{
+		BestOf: bestOf,
+		GameNo: 1,
+		Scores: scores,
+	}
+
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// winsNeeded returns how many games a player must win to take the match
+func winsNeeded(bestOf int) int {
+	return bestOf/2 + 1
+}
+
+// scoreMatchGame adds the finished game to the room's series: its result
+// is kept and a win scores a point. It runs before game_over goes out, so
+// the broadcast carries the updated scores.
+func scoreMatchGame(r *shared.Room) {
+	mt := r.Match
+	if mt == nil || mt.Finished || len(mt.Results) >= mt.GameNo {
+		return
+	}
+
+	firstPlayerID := ""
+	if len(r.Players) > 0 {
+		firstPlayerID = r.Players[r.FirstTurnIdx].ID
+	}
+
+	mt.Results = append(mt.Results, shared.MatchGame{
+		GameNo:        mt.GameNo,
+		FirstPlayerID: firstPlayerID,
+		WinnerID:      r.WinnerID,
+		Draw:          r.Draw,
+		Moves:         len(r.History),
+		Metrics:       r.Metrics,
+	})
+	if r.WinnerID != nil {
+		mt.Scores[*r.WinnerID]++
+	}
+}
+
+// advanceMatch either starts the next game of the series, the opener
+// rotating round the table, or announces the series result in series_over
+// once a player has won enough games or every game has been played
+func (m *Manager) advanceMatch(r *shared.Room) {
+	mt := r.Match
+	if mt == nil || mt.Finished {
+		return
+	}
+
+	// The series ends when someone reaches the required wins or all games are played
+	var matchWinner *string
+	if r.WinnerID != nil && mt.Scores[*r.WinnerID] >= winsNeeded(mt.BestOf) {
+		id := *r.WinnerID
+		matchWinner = &id
+	}
+
+	if matchWinner != nil || mt.GameNo >= mt.BestOf {
+		if matchWinner == nil {
+			matchWinner = leadingPlayer(mt.Scores)
+		}
+		mt.WinnerID = matchWinner
+		mt.Finished = true
+		m.store.SaveRoom(r)
+
+		m.logEvent(r, roomlog.KindGame, "", "Match over in room %s, scores: %v", r.Code, mt.Scores)
+		over := gin.H{
+			"room_code": r.Code,
+			"best_of":   mt.BestOf,
+			"winner":    mt.WinnerID,
+			"draw":      mt.WinnerID == nil,
+			"scores":    mt.Scores,
+			"results":   mt.Results,
+		}
+		m.hub.Broadcast(r.Code, "series_over", over)
+		m.hub.Broadcast(r.Code, "match_over", over) // The old name, for clients from before series_over
+		return
+	}
+
+	mt.GameNo++
+	m.resetGame(r, nextOpener(r, (mt.GameNo-1)%len(r.Players)))
+
+	m.logEvent(r, roomlog.KindGame, "", "Starting game %d of %d in room %s", mt.GameNo, mt.BestOf, r.Code)
+	m.broadcastGameStarted(r)
+	m.SyncHands(r)
+}
+
+// resetGame clears the board, deals fresh decks and hands and gives the
+// first turn to the player at firstIdx. Players and turn order are kept.
+func (m *Manager) resetGame(r *shared.Room, firstIdx int) {
+	newGame(r)
+	m.training.Discard(r.Code)
+	dealHands(r)
+	for i := range r.Players {
+		r.Players[i].Resigned = false
+		r.Players[i].MissedTurns = 0
+		r.Players[i].TimeBankMs = 0
+		r.Players[i].ResetTallies()
+	}
+	resetGameClock(r)
+
+	r.TurnIdx = firstIdx
+	r.FirstTurnIdx = firstIdx
+	r.RematchVotes = nil
+	r.Status = "playing"
+	seatBoard(r)
+	prepareDecks(r)
+	m.startTurnClock(r)
+
+	m.logState(r, record.EventStart)
+	m.store.SaveRoom(r)
+}
+
+// leadingPlayer returns the player with the most game wins, or nil on a tie
+func leadingPlayer(scores map[string]int) *string {
+	var leader string
+	best, tied := -1, false
+	for id, s := range scores {
+		switch {
+		case s > best:
+			leader, best, tied = id, s, false
+		case s == best:
+			tied = true
+		}
+	}
+	if leader == "" || tied {
+		return nil
+	}
+	return &leader
+}
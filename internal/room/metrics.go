@@ -0,0 +1,60 @@
+package room
+
+import (
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+	"time"
+)
+
+// computeMetrics builds the end-of-game snapshot by replaying the move
+// history once: think time is the gap since the previous move (or the game
+// start), captures are overwrites of opponent cards and max line is the
+// longest line the mover had right after each placement.
+func computeMetrics(r *shared.Room, end time.Time) *shared.GameMetrics {
+	gm := &shared.GameMetrics{
+		Moves:   len(r.History),
+		Players: make(map[string]shared.PlayerMetrics, len(r.Players)),
+	}
+	if !r.StartedAt.IsZero() {
+		gm.DurationSeconds = end.Sub(r.StartedAt).Seconds()
+	}
+	gm.Standings = standings(r)
+
+	think := map[string]float64{}
+	eng := engineFor(r)
+	board := eng.NewGame(r.Board.Size)
+	board.KeepRules(&r.Board)
+	prev := r.StartedAt
+	for _, rec := range r.History {
+		pm := gm.Players[rec.PlayerID]
+		pm.Moves++
+		if !prev.IsZero() && !rec.At.IsZero() {
+			think[rec.PlayerID] += rec.At.Sub(prev).Seconds()
+		}
+		prev = rec.At
+
+		switch rec.Type.Normalize() {
+		case game.MoveSkip:
+			pm.Skips++
+		case game.MovePlace:
+			if v := rec.Captured(); v > 0 {
+				pm.Captures++
+				pm.CapturedValue += v
+			}
+			eng.Apply(&board, game.Move{X: rec.X, Y: rec.Y, Card: rec.Card, PlayerID: rec.PlayerID})
+			if l := game.LineLength(board, rec.X, rec.Y, rec.PlayerID); l > pm.MaxLine {
+				pm.MaxLine = l
+			}
+		}
+		gm.Players[rec.PlayerID] = pm
+	}
+
+	for _, p := range r.Players {
+		pm := gm.Players[p.ID]
+		if pm.Moves > 0 {
+			pm.AvgThinkSeconds = think[p.ID] / float64(pm.Moves)
+		}
+		gm.Players[p.ID] = pm
+	}
+	return gm
+}
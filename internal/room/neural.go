@@ -0,0 +1,73 @@
+package room
+
+import (
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/nn"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+	"math"
+)
+
+// SetPolicyModel lets bots choose their moves with a policy network, weighed
+// against the heuristic by blend from 0 (heuristic only) to 1 (network
+// only). A nil model goes back to the heuristic alone.
+func (m *Manager) SetPolicyModel(model nn.Model, blend float64) {
+	m.policy = model
+	m.policyBlend = blend
+}
+
+// networkMove picks among the moves the heuristic scored by blending the
+// network's policy with the heuristic scores, both scaled so the best move
+// counts 1. Wins the heuristic spotted are left to it. It reports false
+// without a model or when the model fails, and the bot keeps the heuristic's
+// choice.
+func (m *Manager) networkMove(r *shared.Room, cp *shared.Player, scored []game.ScoredMove, winScore int) (game.ScoredMove, bool) {
+	if m.policy == nil || len(scored) == 0 {
+		return game.ScoredMove{}, false
+	}
+
+	lo, hi := scored[0].Score, scored[0].Score
+	for _, s := range scored {
+		lo, hi = min(lo, s.Score), max(hi, s.Score)
+	}
+	if hi >= winScore {
+		return game.ScoredMove{}, false
+	}
+
+	out, err := m.policy.Predict(nn.Encode(&r.Board, cp.Hand, cp.ID))
+	if err != nil {
+		m.logEvent(r, roomlog.KindBot, cp.ID, "Policy network failed for bot %s in room %s, using the heuristic: %v", cp.ID, r.Code, err)
+		return game.ScoredMove{}, false
+	}
+
+	// Softmax over the candidates the policy covers, kept relative to the
+	// most likely one
+	probs := make([]float64, len(scored))
+	top := math.Inf(-1)
+	for _, s := range scored {
+		if logit, ok := out.Logit(s.Move); ok {
+			top = max(top, float64(logit))
+		}
+	}
+	if math.IsInf(top, -1) {
+		m.logEvent(r, roomlog.KindBot, cp.ID, "Policy network covers none of the moves of bot %s in room %s, using the heuristic", cp.ID, r.Code)
+		return game.ScoredMove{}, false
+	}
+	for i, s := range scored {
+		if logit, ok := out.Logit(s.Move); ok {
+			probs[i] = math.Exp(float64(logit) - top)
+		}
+	}
+
+	best, bestValue := 0, math.Inf(-1)
+	for i, s := range scored {
+		h := 1.0
+		if hi > lo {
+			h = float64(s.Score-lo) / float64(hi-lo)
+		}
+		if v := (1-m.policyBlend)*h + m.policyBlend*probs[i]; v > bestValue {
+			best, bestValue = i, v
+		}
+	}
+	return scored[best], true
+}
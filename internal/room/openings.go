@@ -0,0 +1,42 @@
+package room
+
+import (
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+)
+
+// SetOpeningBook lets the room's bots play their first moves from the opening
+// book instead of evaluating them
+func (m *Manager) SetOpeningBook(room *shared.Room, enabled bool) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	r.OpeningBook = enabled
+	m.store.SaveRoom(r)
+}
+
+// bookMove returns the book move for a bot, or nil when the room does not use
+// the book or the position is not in it. Bots that sample opening moves pick
+// among the book's choices at random.
+func bookMove(r *shared.Room, cp *shared.Player) *game.Move {
+	if !r.OpeningBook {
+		return nil
+	}
+	moves := game.BookMoves(&r.Board, cp.Hand, cp.ID)
+	if len(moves) == 0 {
+		return nil
+	}
+	if openingTemperature(r) > 0 {
+		return &moves[roomRand(r).Intn(len(moves))]
+	}
+	return &moves[0]
+}
+
+// openingTemperature is the bots' sampling temperature for the next move:
+// the room's while the game is in its opening moves, 0 afterwards
+func openingTemperature(r *shared.Room) float64 {
+	if r.RoomConfig != nil && len(r.History) < r.RoomConfig.GetTemperatureMoves() {
+		return r.RoomConfig.GetTemperature()
+	}
+	return 0
+}
@@ -0,0 +1,17 @@
+package room
+
+import (
+	"javanese-chess/internal/shared"
+)
+
+// SetOptions sets what broadcasts reveal, whether the game is ranked (seats
+// cannot be taken over) and whether humans may ask for suggested moves
+func (m *Manager) SetOptions(room *shared.Room, policy shared.BroadcastPolicy, ranked, hints bool) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	r.Policy = policy
+	r.Ranked = ranked
+	r.Hints = hints
+	m.store.SaveRoom(r)
+}
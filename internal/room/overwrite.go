@@ -0,0 +1,21 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/shared"
+)
+
+// SetOwnOverwrite lets players cover their own cards with higher ones, or
+// restores the classic rule that only opponents' cards can be covered. It
+// can only change before the first move.
+func (m *Manager) SetOwnOverwrite(room *shared.Room, enabled bool) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.Status == "playing" && len(r.History) > 0 {
+		return errors.New("own overwrite can only change before the first move")
+	}
+	r.Board.OwnOverwrite = enabled
+	m.store.SaveRoom(r)
+	return nil
+}
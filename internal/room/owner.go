@@ -0,0 +1,86 @@
+package room
+
+import (
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClosedRoom summarizes a room closed by its owner
+type ClosedRoom struct {
+	Code     string `json:"code"`
+	Status   string `json:"status"`   // Status before closing
+	Archived bool   `json:"archived"` // A game was in progress and was kept as played
+	Moves    int    `json:"moves"`
+}
+
+// ClaimRoom records the authenticated account that created the room. Anonymous
+// creators own nothing, and the first claim sticks.
+func (m *Manager) ClaimRoom(room *shared.Room, userID string) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if userID == "" || r.OwnerID != "" {
+		return
+	}
+	r.OwnerID = userID
+	m.store.SaveRoom(r)
+}
+
+// CloseOwnedRooms closes every room created by userID that is still open, for
+// example a batch of classroom rooms after the session. Games in progress end
+// without a result and keep their board and history, so they can still be
+// exported and replayed.
+func (m *Manager) CloseOwnedRooms(userID, reason string) []ClosedRoom {
+	closed := []ClosedRoom{}
+	if userID == "" {
+		return closed
+	}
+
+	for _, r := range m.store.ListRooms() {
+		if r.OwnerID != userID {
+			continue
+		}
+		if c, ok := m.closeRoom(r, reason); ok {
+			closed = append(closed, c)
+		}
+	}
+
+	log.Printf("User %s closed %d room(s): %s", userID, len(closed), reason)
+	return closed
+}
+
+// closeRoom closes one room unless it is closed already
+func (m *Manager) closeRoom(room *shared.Room, reason string) (ClosedRoom, bool) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	defer m.dropLock(r.Code)
+	if isClosed(r) {
+		return ClosedRoom{}, false
+	}
+
+	inProgress := r.Status == "playing" && !r.Over()
+	c := ClosedRoom{
+		Code:     r.Code,
+		Status:   r.Status,
+		Archived: inProgress,
+		Moves:    len(r.History),
+	}
+
+	r.Status = "closed"
+	r.PendingUndo = nil
+	m.startTurnClock(r)
+	m.logResult(r)
+	m.training.Discard(r.Code)
+	m.store.SaveRoom(r)
+	m.noteEvent(r, roomlog.KindRoom, "", "Room %s closed by its owner: %s", r.Code, reason)
+
+	m.hub.Broadcast(r.Code, "room_closed", gin.H{
+		"reason":   reason,
+		"archived": inProgress,
+		"board":    r.Board,
+	})
+	return c, true
+}
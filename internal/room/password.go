@@ -0,0 +1,40 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/shared"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SetRoomPassword protects a lobby so only players who know the password can
+// join. An empty password removes the protection.
+func (m *Manager) SetRoomPassword(room *shared.Room, password string) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if password == "" {
+		r.PasswordHash = nil
+		m.store.SaveRoom(r)
+		return nil
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	r.PasswordHash = hash
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// CheckRoomPassword verifies the password of a protected room
+func (m *Manager) CheckRoomPassword(r *shared.Room, password string) error {
+	if !r.HasPassword() {
+		return nil
+	}
+	if bcrypt.CompareHashAndPassword(r.PasswordHash, []byte(password)) != nil {
+		return errors.New("wrong room password")
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package room
+
+import (
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+	"log"
+	"math/rand"
+)
+
+// personaFor returns the persona assigned to the n-th bot of a room
+func personaFor(n int) config.BotPersona {
+	personas := config.DefaultBotPersonas
+	return personas[n%len(personas)]
+}
+
+// basePersonaDifficulty looks up the configured baseline for a persona name
+func basePersonaDifficulty(name string) float64 {
+	for _, p := range config.DefaultBotPersonas {
+		if p.Name == name {
+			return p.BaseDifficulty
+		}
+	}
+	return config.MaxPersonaDifficulty
+}
+
+// personaDifficulty averages the persona's adapted difficulty over the human
+// players in the room. Anonymous and unknown opponents use the persona
+// baseline.
+func (m *Manager) personaDifficulty(r *shared.Room, bot *shared.Player) float64 {
+	if bot.Persona == "" {
+		return config.MaxPersonaDifficulty
+	}
+
+	total, n := 0.0, 0
+	for _, p := range r.Players {
+		if p.IsBot {
+			continue
+		}
+		if rec, ok := m.personaRecord(bot.Persona, p); ok {
+			total += rec.Difficulty
+		} else {
+			total += basePersonaDifficulty(bot.Persona)
+		}
+		n++
+	}
+	if n == 0 {
+		return basePersonaDifficulty(bot.Persona)
+	}
+	return total / float64(n)
+}
+
+// botConfig returns the configuration a bot scores its moves with. Bots play
+// with their own weights when set, otherwise the weights of the room's
+// experiment arm or the defaults, adjusted by their personality preset if
+// they have one.
+func (m *Manager) botConfig(r *shared.Room, bot *shared.Player) *config.Config {
+	cfg := m.cfg
+	if weights, ok := m.armWeights(r); ok {
+		cfg.DefaultWeights = weights
+	}
+	if bot.Weights != nil {
+		cfg.DefaultWeights = *bot.Weights
+	}
+	if p, ok := config.GetBotPersonality(bot.Personality); ok {
+		cfg.DefaultWeights = p.Apply(cfg.DefaultWeights)
+	}
+	return &cfg
+}
+
+// personaRecord looks up the persona's record against the player's account.
+// Seat names are free text, so anonymous seats never match a record.
+func (m *Manager) personaRecord(persona string, p shared.Player) (*shared.PersonaRecord, bool) {
+	if p.UserID == "" {
+		return nil, false
+	}
+	return m.store.GetPersonaRecord(persona, p.UserID)
+}
+
+// recordPersonaResults updates the persona record of every human with an
+// account after a game ends and re-tunes the persona difficulty towards an
+// even win rate.
+func (m *Manager) recordPersonaResults(r *shared.Room) {
+	for _, bot := range r.Players {
+		if !bot.IsBot || bot.Persona == "" {
+			continue
+		}
+		for _, p := range r.Players {
+			if p.IsBot || p.UserID == "" {
+				continue
+			}
+
+			rec, ok := m.personaRecord(bot.Persona, p)
+			if !ok {
+				rec = &shared.PersonaRecord{
+					Persona: bot.Persona,
+					UserID:  p.UserID,
+				}
+			}
+
+			rec.Games++
+			if r.WinnerID != nil && *r.WinnerID == p.ID {
+				rec.PlayerWins++
+			}
+
+			// Players who win often face a stronger persona, and vice versa
+			winRate := float64(rec.PlayerWins) / float64(rec.Games)
+			rec.Difficulty = clampDifficulty(basePersonaDifficulty(bot.Persona) + (winRate - 0.5))
+
+			m.store.SavePersonaRecord(rec)
+			log.Printf("Persona %s vs %s: %d/%d player wins, difficulty=%.2f",
+				rec.Persona, rec.UserID, rec.PlayerWins, rec.Games, rec.Difficulty)
+		}
+	}
+}
+
+func clampDifficulty(d float64) float64 {
+	if d < config.MinPersonaDifficulty {
+		return config.MinPersonaDifficulty
+	}
+	if d > config.MaxPersonaDifficulty {
+		return config.MaxPersonaDifficulty
+	}
+	return d
+}
+
+// pickByDifficulty plays the best move with probability equal to difficulty,
+// otherwise a random legal move.
+func pickByDifficulty(rng *rand.Rand, best *game.Move, cands []game.Move, difficulty float64) *game.Move {
+	if best == nil || len(cands) == 0 {
+		return best
+	}
+
+	if rng.Float64() < difficulty {
+		return best
+	}
+	mv := cands[rng.Intn(len(cands))]
+	return &mv
+}
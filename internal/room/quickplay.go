@@ -0,0 +1,41 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/shared"
+)
+
+// CreateQuickRoom creates and starts a room for matchmaking. Duplicate names
+// get a numeric suffix. Player IDs are returned in the same order as names.
+func (m *Manager) CreateQuickRoom(names []string, bots int) (*shared.Room, []string, error) {
+	if len(names) == 0 {
+		return nil, nil, errors.New("no players to seat")
+	}
+
+	code := randCode(6)
+	for _, exists := m.store.GetRoom(code); exists; _, exists = m.store.GetRoom(code) {
+		code = randCode(6)
+	}
+
+	// Joining suffixes duplicate names
+	r, err := m.CreateLobbyRoom(code, names[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]string, len(names))
+	ids[0] = r.MasterID
+	for i, name := range names[1:] {
+		_, p, err := m.JoinRoom(code, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		ids[i+1] = p.ID
+	}
+	if bots > 0 {
+		if err := m.AddBots(r, bots); err != nil {
+			return nil, nil, err
+		}
+	}
+	m.StartGame(r)
+	return r, ids, nil
+}
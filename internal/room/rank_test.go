@@ -0,0 +1,58 @@
+package room
+
+import (
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+	"slices"
+	"testing"
+)
+
+// The non-instant rules rank players by their best line sum, then by the
+// total they own
+func TestRankTieBreaks(t *testing.T) {
+	type card struct {
+		x, y  int
+		owner string
+		value int
+	}
+	tests := []struct {
+		name  string
+		cards []card
+		want  []string
+	}{
+		{
+			name: "highest line sum ranks first",
+			cards: []card{
+				{1, 1, "a", 4}, {2, 1, "a", 4},
+				{1, 3, "b", 2}, {2, 3, "b", 2}, {3, 3, "b", 2},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "equal line sums fall back to total owned sum",
+			cards: []card{
+				{1, 1, "a", 5}, {2, 1, "a", 3},
+				{1, 5, "b", 4}, {2, 5, "b", 4},
+				{7, 7, "b", 1},
+			},
+			want: []string{"b", "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &shared.Room{Players: []shared.Player{{ID: "a"}, {ID: "b"}}}
+			r.Board = game.NewBoard(9)
+			for _, c := range tt.cards {
+				game.ApplyMove(&r.Board, c.x, c.y, c.owner, c.value)
+			}
+
+			var got []string
+			for _, row := range rank(r) {
+				got = append(got, row.PlayerID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ranking = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package room
+
+import (
+	"javanese-chess/internal/rating"
+	"javanese-chess/internal/shared"
+	"log"
+	"time"
+)
+
+// RatingStore persists player ratings across games and restarts
+type RatingStore interface {
+	GetRating(playerID string) (*shared.PlayerRating, bool)
+	SaveRatings(ratings []*shared.PlayerRating)
+	ListRatings() []shared.PlayerRating
+}
+
+func (m *Manager) SetRatings(rs RatingStore) {
+	m.ratings = rs
+}
+
+// Ratings returns the rating store, or nil when ratings are disabled
+func (m *Manager) Ratings() RatingStore {
+	return m.ratings
+}
+
+// BotRatingPrefix starts the rating identity of bot personas
+const BotRatingPrefix = "bot:"
+
+// ratingID returns the stable identity a seat is rated under. Anonymous
+// humans have no stable identity and are not rated.
+func ratingID(p shared.Player) string {
+	if p.IsBot && p.Persona != "" {
+		return BotRatingPrefix + p.Persona
+	}
+	return p.UserID
+}
+
+// recordRatings updates ELO and lifetime stats of every rated seat after a
+// ranked game. Casual, manual and restored rooms leave ratings alone.
+func (m *Manager) recordRatings(r *shared.Room) {
+	if m.ratings == nil || !r.Ranked {
+		return
+	}
+
+	current := make(map[string]float64)
+	entries := make(map[string]*shared.PlayerRating)
+	seats := make(map[string]string) // Rating ID -> seat whose metrics are counted
+	winnerKey := ""
+	for _, p := range r.Players {
+		id := ratingID(p)
+		if id == "" {
+			continue
+		}
+		if _, seen := entries[id]; seen {
+			continue // Several bots of the same persona count once
+		}
+
+		entry, ok := m.ratings.GetRating(id)
+		if !ok {
+			entry = &shared.PlayerRating{PlayerID: id, Rating: rating.DefaultRating}
+		}
+		entry.Name = p.Name
+		entries[id] = entry
+		seats[id] = p.ID
+		current[id] = entry.Rating
+
+		if r.WinnerID != nil && *r.WinnerID == p.ID {
+			winnerKey = id
+		}
+	}
+	if len(entries) < 2 {
+		return
+	}
+
+	// When an unrated player wins, the rated seats are even among themselves
+	updated := rating.Update(current, winnerKey)
+
+	gm := r.Metrics
+	if gm == nil {
+		gm = computeMetrics(r, time.Now())
+	}
+
+	list := make([]*shared.PlayerRating, 0, len(entries))
+	for id, entry := range entries {
+		entry.Rating = updated[id]
+		entry.Games++
+		entry.TotalMoves += gm.Moves
+		entry.TotalSeconds += gm.DurationSeconds
+
+		pm := gm.Players[seats[id]]
+		entry.PlayerMoves += pm.Moves
+		entry.ThinkSeconds += pm.AvgThinkSeconds * float64(pm.Moves)
+		entry.TotalCaptures += pm.Captures
+		entry.TotalSkips += pm.Skips
+		if pm.MaxLine > entry.BestLine {
+			entry.BestLine = pm.MaxLine
+		}
+		entry.UpdatedAt = time.Now()
+		switch {
+		case r.WinnerID == nil:
+			entry.Draws++
+		case id == winnerKey:
+			entry.Wins++
+		default:
+			entry.Losses++
+		}
+		list = append(list, entry)
+	}
+
+	m.ratings.SaveRatings(list)
+	log.Printf("Ratings updated for %d player(s) in room %s", len(list), r.Code)
+}
@@ -0,0 +1,38 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+	"slices"
+	"time"
+)
+
+// React shows the room a quick reaction from a seated player. Reactions are
+// rate limited like chat but are not kept: they are broadcast as reaction
+// and forgotten.
+func (m *Manager) React(room *shared.Room, playerID, code string) (shared.Reaction, error) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	p := findPlayer(r, playerID)
+	if p == nil {
+		return shared.Reaction{}, errors.New("player not in room")
+	}
+	if !slices.Contains(config.Reactions, code) {
+		return shared.Reaction{}, errors.New("unknown reaction")
+	}
+
+	now := time.Now()
+	if r.ReactionSent == nil {
+		r.ReactionSent = make(map[string][]time.Time)
+	}
+	if !allowRate(r.ReactionSent, playerID, now, config.ReactionRateLimit, config.ReactionRateWindow) {
+		return shared.Reaction{}, errors.New("sending reactions too fast")
+	}
+	m.store.SaveRoom(r)
+
+	reaction := shared.Reaction{PlayerID: playerID, Name: p.Name, Code: code, At: now}
+	m.hub.Broadcast(r.Code, "reaction", reaction)
+	return reaction, nil
+}
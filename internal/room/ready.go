@@ -0,0 +1,42 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"javanese-chess/internal/shared"
+	"strings"
+)
+
+// SetReady marks a lobby player as ready for the game to start, or not. The
+// flag is kept with the room, so it survives the player reconnecting.
+func (m *Manager) SetReady(room *shared.Room, playerID string, ready bool) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.Status != "lobby" {
+		return errors.New("game has already started")
+	}
+	p := findPlayer(r, playerID)
+	if p == nil {
+		return errors.New("player not in room")
+	}
+	if p.IsBot {
+		return errors.New("bots are always ready")
+	}
+
+	p.Ready = ready
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// CheckReady refuses to start a game while human players in the lobby are
+// not ready. Seats filled with bots need no check.
+func (m *Manager) CheckReady(room *shared.Room) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if waiting := r.NotReady(); len(waiting) > 0 {
+		return fmt.Errorf("waiting for %s to be ready", strings.Join(waiting, ", "))
+	}
+	return nil
+}
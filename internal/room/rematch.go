@@ -0,0 +1,82 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestRematch records a human player's vote for a rematch. Once every
+// human seat has voted the room restarts with the next player moving first.
+// Returns true when the rematch has started. An aborted game is replayed with
+// the same player moving first, and does not count towards a running match.
+func (m *Manager) RequestRematch(room *shared.Room, playerID string) (bool, error) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	aborted := r.AbortedBy != nil
+	if !r.Over() && !aborted {
+		return false, errors.New("game is not over yet")
+	}
+	if r.Match != nil && !r.Match.Finished && !aborted {
+		return false, errors.New("match is still in progress")
+	}
+
+	p := findPlayer(r, playerID)
+	if p == nil {
+		return false, errors.New("player not in room")
+	}
+	if p.IsBot {
+		return false, errors.New("bots do not vote for rematches")
+	}
+
+	if r.RematchVotes == nil {
+		r.RematchVotes = make(map[string]bool)
+	}
+	r.RematchVotes[playerID] = true
+
+	pending := 0
+	for _, pl := range r.Players {
+		if !pl.IsBot && !r.RematchVotes[pl.ID] {
+			pending++
+		}
+	}
+
+	if pending > 0 {
+		m.store.SaveRoom(r)
+		m.hub.Broadcast(r.Code, "rematch_requested", gin.H{
+			"player_id": playerID,
+			"votes":     len(r.RematchVotes),
+			"pending":   pending,
+		})
+		return false, nil
+	}
+
+	// A finished series restarts as a fresh series of the same length
+	if r.Match != nil && r.Match.Finished {
+		if err := m.startMatch(r, r.Match.BestOf); err != nil {
+			return false, err
+		}
+	}
+
+	firstIdx := nextOpener(r, (r.FirstTurnIdx+1)%len(r.Players))
+	if aborted {
+		firstIdx = r.FirstTurnIdx
+	}
+	m.resetGame(r, firstIdx)
+
+	m.logEvent(r, roomlog.KindGame, "", "Rematch started in room %s, first player %s", r.Code, r.Players[r.TurnIdx].ID)
+	m.hub.Broadcast(r.Code, "game_restarted", gin.H{
+		"room_code":  r.Code,
+		"turn_order": r.TurnOrder,
+		"players":    r.Players,
+		"board":      r.Board,
+		"status":     r.Status,
+		"match":      r.Match,
+		"next_turn":  r.Players[r.TurnIdx].ID,
+	})
+	m.SyncHands(r)
+	return true, nil
+}
@@ -0,0 +1,79 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+	"slices"
+	"time"
+)
+
+// GameResult is the final score of a finished game, player by player
+type GameResult struct {
+	RoomCode string        `json:"room_code"`
+	WinnerID *string       `json:"winner_id"`
+	Draw     bool          `json:"draw"`
+	Players  []PlayerScore `json:"players"` // From first place to last
+}
+
+// PlayerScore is how one player finished. Placement is 1 for the winner;
+// in a draw every player still in the game shares first place.
+type PlayerScore struct {
+	PlayerID       string       `json:"player_id"`
+	Name           string       `json:"name"`
+	Placement      int          `json:"placement"`
+	BestSegment    []game.Coord `json:"best_segment"` // The line the tie-break scores, end to end
+	SegmentSum     int          `json:"segment_sum"`
+	TotalSum       int          `json:"total_sum"` // Every card the player owns on the board
+	Captures       int          `json:"captures"`  // Opponent cards overwritten
+	CapturedValue  int          `json:"captured_value"`
+	CardsRemaining int          `json:"cards_remaining"`
+	Resigned       bool         `json:"resigned"`
+}
+
+// Result scores the room's finished game. It fails while the game is still
+// being played.
+func (m *Manager) Result(room *shared.Room) (*GameResult, error) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if !r.Over() {
+		return nil, errors.New("game is not over")
+	}
+	return resultOf(r), nil
+}
+
+// resultOf scores the room's game as it stands
+func resultOf(r *shared.Room) *GameResult {
+	metrics := r.Metrics
+	if metrics == nil {
+		metrics = computeMetrics(r, time.Now())
+	}
+
+	res := &GameResult{RoomCode: r.Code, WinnerID: r.WinnerID, Draw: r.Draw}
+	leaders := drawnLeaders(r)
+	for i, id := range metrics.Standings {
+		p := findPlayer(r, id)
+		if p == nil {
+			continue
+		}
+		segment, sum := game.BestSegment(r.Board, id)
+		score := PlayerScore{
+			PlayerID:       id,
+			Name:           p.Name,
+			Placement:      i + 1,
+			BestSegment:    segment,
+			SegmentSum:     sum,
+			TotalSum:       game.TotalOwnedSum(r.Board, id),
+			Captures:       metrics.Players[id].Captures,
+			CapturedValue:  metrics.Players[id].CapturedValue,
+			CardsRemaining: len(p.Hand) + len(p.Deck),
+			Resigned:       p.Resigned,
+		}
+		if slices.Contains(leaders, id) {
+			score.Placement = 1
+		}
+		res.Players = append(res.Players, score)
+	}
+	return res
+}
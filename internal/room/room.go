@@ -3,6 +3,7 @@ package room
 import (
 	"javanese-chess/internal/config"
 	"javanese-chess/internal/game"
+	"javanese-chess/internal/record"
 	"javanese-chess/internal/shared"
 	"time"
 )
@@ -29,7 +30,14 @@ type Room struct {
 	RoomConfig *config.RoomConfig `json:"roomConfig,omitempty"`
 }
 
+// Store keeps rooms and their event logs. GetRoom returns a copy: changes
+// to it only reach the store through SaveRoom.
 type Store interface {
 	GetRoom(code string) (*shared.Room, bool)
 	SaveRoom(r *shared.Room)
+	ListRooms() []*shared.Room
+	AppendEvents(r *shared.Room, events ...record.Event)
+	Events(code string) []record.Event
+	GetPersonaRecord(persona, userID string) (*shared.PersonaRecord, bool)
+	SavePersonaRecord(rec *shared.PersonaRecord)
 }
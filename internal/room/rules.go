@@ -0,0 +1,190 @@
+package room
+
+import (
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/engine"
+	"javanese-chess/internal/shared"
+)
+
+// Cards dealt into each hand
+const handSize = 3
+
+// Rules is the complete rule set a room plays by: the engine's rules plus
+// the room's board, deck, clock and variant settings
+type Rules struct {
+	RoomCode string `json:"room_code"`
+	Engine   string `json:"engine"`
+	engine.Rules
+
+	BoardSize   int    `json:"board_size"`
+	OpeningCell string `json:"opening_cell"` // Algebraic cell of the first card
+	// LockAfter is how many captures lock a cell for good; 0 = never
+	LockAfter int `json:"lock_after"`
+
+	Seats   SeatRules   `json:"seats"`
+	Deck    DeckRules   `json:"deck"`
+	Clock   *ClockRule  `json:"clock"` // nil for untimed rooms
+	Abandon AbandonRule `json:"abandon"`
+	Turns   TurnRules   `json:"turns"`
+	Variant Variants    `json:"variants"`
+}
+
+// SeatRules bounds the number of players
+type SeatRules struct {
+	Min    int `json:"min"`
+	Max    int `json:"max"`
+	Seated int `json:"seated"`
+}
+
+// DeckRules describes the cards players draw from
+type DeckRules struct {
+	Kind       string `json:"kind"` // "personal" (one deck per player) or "shared" (one deck for all)
+	Sets       int    `json:"sets"` // Sets of every card value in each deck
+	MinCard    int    `json:"min_card"`
+	MaxCard    int    `json:"max_card"` // Permanent once placed
+	Size       int    `json:"size"`
+	HandSize   int    `json:"hand_size"`
+	Exhaustion string `json:"exhaustion"` // What happens when a deck runs out: continue, communal or endgame
+}
+
+// ClockRule is the time allowed per turn and the most time a player can
+// bank, or for a game clock the time per player and the increment per move
+type ClockRule struct {
+	Kind             string `json:"kind"` // "turn" or "game"
+	TurnSeconds      int    `json:"turn_seconds"`
+	BankCapSeconds   int    `json:"bank_cap_seconds"`
+	InitialSeconds   int    `json:"initial_seconds,omitempty"`
+	IncrementSeconds int    `json:"increment_seconds,omitempty"`
+}
+
+// AbandonRule is when a disconnected player is given up on and what happens
+// to their seat
+type AbandonRule struct {
+	Action         string `json:"action"`          // bot or forfeit
+	MissedTurns    int    `json:"missed_turns"`    // Timed out turns in a row while offline; 0 = no limit
+	OfflineMinutes int    `json:"offline_minutes"` // 0 = the server's reconnect grace
+}
+
+// TurnRules describes the actions besides placing a card
+type TurnRules struct {
+	Skip          string `json:"skip"`            // When a turn may be passed
+	Swap          bool   `json:"swap"`            // A hand card can be exchanged for the top of the deck
+	Hold          bool   `json:"hold"`            // Bots hold (swap) a card too when no placement is worth making
+	Resign        bool   `json:"resign"`          // Resigned players' cards stay on the board
+	UndoConsent   bool   `json:"undo_consent"`    // Human opponents must accept a takeback
+	AbortMaxPlies int    `json:"abort_max_plies"` // Games can be called off before this many moves
+	FirstPlayer   string `json:"first_player"`    // Who opens each game: random, master or loser
+	Rotation      string `json:"rotation"`        // Which way the turn passes: clockwise or counterclockwise
+}
+
+// Variants are the optional room settings that change how a game plays
+type Variants struct {
+	CellLock     bool                   `json:"cell_lock"`
+	OwnOverwrite bool                   `json:"own_overwrite"`
+	OpeningBook  bool                   `json:"opening_book"` // Bots play their first moves from the book
+	SharedDeck   bool                   `json:"shared_deck"`
+	Policy       shared.BroadcastPolicy `json:"broadcast_policy"`
+	Hints        bool                   `json:"hints"`
+	Ranked       bool                   `json:"ranked"` // Rated, and seats cannot be taken over
+	Manual       bool                   `json:"manual"` // Clients may set the dealt hands
+	BestOf       int                    `json:"best_of"`
+	CaptureTie   bool                   `json:"capture_tie"` // Captures break ties on line and total sums
+	Features     map[string]bool        `json:"features"`    // Every feature flag as the room plays it
+}
+
+// RulesOf resolves the rules in effect for a room, filling in every default
+func RulesOf(r *shared.Room) Rules {
+	eng := engineFor(r)
+	er := eng.Rules()
+	center := r.Board.Center()
+
+	// The room's deck decides the card range and the permanent card
+	spec := deckSpec(r)
+	er.CardMin, er.CardMax = spec.MinCard, spec.MaxCard
+	er.Overwrite.Permanent = []int{spec.MaxCard}
+	if r.Board.CoverTop {
+		er.Overwrite.Permanent = []int{}
+	}
+	er.Overwrite.OwnCards = r.Board.OwnOverwrite
+	if r.Board.NextToLast {
+		er.Adjacency = "last_move"
+	}
+
+	deck := DeckRules{
+		Kind:       "personal",
+		Sets:       spec.Copies,
+		MinCard:    spec.MinCard,
+		MaxCard:    spec.MaxCard,
+		Size:       spec.Size(),
+		HandSize:   handSize,
+		Exhaustion: shared.DeckRuleContinue,
+	}
+	if r.DeckRule != "" {
+		deck.Exhaustion = r.DeckRule
+	}
+	if spec.Shared {
+		deck.Kind = "shared"
+	}
+
+	var clock *ClockRule
+	switch {
+	case r.GameClock != nil:
+		clock = &ClockRule{Kind: "game", InitialSeconds: r.GameClock.InitialSeconds, IncrementSeconds: r.GameClock.IncrementSeconds}
+	case r.TimeBank != nil:
+		clock = &ClockRule{Kind: "turn", TurnSeconds: r.TimeBank.TurnSeconds, BankCapSeconds: r.TimeBank.CapSeconds}
+	}
+
+	abandon := AbandonRule{Action: r.Abandon.Action, MissedTurns: r.Abandon.Turns, OfflineMinutes: r.Abandon.Minutes}
+	if abandon.Action == "" {
+		abandon.Action = shared.AbandonBot
+	}
+
+	first, rotation := r.TurnRule.First, r.TurnRule.Rotation
+	if first == "" {
+		first = shared.FirstRandom
+	}
+	if rotation == "" {
+		rotation = shared.RotationClockwise
+	}
+
+	bestOf := 1
+	if r.Match != nil {
+		bestOf = r.Match.BestOf
+	}
+
+	return Rules{
+		RoomCode:    r.Code,
+		Engine:      eng.Name(),
+		Rules:       er,
+		BoardSize:   r.Board.Size,
+		OpeningCell: center.Algebraic(),
+		LockAfter:   r.Board.LockAfter,
+		Seats:       SeatRules{Min: config.MinPlayers, Max: config.MaxPlayers, Seated: len(r.Players)},
+		Deck:        deck,
+		Clock:       clock,
+		Abandon:     abandon,
+		Turns: TurnRules{
+			Skip:          "no_legal_moves",
+			Swap:          true,
+			Hold:          r.Hold,
+			Resign:        true,
+			UndoConsent:   true,
+			AbortMaxPlies: config.AbortMaxPlies,
+			FirstPlayer:   first,
+			Rotation:      rotation,
+		},
+		Variant: Variants{
+			CellLock:     r.Board.LockAfter > 0,
+			OwnOverwrite: r.Board.OwnOverwrite,
+			OpeningBook:  r.OpeningBook,
+			SharedDeck:   spec.Shared,
+			Policy:       r.Policy,
+			Hints:        r.Hints,
+			Ranked:       r.Ranked,
+			Manual:       r.Manual,
+			BestOf:       bestOf,
+			CaptureTie:   r.CaptureTie,
+			Features:     featuresOf(r),
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+
+	"github.com/google/uuid"
+)
+
+// CreateSeatedRoom creates and starts a room for a table arranged in advance.
+// Names must be unique; bot seats keep their given name and play at full
+// strength. Player IDs are returned in the same order as seats.
+func (m *Manager) CreateSeatedRoom(seats []shared.Seat) (*shared.Room, []string, error) {
+	if len(seats) < config.MinPlayers || len(seats) > config.MaxPlayers {
+		return nil, nil, fmt.Errorf("a room seats %d to %d players", config.MinPlayers, config.MaxPlayers)
+	}
+	names := make(map[string]bool, len(seats))
+	for _, s := range seats {
+		if s.Name == "" {
+			return nil, nil, errors.New("every seat needs a name")
+		}
+		if names[s.Name] {
+			return nil, nil, fmt.Errorf("seat name %q is used twice", s.Name)
+		}
+		names[s.Name] = true
+	}
+
+	code := randCode(6)
+	for _, exists := m.store.GetRoom(code); exists; _, exists = m.store.GetRoom(code) {
+		code = randCode(6)
+	}
+
+	r, err := m.CreateLobbyRoom(code, seats[0].Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, s := range seats[1:] {
+		if s.Bot {
+			if err := m.AddBots(r, 1); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		if _, _, err := m.JoinRoom(code, s.Name); err != nil {
+			return nil, nil, err
+		}
+	}
+	ids := m.nameSeats(r, seats)
+	m.StartGame(r)
+	return r, ids, nil
+}
+
+// nameSeats gives each seated player the seat's name, turns the host into a
+// bot when the first seat is one and returns the player IDs in seat order
+func (m *Manager) nameSeats(room *shared.Room, seats []shared.Seat) []string {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	ids := make([]string, len(seats))
+	for i, s := range seats {
+		p := &r.Players[i]
+		if s.Bot {
+			if !p.IsBot {
+				p.ID = "bot-" + uuid.NewString()
+				p.IsBot = true
+			}
+			p.Name = s.Name
+			p.Persona = "" // Persona difficulty would play weak moves on purpose
+		}
+		ids[i] = p.ID
+	}
+	r.MasterID = r.Players[0].ID
+	syncTurnOrder(r)
+	m.store.SaveRoom(r)
+	return ids
+}
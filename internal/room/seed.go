@@ -0,0 +1,60 @@
+package room
+
+import (
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/shared"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// roomRand returns the room's random source, seeding one from the clock the
+// first time it is needed. The seed is kept on the room so games can be replayed.
+func roomRand(r *shared.Room) *rand.Rand {
+	if r.Rand == nil {
+		if r.Seed == 0 {
+			r.Seed = time.Now().UnixNano()
+		}
+		r.SeedRand(r.Seed, 0)
+	}
+	return r.Rand
+}
+
+// Reseed restarts the room's random source from seed and puts the players in
+// a canonical order, so the same seed and the same players always produce
+// the same deal and turn order once StartGame runs.
+func (m *Manager) Reseed(room *shared.Room, seed int64) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	r.SeedRand(seed, 0)
+
+	sort.SliceStable(r.Players, func(i, j int) bool {
+		if r.Players[i].IsBot != r.Players[j].IsBot {
+			return !r.Players[i].IsBot
+		}
+		return r.Players[i].Name < r.Players[j].Name
+	})
+
+	syncTurnOrder(r)
+	m.logState(r, record.EventCheckpoint)
+	m.store.SaveRoom(r)
+}
+
+// shuffleTurnOrder shuffles the players with the room's random source and
+// rebuilds TurnOrder to match
+func shuffleTurnOrder(r *shared.Room) {
+	rng := roomRand(r)
+	rng.Shuffle(len(r.Players), func(i, j int) {
+		r.Players[i], r.Players[j] = r.Players[j], r.Players[i]
+	})
+	syncTurnOrder(r)
+}
+
+// syncTurnOrder rebuilds TurnOrder from the order of the players
+func syncTurnOrder(r *shared.Room) {
+	r.TurnOrder = make([]string, len(r.Players))
+	for i, player := range r.Players {
+		r.TurnOrder[i] = player.ID
+	}
+}
@@ -0,0 +1,100 @@
+package room
+
+import (
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetSharedDeck makes every player draw from one shuffled deck holding
+// `copies` sets of the room's cards instead of a personal deck. Zero restores
+// personal decks. It is shorthand for SetDeckSpec with Shared set.
+func (m *Manager) SetSharedDeck(room *shared.Room, copies int) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if copies < 0 || copies > config.MaxSharedDeckCopies {
+		return fmt.Errorf("shared deck copies must be between 1 and %d", config.MaxSharedDeckCopies)
+	}
+	spec := deckSpec(r)
+	switch {
+	case copies > 0:
+		spec.Shared, spec.Copies = true, copies
+	case spec.Shared:
+		spec.Shared, spec.Copies = false, config.DefaultDeckCopies
+	}
+	return m.applyDeckSpec(r, spec)
+}
+
+// overwrittenCards lists the cards removed from the board by overwrites
+func overwrittenCards(r *shared.Room) []int {
+	var out []int
+	for _, rec := range r.History {
+		if rec.Type.Normalize() == game.MovePlace && rec.PrevCell.Value != 0 {
+			out = append(out, rec.PrevCell.Value)
+		}
+	}
+	return out
+}
+
+// checkCardCount verifies that a shared deck room still accounts for every
+// card exactly once across hands, the draw pile, the board and overwritten
+// cards. A mismatch means cards were duplicated or lost.
+func checkCardCount(r *shared.Room) error {
+	if r.SharedDeck == 0 {
+		return nil
+	}
+
+	spec := deckSpec(r)
+	counts := make([]int, spec.MaxCard+1)
+	add := func(v int) {
+		if v >= 1 && v <= spec.MaxCard {
+			counts[v]++
+		}
+	}
+	for _, p := range r.Players {
+		for _, v := range p.Hand {
+			add(v)
+		}
+	}
+	for _, v := range r.CommunalPile {
+		add(v)
+	}
+	r.Board.Each(func(_ game.Coord, cell game.Cell) {
+		add(cell.Value)
+	})
+	for _, v := range overwrittenCards(r) {
+		add(v)
+	}
+
+	for v := spec.MinCard; v <= spec.MaxCard; v++ {
+		if counts[v] != r.SharedDeck {
+			return fmt.Errorf("card %d counted %d times, expected %d", v, counts[v], r.SharedDeck)
+		}
+	}
+	return nil
+}
+
+// auditCards logs and reports a card count mismatch in shared deck rooms
+func (m *Manager) auditCards(r *shared.Room) {
+	if err := checkCardCount(r); err != nil {
+		m.logEvent(r, roomlog.KindError, "", "ANTI-CHEAT: card count mismatch in room %s: %v", r.Code, err)
+		m.hub.Broadcast(r.Code, "card_count_mismatch", gin.H{
+			"message": err.Error(),
+		})
+	}
+}
+
+// exposurePenalty lowers the score of placing card by how likely a random
+// card the bot has not seen could overwrite it (shared deck rooms only)
+func exposurePenalty(r *shared.Room, bot *shared.Player, card int) int {
+	if r.SharedDeck == 0 {
+		return 0
+	}
+	unseen := game.UnseenCards(r.SharedDeck, deckSpec(r).MinCard, &r.Board, overwrittenCards(r), bot.Hand)
+	return int(game.OverwriteChance(unseen, card) * float64(config.SharedDeckExposurePenalty))
+}
@@ -0,0 +1,31 @@
+package room
+
+import (
+	"fmt"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+)
+
+// RestoreRoom adds a room rebuilt from a snapshot and resumes its turn clock.
+// The room code must be free; restored rooms belong to ownerID. Snapshots can
+// be edited before they are restored, so restored rooms are never ranked and
+// their results leave ratings alone.
+func (m *Manager) RestoreRoom(r *shared.Room, ownerID string) error {
+	existing, unlock := m.lockCode(r.Code)
+	defer unlock()
+	if existing != nil {
+		return fmt.Errorf("room %s already exists", r.Code)
+	}
+
+	r.Cfg = m.cfg
+	r.OwnerID = ownerID
+	r.Ranked = false
+	r.TurnTimer = nil
+	m.startTurnClock(r)
+	m.logState(r, record.EventCheckpoint)
+	m.store.SaveRoom(r)
+
+	m.logEvent(r, roomlog.KindRoom, "", "Room %s restored from snapshot after %d moves", r.Code, len(r.History))
+	return nil
+}
@@ -0,0 +1,42 @@
+package room
+
+import (
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+	"slices"
+)
+
+// standings orders the seats for the final ranking: the winner first, then
+// the players still in the game by their tie-break scores, then those who
+// resigned, the latest resignation first
+func standings(r *shared.Room) []string {
+	out := make([]string, 0, len(r.Players))
+	if r.WinnerID != nil {
+		out = append(out, *r.WinnerID)
+	}
+	for _, row := range rank(r) {
+		if !slices.Contains(out, row.PlayerID) {
+			out = append(out, row.PlayerID)
+		}
+	}
+
+	var resigned []string
+	for _, rec := range r.History {
+		if rec.Type.Normalize() == game.MoveResign && !slices.Contains(resigned, rec.PlayerID) {
+			resigned = append(resigned, rec.PlayerID)
+		}
+	}
+	// Seats restored without their resign move still rank below everyone playing
+	for _, p := range r.Players {
+		if p.Resigned && !slices.Contains(resigned, p.ID) {
+			resigned = append([]string{p.ID}, resigned...)
+		}
+	}
+	slices.Reverse(resigned)
+	for _, id := range resigned {
+		if !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package room
+
+import (
+	"javanese-chess/internal/shared"
+	"time"
+)
+
+// GameStats are the players' in-game stats, for the post-game screen and
+// for following a game while it is played
+type GameStats struct {
+	RoomCode string     `json:"room_code"`
+	Status   string     `json:"status"`
+	Moves    int        `json:"moves"`   // Every recorded move, including skips and swaps
+	Players  []StatLine `json:"players"` // In seat order
+}
+
+// StatLine is one player's stats. Think time counts placements only, from
+// the start of the player's turn (or the move before it) to the placement.
+type StatLine struct {
+	PlayerID      string      `json:"player_id"`
+	Name          string      `json:"name"`
+	Placements    int         `json:"placements"`
+	ThinkMs       int64       `json:"think_ms"`
+	AvgThinkMs    int64       `json:"avg_think_ms"`
+	CardsPlayed   map[int]int `json:"cards_played"` // Card value -> times placed
+	AvgCard       float64     `json:"avg_card"`
+	Captures      int         `json:"captures"` // Opponent cards overwritten
+	CapturedValue int         `json:"captured_value"`
+	Blocks        int         `json:"blocks"` // Placements that blocked an opponent's three in a row
+}
+
+// Stats reports the players' stats for the room's current or last game
+func (m *Manager) Stats(room *shared.Room) *GameStats {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+	return statsOf(r)
+}
+
+// statsOf builds the stats report from the players' running tallies
+func statsOf(r *shared.Room) *GameStats {
+	gs := &GameStats{RoomCode: r.Code, Status: r.Status, Moves: len(r.History), Players: make([]StatLine, 0, len(r.Players))}
+	for _, p := range r.Players {
+		s := p.Stats
+		line := StatLine{
+			PlayerID:      p.ID,
+			Name:          p.Name,
+			Placements:    s.Placements,
+			ThinkMs:       s.ThinkMs,
+			CardsPlayed:   map[int]int{},
+			Captures:      p.Captures,
+			CapturedValue: p.CapturedValue,
+			Blocks:        s.Blocks,
+		}
+		for card, n := range s.CardsPlayed {
+			line.CardsPlayed[card] = n
+		}
+		if s.Placements > 0 {
+			line.AvgThinkMs = s.ThinkMs / int64(s.Placements)
+			line.AvgCard = float64(s.CardSum) / float64(s.Placements)
+		}
+		gs.Players = append(gs.Players, line)
+	}
+	return gs
+}
+
+// turnStart is when the current turn began: the latest of the game start,
+// the previous move and the turn clock's start
+func turnStart(r *shared.Room) time.Time {
+	start := r.StartedAt
+	if n := len(r.History); n > 0 && r.History[n-1].At.After(start) {
+		start = r.History[n-1].At
+	}
+	if r.TurnStartedAt.After(start) {
+		start = r.TurnStartedAt
+	}
+	return start
+}
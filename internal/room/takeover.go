@@ -0,0 +1,91 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// abandonSeat hands the seat of an abandoned human to a bot so the game can
+// go on. Another human can later reclaim it with TakeOverSeat.
+func (m *Manager) abandonSeat(r *shared.Room, seat *shared.Player) {
+	seat.Abandoned = true
+	seat.IsBot = true
+	r.PendingUndo = nil
+	m.logState(r, record.EventCheckpoint)
+	m.store.SaveRoom(r)
+
+	m.logEvent(r, roomlog.KindSeat, seat.ID, "Seat %s in room %s abandoned, a bot plays on", seat.ID, r.Code)
+	m.hub.Broadcast(r.Code, "seat_abandoned", gin.H{
+		"player_id": seat.ID,
+		"players":   r.Players,
+		"next_turn": r.Players[r.TurnIdx].ID,
+	})
+	if r.Players[r.TurnIdx].ID == seat.ID {
+		m.hub.ResumeBots(r.Code)
+	}
+}
+
+// TakeOverSeat lets a new human continue an abandoned or resigned seat in a
+// casual game. The seat keeps its ID, so the newcomer inherits the seat's
+// cards on the board, hand and deck.
+func (m *Manager) TakeOverSeat(room *shared.Room, seatID string, playerName string) (*shared.Player, error) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.Status != "playing" || r.Over() {
+		return nil, errors.New("game is not in progress")
+	}
+	if r.Ranked {
+		return nil, errors.New("seats cannot be taken over in ranked games")
+	}
+	if playerName == "" {
+		return nil, errors.New("player_name is required")
+	}
+	for _, p := range r.Players {
+		if p.Name == playerName && p.ID != seatID {
+			return nil, errors.New("player name already exists in this room")
+		}
+	}
+
+	seat := findPlayer(r, seatID)
+	if seat == nil {
+		return nil, errors.New("seat not found")
+	}
+	if !seat.Resigned && !seat.Abandoned {
+		return nil, errors.New("seat is still occupied")
+	}
+
+	change := shared.SeatChange{
+		PlayerID:     seat.ID,
+		PreviousName: seat.Name,
+		NewName:      playerName,
+		At:           time.Now(),
+	}
+	r.SeatChanges = append(r.SeatChanges, change)
+
+	seat.Name = playerName
+	seat.IsBot = false
+	seat.Persona = ""
+	seat.Resigned = false
+	seat.Abandoned = false
+	seat.MissedTurns = 0
+	seat.UserID = ""
+	m.logState(r, record.EventCheckpoint)
+	m.store.SaveRoom(r)
+
+	m.logEvent(r, roomlog.KindSeat, seat.ID, "Seat %s in room %s taken over by %s (was %s)", seat.ID, r.Code, playerName, change.PreviousName)
+	m.hub.Broadcast(r.Code, "seat_taken_over", gin.H{
+		"player_id":     seat.ID,
+		"previous_name": change.PreviousName,
+		"player_name":   playerName,
+		"players":       r.Players,
+		"next_turn":     r.Players[r.TurnIdx].ID,
+	})
+	m.SyncHands(r)
+	return seat, nil
+}
@@ -0,0 +1,137 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetTimeBank enables timed turns for a room. Each turn allows turnSeconds;
+// time left over is banked (up to capSeconds) and spent when a later turn
+// runs long. A player who exhausts both loses the turn. The time bank
+// replaces a game clock.
+func (m *Manager) SetTimeBank(room *shared.Room, turnSeconds, capSeconds int) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if turnSeconds <= 0 {
+		return errors.New("turn_seconds must be positive")
+	}
+	if capSeconds < 0 {
+		return errors.New("time bank cap must be non-negative")
+	}
+
+	r.TimeBank = &shared.TimeBankRule{TurnSeconds: turnSeconds, CapSeconds: capSeconds}
+	r.GameClock = nil
+	for i := range r.Players {
+		r.Players[i].TimeBankMs = 0
+	}
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// settleTurnTime closes the current player's turn: unused turn time is added
+// to their bank up to the cap, overtime is taken out of it. Game clock rooms
+// charge the turn to the player's clock instead.
+func settleTurnTime(r *shared.Room, now time.Time) {
+	if r.GameClock != nil && !r.TurnStartedAt.IsZero() {
+		settleGameClock(r, now)
+		return
+	}
+	if r.TimeBank == nil || r.TurnStartedAt.IsZero() {
+		return
+	}
+
+	p := &r.Players[r.TurnIdx]
+	turn := time.Duration(r.TimeBank.TurnSeconds) * time.Second
+	elapsed := now.Sub(r.TurnStartedAt)
+
+	p.TimeBankMs += (turn - elapsed).Milliseconds()
+	if limit := int64(r.TimeBank.CapSeconds) * 1000; p.TimeBankMs > limit {
+		p.TimeBankMs = limit
+	}
+	if p.TimeBankMs < 0 {
+		p.TimeBankMs = 0
+	}
+}
+
+// turnDeadline is when the current player runs out of turn and bank time,
+// or of time on their game clock
+func turnDeadline(r *shared.Room) time.Time {
+	if r.GameClock != nil {
+		return r.TurnStartedAt.Add(time.Duration(r.Players[r.TurnIdx].ClockMs) * time.Millisecond)
+	}
+	turn := time.Duration(r.TimeBank.TurnSeconds) * time.Second
+	bank := time.Duration(r.Players[r.TurnIdx].TimeBankMs) * time.Millisecond
+	return r.TurnStartedAt.Add(turn + bank)
+}
+
+// startTurnClock restarts the clock for the player to move and arms the
+// timeout for their turn: the turn passes in time bank rooms and the player
+// loses on a game clock
+func (m *Manager) startTurnClock(r *shared.Room) {
+	if r.TurnTimer != nil {
+		r.TurnTimer.Stop()
+		r.TurnTimer = nil
+	}
+	if (r.TimeBank == nil && r.GameClock == nil) || r.Status != "playing" || r.Over() {
+		return
+	}
+
+	timeout := m.turnTimeout
+	if r.GameClock != nil {
+		timeout = m.flagFall
+	}
+
+	r.TurnStartedAt = time.Now()
+	code, startedAt := r.Code, r.TurnStartedAt
+	playerID := r.Players[r.TurnIdx].ID
+	r.TurnTimer = time.AfterFunc(time.Until(turnDeadline(r)), func() {
+		timeout(code, playerID, startedAt)
+	})
+}
+
+// turnTimeout passes the turn of a player who ran out of time. Stale timers
+// (the turn already moved on) are ignored.
+func (m *Manager) turnTimeout(code, playerID string, startedAt time.Time) {
+	r, unlock := m.lockCode(code)
+	defer unlock()
+	if r == nil || r.Over() || !r.TurnStartedAt.Equal(startedAt) || r.Players[r.TurnIdx].ID != playerID {
+		return
+	}
+
+	m.logEvent(r, roomlog.KindClock, playerID, "Player %s ran out of time in room %s", playerID, r.Code)
+	m.hub.Broadcast(r.Code, "turn_timeout", gin.H{
+		"player_id": playerID,
+	})
+	r.Players[r.TurnIdx].MissedTurns++
+	m.recordSkip(r, playerID, "timeout")
+	m.abandonIfAway(r, playerID)
+	m.skipStuckPlayers(r)
+	m.hub.ResumeBots(r.Code)
+}
+
+// clockView describes the turn clock for broadcasts; nil for untimed rooms
+func clockView(r *shared.Room) gin.H {
+	if r.GameClock != nil {
+		return gameClockView(r)
+	}
+	if r.TimeBank == nil {
+		return nil
+	}
+
+	banks := make(map[string]int64, len(r.Players))
+	for _, p := range r.Players {
+		banks[p.ID] = p.TimeBankMs
+	}
+	return gin.H{
+		"turn_seconds":    r.TimeBank.TurnSeconds,
+		"cap_seconds":     r.TimeBank.CapSeconds,
+		"turn_started_at": r.TurnStartedAt,
+		"deadline":        turnDeadline(r),
+		"time_bank_ms":    banks,
+	}
+}
@@ -0,0 +1,20 @@
+package room
+
+import (
+	"context"
+	"javanese-chess/internal/shared"
+	"javanese-chess/internal/tracing"
+)
+
+// roomSpan starts a span for an action by a player in a room
+func roomSpan(ctx context.Context, name string, r *shared.Room, playerID string, attrs ...tracing.Attr) (context.Context, *tracing.Span) {
+	return tracing.Start(ctx, name, append([]tracing.Attr{
+		tracing.String("room.code", r.Code),
+		tracing.String("player.id", playerID),
+	}, attrs...)...)
+}
+
+// placeAttrs describes a card placement on a span
+func placeAttrs(x, y, card int) []tracing.Attr {
+	return []tracing.Attr{tracing.Int("move.x", x), tracing.Int("move.y", y), tracing.Int("move.card", card)}
+}
@@ -0,0 +1,58 @@
+package room
+
+import (
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+	"javanese-chess/internal/training"
+	"slices"
+)
+
+// SetTrainingRecorder turns on the export of every move played for training
+// models; nil turns it off
+func (m *Manager) SetTrainingRecorder(rc *training.Recorder) {
+	m.training = rc
+}
+
+// recordPosition hands the position the current player faced, before mv is
+// applied, to the training recorder
+func (m *Manager) recordPosition(r *shared.Room, cp *shared.Player, mv game.Move) {
+	if m.training == nil {
+		return
+	}
+	weights := m.cfg.DefaultWeights
+	if cp.IsBot {
+		weights = m.botConfig(r, cp).DefaultWeights
+	}
+	m.training.Record(training.Position{
+		RoomCode: r.Code,
+		Ply:      len(r.History),
+		PlayerID: cp.ID,
+		Bot:      cp.IsBot,
+		Board:    r.Board.Clone(),
+		Hand:     append([]int(nil), cp.Hand...),
+		Legal:    engineFor(r).LegalMoves(&r.Board, cp.Hand, cp.ID),
+		Chosen:   mv,
+		Weights:  weights,
+	})
+}
+
+// recordOutcome sends the finished game to the training export: a win for
+// the winner, a draw for the leaders of a drawn game, a loss otherwise
+func (m *Manager) recordOutcome(r *shared.Room) {
+	if m.training == nil {
+		return
+	}
+	outcome := make(map[string]float64, len(r.Players))
+	leaders := drawnLeaders(r)
+	for _, p := range r.Players {
+		switch {
+		case r.WinnerID != nil && *r.WinnerID == p.ID:
+			outcome[p.ID] = training.OutcomeWin
+		case slices.Contains(leaders, p.ID):
+			outcome[p.ID] = training.OutcomeDraw
+		default:
+			outcome[p.ID] = training.OutcomeLoss
+		}
+	}
+	m.training.Finish(r.Code, outcome)
+}
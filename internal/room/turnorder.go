@@ -0,0 +1,118 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/shared"
+)
+
+// SetTurnRule chooses who opens the room's games and which way the turn
+// passes round the table. Empty fields keep the defaults: a random opener,
+// clockwise.
+func (m *Manager) SetTurnRule(room *shared.Room, rule shared.TurnOrderRule) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	switch rule.First {
+	case "", shared.FirstRandom, shared.FirstMaster, shared.FirstLoser:
+	default:
+		return errors.New("first_player must be random, master or loser")
+	}
+	switch rule.Rotation {
+	case "", shared.RotationClockwise, shared.RotationCounterclockwise:
+	default:
+		return errors.New("rotation must be clockwise or counterclockwise")
+	}
+
+	r.TurnRule = rule
+	m.store.SaveRoom(r)
+	return nil
+}
+
+// drawTurnOrder puts the players in turn order for the first game of the
+// room, opener first. Random rooms shuffle the seats, the others keep them
+// and pick the opener by the room's rule; counterclockwise rooms then take
+// their turns against the seating. Later games keep this order.
+func drawTurnOrder(r *shared.Room) {
+	var opener string
+	switch r.TurnRule.First {
+	case shared.FirstMaster:
+		opener = masterID(r)
+	case shared.FirstLoser:
+		// No game has been lost yet
+		opener = r.Players[roomRand(r).Intn(len(r.Players))].ID
+	default:
+		shuffleTurnOrder(r)
+		opener = r.Players[0].ID
+	}
+
+	if r.TurnRule.Rotation == shared.RotationCounterclockwise {
+		for i, j := 0, len(r.Players)-1; i < j; i, j = i+1, j-1 {
+			r.Players[i], r.Players[j] = r.Players[j], r.Players[i]
+		}
+	}
+	if idx := playerIndex(r, opener); idx > 0 {
+		r.Players = append(append([]shared.Player(nil), r.Players[idx:]...), r.Players[:idx]...)
+	}
+
+	syncTurnOrder(r)
+	r.TurnIdx = 0
+	r.FirstTurnIdx = 0
+}
+
+// nextOpener returns the index of the player to open the next game: the
+// room master or the loser of the game just finished when the room's rule
+// asks for one, otherwise fallback
+func nextOpener(r *shared.Room, fallback int) int {
+	var opener string
+	switch r.TurnRule.First {
+	case shared.FirstMaster:
+		opener = masterID(r)
+	case shared.FirstLoser:
+		opener = lastLoser(r)
+	}
+	if idx := playerIndex(r, opener); idx >= 0 {
+		return idx
+	}
+	return fallback
+}
+
+// masterID is the room master's seat. Rooms from before the master was
+// recorded fall back to the first seat.
+func masterID(r *shared.Room) string {
+	if r.MasterID != "" {
+		return r.MasterID
+	}
+	return r.Players[0].ID
+}
+
+// lastLoser is the player who did worst in the game just finished: one who
+// resigned, otherwise the lowest on the tie-break scores besides the winner.
+// Empty when the game was called off.
+func lastLoser(r *shared.Room) string {
+	if r.AbortedBy != nil {
+		return ""
+	}
+	for _, p := range r.Players {
+		if p.Resigned {
+			return p.ID
+		}
+	}
+
+	rows := rank(r)
+	for i := len(rows) - 1; i >= 0; i-- {
+		if r.WinnerID == nil || rows[i].PlayerID != *r.WinnerID {
+			return rows[i].PlayerID
+		}
+	}
+	return ""
+}
+
+// playerIndex returns the player's index in the room, or -1
+func playerIndex(r *shared.Room, playerID string) int {
+	for i, p := range r.Players {
+		if p.ID == playerID {
+			return i
+		}
+	}
+	return -1
+}
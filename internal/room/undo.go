@@ -0,0 +1,204 @@
+package room
+
+import (
+	"errors"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/roomlog"
+	"javanese-chess/internal/shared"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestUndo asks to take back the requester's last move. Against bots-only
+// opponents the undo is applied immediately; otherwise the request waits for
+// RespondUndo. Returns true when the undo has been applied.
+func (m *Manager) RequestUndo(room *shared.Room, playerID string) (bool, error) {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	if r.Over() || isClosed(r) {
+		return false, errors.New("game is already over")
+	}
+	if r.PendingUndo != nil {
+		return false, errors.New("an undo request is already pending")
+	}
+	if _, err := undoDepth(r, playerID); err != nil {
+		return false, err
+	}
+
+	// Bots always agree
+	if !hasHumanOpponent(r, playerID) {
+		if err := m.undo(r, playerID); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	r.PendingUndo = &shared.UndoRequest{
+		RequesterID: playerID,
+		RequestedAt: time.Now(),
+	}
+	m.store.SaveRoom(r)
+
+	m.hub.Broadcast(r.Code, "undo_requested", gin.H{
+		"requester_id": playerID,
+	})
+	return false, nil
+}
+
+// RespondUndo accepts or declines the pending undo request on behalf of an opponent
+func (m *Manager) RespondUndo(room *shared.Room, playerID string, accept bool) error {
+	r, unlock := m.lockRoom(room)
+	defer unlock()
+
+	pending := r.PendingUndo
+	if pending == nil {
+		return errors.New("no undo request pending")
+	}
+	// The game may have ended, won or drawn, while the request waited
+	if r.Over() || isClosed(r) {
+		return errors.New("game is already over")
+	}
+	if pending.RequesterID == playerID {
+		return errors.New("cannot respond to your own undo request")
+	}
+	if !isPlayer(r, playerID) {
+		return errors.New("player not in room")
+	}
+
+	r.PendingUndo = nil
+	if !accept {
+		m.store.SaveRoom(r)
+		m.hub.Broadcast(r.Code, "undo_declined", gin.H{
+			"requester_id": pending.RequesterID,
+			"responder_id": playerID,
+		})
+		return nil
+	}
+
+	return m.undo(r, pending.RequesterID)
+}
+
+// undo reverts the requester's last move together with any bot replies played after it
+func (m *Manager) undo(r *shared.Room, requesterID string) error {
+	depth, err := undoDepth(r, requesterID)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < depth; i++ {
+		rec := r.History[len(r.History)-1]
+		r.History = r.History[:len(r.History)-1]
+		revertRecord(r, rec)
+	}
+	r.Board.LastMove = game.LastPlaced(r.History)
+	r.PendingUndo = nil
+	m.startTurnClock(r)
+	m.logUndo(r, len(r.History))
+	m.training.Truncate(r.Code, len(r.History))
+	m.store.SaveRoom(r)
+
+	m.logEvent(r, roomlog.KindMove, requesterID, "Undo applied in room %s: %d move(s) reverted for %s", r.Code, depth, requesterID)
+	m.hub.Broadcast(r.Code, "undo_applied", gin.H{
+		"requester_id": requesterID,
+		"reverted":     depth,
+		"board":        r.Board,
+		"next_turn":    r.Players[r.TurnIdx].ID,
+	})
+	m.SyncHands(r)
+	return nil
+}
+
+// undoDepth returns how many records must be popped to reach the requester's last move
+func undoDepth(r *shared.Room, requesterID string) (int, error) {
+	for i := len(r.History) - 1; i >= 0; i-- {
+		rec := r.History[i]
+		if rec.PlayerID == requesterID {
+			return len(r.History) - i, nil
+		}
+		if !isBot(r, rec.PlayerID) {
+			break
+		}
+	}
+	return 0, errors.New("no move to undo")
+}
+
+// revertRecord restores the board cell, the hand, the deck and the turn index
+func revertRecord(r *shared.Room, rec game.MoveRecord) {
+	game.RevertMove(&r.Board, rec)
+	r.TurnIdx = rec.TurnIdx
+
+	p := findPlayer(r, rec.PlayerID)
+	if p == nil {
+		return
+	}
+
+	switch rec.Type.Normalize() {
+	case game.MoveSkip:
+		return
+	case game.MoveResign:
+		p.Resigned = false
+		return
+	}
+	p.TallyCapture(rec, -1)
+	p.TallyStats(rec, -1)
+
+	// Put the drawn card back on top of the deck (or the communal pile)
+	if rec.DrawnCard != 0 && len(p.Hand) > 0 {
+		p.Hand = p.Hand[:len(p.Hand)-1]
+		if rec.FromPile {
+			r.CommunalPile = append([]int{rec.DrawnCard}, r.CommunalPile...)
+		} else {
+			p.Deck = append([]int{rec.DrawnCard}, p.Deck...)
+		}
+	}
+
+	// A swapped card was placed under the deck (or the shared pile)
+	if rec.Type == game.MoveSwap {
+		deck := &p.Deck
+		if rec.FromPile {
+			deck = &r.CommunalPile
+		}
+		if len(*deck) > 0 {
+			*deck = (*deck)[:len(*deck)-1]
+		}
+	}
+
+	// Return the played card to its original hand position
+	idx := rec.HandIdx
+	if idx > len(p.Hand) {
+		idx = len(p.Hand)
+	}
+	hand := make([]int, 0, len(p.Hand)+1)
+	hand = append(hand, p.Hand[:idx]...)
+	hand = append(hand, rec.Card)
+	p.Hand = append(hand, p.Hand[idx:]...)
+}
+
+func isPlayer(r *shared.Room, playerID string) bool {
+	for _, p := range r.Players {
+		if p.ID == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+func isBot(r *shared.Room, playerID string) bool {
+	for _, p := range r.Players {
+		if p.ID == playerID {
+			return p.IsBot
+		}
+	}
+	return false
+}
+
+func hasHumanOpponent(r *shared.Room, playerID string) bool {
+	for _, p := range r.Players {
+		if p.ID != playerID && !p.IsBot {
+			return true
+		}
+	}
+	return false
+}
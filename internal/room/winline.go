@@ -0,0 +1,24 @@
+package room
+
+import (
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/shared"
+)
+
+// winLine tags each cell of the winning line with the move number that
+// placed its current card
+func winLine(r *shared.Room, coords []game.Coord) []game.WinCell {
+	out := make([]game.WinCell, 0, len(coords))
+	for _, c := range coords {
+		cell := game.WinCell{Coord: c}
+		for i := len(r.History) - 1; i >= 0; i-- {
+			rec := r.History[i]
+			if rec.Type.Normalize() == game.MovePlace && rec.X == c.X && rec.Y == c.Y {
+				cell.MoveNo = i + 1
+				break
+			}
+		}
+		out = append(out, cell)
+	}
+	return out
+}
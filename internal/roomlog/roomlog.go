@@ -0,0 +1,107 @@
+// Package roomlog keeps the recent events of every room in memory, so
+// operators can follow what happened in one room without reading the
+// process log. Each room keeps a fixed number of events; the oldest rooms
+// are forgotten once too many are tracked.
+package roomlog
+
+import (
+	"sync"
+	"time"
+)
+
+// Event kinds
+const (
+	KindRoom  = "room"  // Created, restored or closed
+	KindSeat  = "seat"  // Joins, bots seated, seats abandoned or taken over
+	KindGame  = "game"  // Starts, results, aborts, rematches and series
+	KindMove  = "move"  // Moves, skips, resignations and undos
+	KindClock = "clock" // Players running out of time
+	KindBot   = "bot"   // Bots falling back or running out of budget
+	KindError = "error" // Rejected moves and failed checks
+)
+
+// Entry is one event of a room. Seq numbers a room's events from 1.
+type Entry struct {
+	Seq      uint64    `json:"seq"`
+	At       time.Time `json:"at"`
+	Kind     string    `json:"kind"`
+	PlayerID string    `json:"player_id,omitempty"`
+	Message  string    `json:"message"`
+}
+
+// Log holds the recent events of each room. The zero value is not usable;
+// a nil Log records nothing.
+type Log struct {
+	mu       sync.Mutex
+	size     int // Events kept per room
+	maxRooms int
+	rooms    map[string]*ring
+	order    []string // Room codes in the order they were first seen
+}
+
+// ring is one room's events, overwritten oldest first once full
+type ring struct {
+	entries []Entry
+	next    int // Where the next event goes once the ring is full
+	seq     uint64
+}
+
+// New returns a log keeping size events for each of at most maxRooms rooms
+func New(size, maxRooms int) *Log {
+	return &Log{size: size, maxRooms: maxRooms, rooms: make(map[string]*ring)}
+}
+
+// Add records an event of a room
+func (l *Log) Add(code, kind, playerID, message string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rg, ok := l.rooms[code]
+	if !ok {
+		if len(l.order) >= l.maxRooms {
+			delete(l.rooms, l.order[0])
+			l.order = l.order[1:]
+		}
+		rg = &ring{entries: make([]Entry, 0, l.size)}
+		l.rooms[code] = rg
+		l.order = append(l.order, code)
+	}
+
+	rg.seq++
+	e := Entry{Seq: rg.seq, At: time.Now(), Kind: kind, PlayerID: playerID, Message: message}
+	if len(rg.entries) < l.size {
+		rg.entries = append(rg.entries, e)
+		return
+	}
+	rg.entries[rg.next] = e
+	rg.next = (rg.next + 1) % l.size
+}
+
+// Since returns a room's events numbered after since, oldest first.
+// Truncated reports that events after since were already overwritten.
+func (l *Log) Since(code string, since uint64) (events []Entry, truncated bool) {
+	if l == nil {
+		return nil, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rg, ok := l.rooms[code]
+	if !ok {
+		return []Entry{}, false
+	}
+	events = make([]Entry, 0, len(rg.entries))
+	for i := range rg.entries {
+		e := rg.entries[(rg.next+i)%len(rg.entries)]
+		if e.Seq > since {
+			events = append(events, e)
+		}
+	}
+	if len(events) > 0 {
+		truncated = events[0].Seq > since+1
+	}
+	return events, truncated
+}
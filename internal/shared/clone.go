@@ -0,0 +1,77 @@
+package shared
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Clone returns a deep copy of the room's game state, so the copy can be
+// changed without affecting the original. The room configuration, the random
+// source and the turn timer are shared: they are synchronized on their own
+// and belong to the room rather than to one copy of its state.
+func (r *Room) Clone() *Room {
+	out := *r
+	out.Game = r.Game.clone()
+
+	out.Players = make([]Player, len(r.Players))
+	for i, p := range r.Players {
+		p.Hand = slices.Clone(p.Hand)
+		p.Deck = slices.Clone(p.Deck)
+		p.Stats.CardsPlayed = maps.Clone(p.Stats.CardsPlayed)
+		out.Players[i] = p
+	}
+
+	out.TurnOrder = slices.Clone(r.TurnOrder)
+	out.SeatChanges = slices.Clone(r.SeatChanges)
+	out.Chat = slices.Clone(r.Chat)
+	out.PasswordHash = slices.Clone(r.PasswordHash)
+
+	if r.TimeBank != nil {
+		rule := *r.TimeBank
+		out.TimeBank = &rule
+	}
+	if r.GameClock != nil {
+		rule := *r.GameClock
+		out.GameClock = &rule
+	}
+	if r.Experiment != nil {
+		arm := *r.Experiment
+		out.Experiment = &arm
+	}
+	if r.Match != nil {
+		out.Match = r.Match.clone()
+	}
+	out.RematchVotes = maps.Clone(r.RematchVotes)
+	out.ChatSent = cloneSent(r.ChatSent)
+	out.ReactionSent = cloneSent(r.ReactionSent)
+	return &out
+}
+
+// cloneSent copies a per-player log of send times
+func cloneSent(sent map[string][]time.Time) map[string][]time.Time {
+	if sent == nil {
+		return nil
+	}
+	out := make(map[string][]time.Time, len(sent))
+	for id, times := range sent {
+		out[id] = slices.Clone(times)
+	}
+	return out
+}
+
+func (m *Match) clone() *Match {
+	out := *m
+	out.WinnerID = cloneString(m.WinnerID)
+	out.Results = slices.Clone(m.Results)
+	out.Scores = maps.Clone(m.Scores)
+	return &out
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := *s
+	return &v
+}
@@ -0,0 +1,70 @@
+package shared
+
+import (
+	"javanese-chess/internal/game"
+	"slices"
+	"time"
+)
+
+// Game is the state of one game played in a room: the board, whose turn it
+// is, the moves played and how the game ended. The lobby side of a room (its
+// code, seats and settings) outlives its games; each game starts from
+// NewGame. The JSON names are the ones rooms have always used, so a Game
+// marshals on its own and stored rooms load unchanged. Like on the room, the
+// move history and the draw pile are left out of the JSON and stored next to
+// it.
+type Game struct {
+	// No counts the games started in the room, from 1; 0 is the empty game
+	// of a lobby that has not started yet
+	No int `json:"game_no"`
+
+	Board     game.Board `json:"board"`
+	TurnIdx   int        `json:"turn_idx"`
+	WinnerID  *string    `json:"winner_id"`
+	Draw      bool       `json:"draw"`
+	StartedAt time.Time  `json:"started_at"`
+	// FirstTurnIdx is the player index that opened the game
+	FirstTurnIdx int `json:"first_turn_idx"`
+	// TurnStartedAt is when the current turn's clock started (timed rooms)
+	TurnStartedAt time.Time `json:"turn_started_at"`
+
+	// WinLine holds the cells that won the game, for finish animations
+	WinLine []game.WinCell `json:"win_line,omitempty"`
+	// Metrics is the snapshot taken when the game ended
+	Metrics *GameMetrics `json:"metrics,omitempty"`
+	// AbortedBy is the player who called off the game in its first moves
+	AbortedBy *string `json:"aborted_by,omitempty"`
+
+	// History keeps reversible records of every move in the game
+	History     []game.MoveRecord `json:"-"`
+	PendingUndo *UndoRequest      `json:"pending_undo,omitempty"`
+	// CommunalPile is the shared deck or the communal draw pile
+	CommunalPile []int `json:"-"`
+}
+
+// NewGame sets up game number no on board, started now
+func NewGame(no int, board game.Board) Game {
+	return Game{No: no, Board: board, StartedAt: time.Now()}
+}
+
+// Over reports whether the game has been won or drawn
+func (g *Game) Over() bool {
+	return g.WinnerID != nil || g.Draw
+}
+
+// clone returns a deep copy of the game
+func (g *Game) clone() Game {
+	out := *g
+	out.Board = g.Board.Clone()
+	out.Board.Seats = slices.Clone(g.Board.Seats)
+	out.History = slices.Clone(g.History)
+	out.CommunalPile = slices.Clone(g.CommunalPile)
+	out.WinLine = slices.Clone(g.WinLine)
+	out.WinnerID = cloneString(g.WinnerID)
+	out.AbortedBy = cloneString(g.AbortedBy)
+	if g.PendingUndo != nil {
+		undo := *g.PendingUndo
+		out.PendingUndo = &undo
+	}
+	return out
+}
@@ -0,0 +1,15 @@
+package shared
+
+// NotReady returns the names of the players the lobby is waiting for before
+// the game can start: every human but the room master, who starts the game,
+// until they mark themselves ready. Bots are always ready.
+func (r *Room) NotReady() []string {
+	var names []string
+	for i, p := range r.Players {
+		if i == 0 || p.IsBot || p.Ready {
+			continue
+		}
+		names = append(names, p.Name)
+	}
+	return names
+}
@@ -0,0 +1,102 @@
+package shared
+
+// BroadcastPolicy controls what public broadcasts reveal about a room. The
+// zero value is an open room: human hands and deck counts are shown, cards
+// drawn by humans are public and bot evaluation scores are left out. Bot hands
+// and every deck's contents are never shown, whatever the policy.
+type BroadcastPolicy struct {
+	HideHands      bool `json:"hide_hands"`       // Players carry hand sizes instead of hand contents
+	HideDeckCounts bool `json:"hide_deck_counts"` // Leave out how many cards remain in decks and the shared pile
+	PrivateDraws   bool `json:"private_draws"`    // Drawn cards are only sent to the player who drew them
+	EvalScores     bool `json:"eval_scores"`      // Bot moves carry the heuristic score of the chosen move
+}
+
+// HiddenHandsPolicy keeps every card private; each player gets their own hand
+// over a private message
+var HiddenHandsPolicy = BroadcastPolicy{HideHands: true, PrivateDraws: true}
+
+// PrivateHands reports whether players need their hand sent privately because
+// broadcasts do not show it in full
+func (p BroadcastPolicy) PrivateHands() bool {
+	return p.HideHands || p.PrivateDraws
+}
+
+// Payload keys that Project redacts
+const (
+	keyPlayers    = "players"
+	keyMover      = "playerID"
+	keyDrawnCard  = "drawnCard"
+	keyDeckCount  = "deck_count"
+	keyPileCount  = "pileCount"
+	keyEvaluation = "evaluation"
+)
+
+// Project applies the room's broadcast policy to an event payload. Every
+// public broadcast goes through it, so callers put the full state in their
+// payloads and never redact by hand. Player lists are replaced by the public
+// player view, and the card a bot drew is left out of its move. The payload
+// itself is not modified.
+func (r *Room) Project(payload map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(payload))
+	for k, v := range payload {
+		out[k] = v
+	}
+	if _, ok := out[keyPlayers].([]Player); ok {
+		out[keyPlayers] = r.PlayerView()
+	}
+	if r.Policy.PrivateDraws || r.isBot(out[keyMover]) {
+		delete(out, keyDrawnCard)
+	}
+	if r.Policy.HideDeckCounts {
+		delete(out, keyDeckCount)
+		delete(out, keyPileCount)
+	}
+	if !r.Policy.EvalScores {
+		delete(out, keyEvaluation)
+	}
+	return out
+}
+
+// PlayerView returns the player list as the room's policy allows every
+// client to see it
+func (r *Room) PlayerView() []PublicPlayer {
+	out := make([]PublicPlayer, 0, len(r.Players))
+	for _, p := range r.Players {
+		pp := PublicPlayer{
+			ID:          p.ID,
+			Name:        p.Name,
+			IsBot:       p.IsBot,
+			Color:       p.Color,
+			Resigned:    p.Resigned,
+			Abandoned:   p.Abandoned,
+			UserID:      p.UserID,
+			Persona:     p.Persona,
+			Personality: p.Personality,
+			HandCount:   len(p.Hand),
+			TimeBankMs:  p.TimeBankMs,
+			ClockMs:     p.ClockMs,
+			Ready:       p.Ready,
+			Captures:    p.Captures,
+			CapturedVal: p.CapturedValue,
+		}
+		if !r.Policy.HideHands && !p.IsBot {
+			pp.Hand = p.Hand
+		}
+		if !r.Policy.HideDeckCounts {
+			count := len(p.Deck)
+			pp.DeckCount = &count
+		}
+		out = append(out, pp)
+	}
+	return out
+}
+
+// isBot reports whether id names a bot seated in the room
+func (r *Room) isBot(id interface{}) bool {
+	for _, p := range r.Players {
+		if p.ID == id {
+			return p.IsBot
+		}
+	}
+	return false
+}
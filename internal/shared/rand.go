@@ -0,0 +1,44 @@
+package shared
+
+import "math/rand"
+
+// CountingSource is a seeded math/rand source that counts its draws, so a
+// room's random state can be saved as its seed and draw count
+type CountingSource struct {
+	src   rand.Source64
+	draws uint64
+}
+
+// MaxDraws is the most draws a saved random state may claim. Restoring one
+// replays every draw, so counts from outside the server are checked against
+// it first; a room draws far fewer over many games.
+const MaxDraws = 1 << 24
+
+// NewCountingSource seeds a source and fast-forwards it past draws values
+func NewCountingSource(seed int64, draws uint64) *CountingSource {
+	s := &CountingSource{src: rand.NewSource(seed).(rand.Source64)}
+	for s.draws < draws {
+		s.Uint64()
+	}
+	return s
+}
+
+func (s *CountingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *CountingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+func (s *CountingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.draws = 0
+}
+
+// Draws returns how many values the source has produced since seeding
+func (s *CountingSource) Draws() uint64 {
+	return s.draws
+}
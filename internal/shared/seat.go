@@ -0,0 +1,8 @@
+package shared
+
+// Seat is one place at a table arranged in advance, such as a tournament
+// pairing: the name to seat and whether a bot plays it
+type Seat struct {
+	Name string `json:"name"`
+	Bot  bool   `json:"bot"`
+}
@@ -3,28 +3,182 @@ package shared
 import (
 	"javanese-chess/internal/config"
 	"javanese-chess/internal/game"
+	"math/rand"
 	"time"
 )
 
+// Room is a table players gather at: its code, seats, settings and the
+// game currently played there. The game's state lives in the embedded Game,
+// whose fields read as the room's own.
 type Room struct {
+	// Game is the game played in the room now. StartGame replaces it with
+	// a fresh one, so nothing of a finished game leaks into the next.
+	Game
+
 	Code       string             `json:"code"`
-	Board      game.Board         `json:"board"`
 	Players    []Player           `json:"players"`
-	TurnIdx    int                `json:"turn_idx"`
-	WinnerID   *string            `json:"winner_id"`
-	Draw       bool               `json:"draw"`
 	CreatedAt  time.Time          `json:"created_at"`
 	Cfg        config.Config      `json:"-"`
 	RoomConfig *config.RoomConfig `json:"room_config,omitempty"`
 	TurnOrder  []string           `json:"turn_order"`
-	Status     string             `json:"status"` // "lobby" or "playing"
+	Status     string             `json:"status"` // "lobby", "playing", "ended", "aborted" or "closed"
+	Match      *Match             `json:"match,omitempty"`
+	// Policy decides what public broadcasts reveal (see Project)
+	Policy BroadcastPolicy `json:"broadcast_policy"`
+
+	// PasswordHash protects the lobby; nil means anyone can join
+	PasswordHash []byte `json:"-"`
+
+	// Hints lets human players ask for suggested moves
+	Hints bool `json:"hints"`
+
+	// Demo rooms seat only bots, who play each other for spectators
+	Demo bool `json:"demo,omitempty"`
+
+	// OpeningBook lets bots play their first moves from the opening book
+	OpeningBook bool `json:"opening_book,omitempty"`
+
+	// Hold lets bots hold a card (swap it for the top of their deck) when
+	// no placement is worth making
+	Hold bool `json:"hold,omitempty"`
+
+	// Ranked rooms move ratings and do not allow seat takeovers
+	Ranked      bool         `json:"ranked"`
+	SeatChanges []SeatChange `json:"seat_changes,omitempty"`
+
+	// Manual rooms are for testing: clients may set the dealt hands, so
+	// they are never ranked and their games are not rated
+	Manual bool `json:"manual,omitempty"`
+
+	// Seed drives all dealing and shuffling in the room through Rand.
+	// RandSource counts the draws so the random state can be snapshotted.
+	// Seed is kept out of the room's JSON since it predicts every deal;
+	// snapshots carry it, and records of finished games show it.
+	Seed       int64           `json:"-"`
+	Rand       *rand.Rand      `json:"-"`
+	RandSource *CountingSource `json:"-"`
+
+	// OwnerID is the account that created the room; empty for anonymous rooms
+	OwnerID string `json:"owner_id,omitempty"`
+	// RematchVotes holds the human players who accepted a rematch
+	RematchVotes map[string]bool `json:"rematch_votes,omitempty"`
+
+	// Engine names the rules engine the room plays by ("" = the default)
+	Engine string `json:"engine,omitempty"`
+
+	// DeckRule decides what happens once a player's deck is empty
+	DeckRule string `json:"deck_rule,omitempty"`
+	// SharedDeck is the number of card sets in one deck all players draw
+	// from; 0 means personal decks. The shared deck lives in CommunalPile.
+	SharedDeck int `json:"shared_deck,omitempty"`
+
+	// Chat is the bounded in-room chat log; ChatSent holds each player's
+	// recent message times for rate limiting
+	Chat     []ChatMessage          `json:"-"`
+	ChatSent map[string][]time.Time `json:"-"`
+	// ReactionSent holds each player's recent reaction times; reactions
+	// themselves are only broadcast
+	ReactionSent map[string][]time.Time `json:"-"`
+
+	// TimeBank enables per-turn time limits with banked unused time (nil = untimed)
+	TimeBank  *TimeBankRule `json:"time_bank,omitempty"`
+	TurnTimer *time.Timer   `json:"-"`
+	// GameClock gives each player a time budget for the whole game instead
+	// (nil = no game clock); a room runs one clock or the other
+	GameClock *GameClockRule `json:"game_clock,omitempty"`
+
+	// Abandon decides when a disconnected player is given up on
+	Abandon AbandonRule `json:"abandon"`
+
+	// TurnRule decides who opens each game and which way the turn passes.
+	// MasterID is the seat that opened the lobby, kept because starting
+	// the game reorders the players.
+	TurnRule TurnOrderRule `json:"turn_rule"`
+	MasterID string        `json:"master_id,omitempty"`
+
+	// Experiment is the weight experiment arm the room was assigned to when
+	// it was created; its bots play with the arm's weights
+	Experiment *ArmAssignment `json:"experiment,omitempty"`
+
+	// Locale is the language errors are reported in to clients that do
+	// not ask for one ("" = the server default)
+	Locale string `json:"locale,omitempty"`
+
+	// CaptureTie breaks ties on the line and total sums by captures, then
+	// by captured value, before the game is called a draw
+	CaptureTie bool `json:"capture_tie,omitempty"`
+}
+
+// ArmAssignment places a room in one arm of a heuristic weight experiment
+type ArmAssignment struct {
+	ExperimentID string `json:"experiment_id"`
+	Arm          string `json:"arm"`
+}
+
+// Deck exhaustion rules
+const (
+	DeckRuleContinue = "continue" // Play on with the cards left in hand (default)
+	DeckRuleCommunal = "communal" // Draw from a shared pile once the own deck is empty
+	DeckRuleEndgame  = "endgame"  // Score the game as soon as any deck runs out
+)
+
+// What happens to the seat of an abandoned player
+const (
+	AbandonBot     = "bot"     // A bot plays on in the seat (default)
+	AbandonForfeit = "forfeit" // The player resigns
+)
+
+// Who opens a game
+const (
+	FirstRandom = "random" // Drawn at random, then passed round the table in later games (default)
+	FirstMaster = "master" // The room master opens every game
+	FirstLoser  = "loser"  // The loser of the previous game; drawn at random for the first one
+)
+
+// Which way the turn passes round the table
+const (
+	RotationClockwise        = "clockwise"        // In seating order (default)
+	RotationCounterclockwise = "counterclockwise" // Against seating order
+)
+
+// TurnOrderRule is how a room orders its players' turns. Empty fields mean
+// FirstRandom and RotationClockwise.
+type TurnOrderRule struct {
+	First    string `json:"first,omitempty"`
+	Rotation string `json:"rotation,omitempty"`
+}
+
+// AbandonRule gives up on a disconnected player after Turns of their turns
+// timed out in a row, or after Minutes offline. Zero Turns never counts
+// turns; zero Minutes uses the server's reconnect grace.
+type AbandonRule struct {
+	Action  string `json:"action,omitempty"` // AbandonBot or AbandonForfeit; empty means AbandonBot
+	Turns   int    `json:"turns,omitempty"`
+	Minutes int    `json:"minutes,omitempty"`
+}
+
+// TimeBankRule grants each turn TurnSeconds; unused time is banked up to
+// CapSeconds and spent automatically when a later turn runs over
+type TimeBankRule struct {
+	TurnSeconds int `json:"turn_seconds"`
+	CapSeconds  int `json:"cap_seconds"`
+}
+
+// GameClockRule is a chess clock: each player has InitialSeconds for the
+// whole game and gains IncrementSeconds with every move they make. A player
+// whose clock runs out loses.
+type GameClockRule struct {
+	InitialSeconds   int `json:"initial_seconds"`
+	IncrementSeconds int `json:"increment_seconds"`
 }
 
 type Move struct {
-	X        int    `json:"x"`
-	Y        int    `json:"y"`
-	Card     int    `json:"card"`
-	PlayerID string `json:"player_id"`
+	X        int           `json:"x"`
+	Y        int           `json:"y"`
+	Card     int           `json:"card"`
+	PlayerID string        `json:"player_id"`
+	Type     game.MoveType `json:"type,omitempty"` // Empty means place
+	Score    int           `json:"-"`              // Bot moves: heuristic score of the chosen move
 }
 
 type Player struct {
@@ -34,4 +188,247 @@ type Player struct {
 	Hand  []int  `json:"hand"`
 	Deck  []int  `json:"-"`
 	Color string `json:"color"` // Added field for player color
+	// Resigned players keep their cards on the board but no longer take turns
+	Resigned bool `json:"resigned"`
+	// Abandoned marks a human seat that was left and is being played by a bot
+	Abandoned bool `json:"abandoned"`
+	// UserID links the seat to an authenticated account; empty for anonymous players
+	UserID string `json:"user_id,omitempty"`
+	// Persona is the bot character name (bots only)
+	Persona string `json:"persona,omitempty"`
+	// Personality is the bot's play style weight preset (bots only)
+	Personality string `json:"personality,omitempty"`
+	// Weights replace the default heuristic weights of a bot; offline
+	// evaluation uses them to pit weight profiles against each other
+	Weights *config.HeuristicWeights `json:"-"`
+	// TimeBankMs is banked turn time in milliseconds (time bank rooms only)
+	TimeBankMs int64 `json:"time_bank_ms"`
+	// ClockMs is the player's time left on the game clock in milliseconds
+	// (game clock rooms only)
+	ClockMs int64 `json:"clock_ms"`
+	// MissedTurns counts the player's turns in a row that timed out
+	MissedTurns int `json:"missed_turns,omitempty"`
+	// Ready marks a player in the lobby who is ready for the game to start
+	Ready bool `json:"ready,omitempty"`
+	// Captures counts the opponent cards the player covered this game and
+	// CapturedValue adds up their values
+	Captures      int `json:"captures"`
+	CapturedValue int `json:"captured_value"`
+	// Stats tally the player's placements this game, move by move
+	Stats PlayerStats `json:"stats"`
+}
+
+// PlayerStats are a player's running numbers for the current game, kept up
+// to date as placements are played and undone
+type PlayerStats struct {
+	Placements  int         `json:"placements"`
+	ThinkMs     int64       `json:"think_ms"`     // Time taken over those placements
+	CardsPlayed map[int]int `json:"cards_played"` // Card value -> times placed
+	CardSum     int         `json:"card_sum"`     // Sum of the cards placed
+	Blocks      int         `json:"blocks"`       // Placements that blocked an opponent's three in a row
+}
+
+// TallyCapture counts the capture rec made, if any, towards the player;
+// sign is 1 when the move is played and -1 when it is taken back
+func (p *Player) TallyCapture(rec game.MoveRecord, sign int) {
+	if v := rec.Captured(); v > 0 {
+		p.Captures += sign
+		p.CapturedValue += sign * v
+	}
+}
+
+// TallyStats counts the placement rec made, if it is one, towards the
+// player's stats; sign -1 takes it back when the move is undone
+func (p *Player) TallyStats(rec game.MoveRecord, sign int) {
+	if rec.Type.Normalize() != game.MovePlace {
+		return
+	}
+	s := &p.Stats
+	s.Placements += sign
+	s.ThinkMs += int64(sign) * rec.ThinkMs
+	s.CardSum += sign * rec.Card
+	if rec.Block {
+		s.Blocks += sign
+	}
+	if s.CardsPlayed == nil {
+		s.CardsPlayed = map[int]int{}
+	}
+	if s.CardsPlayed[rec.Card] += sign; s.CardsPlayed[rec.Card] <= 0 {
+		delete(s.CardsPlayed, rec.Card)
+	}
+}
+
+// ResetTallies clears the capture tally and the stats for a new game
+func (p *Player) ResetTallies() {
+	p.Captures, p.CapturedValue = 0, 0
+	p.Stats = PlayerStats{}
+}
+
+// PersonaRecord tracks a bot persona's history against one account. Seats
+// without an account have no lasting identity and keep no record.
+type PersonaRecord struct {
+	Persona    string  `json:"persona"`
+	UserID     string  `json:"user_id"`
+	Games      int     `json:"games"`
+	PlayerWins int     `json:"player_wins"`
+	Difficulty float64 `json:"difficulty"`
+}
+
+// SeatChange records a new human taking over an abandoned or resigned seat
+type SeatChange struct {
+	PlayerID     string    `json:"player_id"`
+	PreviousName string    `json:"previous_name"`
+	NewName      string    `json:"new_name"`
+	At           time.Time `json:"at"`
+}
+
+// ChatMessage is one line of in-room chat
+type ChatMessage struct {
+	PlayerID string    `json:"player_id"`
+	Name     string    `json:"name"`
+	Text     string    `json:"text"`
+	At       time.Time `json:"at"`
+}
+
+// Reaction is a quick reaction a player showed the room
+type Reaction struct {
+	PlayerID string    `json:"player_id"`
+	Name     string    `json:"name"`
+	Code     string    `json:"code"` // One of config.Reactions
+	At       time.Time `json:"at"`
+}
+
+// UndoRequest is an outstanding takeback request waiting for opponent confirmation
+type UndoRequest struct {
+	RequesterID string    `json:"requester_id"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// Match wraps consecutive games played in the same room as a best-of-N series
+type Match struct {
+	BestOf   int            `json:"best_of"`
+	GameNo   int            `json:"game_no"` // 1-based index of the game in progress
+	Scores   map[string]int `json:"scores"`  // Player ID -> games won
+	Results  []MatchGame    `json:"results"`
+	WinnerID *string        `json:"winner_id"`
+	Finished bool           `json:"finished"`
+}
+
+// MatchGame records the outcome of a single game within a match
+type MatchGame struct {
+	GameNo        int          `json:"game_no"`
+	FirstPlayerID string       `json:"first_player_id"`
+	WinnerID      *string      `json:"winner_id"`
+	Draw          bool         `json:"draw"`
+	Moves         int          `json:"moves"`
+	Metrics       *GameMetrics `json:"metrics,omitempty"`
+}
+
+// GameMetrics summarizes a finished game
+type GameMetrics struct {
+	DurationSeconds float64                  `json:"duration_seconds"`
+	Moves           int                      `json:"moves"`
+	Players         map[string]PlayerMetrics `json:"players"` // Player ID -> metrics
+	// Standings are the player IDs from first place to last. Players who
+	// resigned come last, the latest resignation first.
+	Standings []string `json:"standings"`
+}
+
+// PlayerMetrics summarizes one player's part in a finished game
+type PlayerMetrics struct {
+	Moves           int     `json:"moves"` // Every recorded move, including skips and swaps
+	AvgThinkSeconds float64 `json:"avg_think_seconds"`
+	Captures        int     `json:"captures"`       // Opponent cards overwritten
+	CapturedValue   int     `json:"captured_value"` // Sum of the overwritten cards
+	Skips           int     `json:"skips"`
+	MaxLine         int     `json:"max_line"` // Longest own line reached during the game
+}
+
+// PublicPlayer is the view of a player that is safe to share with every
+// client. Hand and DeckCount are left out when the room's policy hides them;
+// a bot's hand is always left out.
+type PublicPlayer struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	IsBot       bool   `json:"isBot"`
+	Color       string `json:"color"`
+	Resigned    bool   `json:"resigned"`
+	Abandoned   bool   `json:"abandoned"`
+	UserID      string `json:"user_id,omitempty"`
+	Persona     string `json:"persona,omitempty"`
+	Personality string `json:"personality,omitempty"`
+	Hand        []int  `json:"hand,omitempty"`
+	HandCount   int    `json:"hand_count"`
+	DeckCount   *int   `json:"deck_count,omitempty"`
+	TimeBankMs  int64  `json:"time_bank_ms"`
+	ClockMs     int64  `json:"clock_ms"`
+	Ready       bool   `json:"ready,omitempty"` // Lobby only
+	Captures    int    `json:"captures"`
+	CapturedVal int    `json:"captured_value"`
+}
+
+// SeedRand restarts the room's random source from seed, fast-forwarded past
+// draws values
+func (r *Room) SeedRand(seed int64, draws uint64) {
+	r.Seed = seed
+	r.RandSource = NewCountingSource(seed, draws)
+	r.Rand = rand.New(r.RandSource)
+}
+
+// RandDraws returns how many values the room's random source has produced.
+// Sources created before draws were counted report 0.
+func (r *Room) RandDraws() uint64 {
+	if r.RandSource == nil {
+		return 0
+	}
+	return r.RandSource.Draws()
+}
+
+// HasPassword reports whether joining the room requires a password
+func (r *Room) HasPassword() bool {
+	return len(r.PasswordHash) > 0
+}
+
+// PlayerRating is a rated identity's ELO and lifetime results
+type PlayerRating struct {
+	PlayerID     string  `json:"player_id"` // Account user ID, or "bot:<persona>" for bots
+	Name         string  `json:"name"`
+	Rating       float64 `json:"rating"`
+	Games        int     `json:"games"`
+	Wins         int     `json:"wins"`
+	Losses       int     `json:"losses"`
+	Draws        int     `json:"draws"`
+	TotalMoves   int     `json:"total_moves"`
+	TotalSeconds float64 `json:"total_seconds"`
+	// Per-player totals taken from each game's metrics snapshot
+	PlayerMoves   int       `json:"player_moves"`
+	ThinkSeconds  float64   `json:"think_seconds"`
+	TotalCaptures int       `json:"total_captures"`
+	TotalSkips    int       `json:"total_skips"`
+	BestLine      int       `json:"best_line"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// AverageMoves returns the mean number of moves per game played
+func (p PlayerRating) AverageMoves() float64 {
+	if p.Games == 0 {
+		return 0
+	}
+	return float64(p.TotalMoves) / float64(p.Games)
+}
+
+// AverageThinkSeconds returns the mean time the player took per move
+func (p PlayerRating) AverageThinkSeconds() float64 {
+	if p.PlayerMoves == 0 {
+		return 0
+	}
+	return p.ThinkSeconds / float64(p.PlayerMoves)
+}
+
+// AverageSeconds returns the mean game duration in seconds
+func (p PlayerRating) AverageSeconds() float64 {
+	if p.Games == 0 {
+		return 0
+	}
+	return p.TotalSeconds / float64(p.Games)
 }
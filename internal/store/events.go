@@ -0,0 +1,115 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/shared"
+	"log"
+)
+
+// AppendEvents adds events to the end of the room's log, tagged with the
+// game in progress
+func (s *PostgresStore) AppendEvents(r *shared.Room, events ...record.Event) {
+	if err := s.writeEvents(r, events); err != nil {
+		log.Printf("Warning: could not append events of room %s: %v", r.Code, err)
+	}
+}
+
+func (s *PostgresStore) writeEvents(r *shared.Room, events []record.Event) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, ev := range events {
+		payload, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO room_events (room_code, game_started_at, kind, payload)
+			VALUES ($1, $2, $3, $4)`,
+			r.Code, nullTime(r.StartedAt), ev.Kind, payload); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Events returns the room's log, oldest event first
+func (s *PostgresStore) Events(code string) []record.Event {
+	events, err := s.loadEvents(code, false)
+	if err != nil {
+		log.Printf("Warning: could not load events of room %s: %v", code, err)
+	}
+	return events
+}
+
+// loadEvents reads the room's log. With fromState it starts at the latest
+// event that carries the whole room, which is all folding needs.
+func (s *PostgresStore) loadEvents(code string, fromState bool) ([]record.Event, error) {
+	query := `SELECT kind, payload FROM room_events WHERE room_code = $1 ORDER BY seq`
+	args := []interface{}{code}
+	if fromState {
+		query = `
+			SELECT kind, payload FROM room_events
+			WHERE room_code = $1 AND seq >= (
+				SELECT MAX(seq) FROM room_events WHERE room_code = $1 AND kind IN ($2, $3, $4)
+			)
+			ORDER BY seq`
+		args = append(args, record.EventCreate, record.EventStart, record.EventCheckpoint)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []record.Event
+	for rows.Next() {
+		var kind string
+		var payload []byte
+		if err := rows.Scan(&kind, &payload); err != nil {
+			return nil, err
+		}
+
+		var ev record.Event
+		if err := json.Unmarshal(payload, &ev); err != nil {
+			return nil, fmt.Errorf("%s event: %w", kind, err)
+		}
+		// Older checkpoints hold the serialized room instead of an event
+		if kind == record.EventCheckpoint && ev.Kind == "" {
+			r, err := decodeState(payload)
+			if err != nil {
+				return nil, fmt.Errorf("checkpoint: %w", err)
+			}
+			if ev, err = record.StateEvent(record.EventCheckpoint, r); err != nil {
+				return nil, err
+			}
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+// rebuildRoom folds the room's log from its latest create, start or
+// checkpoint event. It returns sql.ErrNoRows when there is none.
+func (s *PostgresStore) rebuildRoom(code string) (*shared.Room, error) {
+	events, err := s.loadEvents(code, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	r, err := record.Fold(events)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	return r, nil
+}
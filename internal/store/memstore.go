@@ -1,18 +1,30 @@
 package store
 
 import (
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/record"
 	"javanese-chess/internal/shared"
+	"sort"
 	"sync"
 )
 
+// MemoryStore keeps everything in process memory. Rooms are copied in and
+// out, so changing a room fetched from the store has no effect until it is
+// saved.
 type MemoryStore struct {
-	mu    sync.RWMutex
-	rooms map[string]*shared.Room
+	mu       sync.RWMutex
+	rooms    map[string]*shared.Room
+	events   map[string][]record.Event // Room code -> event log
+	personas map[string]*shared.PersonaRecord
+	users    map[string]*auth.User // Keyed by username
 }
 
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
-		rooms: map[string]*shared.Room{},
+		rooms:    map[string]*shared.Room{},
+		events:   map[string][]record.Event{},
+		personas: map[string]*shared.PersonaRecord{},
+		users:    map[string]*auth.User{},
 	}
 }
 
@@ -20,11 +32,77 @@ func (m *MemoryStore) GetRoom(code string) (*shared.Room, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	r, ok := m.rooms[code]
-	return r, ok
+	if !ok {
+		return nil, false
+	}
+	return r.Clone(), true
 }
 
 func (m *MemoryStore) SaveRoom(r *shared.Room) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.rooms[r.Code] = r
+	m.rooms[r.Code] = r.Clone()
+}
+
+// AppendEvents adds events to the end of the room's log
+func (m *MemoryStore) AppendEvents(r *shared.Room, events ...record.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events[r.Code] = append(m.events[r.Code], events...)
+}
+
+// Events returns a copy of the room's log, oldest event first
+func (m *MemoryStore) Events(code string) []record.Event {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]record.Event(nil), m.events[code]...)
+}
+
+// ListRooms returns every stored room ordered by creation time
+func (m *MemoryStore) ListRooms() []*shared.Room {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]*shared.Room, 0, len(m.rooms))
+	for _, r := range m.rooms {
+		out = append(out, r.Clone())
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out
+}
+
+func personaKey(persona, userID string) string {
+	return persona + "|" + userID
+}
+
+func (m *MemoryStore) GetPersonaRecord(persona, userID string) (*shared.PersonaRecord, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rec, ok := m.personas[personaKey(persona, userID)]
+	if !ok {
+		return nil, false
+	}
+	cp := *rec
+	return &cp, true
+}
+
+func (m *MemoryStore) SavePersonaRecord(rec *shared.PersonaRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *rec
+	m.personas[personaKey(rec.Persona, rec.UserID)] = &cp
+}
+
+func (m *MemoryStore) GetUserByName(username string) (*auth.User, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	u, ok := m.users[username]
+	return u, ok
+}
+
+func (m *MemoryStore) SaveUser(u *auth.User) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[u.Username] = u
 }
@@ -0,0 +1,72 @@
+-- Rooms keep their full serialized state so live games survive restarts;
+-- the other tables normalize what analytics queries need.
+CREATE TABLE rooms (
+    code        TEXT PRIMARY KEY,
+    status      TEXT        NOT NULL,
+    seed        BIGINT      NOT NULL DEFAULT 0,
+    board_size  INTEGER     NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL,
+    started_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    state       JSONB       NOT NULL
+);
+
+CREATE TABLE players (
+    room_code   TEXT    NOT NULL REFERENCES rooms (code) ON DELETE CASCADE,
+    player_id   TEXT    NOT NULL,
+    seat        INTEGER NOT NULL,
+    name        TEXT    NOT NULL,
+    is_bot      BOOLEAN NOT NULL,
+    user_id     TEXT,
+    persona     TEXT,
+    resigned    BOOLEAN NOT NULL DEFAULT false,
+    PRIMARY KEY (room_code, player_id)
+);
+
+-- Moves of each game in a room; a game is identified by its start time
+CREATE TABLE moves (
+    room_code       TEXT        NOT NULL REFERENCES rooms (code) ON DELETE CASCADE,
+    game_started_at TIMESTAMPTZ NOT NULL,
+    move_no         INTEGER     NOT NULL,
+    type            TEXT        NOT NULL,
+    player_id       TEXT        NOT NULL,
+    x               INTEGER     NOT NULL,
+    y               INTEGER     NOT NULL,
+    card            INTEGER     NOT NULL,
+    captured_id     TEXT,
+    captured_card   INTEGER,
+    played_at       TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (room_code, game_started_at, move_no)
+);
+
+CREATE TABLE results (
+    room_code       TEXT        NOT NULL REFERENCES rooms (code) ON DELETE CASCADE,
+    game_started_at TIMESTAMPTZ NOT NULL,
+    winner_id       TEXT,
+    draw            BOOLEAN     NOT NULL,
+    moves           INTEGER     NOT NULL,
+    win_line        JSONB,
+    finished_at     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (room_code, game_started_at)
+);
+
+-- Games each bot persona played against each account; anonymous seats are
+-- not recorded, since their names are free text anyone can type
+CREATE TABLE persona_records (
+    persona     TEXT             NOT NULL,
+    user_id     TEXT             NOT NULL,
+    games       INTEGER          NOT NULL,
+    player_wins INTEGER          NOT NULL,
+    difficulty  DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (persona, user_id)
+);
+
+CREATE TABLE users (
+    id            TEXT PRIMARY KEY,
+    username      TEXT UNIQUE NOT NULL,
+    password_hash BYTEA       NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX results_winner_idx ON results (winner_id);
+CREATE INDEX moves_player_idx ON moves (player_id);
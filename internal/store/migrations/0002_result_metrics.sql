@@ -0,0 +1,2 @@
+-- End-of-game metrics snapshot (duration, think times, captures, skips, max line)
+ALTER TABLE results ADD COLUMN metrics JSONB;
@@ -0,0 +1,14 @@
+-- Append-only event stream per room: a checkpoint snapshot when each game
+-- starts, then its moves, undos and result. Used to rebuild a room whose
+-- snapshot in rooms.state is missing or corrupt, so it is deliberately not
+-- tied to rooms by a foreign key.
+CREATE TABLE room_events (
+    seq             BIGSERIAL   PRIMARY KEY,
+    room_code       TEXT        NOT NULL,
+    game_started_at TIMESTAMPTZ,
+    kind            TEXT        NOT NULL,
+    payload         JSONB       NOT NULL,
+    at              TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX room_events_room_idx ON room_events (room_code, seq);
@@ -0,0 +1,21 @@
+-- ELO ratings and lifetime stats per account, or per bot persona under
+-- "bot:<persona>", so the leaderboard lives with the rest of the data
+CREATE TABLE ratings (
+    player_id      TEXT             PRIMARY KEY,
+    name           TEXT             NOT NULL,
+    rating         DOUBLE PRECISION NOT NULL,
+    games          INTEGER          NOT NULL,
+    wins           INTEGER          NOT NULL,
+    losses         INTEGER          NOT NULL,
+    draws          INTEGER          NOT NULL,
+    total_moves    INTEGER          NOT NULL,
+    total_seconds  DOUBLE PRECISION NOT NULL,
+    player_moves   INTEGER          NOT NULL,
+    think_seconds  DOUBLE PRECISION NOT NULL,
+    total_captures INTEGER          NOT NULL,
+    total_skips    INTEGER          NOT NULL,
+    best_line      INTEGER          NOT NULL,
+    updated_at     TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX ratings_rating_idx ON ratings (rating DESC);
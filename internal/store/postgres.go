@@ -0,0 +1,490 @@
+package store
+
+import (
+	"container/list"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"javanese-chess/internal/auth"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/record"
+	"javanese-chess/internal/shared"
+	"log"
+	"sync"
+	"time"
+)
+
+// PostgresStore persists rooms, players, moves, results, room event logs,
+// persona records, ratings and accounts in PostgreSQL. The last saved copy of
+// recently used rooms is cached in memory, up to config.PostgresCacheRooms
+// rooms; callers get their own copy of it. Rooms dropped from the cache are
+// read back from the database.
+//
+// The database/sql driver is not linked by this package: the binary must
+// import one that registers the requested driver name. internal/app links
+// pgx's stdlib, registered as "pgx".
+type PostgresStore struct {
+	db *sql.DB
+
+	mu       sync.Mutex               // Guards the cache only, never held across queries
+	cache    map[string]*list.Element // Room code -> entry in lru
+	lru      *list.List               // Cached rooms, most recently used first
+	maxRooms int
+}
+
+// cachedRoom is a room in the cache with the number of moves of its current
+// game already written, or -1 while a write is in flight or after one failed
+type cachedRoom struct {
+	room  *shared.Room
+	saved int
+}
+
+// roomState is the serialized form of a room, including the fields that are
+// hidden from clients (decks, move history and the random state)
+type roomState struct {
+	Room      *shared.Room      `json:"room"`
+	Decks     map[string][]int  `json:"decks"`
+	History   []game.MoveRecord `json:"history"`
+	Pile      []int             `json:"pile,omitempty"`
+	PwHash    []byte            `json:"password_hash,omitempty"`
+	Seed      int64             `json:"seed,omitempty"`
+	RandDraws uint64            `json:"rand_draws,omitempty"`
+}
+
+// OpenPostgres connects to the database and applies pending migrations
+func OpenPostgres(driver, dsn string) (*PostgresStore, error) {
+	if dsn == "" {
+		return nil, errors.New("DATABASE_URL is required for the postgres store")
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	if err := Migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &PostgresStore{
+		db:       db,
+		cache:    map[string]*list.Element{},
+		lru:      list.New(),
+		maxRooms: config.PostgresCacheRooms,
+	}, nil
+}
+
+func (s *PostgresStore) Close() error {
+	return s.db.Close()
+}
+
+// cached returns the cached entry of a room and marks it as just used. The
+// caller holds s.mu.
+func (s *PostgresStore) cached(code string) (*cachedRoom, bool) {
+	el, ok := s.cache[code]
+	if !ok {
+		return nil, false
+	}
+	s.lru.MoveToFront(el)
+	return el.Value.(*cachedRoom), true
+}
+
+// cacheRoom caches a room, dropping the least recently used rooms beyond
+// the limit. The caller holds s.mu.
+func (s *PostgresStore) cacheRoom(r *shared.Room, saved int) *cachedRoom {
+	if c, ok := s.cached(r.Code); ok {
+		c.room, c.saved = r, saved
+		return c
+	}
+	c := &cachedRoom{room: r, saved: saved}
+	s.cache[r.Code] = s.lru.PushFront(c)
+	for s.lru.Len() > s.maxRooms {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.cache, oldest.Value.(*cachedRoom).room.Code)
+	}
+	return c
+}
+
+func (s *PostgresStore) GetRoom(code string) (*shared.Room, bool) {
+	s.mu.Lock()
+	if c, ok := s.cached(code); ok {
+		r := c.room.Clone()
+		s.mu.Unlock()
+		return r, true
+	}
+	s.mu.Unlock()
+
+	r, rebuilt, err := s.loadRoom(code)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Warning: could not load room %s: %v", code, err)
+		}
+		return nil, false
+	}
+
+	// Replace the bad snapshot; the event log already has everything
+	if rebuilt {
+		if err := s.writeRoom(r, len(r.History)); err != nil {
+			log.Printf("Warning: could not repair snapshot of room %s: %v", code, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.cached(code); ok {
+		return c.room.Clone(), true // Saved while we were loading
+	}
+	s.cacheRoom(r, len(r.History))
+	return r.Clone(), true
+}
+
+// loadRoom reads a room's snapshot. When the snapshot is missing or fails
+// validation the room is rebuilt from its event stream instead, and rebuilt
+// is true.
+func (s *PostgresStore) loadRoom(code string) (r *shared.Room, rebuilt bool, err error) {
+	var data []byte
+	err = s.db.QueryRow(`SELECT state FROM rooms WHERE code = $1`, code).Scan(&data)
+	if err == nil {
+		if r, err = decodeState(data); err == nil {
+			if err = record.Validate(r); err == nil {
+				return r, false, nil
+			}
+		}
+	}
+
+	r, rerr := s.rebuildRoom(code)
+	if rerr != nil {
+		if errors.Is(rerr, sql.ErrNoRows) {
+			return nil, false, err
+		}
+		return nil, false, fmt.Errorf("bad snapshot (%v) and rebuild failed: %w", err, rerr)
+	}
+	log.Printf("Room %s rebuilt from its event stream (snapshot: %v)", code, err)
+	return r, true, nil
+}
+
+// decodeState restores a room from its serialized state
+func decodeState(data []byte) (*shared.Room, error) {
+	var st roomState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	r := st.Room
+	if r == nil {
+		return nil, errors.New("snapshot has no room")
+	}
+	for i := range r.Players {
+		r.Players[i].Deck = st.Decks[r.Players[i].ID]
+	}
+	r.History = st.History
+	r.CommunalPile = st.Pile
+	r.PasswordHash = st.PwHash
+
+	// Pick the random source up where it stopped, so values already drawn
+	// are not drawn again. Rooms that never drew are seeded when they first do.
+	if st.Seed != 0 {
+		if st.RandDraws > shared.MaxDraws {
+			return nil, fmt.Errorf("rand_draws %d out of range (at most %d)", st.RandDraws, shared.MaxDraws)
+		}
+		r.SeedRand(st.Seed, st.RandDraws)
+	}
+	r.Board.Rehash() // Rooms saved before position hashing have no hash
+	return r, nil
+}
+
+// SaveRoom caches the room and writes it to the database. Readers see the
+// new copy at once; the database is written without holding the cache lock,
+// so slow writes never stall reads. Saves of one room must not run at the
+// same time, which the room manager's per-room lock ensures.
+func (s *PostgresStore) SaveRoom(r *shared.Room) {
+	cp := r.Clone()
+	s.mu.Lock()
+	saved := -1 // Unknown for rooms not cached
+	if c, ok := s.cached(r.Code); ok {
+		saved = c.saved
+	}
+	s.cacheRoom(cp, -1)
+	s.mu.Unlock()
+
+	if err := s.writeRoom(cp, saved); err != nil {
+		log.Printf("Warning: could not save room %s: %v", r.Code, err)
+		return
+	}
+
+	s.mu.Lock()
+	if c, ok := s.cache[r.Code]; ok && c.Value.(*cachedRoom).room == cp {
+		c.Value.(*cachedRoom).saved = len(cp.History)
+	}
+	s.mu.Unlock()
+}
+
+// writeRoom upserts the room and its players, syncs the current game's moves
+// and records the result once the game is over. saved is the number of moves
+// already written, -1 when unknown.
+func (s *PostgresStore) writeRoom(r *shared.Room, saved int) error {
+	st := roomState{Room: r, Decks: map[string][]int{}, History: r.History, Pile: r.CommunalPile, PwHash: r.PasswordHash, Seed: r.Seed, RandDraws: r.RandDraws()}
+	for _, p := range r.Players {
+		st.Decks[p.ID] = p.Deck
+	}
+	state, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO rooms (code, status, seed, board_size, created_at, started_at, updated_at, state)
+		VALUES ($1, $2, $3, $4, $5, $6, now(), $7)
+		ON CONFLICT (code) DO UPDATE SET
+			status = EXCLUDED.status, seed = EXCLUDED.seed, started_at = EXCLUDED.started_at,
+			updated_at = now(), state = EXCLUDED.state`,
+		r.Code, r.Status, r.Seed, r.Board.Size, r.CreatedAt, nullTime(r.StartedAt), state); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM players WHERE room_code = $1`, r.Code); err != nil {
+		return err
+	}
+	for seat, p := range r.Players {
+		if _, err := tx.Exec(`
+			INSERT INTO players (room_code, player_id, seat, name, is_bot, user_id, persona, resigned)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			r.Code, p.ID, seat, p.Name, p.IsBot, nullString(p.UserID), nullString(p.Persona), p.Resigned); err != nil {
+			return err
+		}
+	}
+
+	if !r.StartedAt.IsZero() {
+		if err := syncMoves(tx, r, saved); err != nil {
+			return err
+		}
+		if r.Over() {
+			winLine, _ := json.Marshal(r.WinLine)
+			metrics, _ := json.Marshal(r.Metrics)
+			if _, err := tx.Exec(`
+				INSERT INTO results (room_code, game_started_at, winner_id, draw, moves, win_line, metrics, finished_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, now())
+				ON CONFLICT DO NOTHING`,
+				r.Code, r.StartedAt, r.WinnerID, r.Draw, len(r.History), winLine, metrics); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// syncMoves writes moves added since the last save and drops moves that
+// were taken back. When saved is unknown (-1) every move is written and any
+// move beyond the history dropped.
+func syncMoves(tx *sql.Tx, r *shared.Room, saved int) error {
+	if saved < 0 || saved > len(r.History) {
+		if _, err := tx.Exec(`DELETE FROM moves WHERE room_code = $1 AND game_started_at = $2 AND move_no > $3`,
+			r.Code, r.StartedAt, len(r.History)); err != nil {
+			return err
+		}
+		saved = min(max(saved, 0), len(r.History))
+	}
+
+	for i := saved; i < len(r.History); i++ {
+		mv := r.History[i]
+		var capturedID sql.NullString
+		var capturedCard sql.NullInt64
+		if mv.PrevCell.OwnerID != "" && mv.PrevCell.OwnerID != mv.PlayerID {
+			capturedID = sql.NullString{String: mv.PrevCell.OwnerID, Valid: true}
+			capturedCard = sql.NullInt64{Int64: int64(mv.PrevCell.Value), Valid: true}
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO moves (room_code, game_started_at, move_no, type, player_id, x, y, card, captured_id, captured_card, played_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT DO NOTHING`,
+			r.Code, r.StartedAt, i+1, string(mv.Type.Normalize()), mv.PlayerID, mv.X, mv.Y, mv.Card,
+			capturedID, capturedCard, mv.At); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListRooms returns every stored room ordered by creation time. Rooms not in
+// the cache are read from the database without being cached, so listing does
+// not push live rooms out of the cache.
+func (s *PostgresStore) ListRooms() []*shared.Room {
+	rows, err := s.db.Query(`SELECT code FROM rooms ORDER BY created_at`)
+	if err != nil {
+		log.Printf("Warning: could not list rooms: %v", err)
+		return nil
+	}
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err == nil {
+			codes = append(codes, code)
+		}
+	}
+	rows.Close()
+
+	out := make([]*shared.Room, 0, len(codes))
+	for _, code := range codes {
+		s.mu.Lock()
+		el, ok := s.cache[code]
+		var r *shared.Room
+		if ok {
+			r = el.Value.(*cachedRoom).room.Clone()
+		}
+		s.mu.Unlock()
+		if !ok {
+			if r, _, err = s.loadRoom(code); err != nil {
+				log.Printf("Warning: could not load room %s: %v", code, err)
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+func (s *PostgresStore) GetPersonaRecord(persona, userID string) (*shared.PersonaRecord, bool) {
+	rec := shared.PersonaRecord{Persona: persona, UserID: userID}
+	err := s.db.QueryRow(`
+		SELECT games, player_wins, difficulty FROM persona_records
+		WHERE persona = $1 AND user_id = $2`, persona, userID).
+		Scan(&rec.Games, &rec.PlayerWins, &rec.Difficulty)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Warning: could not load persona record: %v", err)
+		}
+		return nil, false
+	}
+	return &rec, true
+}
+
+func (s *PostgresStore) SavePersonaRecord(rec *shared.PersonaRecord) {
+	if _, err := s.db.Exec(`
+		INSERT INTO persona_records (persona, user_id, games, player_wins, difficulty)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (persona, user_id) DO UPDATE SET
+			games = EXCLUDED.games, player_wins = EXCLUDED.player_wins, difficulty = EXCLUDED.difficulty`,
+		rec.Persona, rec.UserID, rec.Games, rec.PlayerWins, rec.Difficulty); err != nil {
+		log.Printf("Warning: could not save persona record: %v", err)
+	}
+}
+
+// ratingColumns lists the ratings columns in the order scanRating reads them
+const ratingColumns = `player_id, name, rating, games, wins, losses, draws, total_moves, total_seconds,
+	player_moves, think_seconds, total_captures, total_skips, best_line, updated_at`
+
+// scanRating reads one row selected with ratingColumns
+func scanRating(row interface{ Scan(...any) error }) (shared.PlayerRating, error) {
+	var p shared.PlayerRating
+	err := row.Scan(&p.PlayerID, &p.Name, &p.Rating, &p.Games, &p.Wins, &p.Losses, &p.Draws,
+		&p.TotalMoves, &p.TotalSeconds, &p.PlayerMoves, &p.ThinkSeconds, &p.TotalCaptures,
+		&p.TotalSkips, &p.BestLine, &p.UpdatedAt)
+	return p, err
+}
+
+func (s *PostgresStore) GetRating(playerID string) (*shared.PlayerRating, bool) {
+	p, err := scanRating(s.db.QueryRow(`SELECT `+ratingColumns+` FROM ratings WHERE player_id = $1`, playerID))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Warning: could not load rating: %v", err)
+		}
+		return nil, false
+	}
+	return &p, true
+}
+
+// SaveRatings stores the ratings of one game together, so the leaderboard
+// never shows a game counted for some of its players only
+func (s *PostgresStore) SaveRatings(ratings []*shared.PlayerRating) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Warning: could not save ratings: %v", err)
+		return
+	}
+	defer tx.Rollback()
+
+	for _, p := range ratings {
+		if _, err := tx.Exec(`
+			INSERT INTO ratings (`+ratingColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			ON CONFLICT (player_id) DO UPDATE SET
+				name = EXCLUDED.name, rating = EXCLUDED.rating, games = EXCLUDED.games,
+				wins = EXCLUDED.wins, losses = EXCLUDED.losses, draws = EXCLUDED.draws,
+				total_moves = EXCLUDED.total_moves, total_seconds = EXCLUDED.total_seconds,
+				player_moves = EXCLUDED.player_moves, think_seconds = EXCLUDED.think_seconds,
+				total_captures = EXCLUDED.total_captures, total_skips = EXCLUDED.total_skips,
+				best_line = EXCLUDED.best_line, updated_at = EXCLUDED.updated_at`,
+			p.PlayerID, p.Name, p.Rating, p.Games, p.Wins, p.Losses, p.Draws, p.TotalMoves, p.TotalSeconds,
+			p.PlayerMoves, p.ThinkSeconds, p.TotalCaptures, p.TotalSkips, p.BestLine, p.UpdatedAt); err != nil {
+			log.Printf("Warning: could not save rating of %s: %v", p.PlayerID, err)
+			return
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Warning: could not save ratings: %v", err)
+	}
+}
+
+// ListRatings returns all ratings, best first
+func (s *PostgresStore) ListRatings() []shared.PlayerRating {
+	rows, err := s.db.Query(`SELECT ` + ratingColumns + ` FROM ratings ORDER BY rating DESC, player_id`)
+	if err != nil {
+		log.Printf("Warning: could not list ratings: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	var out []shared.PlayerRating
+	for rows.Next() {
+		p, err := scanRating(rows)
+		if err != nil {
+			log.Printf("Warning: could not read rating: %v", err)
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+func (s *PostgresStore) GetUserByName(username string) (*auth.User, bool) {
+	var u auth.User
+	err := s.db.QueryRow(`SELECT id, username, password_hash, created_at FROM users WHERE username = $1`, username).
+		Scan(&u.ID, &u.Username, &u.PasswordHash, &u.CreatedAt)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Warning: could not load user: %v", err)
+		}
+		return nil, false
+	}
+	return &u, true
+}
+
+func (s *PostgresStore) SaveUser(u *auth.User) {
+	if _, err := s.db.Exec(`
+		INSERT INTO users (id, username, password_hash, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET username = EXCLUDED.username, password_hash = EXCLUDED.password_hash`,
+		u.ID, u.Username, u.PasswordHash, u.CreatedAt); err != nil {
+		log.Printf("Warning: could not save user: %v", err)
+	}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
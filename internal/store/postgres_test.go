@@ -0,0 +1,246 @@
+package store
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"io"
+	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
+	"javanese-chess/internal/room"
+	"javanese-chess/internal/shared"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// The postgres store tests need a database they may write to, e.g.
+//
+//	TEST_DATABASE_URL=postgres://localhost/chess_test?sslmode=disable go test ./internal/store
+//
+// They are skipped when TEST_DATABASE_URL is not set. Rooms get fresh codes
+// and are deleted afterwards, so the database may be shared.
+const testDSNEnv = "TEST_DATABASE_URL"
+
+// openTestStore opens the test database, or skips the test without one
+func openTestStore(t *testing.T) *PostgresStore {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s not set", testDSNEnv)
+	}
+	s, err := OpenPostgres("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// testCode returns a room code no other test uses and deletes the room's
+// rows when the test ends
+func testCode(t *testing.T, s *PostgresStore) string {
+	t.Helper()
+	code := "PGT" + strings.ToUpper(uuid.NewString()[:8])
+	t.Cleanup(func() {
+		s.db.Exec(`DELETE FROM rooms WHERE code = $1`, code)
+		s.db.Exec(`DELETE FROM room_events WHERE room_code = $1`, code)
+	})
+	return code
+}
+
+// newTestManager returns a room manager on the store whose bots move
+// without thinking time
+func newTestManager(s *PostgresStore) *room.Manager {
+	log.SetOutput(io.Discard)
+	cfg := *config.Get()
+	cfg.JWTSecret = "test"
+	m := room.NewManager(s, cfg, nil)
+	m.SetHub(ws.NewHub(m))
+	m.SetBotDelay(0)
+	return m
+}
+
+// countMoves returns the rows of the room's current game in the moves table
+func countMoves(t *testing.T, db *sql.DB, r *shared.Room) int {
+	t.Helper()
+	var n int
+	err := db.QueryRow(`SELECT count(*) FROM moves WHERE room_code = $1 AND game_started_at = $2`,
+		r.Code, r.StartedAt).Scan(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// A game played and partly taken back reads back the same from a store with
+// an empty cache, and the moves table holds exactly the moves left
+func TestPostgresRoomRoundTrip(t *testing.T) {
+	s := openTestStore(t)
+	m := newTestManager(s)
+	code := testCode(t, s)
+
+	r, err := m.CreateLobbyRoom(code, "host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddBots(r, 1); err != nil {
+		t.Fatal(err)
+	}
+	m.StartGame(r)
+	var human string
+	for _, p := range r.Players {
+		if !p.IsBot {
+			human = p.ID
+		}
+	}
+
+	for len(r.History) < 6 && !r.Over() {
+		cp := r.Players[r.TurnIdx]
+		if cp.IsBot {
+			if _, err := m.BotMove(context.Background(), r, cp.ID); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		moves := game.GenerateLegalMoves(&r.Board, cp.Hand, cp.ID)
+		if len(moves) == 0 {
+			t.Fatal("human has no legal move")
+		}
+		if err := m.ApplyMove(context.Background(), r, cp.ID, moves[0].X, moves[0].Y, moves[0].Card); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := m.RequestUndo(r, human); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh := openTestStore(t)
+	got, ok := fresh.GetRoom(code)
+	if !ok {
+		t.Fatal("room not found in a fresh store")
+	}
+	if len(got.History) != len(r.History) || got.TurnIdx != r.TurnIdx {
+		t.Fatalf("read back %d moves, turn %d; want %d moves, turn %d",
+			len(got.History), got.TurnIdx, len(r.History), r.TurnIdx)
+	}
+	if got.RandDraws() != r.RandDraws() {
+		t.Errorf("read back %d random draws, want %d", got.RandDraws(), r.RandDraws())
+	}
+	if n := countMoves(t, s.db, r); n != len(r.History) {
+		t.Errorf("moves table holds %d moves, history %d", n, len(r.History))
+	}
+}
+
+// Only the most recently used rooms stay cached; dropped rooms are read back
+// from the database
+func TestPostgresCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	s := openTestStore(t)
+	s.maxRooms = 2
+	m := newTestManager(s)
+
+	codes := []string{testCode(t, s), testCode(t, s), testCode(t, s)}
+	for _, code := range codes {
+		if _, err := m.CreateLobbyRoom(code, "host"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.cache) != 2 || s.lru.Len() != 2 {
+		t.Fatalf("cache holds %d rooms (%d in lru), want 2", len(s.cache), s.lru.Len())
+	}
+	if _, ok := s.cache[codes[0]]; ok {
+		t.Fatalf("least recently used room %s is still cached", codes[0])
+	}
+
+	r, ok := s.GetRoom(codes[0])
+	if !ok || r.Code != codes[0] {
+		t.Fatalf("dropped room %s not read back", codes[0])
+	}
+	if _, ok := s.cache[codes[1]]; ok {
+		t.Fatalf("room %s should have made way for %s", codes[1], codes[0])
+	}
+}
+
+// Listing rooms does not fill the cache
+func TestPostgresListRoomsLeavesCache(t *testing.T) {
+	s := openTestStore(t)
+	m := newTestManager(s)
+	code := testCode(t, s)
+	if _, err := m.CreateLobbyRoom(code, "host"); err != nil {
+		t.Fatal(err)
+	}
+
+	s.mu.Lock()
+	s.cache = map[string]*list.Element{}
+	s.lru.Init()
+	s.mu.Unlock()
+
+	found := false
+	for _, r := range s.ListRooms() {
+		found = found || r.Code == code
+	}
+	if !found {
+		t.Fatalf("room %s not listed", code)
+	}
+	if s.lru.Len() != 0 {
+		t.Fatalf("listing cached %d rooms", s.lru.Len())
+	}
+}
+
+// Reads are served from the cache while a save waits on the database
+func TestPostgresGetRoomDuringSlowSave(t *testing.T) {
+	s := openTestStore(t)
+	m := newTestManager(s)
+	code := testCode(t, s)
+	r, err := m.CreateLobbyRoom(code, "host")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Hold the room's row so the next save blocks in the database
+	tx, err := s.db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`SELECT 1 FROM rooms WHERE code = $1 FOR UPDATE`, code); err != nil {
+		t.Fatal(err)
+	}
+
+	r.Players[0].Name = "renamed"
+	saved := make(chan struct{})
+	go func() {
+		s.SaveRoom(r)
+		close(saved)
+	}()
+
+	read := make(chan *shared.Room, 1)
+	go func() {
+		for {
+			if got, _ := s.GetRoom(code); got.Players[0].Name == "renamed" {
+				read <- got
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	select {
+	case <-read:
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetRoom blocked behind a save waiting on the database")
+	}
+	select {
+	case <-saved:
+		t.Fatal("save finished while the row was held")
+	default:
+	}
+
+	tx.Rollback()
+	<-saved
+}
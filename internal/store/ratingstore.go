@@ -0,0 +1,102 @@
+package store
+
+import (
+	"encoding/json"
+	"javanese-chess/internal/shared"
+	"log"
+	"os"
+	"sort"
+	"sync"
+)
+
+// FileRatingStore keeps player ratings in memory and persists them to a JSON
+// file after every update so they survive restarts
+type FileRatingStore struct {
+	mu      sync.RWMutex
+	path    string
+	ratings map[string]*shared.PlayerRating
+}
+
+// NewFileRatingStore loads ratings from path if the file exists
+func NewFileRatingStore(path string) *FileRatingStore {
+	s := &FileRatingStore{
+		path:    path,
+		ratings: map[string]*shared.PlayerRating{},
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Warning: could not read ratings file %s: %v", path, err)
+		}
+		return s
+	}
+
+	var list []*shared.PlayerRating
+	if err := json.Unmarshal(data, &list); err != nil {
+		log.Printf("Warning: could not parse ratings file %s: %v", path, err)
+		return s
+	}
+	for _, r := range list {
+		s.ratings[r.PlayerID] = r
+	}
+	return s
+}
+
+func (s *FileRatingStore) GetRating(playerID string) (*shared.PlayerRating, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.ratings[playerID]
+	if !ok {
+		return nil, false
+	}
+	cp := *r
+	return &cp, true
+}
+
+// SaveRatings stores the given ratings and flushes the whole table to disk
+func (s *FileRatingStore) SaveRatings(ratings []*shared.PlayerRating) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range ratings {
+		cp := *r
+		s.ratings[r.PlayerID] = &cp
+	}
+
+	data, err := json.MarshalIndent(s.sortedLocked(), "", "  ")
+	if err != nil {
+		log.Printf("Warning: could not encode ratings: %v", err)
+		return
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		log.Printf("Warning: could not write ratings file %s: %v", s.path, err)
+	}
+}
+
+// ListRatings returns all ratings, best first
+func (s *FileRatingStore) ListRatings() []shared.PlayerRating {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sorted := s.sortedLocked()
+	out := make([]shared.PlayerRating, len(sorted))
+	for i, r := range sorted {
+		out[i] = *r
+	}
+	return out
+}
+
+func (s *FileRatingStore) sortedLocked() []*shared.PlayerRating {
+	list := make([]*shared.PlayerRating, 0, len(s.ratings))
+	for _, r := range s.ratings {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Rating != list[j].Rating {
+			return list[i].Rating > list[j].Rating
+		}
+		return list[i].PlayerID < list[j].PlayerID
+	})
+	return list
+}
@@ -0,0 +1,102 @@
+package tournament
+
+import "math/bits"
+
+// swissRounds is the number of rounds that separates a sole leader from n
+// entrants: ceil(log2 n)
+func swissRounds(n int) int {
+	return bits.Len(uint(n - 1))
+}
+
+// swissPairings pairs entrants on equal points, best first. The bye goes to
+// the lowest ranked entrant who has not had one, and each entrant meets the
+// highest ranked opponent they have not played yet; a rematch is only paired
+// when nobody else is left.
+func swissPairings(t *Tournament, table []Standing) []Pairing {
+	played := make(map[[2]string]bool)
+	hadBye := make(map[string]bool)
+	for _, r := range t.Rounds {
+		for _, p := range r.Pairings {
+			if p.EntrantB == "" {
+				hadBye[p.EntrantA] = true
+				continue
+			}
+			played[[2]string{p.EntrantA, p.EntrantB}] = true
+			played[[2]string{p.EntrantB, p.EntrantA}] = true
+		}
+	}
+
+	order := make([]string, len(table))
+	for i, st := range table {
+		order[i] = st.EntrantID
+	}
+
+	var pairings []Pairing
+	if len(order)%2 == 1 {
+		bye := len(order) - 1
+		for i := len(order) - 1; i >= 0; i-- {
+			if !hadBye[order[i]] {
+				bye = i
+				break
+			}
+		}
+		pairings = append(pairings, Pairing{EntrantA: order[bye]})
+		order = append(order[:bye:bye], order[bye+1:]...)
+	}
+
+	for len(order) > 0 {
+		a, opp := order[0], 1
+		for j := 1; j < len(order); j++ {
+			if !played[[2]string{a, order[j]}] {
+				opp = j
+				break
+			}
+		}
+		pairings = append(pairings, Pairing{EntrantA: a, EntrantB: order[opp]})
+		order = append(order[1:opp:opp], order[opp+1:]...)
+	}
+
+	// List the games first and the bye last
+	if len(pairings) > 0 && pairings[0].EntrantB == "" {
+		pairings = append(pairings[1:], pairings[0])
+	}
+	return pairings
+}
+
+// roundRobinPairings pairs round number round (from 0) with the circle
+// method: the first entrant stays put while the others rotate one place
+// each round. An odd field adds an empty seat, and its partner has the bye.
+func roundRobinPairings(entrants []Entrant, round int) []Pairing {
+	ids := make([]string, 0, len(entrants)+1)
+	for _, e := range entrants {
+		ids = append(ids, e.ID)
+	}
+	if len(ids)%2 == 1 {
+		ids = append(ids, "")
+	}
+
+	n := len(ids)
+	circle := make([]string, n)
+	circle[0] = ids[0]
+	for i := 1; i < n; i++ {
+		circle[i] = ids[1+(i-1+round)%(n-1)]
+	}
+
+	var games, byes []Pairing
+	for i := 0; i < n/2; i++ {
+		a, b := circle[i], circle[n-1-i]
+		// Alternate who is listed first so nobody always hosts
+		if round%2 == 1 {
+			a, b = b, a
+		}
+		switch {
+		case a == "":
+			byes = append(byes, Pairing{EntrantA: b})
+		case b == "":
+			byes = append(byes, Pairing{EntrantA: a})
+		default:
+			games = append(games, Pairing{EntrantA: a, EntrantB: b})
+		}
+	}
+	return append(games, byes...)
+}
@@ -0,0 +1,92 @@
+package tournament
+
+import "sort"
+
+// Points per result
+const (
+	pointsWin  = 1.0
+	pointsDraw = 0.5
+	pointsBye  = 1.0
+)
+
+// Standing is an entrant's record so far. Ties on points are broken by
+// Buchholz, the sum of the points of everyone the entrant played.
+type Standing struct {
+	Rank      int     `json:"rank"`
+	EntrantID string  `json:"entrant_id"`
+	Name      string  `json:"name"`
+	Bot       bool    `json:"bot"`
+	Points    float64 `json:"points"`
+	Buchholz  float64 `json:"buchholz"`
+	Played    int     `json:"played"`
+	Wins      int     `json:"wins"`
+	Draws     int     `json:"draws"`
+	Losses    int     `json:"losses"`
+	Byes      int     `json:"byes"`
+}
+
+// standings ranks the entrants by points, then Buchholz, then wins, then
+// registration order
+func standings(t *Tournament) []Standing {
+	table := make([]Standing, len(t.Entrants))
+	at := make(map[string]int, len(t.Entrants))
+	for i, e := range t.Entrants {
+		table[i] = Standing{EntrantID: e.ID, Name: e.Name, Bot: e.Bot}
+		at[e.ID] = i
+	}
+
+	opponents := make(map[string][]string)
+	for _, r := range t.Rounds {
+		for _, p := range r.Pairings {
+			switch p.Status {
+			case PairingBye:
+				st := &table[at[p.EntrantA]]
+				st.Byes++
+				st.Points += pointsBye
+			case PairingFinished:
+				a, b := &table[at[p.EntrantA]], &table[at[p.EntrantB]]
+				a.Played++
+				b.Played++
+				opponents[p.EntrantA] = append(opponents[p.EntrantA], p.EntrantB)
+				opponents[p.EntrantB] = append(opponents[p.EntrantB], p.EntrantA)
+
+				switch p.WinnerID {
+				case "":
+					a.Draws++
+					b.Draws++
+					a.Points += pointsDraw
+					b.Points += pointsDraw
+				case p.EntrantA:
+					a.Wins++
+					b.Losses++
+					a.Points += pointsWin
+				default:
+					b.Wins++
+					a.Losses++
+					b.Points += pointsWin
+				}
+			}
+		}
+	}
+
+	for i := range table {
+		for _, opp := range opponents[table[i].EntrantID] {
+			table[i].Buchholz += table[at[opp]].Points
+		}
+	}
+
+	sort.SliceStable(table, func(i, j int) bool {
+		a, b := table[i], table[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Buchholz != b.Buchholz {
+			return a.Buchholz > b.Buchholz
+		}
+		return a.Wins > b.Wins
+	})
+	for i := range table {
+		table[i].Rank = i + 1
+	}
+	return table
+}
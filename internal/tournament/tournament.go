@@ -0,0 +1,461 @@
+// Package tournament runs events between registered players and bots. Each
+// round pairs the entrants two by two, every pairing plays in a room of its
+// own, and the next round is paired once the manager reports every game of
+// the current one over.
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/shared"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Formats
+const (
+	FormatSwiss      = "swiss"       // Pair entrants on equal points, ceil(log2 n) rounds by default
+	FormatRoundRobin = "round_robin" // Everyone plays everyone once
+)
+
+// Tournament statuses
+const (
+	StatusRegistering = "registering"
+	StatusRunning     = "running"
+	StatusFinished    = "finished"
+)
+
+// Pairing statuses
+const (
+	PairingPlaying  = "playing"
+	PairingFinished = "finished"
+	PairingBye      = "bye"    // The entrant sat the round out and scores a win
+	PairingFailed   = "failed" // The room could not be created; nobody scores
+)
+
+var (
+	ErrNotFound       = errors.New("tournament not found")
+	ErrNotRegistering = errors.New("tournament has already started")
+	ErrNotOwner       = errors.New("only the tournament owner can do that")
+)
+
+// Rooms creates and starts the room of a pairing. It returns the player IDs
+// in the same order as seats.
+type Rooms interface {
+	CreateSeatedRoom(seats []shared.Seat) (*shared.Room, []string, error)
+	SeatSecret(r *shared.Room, playerID string) string
+}
+
+// Notifier delivers events to WebSocket clients subscribed to a channel and
+// sets bots to play when they move first
+type Notifier interface {
+	Broadcast(roomCode string, action string, data interface{})
+	ResumeBots(roomCode string)
+}
+
+// Entrant is a registered player or bot. Human entrants subscribe to their
+// ID over WebSocket (/ws?room_code=<entrant id>) to receive
+// tournament_pairing with the room and player ID of each game.
+type Entrant struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Bot  bool   `json:"bot"`
+}
+
+// Pairing is one game of a round, or a bye when EntrantB is empty
+type Pairing struct {
+	EntrantA string `json:"entrant_a"`
+	EntrantB string `json:"entrant_b,omitempty"`
+	RoomCode string `json:"room_code,omitempty"`
+	Status   string `json:"status"`
+	WinnerID string `json:"winner_id,omitempty"` // Entrant ID; empty for a draw
+	Error    string `json:"error,omitempty"`
+
+	players map[string]string // Entrant ID -> player ID in the room
+	secrets map[string]string // Entrant ID -> seat secret to identify with
+}
+
+// Round is the pairings played at the same time
+type Round struct {
+	Number   int       `json:"number"`
+	Pairings []Pairing `json:"pairings"`
+}
+
+// Tournament is the bracket: who entered and every round paired so far
+type Tournament struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	OwnerID    string    `json:"owner_id"` // Account that created the tournament
+	Format     string    `json:"format"`
+	RoundCount int       `json:"round_count"` // Rounds to play; settled when the tournament starts
+	Status     string    `json:"status"`
+	Entrants   []Entrant `json:"entrants"`
+	Rounds     []Round   `json:"rounds"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// slot locates the pairing a room is playing
+type slot struct {
+	t            *Tournament
+	round, index int
+}
+
+// Service keeps the tournaments in memory and drives them round by round
+type Service struct {
+	mu          sync.Mutex
+	tournaments map[string]*Tournament
+	byRoom      map[string]slot // Room code -> pairing waiting for its result
+	rooms       Rooms
+	notifier    Notifier
+}
+
+func NewService(rooms Rooms, notifier Notifier) *Service {
+	return &Service{
+		tournaments: make(map[string]*Tournament),
+		byRoom:      make(map[string]slot),
+		rooms:       rooms,
+		notifier:    notifier,
+	}
+}
+
+// Create opens a tournament for registration, owned by the account ownerID.
+// An empty format is swiss; rounds 0 lets the format decide and is the only
+// choice for round robin.
+func (s *Service) Create(ownerID, name, format string, rounds int) (Tournament, error) {
+	if ownerID == "" {
+		return Tournament{}, errors.New("an owner is required")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Tournament{}, errors.New("name is required")
+	}
+	if utf8.RuneCountInString(name) > config.TournamentNameMax {
+		return Tournament{}, fmt.Errorf("name is longer than %d characters", config.TournamentNameMax)
+	}
+	if format == "" {
+		format = FormatSwiss
+	}
+	switch {
+	case format != FormatSwiss && format != FormatRoundRobin:
+		return Tournament{}, errors.New("format must be swiss or round_robin")
+	case rounds < 0:
+		return Tournament{}, errors.New("rounds cannot be negative")
+	case format == FormatRoundRobin && rounds != 0:
+		return Tournament{}, errors.New("a round robin plays one round per opponent; leave rounds out")
+	}
+
+	t := &Tournament{
+		ID:         "t-" + uuid.NewString(),
+		Name:       name,
+		OwnerID:    ownerID,
+		Format:     format,
+		RoundCount: rounds,
+		Status:     StatusRegistering,
+		Entrants:   []Entrant{},
+		Rounds:     []Round{},
+		CreatedAt:  time.Now(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tournaments[t.ID] = t
+	log.Printf("Tournament %s (%s, %s) created", t.ID, t.Name, t.Format)
+	return t.view(), nil
+}
+
+// Register enters a player or bot while the tournament takes registrations.
+// Names are unique within a tournament.
+func (s *Service) Register(id, name string, bot bool) (Entrant, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Entrant{}, errors.New("name is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[id]
+	if !ok {
+		return Entrant{}, ErrNotFound
+	}
+	if t.Status != StatusRegistering {
+		return Entrant{}, ErrNotRegistering
+	}
+	if len(t.Entrants) >= config.TournamentMaxEntrants {
+		return Entrant{}, fmt.Errorf("tournament is full (%d entrants)", config.TournamentMaxEntrants)
+	}
+	for _, e := range t.Entrants {
+		if e.Name == name {
+			return Entrant{}, errors.New("name is already registered")
+		}
+	}
+
+	e := Entrant{ID: "e-" + uuid.NewString(), Name: name, Bot: bot}
+	t.Entrants = append(t.Entrants, e)
+	return e, nil
+}
+
+// Start closes registration and pairs the first round. Only the owner may
+// start a tournament.
+func (s *Service) Start(id, userID string) (Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[id]
+	if !ok {
+		return Tournament{}, ErrNotFound
+	}
+	if userID == "" || t.OwnerID != userID {
+		return Tournament{}, ErrNotOwner
+	}
+	if t.Status != StatusRegistering {
+		return Tournament{}, ErrNotRegistering
+	}
+	if len(t.Entrants) < 2 {
+		return Tournament{}, errors.New("a tournament needs at least 2 entrants")
+	}
+
+	// A round robin has as many rounds as opponents, counting the bye when
+	// the field is odd; swiss defaults to enough rounds for a sole leader
+	// and never plays more than the round robin would
+	full := len(t.Entrants) - 1 + len(t.Entrants)%2
+	if t.Format == FormatRoundRobin {
+		t.RoundCount = full
+	} else {
+		if t.RoundCount == 0 {
+			t.RoundCount = swissRounds(len(t.Entrants))
+		}
+		t.RoundCount = min(t.RoundCount, full)
+	}
+
+	t.Status = StatusRunning
+	log.Printf("Tournament %s started with %d entrants over %d rounds", t.ID, len(t.Entrants), t.RoundCount)
+	s.advance(t)
+	return t.view(), nil
+}
+
+// Get returns a copy of the tournament
+func (s *Service) Get(id string) (Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[id]
+	if !ok {
+		return Tournament{}, ErrNotFound
+	}
+	return t.view(), nil
+}
+
+// Standings ranks the entrants on the games finished so far
+func (s *Service) Standings(id string) ([]Standing, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return standings(t), nil
+}
+
+// GameOver records the result of a tournament game. The server registers
+// it as a room manager game over hook; rooms outside any tournament are
+// ignored. It runs under the room's lock, so the next round is paired in
+// the background.
+func (s *Service) GameOver(r *shared.Room) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sl, ok := s.byRoom[r.Code]
+	if !ok {
+		return
+	}
+	delete(s.byRoom, r.Code)
+
+	p := &sl.t.Rounds[sl.round].Pairings[sl.index]
+	p.Status = PairingFinished
+	p.WinnerID = winnerOf(p, r)
+	log.Printf("Tournament %s round %d: room %s finished, winner %q", sl.t.ID, sl.round+1, r.Code, p.WinnerID)
+
+	go func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.advance(sl.t)
+	}()
+}
+
+// winnerOf maps the room's result to an entrant: the winner, or the
+// opponent of a player who aborted. Draws have no winner.
+func winnerOf(p *Pairing, r *shared.Room) string {
+	var loser string
+	for entrant, player := range p.players {
+		if r.WinnerID != nil && *r.WinnerID == player {
+			return entrant
+		}
+		if r.AbortedBy != nil && *r.AbortedBy == player {
+			loser = entrant
+		}
+	}
+	switch loser {
+	case "":
+		return ""
+	case p.EntrantA:
+		return p.EntrantB
+	default:
+		return p.EntrantA
+	}
+}
+
+// advance pairs the next round once every game of the current one is over,
+// and finishes the tournament after the last. The caller holds s.mu.
+func (s *Service) advance(t *Tournament) {
+	for t.Status == StatusRunning && roundOver(t) {
+		if len(t.Rounds) >= t.RoundCount {
+			t.Status = StatusFinished
+			log.Printf("Tournament %s finished", t.ID)
+			s.notifier.Broadcast(t.ID, "tournament_over", map[string]interface{}{
+				"tournament_id": t.ID,
+				"standings":     standings(t),
+			})
+			return
+		}
+		s.startRound(t)
+	}
+}
+
+// roundOver reports whether the latest round has no game still playing
+func roundOver(t *Tournament) bool {
+	if len(t.Rounds) == 0 {
+		return true
+	}
+	for _, p := range t.Rounds[len(t.Rounds)-1].Pairings {
+		if p.Status == PairingPlaying {
+			return false
+		}
+	}
+	return true
+}
+
+// startRound pairs the next round and creates a room for each game. The
+// caller holds s.mu, so a game cannot report its result before its pairing
+// is recorded.
+func (s *Service) startRound(t *Tournament) {
+	round := Round{Number: len(t.Rounds) + 1}
+	if t.Format == FormatRoundRobin {
+		round.Pairings = roundRobinPairings(t.Entrants, len(t.Rounds))
+	} else {
+		round.Pairings = swissPairings(t, standings(t))
+	}
+	t.Rounds = append(t.Rounds, round)
+	ri := len(t.Rounds) - 1
+
+	var started []*shared.Room
+	for i := range t.Rounds[ri].Pairings {
+		p := &t.Rounds[ri].Pairings[i]
+		if p.EntrantB == "" {
+			p.Status = PairingBye
+			p.WinnerID = p.EntrantA
+			continue
+		}
+
+		a, b := t.entrant(p.EntrantA), t.entrant(p.EntrantB)
+		rx, ids, err := s.rooms.CreateSeatedRoom([]shared.Seat{
+			{Name: a.Name, Bot: a.Bot},
+			{Name: b.Name, Bot: b.Bot},
+		})
+		if err != nil {
+			log.Printf("Tournament %s round %d: cannot seat %s vs %s: %v", t.ID, round.Number, a.Name, b.Name, err)
+			p.Status = PairingFailed
+			p.Error = err.Error()
+			continue
+		}
+
+		p.RoomCode = rx.Code
+		p.Status = PairingPlaying
+		p.players = map[string]string{a.ID: ids[0], b.ID: ids[1]}
+		p.secrets = map[string]string{
+			a.ID: s.rooms.SeatSecret(rx, ids[0]),
+			b.ID: s.rooms.SeatSecret(rx, ids[1]),
+		}
+		s.byRoom[rx.Code] = slot{t: t, round: ri, index: i}
+		started = append(started, rx)
+	}
+
+	log.Printf("Tournament %s round %d paired: %d game(s)", t.ID, round.Number, len(started))
+	s.notifier.Broadcast(t.ID, "tournament_round", map[string]interface{}{
+		"tournament_id": t.ID,
+		"round":         t.Rounds[ri].view(),
+	})
+	for _, p := range t.Rounds[ri].Pairings {
+		s.notifyPairing(t, ri, p)
+	}
+
+	// Bots who open their game start playing right away
+	for _, rx := range started {
+		if rx.Players[rx.TurnIdx].IsBot {
+			s.notifier.ResumeBots(rx.Code)
+		}
+	}
+}
+
+// notifyPairing tells each human entrant of a pairing where to play
+func (s *Service) notifyPairing(t *Tournament, round int, p Pairing) {
+	for _, id := range []string{p.EntrantA, p.EntrantB} {
+		e := t.entrant(id)
+		if e == nil || e.Bot {
+			continue
+		}
+		opponent := p.EntrantB
+		if id == p.EntrantB {
+			opponent = p.EntrantA
+		}
+		s.notifier.Broadcast(id, "tournament_pairing", map[string]interface{}{
+			"tournament_id": t.ID,
+			"round":         round + 1,
+			"status":        p.Status,
+			"room_code":     p.RoomCode,
+			"player_id":     p.players[id],
+			"seat_secret":   p.secrets[id],
+			"opponent":      opponent,
+		})
+	}
+}
+
+// entrant finds an entrant by ID; nil when there is none
+func (t *Tournament) entrant(id string) *Entrant {
+	for i := range t.Entrants {
+		if t.Entrants[i].ID == id {
+			return &t.Entrants[i]
+		}
+	}
+	return nil
+}
+
+// view copies the tournament so callers can read it after s.mu is released
+func (t *Tournament) view() Tournament {
+	out := *t
+	out.Entrants = append([]Entrant(nil), t.Entrants...)
+	out.Rounds = make([]Round, len(t.Rounds))
+	for i, r := range t.Rounds {
+		out.Rounds[i] = r.view()
+	}
+	return out
+}
+
+// view copies the round without the room player IDs and seat secrets, which
+// let whoever holds them move for the entrant
+func (r Round) view() Round {
+	out := Round{Number: r.Number, Pairings: make([]Pairing, len(r.Pairings))}
+	for i, p := range r.Pairings {
+		p.players = nil
+		p.secrets = nil
+		out.Pairings[i] = p
+	}
+	return out
+}
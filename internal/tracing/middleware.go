@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware wraps every request in a server span named after its route.
+// A traceparent header continues the client's trace. Handlers reach the span
+// through c.Request.Context().
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if sc, ok := ParseTraceParent(c.GetHeader("traceparent")); ok {
+			ctx = WithRemoteParent(ctx, sc)
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := StartKind(ctx, c.Request.Method+" "+route, KindServer,
+			String("http.request.method", c.Request.Method),
+			String("http.route", route),
+			String("url.path", c.Request.URL.Path),
+			String("client.address", c.ClientIP()),
+		)
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(Int("http.response.status_code", status))
+		if code := c.Param("code"); code != "" {
+			span.SetAttributes(String("room.code", code))
+		}
+		if status >= 500 {
+			span.RecordError(fmt.Errorf("HTTP %d", status))
+		}
+	}
+}
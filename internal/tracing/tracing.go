@@ -0,0 +1,255 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Span kinds, numbered as in OTLP
+const (
+	KindInternal = 1
+	KindServer   = 2
+)
+
+// Tracer records spans and exports them in batches to an OTLP/HTTP collector
+// (see export.go). A nil Tracer records nothing, so code can start spans
+// whether or not tracing is configured.
+type Tracer struct {
+	endpoint string // Full URL of the collector's traces endpoint
+	headers  map[string]string
+	service  string
+	ratio    float64 // Fraction of new traces that are recorded
+	client   *http.Client
+
+	queue chan *Span
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+// Options configures a Tracer
+type Options struct {
+	Endpoint    string            // e.g. http://localhost:4318/v1/traces
+	Headers     map[string]string // Sent with every export, e.g. an API key
+	Service     string            // Reported as service.name
+	SampleRatio float64           // 1 records every trace, 0 none
+}
+
+// New starts a tracer exporting to opts.Endpoint. It returns nil when the
+// endpoint is empty. Call Shutdown to flush the spans still queued.
+func New(opts Options) *Tracer {
+	if opts.Endpoint == "" {
+		return nil
+	}
+	t := &Tracer{
+		endpoint: opts.Endpoint,
+		headers:  opts.Headers,
+		service:  opts.Service,
+		ratio:    opts.SampleRatio,
+		client:   &http.Client{Timeout: exportTimeout},
+		queue:    make(chan *Span, queueSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// Shutdown exports the queued spans and stops the tracer
+func (t *Tracer) Shutdown() {
+	if t == nil {
+		return
+	}
+	t.once.Do(func() {
+		close(t.stop)
+		<-t.done
+	})
+}
+
+var global atomic.Pointer[Tracer]
+
+// SetGlobal makes t the tracer used by Start; nil turns tracing off
+func SetGlobal(t *Tracer) {
+	global.Store(t)
+}
+
+// SpanContext identifies a span across process boundaries
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// IsValid reports whether the trace and span IDs are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// TraceParent formats sc as a W3C traceparent header
+func (sc SpanContext) TraceParent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", hex.EncodeToString(sc.TraceID[:]), hex.EncodeToString(sc.SpanID[:]), flags)
+}
+
+// ParseTraceParent reads a W3C traceparent header
+func ParseTraceParent(h string) (SpanContext, bool) {
+	var sc SpanContext
+	if len(h) != 55 || h[:3] != "00-" || h[35] != '-' || h[52] != '-' {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(h[3:35])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(h[36:52])); err != nil {
+		return sc, false
+	}
+	flags, err := hex.DecodeString(h[53:])
+	if err != nil {
+		return sc, false
+	}
+	sc.Sampled = flags[0]&1 == 1
+	return sc, sc.IsValid()
+}
+
+// Attr is a span attribute
+type Attr struct {
+	Key   string
+	Value interface{} // string, int, int64, float64 or bool
+}
+
+// String returns a string attribute
+func String(key, value string) Attr { return Attr{key, value} }
+
+// Int returns an integer attribute
+func Int(key string, value int) Attr { return Attr{key, value} }
+
+// Bool returns a boolean attribute
+func Bool(key string, value bool) Attr { return Attr{key, value} }
+
+// Span is one timed operation. A nil Span (tracing off or the trace not
+// sampled) ignores every call.
+type Span struct {
+	tracer *Tracer
+	sc     SpanContext
+	parent [8]byte
+	name   string
+	kind   int
+	start  time.Time
+
+	mu    sync.Mutex
+	end   time.Time
+	attrs []Attr
+	err   string
+}
+
+type spanKey struct{}
+
+// Start begins a span as a child of the span in ctx, or as the root of a new
+// trace, and returns a context carrying it. End the span when the operation
+// is done.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	return StartKind(ctx, name, KindInternal, attrs...)
+}
+
+// StartKind is Start for a span of the given kind
+func StartKind(ctx context.Context, name string, kind int, attrs ...Attr) (context.Context, *Span) {
+	t := global.Load()
+	if t == nil {
+		return ctx, nil
+	}
+
+	parent, ok := FromContext(ctx)
+	var sc SpanContext
+	if ok {
+		sc.TraceID = parent.TraceID
+		sc.Sampled = parent.Sampled
+	} else {
+		rand.Read(sc.TraceID[:])
+		sc.Sampled = t.sample(sc.TraceID)
+	}
+	rand.Read(sc.SpanID[:])
+	ctx = context.WithValue(ctx, spanKey{}, sc)
+	if !sc.Sampled {
+		return ctx, nil
+	}
+
+	s := &Span{tracer: t, sc: sc, name: name, kind: kind, start: time.Now(), attrs: attrs}
+	if ok {
+		s.parent = parent.SpanID
+	}
+	return ctx, s
+}
+
+// WithRemoteParent returns a context whose spans continue the trace of a
+// span in another process, such as the client's span in a traceparent header
+func WithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanKey{}, sc)
+}
+
+// FromContext returns the context of the current span in ctx
+func FromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanKey{}).(SpanContext)
+	return sc, ok
+}
+
+// sample decides from the trace ID whether a new trace is recorded, so every
+// service sampling at the same ratio keeps the same traces
+func (t *Tracer) sample(traceID [16]byte) bool {
+	if t.ratio >= 1 {
+		return true
+	}
+	if t.ratio <= 0 {
+		return false
+	}
+	return float64(binary.BigEndian.Uint64(traceID[8:])>>11)/(1<<53) < t.ratio
+}
+
+// SetAttributes adds attributes to the span
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// RecordError marks the span as failed; a nil error is ignored
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err.Error()
+}
+
+// End finishes the span and queues it for export. Spans are dropped when the
+// export queue is full rather than slowing the game down.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if !s.end.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	select {
+	case s.tracer.queue <- s:
+	default:
+	}
+}
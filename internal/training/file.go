@@ -0,0 +1,77 @@
+package training
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileSink appends samples as JSON lines to one file per day in a
+// directory, named moves-YYYY-MM-DD.jsonl
+type FileSink struct {
+	dir string
+
+	mu   sync.Mutex
+	day  string
+	file *os.File
+}
+
+// NewFileSink writes into dir, creating it if needed
+func NewFileSink(dir string) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("training dir: %w", err)
+	}
+	return &FileSink{dir: dir}, nil
+}
+
+func (s *FileSink) Write(samples []Sample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.open(time.Now().Format("2006-01-02")); err != nil {
+		return err
+	}
+
+	// Buffer the game and flush it at once rather than a write per sample
+	w := bufio.NewWriter(s.file)
+	enc := json.NewEncoder(w)
+	for _, sample := range samples {
+		if err := enc.Encode(sample); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// open switches to the file of day, closing the previous day's
+func (s *FileSink) open(day string) error {
+	if s.file != nil && s.day == day {
+		return nil
+	}
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.dir, "moves-"+day+".jsonl"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	s.file, s.day = f, day
+	return nil
+}
+
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,218 @@
+// Package training exports the moves played on the server as training data
+// for policy and value models. The recorder keeps each game's positions
+// until the game ends, then writes one sample per move, labelled with how
+// the game turned out for the player who made it, to a sink.
+package training
+
+import (
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/game"
+	"log"
+	"sync"
+	"time"
+)
+
+// Outcomes from the side of the player who moved
+const (
+	OutcomeLoss = 0.0
+	OutcomeDraw = 0.5
+	OutcomeWin  = 1.0
+)
+
+// queueSize is how many finished games may wait for the writer before new
+// ones are dropped; a slow sink never holds up play
+const queueSize = 64
+
+// Position is a move as the player faced it: the board before the move, the
+// hand and the legal placements, and what was played
+type Position struct {
+	RoomCode string
+	Ply      int // Moves played before this one in the game
+	PlayerID string
+	Bot      bool
+	Board    game.Board // A copy the recorder owns
+	Hand     []int
+	Legal    []game.Move
+	Chosen   game.Move
+	Weights  config.HeuristicWeights // Scores the legal moves
+}
+
+// Candidate is a legal placement and its heuristic score
+type Candidate struct {
+	X     int `json:"x"`
+	Y     int `json:"y"`
+	Card  int `json:"card"`
+	Score int `json:"score"`
+}
+
+// Choice is the move played. Type is "place" or "swap"; a swap only has a
+// card.
+type Choice struct {
+	Type string `json:"type"`
+	X    int    `json:"x,omitempty"`
+	Y    int    `json:"y,omitempty"`
+	Card int    `json:"card"`
+}
+
+// Sample is one training example. Board is the game.EncodeBoard tensor seen
+// from the player to move.
+type Sample struct {
+	RoomCode   string        `json:"room_code"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Ply        int           `json:"ply"`
+	PlayerID   string        `json:"player_id"`
+	Bot        bool          `json:"bot"`
+	Board      [][][]float32 `json:"board"`
+	Hand       []int         `json:"hand"`
+	LegalMoves []Candidate   `json:"legal_moves"`
+	Chosen     Choice        `json:"chosen"`
+	Outcome    float64       `json:"outcome"`
+}
+
+// Sink stores samples, a finished game at a time
+type Sink interface {
+	Write(samples []Sample) error
+	Close() error
+}
+
+// finished is a game waiting to be written
+type finished struct {
+	at        time.Time
+	positions []Position
+	outcome   map[string]float64
+}
+
+// Recorder collects positions while games are played. A nil recorder
+// records nothing, so callers need not check whether the export is on.
+type Recorder struct {
+	mu     sync.Mutex
+	games  map[string][]Position // Room code -> positions of the game in play
+	closed bool
+
+	sink  Sink
+	queue chan finished
+	done  chan struct{}
+}
+
+// NewRecorder starts writing finished games to sink in the background
+func NewRecorder(sink Sink) *Recorder {
+	rc := &Recorder{
+		games: make(map[string][]Position),
+		sink:  sink,
+		queue: make(chan finished, queueSize),
+		done:  make(chan struct{}),
+	}
+	go rc.write()
+	return rc
+}
+
+// Record adds a position to its room's game
+func (rc *Recorder) Record(p Position) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.games[p.RoomCode] = append(rc.games[p.RoomCode], p)
+}
+
+// Truncate forgets the positions of moves taken back: all but the first
+// keep moves of the game
+func (rc *Recorder) Truncate(roomCode string, keep int) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	positions := rc.games[roomCode]
+	for len(positions) > 0 && positions[len(positions)-1].Ply >= keep {
+		positions = positions[:len(positions)-1]
+	}
+	rc.games[roomCode] = positions
+}
+
+// Discard forgets a game that ended without a result
+func (rc *Recorder) Discard(roomCode string) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	delete(rc.games, roomCode)
+}
+
+// Finish queues the room's game for writing with each player's outcome
+func (rc *Recorder) Finish(roomCode string, outcome map[string]float64) {
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	positions := rc.games[roomCode]
+	delete(rc.games, roomCode)
+	if len(positions) == 0 || rc.closed {
+		return
+	}
+
+	select {
+	case rc.queue <- finished{at: time.Now(), positions: positions, outcome: outcome}:
+	default:
+		log.Printf("Training export is behind; dropped %d positions of room %s", len(positions), roomCode)
+	}
+}
+
+// Close writes the games already finished and closes the sink. Games still
+// being played are not written.
+func (rc *Recorder) Close() error {
+	if rc == nil {
+		return nil
+	}
+	rc.mu.Lock()
+	rc.closed = true
+	close(rc.queue)
+	rc.mu.Unlock()
+	<-rc.done
+	return rc.sink.Close()
+}
+
+// write turns finished games into samples until the recorder is closed.
+// Scoring the legal moves happens here, away from the room's lock.
+func (rc *Recorder) write() {
+	defer close(rc.done)
+	for g := range rc.queue {
+		samples := make([]Sample, 0, len(g.positions))
+		for _, p := range g.positions {
+			samples = append(samples, sampleOf(p, g.at, g.outcome[p.PlayerID]))
+		}
+		if err := rc.sink.Write(samples); err != nil {
+			log.Printf("Warning: could not export %d training samples: %v", len(samples), err)
+		}
+	}
+}
+
+// sampleOf encodes a position
+func sampleOf(p Position, at time.Time, outcome float64) Sample {
+	legal := make([]Candidate, len(p.Legal))
+	for i, mv := range p.Legal {
+		bd := game.EvaluateMoveBreakdown(&p.Board, mv.X, mv.Y, mv.Card, p.PlayerID, &p.Weights)
+		legal[i] = Candidate{X: mv.X, Y: mv.Y, Card: mv.Card, Score: bd.Total}
+	}
+
+	chosen := Choice{Type: string(game.MovePlace), X: p.Chosen.X, Y: p.Chosen.Y, Card: p.Chosen.Card}
+	if p.Chosen.Type == game.MoveSwap {
+		chosen = Choice{Type: string(game.MoveSwap), Card: p.Chosen.Card}
+	}
+
+	return Sample{
+		RoomCode:   p.RoomCode,
+		FinishedAt: at,
+		Ply:        p.Ply,
+		PlayerID:   p.PlayerID,
+		Bot:        p.Bot,
+		Board:      game.EncodeBoard(&p.Board, p.PlayerID),
+		Hand:       p.Hand,
+		LegalMoves: legal,
+		Chosen:     chosen,
+		Outcome:    outcome,
+	}
+}
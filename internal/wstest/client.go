@@ -0,0 +1,187 @@
+package wstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"javanese-chess/internal/api/ws"
+
+	"github.com/gorilla/websocket"
+)
+
+// Message is a message the server sent to a client
+type Message struct {
+	Action string          `json:"action"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Decode unmarshals the message data into out
+func (m Message) Decode(out interface{}) error {
+	return json.Unmarshal(m.Data, out)
+}
+
+// Client is a scripted WebSocket client. Every message it receives is kept
+// in order; Expect walks through them and Call waits for the reply to a
+// request, so scripts read like the conversation they expect.
+type Client struct {
+	Timeout time.Duration // How long Expect and Call wait
+
+	conn *websocket.Conn
+
+	mu       sync.Mutex
+	received []Message
+	next     int           // First message Expect has not passed yet
+	changed  chan struct{} // Closed when a message arrives or reading stops
+	err      error         // Why reading stopped
+	requests int
+}
+
+func newClient(conn *websocket.Conn, timeout time.Duration) *Client {
+	c := &Client{Timeout: timeout, conn: conn, changed: make(chan struct{})}
+	go c.read()
+	return c
+}
+
+func (c *Client) read() {
+	for {
+		var m Message
+		err := c.conn.ReadJSON(&m)
+
+		c.mu.Lock()
+		if err != nil {
+			c.err = err
+			close(c.changed)
+			c.mu.Unlock()
+			return
+		}
+		c.received = append(c.received, m)
+		close(c.changed)
+		c.changed = make(chan struct{})
+		c.mu.Unlock()
+	}
+}
+
+// Close closes the connection
+func (c *Client) Close() {
+	c.conn.Close()
+}
+
+// Send sends an action without waiting for the reply
+func (c *Client) Send(action string, data interface{}) error {
+	_, err := c.send(action, data)
+	return err
+}
+
+func (c *Client) send(action string, data interface{}) (string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.requests++
+	id := fmt.Sprintf("wstest-%d", c.requests)
+	c.mu.Unlock()
+
+	return id, c.conn.WriteJSON(ws.Envelope{V: ws.ProtocolVersion, Action: action, Data: raw, RequestID: id})
+}
+
+// Call sends an action and waits for the server to acknowledge it. A
+// rejected action returns the server's error message.
+func (c *Client) Call(action string, data interface{}) error {
+	c.mu.Lock()
+	from := len(c.received)
+	c.mu.Unlock()
+
+	id, err := c.send(action, data)
+	if err != nil {
+		return err
+	}
+
+	var reply struct {
+		RequestID string `json:"request_id"`
+		Message   string `json:"message"`
+	}
+	i, err := c.wait(from, func(m Message) bool {
+		if m.Action != "ack" && m.Action != "error" {
+			return false
+		}
+		return m.Decode(&reply) == nil && reply.RequestID == id
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	if c.message(i).Action == "error" {
+		return fmt.Errorf("%s: %s", action, reply.Message)
+	}
+	return nil
+}
+
+// Expect returns the next message with the given action, passing over any
+// other messages received before it
+func (c *Client) Expect(action string) (Message, error) {
+	return c.expect(action, func(m Message) bool { return m.Action == action })
+}
+
+// Next returns the next message, whatever its action
+func (c *Client) Next() (Message, error) {
+	return c.expect("a message", func(Message) bool { return true })
+}
+
+func (c *Client) expect(what string, match func(Message) bool) (Message, error) {
+	c.mu.Lock()
+	from := c.next
+	c.mu.Unlock()
+
+	i, err := c.wait(from, match)
+	if err != nil {
+		return Message{}, fmt.Errorf("waiting for %s: %w", what, err)
+	}
+
+	c.mu.Lock()
+	c.next = i + 1
+	c.mu.Unlock()
+	return c.message(i), nil
+}
+
+// Received returns every message received so far
+func (c *Client) Received() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Message(nil), c.received...)
+}
+
+func (c *Client) message(i int) Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.received[i]
+}
+
+// wait returns the index of the first message from index from on that
+// matches, waiting up to the timeout for it to arrive
+func (c *Client) wait(from int, match func(Message) bool) (int, error) {
+	deadline := time.After(c.Timeout)
+	for {
+		c.mu.Lock()
+		for i := from; i < len(c.received); i++ {
+			if match(c.received[i]) {
+				c.mu.Unlock()
+				return i, nil
+			}
+		}
+		from = len(c.received)
+		changed, err := c.changed, c.err
+		c.mu.Unlock()
+
+		if err != nil {
+			return -1, fmt.Errorf("connection closed: %w", err)
+		}
+		select {
+		case <-changed:
+		case <-deadline:
+			return -1, fmt.Errorf("timed out after %v", c.Timeout)
+		}
+	}
+}
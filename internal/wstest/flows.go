@@ -0,0 +1,435 @@
+package wstest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	httpapi "javanese-chess/internal/api/http"
+	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/game"
+)
+
+// Flow is one scripted end-to-end scenario
+type Flow struct {
+	Name string
+	Run  func(s *Server) error
+}
+
+// Failure describes a flow that did not go as scripted
+type Failure struct {
+	Flow   string
+	Reason string
+}
+
+func (f Failure) String() string {
+	return fmt.Sprintf("%s: %s", f.Flow, f.Reason)
+}
+
+// Flows are the scenarios checked by cmd/e2e
+var Flows = []Flow{
+	{Name: "lobby_to_game_over", Run: lobbyToGameOver},
+	{Name: "out_of_turn_rejected", Run: outOfTurnRejected},
+	{Name: "start_waits_for_ready", Run: startWaitsForReady},
+	{Name: "demo_game", Run: demoGame},
+	{Name: "export_after_game_over", Run: exportAfterGameOver},
+}
+
+// Run plays every flow against a server of its own and returns the failures
+func Run(flows []Flow) []Failure {
+	var failures []Failure
+	for _, f := range flows {
+		s := NewServer()
+		if err := f.Run(s); err != nil {
+			failures = append(failures, Failure{Flow: f.Name, Reason: err.Error()})
+		}
+		s.Close()
+	}
+	return failures
+}
+
+// Most turns a scripted game may take before the flow gives up
+const maxTurns = 200
+
+// table is a started two player game: both players are connected and
+// identified, and clients maps each player ID to its connection
+type table struct {
+	code    string
+	ids     []string // Host first
+	clients map[string]*Client
+}
+
+func (t *table) close() {
+	for _, c := range t.clients {
+		c.Close()
+	}
+}
+
+// startTable opens a lobby, has the guest get ready and starts the game. It
+// fails unless both players see game_started.
+func startTable(s *Server, code string) (*table, error) {
+	t, err := openLobby(s, code)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.clients[t.ids[1]].Call("ready", map[string]string{"player_id": t.ids[1]}); err != nil {
+		t.close()
+		return nil, err
+	}
+	if err := t.start(s); err != nil {
+		t.close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// start starts the lobby's game and waits for both players to see it
+func (t *table) start(s *Server) error {
+	if err := s.Post("/api/play", map[string]interface{}{
+		"room_id":     t.code,
+		"player_name": []string{"host", "guest"},
+	}, nil); err != nil {
+		return err
+	}
+	for _, id := range t.ids {
+		if _, err := t.clients[id].Expect("game_started"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openLobby creates a lobby over the WebSocket as the host and lets a guest
+// join over REST. Both players are connected and identified.
+func openLobby(s *Server, code string) (*table, error) {
+	t := &table{code: code, clients: map[string]*Client{}}
+
+	host, err := s.Dial(nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := host.Call("room_created", map[string]string{"room_code": code, "player_name": "host"}); err != nil {
+		host.Close()
+		return nil, err
+	}
+	var master struct {
+		PlayerID   string `json:"player_id"`
+		SeatSecret string `json:"seat_secret"`
+	}
+	seat, err := host.Expect("seat_assigned")
+	if err == nil {
+		err = seat.Decode(&master)
+	}
+	if err != nil {
+		host.Close()
+		return nil, err
+	}
+
+	var lobby httpapi.JoinResult
+	if err := s.Post("/api/join", map[string]string{"room_code": code, "player_name": "guest"}, &lobby); err != nil {
+		host.Close()
+		return nil, err
+	}
+	if len(lobby.Players) != 2 {
+		host.Close()
+		return nil, fmt.Errorf("lobby seats %d players after the guest joined, want 2", len(lobby.Players))
+	}
+	guest, err := s.Dial(url.Values{"room_code": {code}})
+	if err != nil {
+		host.Close()
+		return nil, err
+	}
+
+	// A seat without an account only binds with its secret
+	if err := guest.Call("identify", map[string]string{"player_id": master.PlayerID}); err == nil {
+		host.Close()
+		guest.Close()
+		return nil, fmt.Errorf("guest identified as the host's seat without its secret")
+	}
+
+	seats := []struct{ id, secret string }{
+		{master.PlayerID, master.SeatSecret},
+		{lobby.PlayerID, lobby.SeatSecret},
+	}
+	for i, c := range []*Client{host, guest} {
+		id := seats[i].id
+		t.ids = append(t.ids, id)
+		t.clients[id] = c
+		if err := c.Call("identify", map[string]string{"player_id": id, "seat_secret": seats[i].secret}); err != nil {
+			t.close()
+			return nil, err
+		}
+	}
+
+	// Identifying as one seat does not let a connection act for another
+	if err := guest.Call("ready", map[string]string{"player_id": master.PlayerID}); err == nil {
+		t.close()
+		return nil, fmt.Errorf("guest acted for the host's seat")
+	}
+	return t, nil
+}
+
+// lobbyToGameOver plays a whole game between two connected humans, each
+// moving over their own connection, and checks both see the same result
+func lobbyToGameOver(s *Server) error {
+	t, err := startTable(s, "E2EGAME")
+	if err != nil {
+		return err
+	}
+	defer t.close()
+
+	if err := t.playOut(s); err != nil {
+		return err
+	}
+
+	r, _ := s.Manager.Get(t.code)
+	for _, id := range t.ids {
+		msg, err := t.clients[id].Expect("game_over")
+		if err != nil {
+			return err
+		}
+		var over struct {
+			Winner *string `json:"winner"`
+		}
+		if err := msg.Decode(&over); err != nil {
+			return err
+		}
+		if !sameWinner(over.Winner, r.WinnerID) {
+			return fmt.Errorf("game_over names winner %v, the room %v", deref(over.Winner), deref(r.WinnerID))
+		}
+	}
+	return nil
+}
+
+// playOut has each player in turn play their first legal move over their
+// own connection until the game is over
+func (t *table) playOut(s *Server) error {
+	for turn := 0; ; turn++ {
+		if turn == maxTurns {
+			return fmt.Errorf("game still going after %d turns", maxTurns)
+		}
+		// Nothing else changes the room between replies, so reading the
+		// mover's hand from the server is safe
+		r, _ := s.Manager.Get(t.code)
+		if r.WinnerID != nil || r.Draw {
+			break
+		}
+		mover := r.Players[r.TurnIdx]
+		moves := game.GenerateLegalMoves(&r.Board, mover.Hand, mover.ID)
+		if len(moves) == 0 {
+			return fmt.Errorf("%s is to move without a legal move", mover.Name)
+		}
+		mv := moves[0]
+		if err := t.clients[mover.ID].Call("human_move", map[string]interface{}{
+			"player_id": mover.ID, "x": mv.X, "y": mv.Y, "card": mv.Card,
+		}); err != nil {
+			return fmt.Errorf("turn %d: %w", turn+1, err)
+		}
+	}
+	return nil
+}
+
+// exportAfterGameOver checks that a game's record, which holds the seed and
+// every draw, is refused while the game is played and given once it is over
+func exportAfterGameOver(s *Server) error {
+	t, err := startTable(s, "E2EXPRT")
+	if err != nil {
+		return err
+	}
+	defer t.close()
+
+	for _, path := range []string{"/export", "/diff?from=0&to=0"} {
+		err := s.Get("/api/rooms/"+t.code+path, nil)
+		if err == nil || !strings.Contains(err.Error(), "game_not_over") {
+			return fmt.Errorf("%s during the game: got %v, want game_not_over", path, err)
+		}
+	}
+
+	if err := t.playOut(s); err != nil {
+		return err
+	}
+	for _, path := range []string{"/export", "/diff?from=0&to=1"} {
+		if err := s.Get("/api/rooms/"+t.code+path, nil); err != nil {
+			return fmt.Errorf("%s after the game: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// outOfTurnRejected checks that a move out of turn is refused and reported
+// to its sender only
+func outOfTurnRejected(s *Server) error {
+	t, err := startTable(s, "E2ETURN")
+	if err != nil {
+		return err
+	}
+	defer t.close()
+
+	r, _ := s.Manager.Get(t.code)
+	waiting := r.Players[(r.TurnIdx+1)%len(r.Players)]
+	center := r.Board.Center()
+	err = t.clients[waiting.ID].Call("human_move", map[string]interface{}{
+		"player_id": waiting.ID, "x": center.X, "y": center.Y, "card": waiting.Hand[0],
+	})
+	if err == nil {
+		return errors.New("a move out of turn was accepted")
+	}
+	if !strings.Contains(err.Error(), "not your turn") {
+		return fmt.Errorf("out of turn move failed for another reason: %w", err)
+	}
+	r, _ = s.Manager.Get(t.code)
+	if r.Board.At(center).Value != 0 {
+		return errors.New("the rejected move reached the board")
+	}
+	return nil
+}
+
+// startWaitsForReady checks the game cannot start before the guest is ready,
+// and that the host's lobby shows the guest online and then ready
+func startWaitsForReady(s *Server) error {
+	t, err := openLobby(s, "E2EREADY")
+	if err != nil {
+		return err
+	}
+	defer t.close()
+	host, guest := t.clients[t.ids[0]], t.ids[1]
+
+	lobby, err := nextLobby(host)
+	for err == nil && !seat(lobby, guest).Online {
+		lobby, err = nextLobby(host)
+	}
+	if err != nil {
+		return err
+	}
+	if lobby.AllReady {
+		return errors.New("the lobby is all ready before the guest said so")
+	}
+
+	err = s.Post("/api/play", map[string]interface{}{"room_id": t.code, "player_name": []string{"host", "guest"}}, nil)
+	if err == nil {
+		return errors.New("the game started before the guest was ready")
+	}
+	if !strings.Contains(err.Error(), "ready") {
+		return fmt.Errorf("start failed for another reason: %w", err)
+	}
+
+	if err := t.clients[guest].Call("ready", map[string]string{"player_id": guest}); err != nil {
+		return err
+	}
+	for !lobby.AllReady {
+		if lobby, err = nextLobby(host); err != nil {
+			return err
+		}
+	}
+	if !seat(lobby, guest).Ready {
+		return errors.New("the lobby is all ready without the guest being ready")
+	}
+	return t.start(s)
+}
+
+func nextLobby(c *Client) (ws.LobbyStateData, error) {
+	var lobby ws.LobbyStateData
+	msg, err := c.Expect("lobby_state")
+	if err != nil {
+		return lobby, err
+	}
+	return lobby, msg.Decode(&lobby)
+}
+
+// seat returns the lobby's view of a player, empty when not seated
+func seat(lobby ws.LobbyStateData, id string) ws.LobbyPlayer {
+	for _, p := range lobby.Players {
+		if p.ID == id {
+			return p
+		}
+	}
+	return ws.LobbyPlayer{}
+}
+
+// demoGame watches a bot demo from a delta board connection until the game
+// ends, checking the board stays in sync through the deltas
+func demoGame(s *Server) error {
+	var demo httpapi.DemoResult
+	if err := s.Post("/api/demo", map[string]int{"bots": 2, "delay_ms": 200}, &demo); err != nil {
+		return err
+	}
+
+	viewer, err := s.Dial(url.Values{"room_code": {demo.RoomCode}, "board": {"delta"}})
+	if err != nil {
+		return err
+	}
+	defer viewer.Close()
+	viewer.Timeout = time.Minute
+
+	// Follow every board update until the game is over
+	var board game.Board
+	version := -1
+	for {
+		msg, err := viewer.Next()
+		if err != nil {
+			return err
+		}
+		if err := applyUpdate(&board, &version, msg); err != nil {
+			return err
+		}
+		if msg.Action == "game_over" {
+			break
+		}
+	}
+
+	r, _ := s.Manager.Get(demo.RoomCode)
+	if r.WinnerID == nil && !r.Draw {
+		return errors.New("the demo game did not finish")
+	}
+	if !reflect.DeepEqual(board.Cells, r.Board.Cells) {
+		return errors.New("the board rebuilt from deltas differs from the room's board")
+	}
+	return nil
+}
+
+// applyUpdate brings a delta client's copy of the board up to date with a
+// message carrying the full board or a delta. Updates older than the copy,
+// sent before the connection's first board_sync, are skipped.
+func applyUpdate(board *game.Board, version *int, msg Message) error {
+	var update struct {
+		Version *int           `json:"version"`
+		Board   *game.Board    `json:"board"`
+		Delta   *ws.BoardDelta `json:"board_delta"`
+	}
+	if err := msg.Decode(&update); err != nil {
+		return err
+	}
+	if update.Version == nil || *update.Version <= *version {
+		return nil
+	}
+
+	switch {
+	case update.Board != nil:
+		*board = *update.Board
+	case update.Delta != nil:
+		if update.Delta.From != *version {
+			return fmt.Errorf("%s: delta from version %d, client is at %d", msg.Action, update.Delta.From, *version)
+		}
+		for _, c := range update.Delta.Cells {
+			*board.CellAt(game.Coord{X: c.X, Y: c.Y}) = c.Cell
+		}
+	default:
+		return nil
+	}
+	*version = *update.Version
+	return nil
+}
+
+func sameWinner(a, b *string) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return "nobody"
+	}
+	return *s
+}
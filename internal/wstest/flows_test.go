@@ -0,0 +1,41 @@
+package wstest
+
+import (
+	"io"
+	"javanese-chess/internal/config"
+	"log"
+	"testing"
+)
+
+// Every flow plays out against a server of its own
+func TestFlows(t *testing.T) {
+	log.SetOutput(io.Discard)
+	for _, f := range Flows {
+		t.Run(f.Name, func(t *testing.T) {
+			s := NewServer()
+			defer s.Close()
+			if err := f.Run(s); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// Starting a server leaves the process-wide config as it was
+func TestNewServerKeepsConfig(t *testing.T) {
+	log.SetOutput(io.Discard)
+	global := config.Get()
+	want := *global
+	if want.HTTPRateLimit == 0 || want.WSRateLimit == 0 {
+		t.Skip("rate limits are already off")
+	}
+
+	NewServer().Close()
+	if global.HTTPRateLimit != want.HTTPRateLimit || global.WSRateLimit != want.WSRateLimit {
+		t.Errorf("rate limits changed from %v/%v to %v/%v",
+			want.HTTPRateLimit, want.WSRateLimit, global.HTTPRateLimit, global.WSRateLimit)
+	}
+	if global.StoreBackend != want.StoreBackend || global.JWTSecret != want.JWTSecret {
+		t.Error("store backend or JWT secret changed")
+	}
+}
@@ -0,0 +1,147 @@
+// Package wstest runs the whole server in process, with an in-memory store,
+// and drives it like a browser would: REST requests and scripted WebSocket
+// clients. Flows (see flows.go) use it to check room creation, joining,
+// playing and game over end to end.
+package wstest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"javanese-chess/internal/api/ws"
+	"javanese-chess/internal/app"
+	"javanese-chess/internal/config"
+	"javanese-chess/internal/room"
+
+	"github.com/gorilla/websocket"
+)
+
+// Server is the HTTP and WebSocket API listening on a local port. Manager
+// and Hub are exposed so scripts can inspect what the server holds.
+type Server struct {
+	URL     string // Base URL, e.g. http://127.0.0.1:41234
+	Manager *room.Manager
+	Hub     *ws.Hub
+
+	app    *app.App
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewServer starts a server wired like cmd/server, with a fresh in-memory
+// store and no gRPC service or ratings file. Bots move without thinking time
+// and rate limits are turned off, as scripted clients act far faster than
+// people. The process-wide config is copied, never changed. Call Close when
+// done.
+func NewServer() *Server {
+	cfg := *config.Get()
+	cfg.HTTPRateLimit, cfg.WSRateLimit = 0, 0
+	cfg.StoreBackend = "memory"
+	cfg.RatingsFile = ""
+	cfg.GRPCAddr = ""
+	if cfg.JWTSecret == "" {
+		cfg.JWTSecret = "wstest-secret" // Production profile without JWT_SECRET
+	}
+	a, err := app.New(&cfg)
+	if err != nil {
+		panic(err) // The memory store and the secret cannot fail
+	}
+	a.Manager.SetBotDelay(0)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("wstest: failed to listen on a port: %v", err))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		URL:     "http://" + lis.Addr().String(),
+		Manager: a.Manager,
+		Hub:     a.Hub,
+		app:     a,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		if err := a.Serve(ctx, lis); err != nil {
+			log.Printf("wstest: server stopped: %v", err)
+		}
+	}()
+	return s
+}
+
+// Close shuts the server down and waits until it has stopped
+func (s *Server) Close() {
+	s.cancel()
+	<-s.done
+}
+
+// Post sends body as JSON and decodes the data of the success envelope into
+// out, which may be nil. Failed requests return the server's error message.
+func (s *Server) Post(path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(s.URL+path, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	return decodeResponse(path, resp, out)
+}
+
+// Get fetches path and decodes the data of the success envelope into out
+func (s *Server) Get(path string, out interface{}) error {
+	resp, err := http.Get(s.URL + path)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(path, resp, out)
+}
+
+func decodeResponse(path string, resp *http.Response, out interface{}) error {
+	defer resp.Body.Close()
+
+	var env struct {
+		Data  json.RawMessage `json:"data"`
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return fmt.Errorf("%s: %s with unreadable body: %v", path, resp.Status, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: %s: %s (%s)", path, resp.Status, env.Error.Message, env.Error.Code)
+	}
+	if out == nil || len(env.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(env.Data, out)
+}
+
+// Dial opens a WebSocket connection with the given query parameters, such
+// as room_code, player_id or board
+func (s *Server) Dial(query url.Values) (*Client, error) {
+	u := "ws" + strings.TrimPrefix(s.URL, "http") + "/ws"
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(conn, DefaultTimeout), nil
+}
+
+// DefaultTimeout is how long a client waits for an expected message
+const DefaultTimeout = 5 * time.Second